@@ -0,0 +1,79 @@
+// Команда credit-history-export выгружает кредитные истории всех заемщиков банка в файл для передачи
+// в бюро кредитных историй. Подключение к базе данных и реквизиты банка берутся из тех же переменных
+// окружения, что и у сервера.
+//
+// Использование:
+//
+//	credit-history-export -format fixed -out credit_history.txt
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/bureau"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/db"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+func main() {
+	formatFlag := flag.String("format", string(bureau.FIXED), "формат выгрузки: json, xml или fixed")
+	outFlag := flag.String("out", "", "файл выгрузки; по умолчанию credit_history_YYYYMMDD.<расширение> в текущем каталоге")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+
+	format, err := bureau.ParseFormat(*formatFlag)
+	if err != nil {
+		logger.Fatalf("Поддерживаются форматы json, xml и fixed: %v", err)
+	}
+	out := *outFlag
+	if out == "" {
+		out = fmt.Sprintf("credit_history_%s.%s", time.Now().UTC().Format("20060102"), format.Extension())
+	}
+
+	// Подключение к базе данных
+	ctx := context.Background()
+	pool, err := db.New(ctx, config.LoadDB())
+	if err != nil {
+		logger.Fatalf("Ошибка подключения к базе данных: %v", err)
+	}
+	defer pool.Close()
+
+	creditHistoryService := service.NewCreditHistoryService(repository.NewCreditRepository(pool),
+		repository.NewCollectionRepository(pool), repository.NewUserRepository(pool), config.LoadBank())
+
+	file, err := os.Create(out)
+	if err != nil {
+		logger.Fatalf("Ошибка создания файла выгрузки: %v", err)
+	}
+	buf := bufio.NewWriter(file)
+
+	bw, err := bureau.NewWriter(format, buf)
+	if err != nil {
+		logger.Fatalf("Ошибка выбора формата выгрузки: %v", err)
+	}
+	totals, err := creditHistoryService.Export(ctx, bw)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Неполный файл не должен попасть в бюро
+		os.Remove(out)
+		logger.Fatalf("Ошибка выгрузки кредитных историй: %v", err)
+	}
+
+	logger.Infof("Кредитные истории выгружены в %s: заемщиков %d, кредитов %d", out, totals.Subjects, totals.Credits)
+}
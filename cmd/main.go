@@ -14,11 +14,15 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/calendar"
+	"github.com/yujihn/bank_API/internal/cbr"
 	"github.com/yujihn/bank_API/internal/config"
 	"github.com/yujihn/bank_API/internal/db"
 	"github.com/yujihn/bank_API/internal/handler"
 	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/notify"
 	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/scheduler"
 	"github.com/yujihn/bank_API/internal/service"
 )
 
@@ -53,7 +57,28 @@ func main() {
 	ctx := context.Background()
 	dbCfg := config.LoadDB()
 	jwtCfg := config.LoadJWT()
-	cryptoCfg := config.LoadCrypto()
+	cryptoCfg, err := config.LoadCrypto()
+	if err != nil {
+		logger.Fatalf("Ошибка конфигурации: %v", err)
+	}
+	bankCfg := config.LoadBank()
+	schedCfg := config.LoadScheduler()
+	qrCfg := config.LoadQR()
+	savingsCfg := config.LoadSavings()
+	depositCfg := config.LoadDeposit()
+	cbrCfg := config.LoadCBR()
+	overdraftCfg := config.LoadOverdraft()
+	cardCfg := config.LoadCard()
+	creditCfg := config.LoadCredit()
+	calendarCfg := config.LoadCalendar()
+	smtpCfg := config.LoadSMTP()
+	scoringCfg := config.LoadScoring()
+	collectionsCfg := config.LoadCollections()
+	mfaCfg := config.LoadMFA()
+	lockoutCfg := config.LoadLockout()
+	proxyCfg := config.LoadProxy()
+	credentialsCfg := config.LoadCredentials()
+	kycCfg := config.LoadKYC()
 
 	// Формирование DSN и запуск миграций базы данных
 	dsn := db.BuildDSN(dbCfg)
@@ -72,19 +97,122 @@ func main() {
 	accountRepo := repository.NewAccountRepository(pool)
 	transactionRepo := repository.NewTransactionRepository(pool)
 	cardRepo := repository.NewCardRepository(pool)
+	batchRepo := repository.NewBatchRepository(pool)
+	standingOrderRepo := repository.NewStandingOrderRepository(pool)
+	paymentRequestRepo := repository.NewPaymentRequestRepository(pool)
+	qrPaymentRepo := repository.NewQRPaymentRepository(pool)
+	savingsRepo := repository.NewSavingsRepository(pool)
+	depositRepo := repository.NewDepositRepository(pool)
+	overdraftRepo := repository.NewOverdraftRepository(pool)
+	creditRepo := repository.NewCreditRepository(pool)
+	keyRateRepo := repository.NewKeyRateRepository(pool)
+	creditApplicationRepo := repository.NewCreditApplicationRepository(pool)
+	collectionRepo := repository.NewCollectionRepository(pool)
+	sessionRepo := repository.NewSessionRepository(pool)
+	jwtKeyRepo := repository.NewJWTKeyRepository(pool, cryptoCfg.PGPKey)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(pool)
+	mfaRepo := repository.NewMFARepository(pool, cryptoCfg.PGPKey)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(pool)
+	userTokenRepo := repository.NewUserTokenRepository(pool)
+
+	// Клиент ЦБ РФ для получения ключевой ставки
+	cbrClient := cbr.NewClient(cbrCfg.URL, cbrCfg.Timeout)
+
+	// Отправка уведомлений по электронной почте
+	notifier := notify.New(smtpCfg, logger)
+
+	// Производственный календарь для переноса дат платежей с нерабочих дней
+	cal, err := calendar.Load(calendarCfg.Files...)
+	if err != nil {
+		logger.Fatalf("Ошибка загрузки производственного календаря: %v", err)
+	}
 
 	// Создание сервисов бизнес-логики
-	authService := service.NewAuthService(userRepo, jwtCfg)
-	accountService := service.NewAccountService(accountRepo, transactionRepo)
-	cardService := service.NewCardService(cardRepo, pool, cryptoCfg.HMACKey)
+	jwtKeyService := service.NewJWTKeyService(jwtKeyRepo, jwtCfg, schedCfg.JWTKeysInterval, logger)
+	mfaService := service.NewMFAService(mfaRepo, sessionRepo, userRepo, mfaCfg)
+	loginGuardService := service.NewLoginGuardService(loginThrottleRepo, userRepo, notifier, lockoutCfg, logger)
+	credentialService := service.NewCredentialService(userTokenRepo, userRepo, sessionRepo, loginGuardService, notifier,
+		credentialsCfg, logger)
+	authService := service.NewAuthService(userRepo, sessionRepo, revokedTokenRepo, jwtKeyService, mfaService,
+		loginGuardService, credentialService, jwtCfg)
+	kycPolicy := service.NewKYCPolicy(userRepo, transactionRepo, kycCfg)
+	profileService := service.NewProfileService(userRepo, kycPolicy, notifier, kycCfg, logger)
+	accountService := service.NewAccountService(accountRepo, transactionRepo, kycPolicy)
+	overdraftService := service.NewOverdraftService(accountRepo, overdraftRepo, transactionRepo, accountService, overdraftCfg, logger)
+	p2pService := service.NewP2PService(userRepo, accountRepo, accountService)
+	qrPaymentService := service.NewQRPaymentService(qrPaymentRepo, userRepo, accountService, cryptoCfg.HMACKey, qrCfg)
+	cardService := service.NewCardService(cardRepo, userRepo, accountService, kycPolicy, pool, cryptoCfg.HMACKey, cardCfg)
+	statementService := service.NewStatementService(accountRepo, transactionRepo, userRepo, bankCfg)
+	batchService := service.NewBatchService(batchRepo, accountRepo, accountService, logger)
+	standingOrderService := service.NewStandingOrderService(standingOrderRepo, accountRepo, accountService, cal, schedCfg, logger)
+	paymentRequestService := service.NewPaymentRequestService(paymentRequestRepo, userRepo, accountService, logger)
+	savingsService := service.NewSavingsService(savingsRepo, accountRepo, transactionRepo, accountService, savingsCfg, logger)
+	creditService := service.NewCreditService(creditRepo, keyRateRepo, accountService, cbrClient, notifier, cal, creditCfg, logger)
+	creditApplicationService := service.NewCreditApplicationService(creditApplicationRepo, creditRepo, transactionRepo,
+		accountService, creditService, kycPolicy, scoringCfg, logger)
+	collectionService := service.NewCollectionService(collectionRepo, creditRepo, accountRepo, creditService, notifier,
+		collectionsCfg, logger)
+	creditHistoryService := service.NewCreditHistoryService(creditRepo, collectionRepo, userRepo, bankCfg)
+	depositService := service.NewDepositService(depositRepo, accountService, cbrClient, depositCfg, logger)
+
+	// Сохранение настроенной ставки по накопительным счетам
+	if err := savingsService.SyncRate(ctx); err != nil {
+		logger.Fatalf("Ошибка установки ставки по накопительным счетам: %v", err)
+	}
+
+	// Загрузка ключей подписи JWT; при первом запуске создается ключ
+	if err := jwtKeyService.Rotate(ctx); err != nil {
+		logger.Fatalf("Ошибка загрузки ключей подписи JWT: %v", err)
+	}
+
+	// Регистрация периодических задач планировщика
+	jobs := scheduler.New(logger)
+	jobs.Add(scheduler.Job{Name: "standing_orders", Interval: schedCfg.StandingOrdersInterval, Run: standingOrderService.RunDue})
+	jobs.Add(scheduler.Job{Name: "payment_requests_expiry", Interval: schedCfg.PaymentRequestsInterval, Run: paymentRequestService.ExpireOverdue})
+	jobs.Add(scheduler.Job{Name: "savings_interest", Interval: schedCfg.SavingsAccrualInterval, Run: savingsService.Accrue})
+	jobs.Add(scheduler.Job{Name: "deposit_maturity", Interval: schedCfg.DepositMaturityInterval, Run: depositService.MatureDue})
+	jobs.Add(scheduler.Job{Name: "overdraft_interest", Interval: schedCfg.OverdraftInterestInterval, Run: overdraftService.ChargeInterest})
+	jobs.Add(scheduler.Job{Name: "credit_auto_debit", Interval: schedCfg.CreditAutoDebitInterval, Run: creditService.CollectDue})
+	jobs.Add(scheduler.Job{Name: "credit_floating_rate", Interval: schedCfg.CreditRateResetInterval, Run: creditService.ResetFloatingRates})
+	jobs.Add(scheduler.Job{Name: "credit_collections", Interval: schedCfg.CollectionsInterval, Run: collectionService.Run})
+	jobs.Add(scheduler.Job{Name: "jwt_keys", Interval: schedCfg.JWTKeysInterval, Run: jwtKeyService.Rotate})
+	jobs.Add(scheduler.Job{Name: "revoked_tokens_cleanup", Interval: schedCfg.RevokedTokensInterval, Run: authService.PurgeRevoked})
+	jobs.Add(scheduler.Job{Name: "mfa_challenges_cleanup", Interval: schedCfg.MFAChallengesInterval, Run: mfaService.PurgeChallenges})
+	jobs.Add(scheduler.Job{Name: "login_throttles_cleanup", Interval: schedCfg.LoginThrottlesInterval, Run: loginGuardService.Purge})
+	jobs.Add(scheduler.Job{Name: "user_tokens_cleanup", Interval: schedCfg.UserTokensInterval, Run: credentialService.Purge})
+
+	// Запуск фоновых обработчиков; они останавливаются при завершении работы сервера
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	batchService.Start(workersCtx)
+	jobs.Start(workersCtx)
 
 	// Создание обработчиков HTTP-запросов
-	authHandler := handler.NewAuthHandler(authService, logger)
-	accountHandler := handler.NewAccountHandler(accountService, logger)
-	cardHandler := handler.NewCardHandler(cardService, logger)
+	authHandler := handler.NewAuthHandler(authService, proxyCfg, logger)
+	credentialHandler := handler.NewCredentialHandler(credentialService, logger)
+	jwksHandler := handler.NewJWKSHandler(jwtKeyService, logger)
+	mfaHandler := handler.NewMFAHandler(mfaService, logger)
+	profileHandler := handler.NewProfileHandler(profileService, logger)
+	accountHandler := handler.NewAccountHandler(accountService, overdraftService, mfaService, logger)
+	p2pHandler := handler.NewP2PHandler(p2pService, mfaService, logger)
+	qrHandler := handler.NewQRHandler(qrPaymentService, mfaService, logger)
+	savingsHandler := handler.NewSavingsHandler(savingsService, logger)
+	depositHandler := handler.NewDepositHandler(depositService, logger)
+	creditHandler := handler.NewCreditHandler(creditService, collectionService, logger)
+	creditApplicationHandler := handler.NewCreditApplicationHandler(creditApplicationService, logger)
+	creditHistoryHandler := handler.NewCreditHistoryHandler(creditHistoryService, logger)
+	cardHandler := handler.NewCardHandler(cardService, mfaService, logger)
+	statementHandler := handler.NewStatementHandler(statementService, logger)
+	batchHandler := handler.NewBatchHandler(batchService, mfaService, logger)
+	standingOrderHandler := handler.NewStandingOrderHandler(standingOrderService, mfaService, logger)
+	paymentRequestHandler := handler.NewPaymentRequestHandler(paymentRequestService, mfaService, logger)
+	adminHandler := handler.NewAdminHandler(overdraftService, creditApplicationService, loginGuardService, profileService, logger)
 
 	// Middleware для проверки JWT токена
 	jwtMiddleware := middleware.NewJWTMiddleware(authService, logger)
+	// Middleware для проверки роли администратора
+	adminMiddleware := middleware.NewAdminMiddleware(userRepo, logger)
+	verifiedMiddleware := middleware.NewVerifiedMiddleware(userRepo, logger)
 
 	// Настройка маршрутизации API
 	r := mux.NewRouter().PathPrefix("/api").Subrouter()
@@ -92,23 +220,118 @@ func main() {
 	// Публичные маршруты
 	r.HandleFunc("/register", authHandler.Register).Methods(http.MethodPost)
 	r.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
+	r.HandleFunc("/login/mfa", authHandler.LoginMFA).Methods(http.MethodPost)
+	r.HandleFunc("/token/refresh", authHandler.Refresh).Methods(http.MethodPost)
+	r.HandleFunc("/email/verify", credentialHandler.VerifyEmail).Methods(http.MethodPost)
+	r.HandleFunc("/password/forgot", credentialHandler.ForgotPassword).Methods(http.MethodPost)
+	r.HandleFunc("/password/reset", credentialHandler.ResetPassword).Methods(http.MethodPost)
+	r.HandleFunc("/.well-known/jwks.json", jwksHandler.GetJWKS).Methods(http.MethodGet)
+	r.HandleFunc("/credits/calculate", creditHandler.CalculateCredit).Methods(http.MethodPost)
 
 	// Защищенные маршруты (JWT авторизация)
 	apiRouter := r.PathPrefix("").Subrouter()
 	apiRouter.Use(jwtMiddleware.Middleware)
 
+	// Маршруты для управления сессиями
+	apiRouter.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/sessions", authHandler.GetSessions).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/sessions/{id}", authHandler.RevokeSession).Methods(http.MethodDelete)
+
+	// Маршруты профиля пользователя
+	apiRouter.HandleFunc("/me", profileHandler.GetMe).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/me", profileHandler.UpdateMe).Methods(http.MethodPatch)
+
+	// Маршруты подтверждения email и смены пароля
+	apiRouter.HandleFunc("/email/verify/resend", credentialHandler.ResendVerification).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/password/change", credentialHandler.ChangePassword).Methods(http.MethodPost)
+
+	// Маршруты двухфакторной аутентификации
+	apiRouter.HandleFunc("/mfa", mfaHandler.GetStatus).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/mfa/totp/enroll", mfaHandler.EnrollTOTP).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/mfa/totp/confirm", mfaHandler.ConfirmTOTP).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/mfa/totp", mfaHandler.DisableTOTP).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/mfa/assert", mfaHandler.Assert).Methods(http.MethodPost)
+
+	// Маршруты администратора
+	adminRouter := apiRouter.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(adminMiddleware.Middleware)
+	adminRouter.HandleFunc("/accounts/{id}/overdraft", adminHandler.SetOverdraftLimit).Methods(http.MethodPut)
+	adminRouter.HandleFunc("/credit-applications", adminHandler.GetCreditApplications).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/credit-applications/{id}/decision", adminHandler.DecideCreditApplication).Methods(http.MethodPost)
+	adminRouter.HandleFunc("/login-lockouts", adminHandler.GetLoginLockouts).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/login-lockouts/unlock", adminHandler.UnlockLogin).Methods(http.MethodPost)
+	adminRouter.HandleFunc("/kyc", adminHandler.GetPendingKYC).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/users/{id}/kyc", adminHandler.ReviewKYC).Methods(http.MethodPost)
+
+	// Операции со счетами доступны только после подтверждения email
+	verifiedRouter := apiRouter.PathPrefix("").Subrouter()
+	verifiedRouter.Use(verifiedMiddleware.Middleware)
+
+	// Отправка профиля на проверку личности
+	verifiedRouter.HandleFunc("/me/kyc", profileHandler.SubmitKYC).Methods(http.MethodPost)
+
 	// Маршруты для управления счетами
-	apiRouter.HandleFunc("/accounts", accountHandler.CreateAccount).Methods(http.MethodPost)
-	apiRouter.HandleFunc("/accounts", accountHandler.GetAccounts).Methods(http.MethodGet)
-	apiRouter.HandleFunc("/accounts/{id}/balance", accountHandler.UpdateBalance).Methods(http.MethodPatch)
-	apiRouter.HandleFunc("/accounts/{id}/transactions", accountHandler.GetTransactions).Methods(http.MethodGet)
-	apiRouter.HandleFunc("/transfer", accountHandler.Transfer).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/accounts", accountHandler.CreateAccount).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/accounts", accountHandler.GetAccounts).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/accounts/{id}/balance", accountHandler.UpdateBalance).Methods(http.MethodPatch)
+	verifiedRouter.HandleFunc("/accounts/{id}/transactions", accountHandler.GetTransactions).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/accounts/{id}/statement", statementHandler.GetStatement).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/accounts/{id}/interest", savingsHandler.GetInterest).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/accounts/{id}/overdraft", accountHandler.GetOverdraft).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/accounts/{id}/default", p2pHandler.SetDefaultAccount).Methods(http.MethodPut)
+	verifiedRouter.HandleFunc("/transfer", accountHandler.Transfer).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/transfer/recipient", p2pHandler.PreviewRecipient).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/transfer/email", p2pHandler.TransferByEmail).Methods(http.MethodPost)
+
+	// Маршруты для оплаты по QR-коду
+	verifiedRouter.HandleFunc("/accounts/{id}/qr", qrHandler.GetQR).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/qr/pay", qrHandler.PayQR).Methods(http.MethodPost)
+
+	// Маршруты для срочных вкладов
+	verifiedRouter.HandleFunc("/deposits", depositHandler.OpenDeposit).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/deposits", depositHandler.GetDeposits).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/deposits/rate", depositHandler.GetDepositRate).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/deposits/{id}", depositHandler.GetDeposit).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/deposits/{id}/close", depositHandler.CloseDeposit).Methods(http.MethodPost)
+
+	// Маршруты для кредитов
+	verifiedRouter.HandleFunc("/credit-applications", creditApplicationHandler.CreateCreditApplication).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/credit-applications", creditApplicationHandler.GetCreditApplications).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credit-applications/{id}", creditApplicationHandler.GetCreditApplication).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credit-applications/{id}/accept", creditApplicationHandler.AcceptCreditApplication).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/credits", creditHandler.GetCredits).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credits/history", creditHistoryHandler.GetCreditHistory).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credits/{id}/schedule", creditHandler.GetCreditSchedule).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credits/{id}/rates", creditHandler.GetCreditRates).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credits/{id}/collections", creditHandler.GetCreditCollections).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/credits/{id}/prepay", creditHandler.PrepayCredit).Methods(http.MethodPost)
+
+	// Маршруты для регулярных и отложенных переводов
+	verifiedRouter.HandleFunc("/standing-orders", standingOrderHandler.CreateStandingOrder).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/standing-orders", standingOrderHandler.GetStandingOrders).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/standing-orders/{id}", standingOrderHandler.GetStandingOrder).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/standing-orders/{id}", standingOrderHandler.CancelStandingOrder).Methods(http.MethodDelete)
+	verifiedRouter.HandleFunc("/standing-orders/{id}/resume", standingOrderHandler.ResumeStandingOrder).Methods(http.MethodPost)
+
+	// Маршруты для запросов на оплату между пользователями
+	verifiedRouter.HandleFunc("/payment-requests", paymentRequestHandler.CreatePaymentRequest).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/payment-requests/incoming", paymentRequestHandler.GetIncoming).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/payment-requests/outgoing", paymentRequestHandler.GetOutgoing).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/payment-requests/{id}", paymentRequestHandler.GetPaymentRequest).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/payment-requests/{id}/approve", paymentRequestHandler.ApprovePaymentRequest).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/payment-requests/{id}/decline", paymentRequestHandler.DeclinePaymentRequest).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/payment-requests/{id}/cancel", paymentRequestHandler.CancelPaymentRequest).Methods(http.MethodPost)
 
 	// Маршруты для управления картами
-	apiRouter.HandleFunc("/cards", cardHandler.CreateCard).Methods(http.MethodPost)
-	apiRouter.HandleFunc("/cards", cardHandler.GetCards).Methods(http.MethodGet)
-	apiRouter.HandleFunc("/cards/{id}", cardHandler.GetCardDetails).Methods(http.MethodGet)
-	apiRouter.HandleFunc("/payments", cardHandler.ProcessPayment).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/cards", cardHandler.CreateCard).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/cards", cardHandler.GetCards).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/cards/{id}", cardHandler.GetCardDetails).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/payments", cardHandler.ProcessPayment).Methods(http.MethodPost)
+
+	// Маршруты для пакетных платежей
+	verifiedRouter.HandleFunc("/payments/batch", batchHandler.SubmitBatch).Methods(http.MethodPost)
+	verifiedRouter.HandleFunc("/payments/batch/{id}", batchHandler.GetBatch).Methods(http.MethodGet)
+	verifiedRouter.HandleFunc("/payments/batch/{id}/report", batchHandler.GetBatchReport).Methods(http.MethodGet)
 
 	// Настройка параметров HTTP-сервера
 	srv := &http.Server{
@@ -132,6 +355,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Завершение работы сервера...")
+	stopWorkers()
 
 	// Ожидание завершения текущих обработок и корректное завершение сервера
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,100 @@
+// Package apperr описывает доменные ошибки сервиса: каждая ошибка имеет вид, определяющий HTTP-статус
+// ответа, и стабильный машиночитаемый код, по которому клиент различает ошибки и по которому выбирается
+// сообщение на языке клиента.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind — вид ошибки; определяет HTTP-статус ответа
+type Kind int
+
+const (
+	Internal             Kind = iota // Внутренняя ошибка сервера
+	Invalid                          // Неверные данные запроса
+	Unauthenticated                  // Требуется вход или неверные учетные данные
+	Forbidden                        // Операция запрещена
+	NotFound                         // Объект не найден
+	Conflict                         // Операция недоступна в текущем состоянии объекта
+	Gone                             // Срок действия объекта истек
+	TooLarge                         // Тело запроса слишком большое
+	UnsupportedMediaType             // Неподдерживаемый тип содержимого
+	Unprocessable                    // Данные разобраны, но не прошли проверку бизнес-правил
+	TooManyRequests                  // Превышен лимит попыток
+	Unavailable                      // Внешний источник данных недоступен
+)
+
+// Status возвращает HTTP-статус ответа для ошибки вида k
+func (k Kind) Status() int {
+	switch k {
+	case Invalid:
+		return http.StatusBadRequest
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+	case NotFound:
+		return http.StatusNotFound
+	case Conflict:
+		return http.StatusConflict
+	case Gone:
+		return http.StatusGone
+	case TooLarge:
+		return http.StatusRequestEntityTooLarge
+	case UnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case Unprocessable:
+		return http.StatusUnprocessableEntity
+	case TooManyRequests:
+		return http.StatusTooManyRequests
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error — доменная ошибка. Сравнивается через errors.Is по указателю, как обычная ошибка errors.New;
+// уточнения добавляются оборачиванием: fmt.Errorf("%w: срок больше 60 месяцев", ErrInvalidDeposit)
+type Error struct {
+	Kind    Kind   // Вид ошибки
+	Code    Code   // Стабильный код ошибки
+	Message string // Текст ошибки для логов и уточнений
+}
+
+// New создает доменную ошибку
+func New(kind Kind, code Code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Error реализует интерфейс error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// From возвращает доменную ошибку из цепочки err или nil, если ее там нет
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+// Общие ошибки, не относящиеся к конкретной области
+var (
+	ErrInternal           = New(Internal, CodeInternal, "внутренняя ошибка сервера")                                // Непредвиденная ошибка
+	ErrMalformedRequest   = New(Invalid, CodeMalformedRequest, "неверный формат запроса")                           // Тело запроса не разбирается
+	ErrValidation         = New(Invalid, CodeValidationFailed, "ошибка валидации данных")                           // Значения полей не прошли проверку
+	ErrInvalidParameter   = New(Invalid, CodeInvalidParameter, "неверный параметр запроса")                         // Неверный параметр пути или строки запроса
+	ErrUnauthenticated    = New(Unauthenticated, CodeUnauthenticated, "требуется авторизация")                      // Нет токена или пользователя в контексте
+	ErrInvalidAccessToken = New(Unauthenticated, CodeInvalidAccessToken, "неверный или просроченный токен")         // Токен не прошел проверку
+	ErrAccessTokenRevoked = New(Unauthenticated, CodeAccessTokenRevoked, "токен отозван")                           // Токен в списке отозванных
+	ErrForbidden          = New(Forbidden, CodeForbidden, "недостаточно прав")                                      // Маршрут недоступен пользователю
+	ErrEmailNotVerified   = New(Forbidden, CodeEmailNotVerified, "email не подтвержден")                            // Операция до подтверждения email
+	ErrNotFound           = New(NotFound, CodeNotFound, "объект не найден")                                         // Объект не найден
+	ErrTooLarge           = New(TooLarge, CodeTooLarge, "тело запроса слишком большое")                             // Превышен размер тела запроса
+	ErrUnsupportedMedia   = New(UnsupportedMediaType, CodeUnsupportedMediaType, "неподдерживаемый тип содержимого") // Неподдерживаемый Content-Type
+)
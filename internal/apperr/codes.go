@@ -0,0 +1,250 @@
+package apperr
+
+// Code — стабильный машиночитаемый код ошибки. Коды не меняются между версиями API: клиенты
+// обрабатывают ошибки по коду, а не по тексту сообщения
+type Code string
+
+// Lang — язык сообщений об ошибках
+type Lang string
+
+const (
+	RU Lang = "ru" // Русский (по умолчанию)
+	EN Lang = "en" // Английский
+)
+
+// Общие коды
+const (
+	CodeInternal             Code = "INTERNAL"
+	CodeMalformedRequest     Code = "MALFORMED_REQUEST"
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeInvalidParameter     Code = "INVALID_PARAMETER"
+	CodeUnsupportedFormat    Code = "UNSUPPORTED_FORMAT"
+	CodeUnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodeTooLarge             Code = "PAYLOAD_TOO_LARGE"
+	CodeUnauthenticated      Code = "UNAUTHENTICATED"
+	CodeInvalidAccessToken   Code = "INVALID_ACCESS_TOKEN"
+	CodeAccessTokenRevoked   Code = "ACCESS_TOKEN_REVOKED"
+	CodeForbidden            Code = "FORBIDDEN"
+	CodeEmailNotVerified     Code = "EMAIL_NOT_VERIFIED"
+	CodeNotFound             Code = "NOT_FOUND"
+)
+
+// Коды пользователей, входа и сессий
+const (
+	CodeInvalidCredentials   Code = "INVALID_CREDENTIALS"
+	CodeUserExists           Code = "USER_EXISTS"
+	CodeUserNotFound         Code = "USER_NOT_FOUND"
+	CodeInvalidRefreshToken  Code = "INVALID_REFRESH_TOKEN"
+	CodeRefreshTokenReused   Code = "REFRESH_TOKEN_REUSED"
+	CodeSessionNotFound      Code = "SESSION_NOT_FOUND"
+	CodeLoginBlocked         Code = "LOGIN_BLOCKED"
+	CodeLoginLocked          Code = "LOGIN_LOCKED"
+	CodeUnlockTargetRequired Code = "UNLOCK_TARGET_REQUIRED"
+	CodeMFAAlreadyEnabled    Code = "MFA_ALREADY_ENABLED"
+	CodeMFANotEnrolled       Code = "MFA_NOT_ENROLLED"
+	CodeMFANotEnabled        Code = "MFA_NOT_ENABLED"
+	CodeInvalidMFACode       Code = "INVALID_MFA_CODE"
+	CodeInvalidMFAToken      Code = "INVALID_MFA_TOKEN"
+	CodeMFARequired          Code = "MFA_REQUIRED"
+	CodeMFALocked            Code = "MFA_LOCKED"
+	CodeInvalidUserToken     Code = "INVALID_USER_TOKEN"
+	CodeEmailAlreadyVerified Code = "EMAIL_ALREADY_VERIFIED"
+	CodeWrongPassword        Code = "WRONG_PASSWORD"
+)
+
+// Коды профиля и проверки личности (KYC)
+const (
+	CodeUsernameTaken     Code = "USERNAME_TAKEN"
+	CodeProfileLocked     Code = "PROFILE_LOCKED"
+	CodeProfileIncomplete Code = "PROFILE_INCOMPLETE"
+	CodeKYCState          Code = "KYC_STATE"
+	CodeKYCRequired       Code = "KYC_REQUIRED"
+	CodeKYCLimitExceeded  Code = "KYC_LIMIT_EXCEEDED"
+)
+
+// Коды счетов и переводов
+const (
+	CodeAccountNotFound       Code = "ACCOUNT_NOT_FOUND"
+	CodeAccountRestricted     Code = "ACCOUNT_RESTRICTED"
+	CodeInsufficientFunds     Code = "INSUFFICIENT_FUNDS"
+	CodeSameAccount           Code = "SAME_ACCOUNT"
+	CodeNonPositiveAmount     Code = "NON_POSITIVE_AMOUNT"
+	CodeUnsupportedCurrency   Code = "UNSUPPORTED_CURRENCY"
+	CodeInvalidAccountType    Code = "INVALID_ACCOUNT_TYPE"
+	CodeNotSavingsAccount     Code = "NOT_SAVINGS_ACCOUNT"
+	CodeInvalidOverdraftLimit Code = "INVALID_OVERDRAFT_LIMIT"
+	CodeOverdraftNotAllowed   Code = "OVERDRAFT_NOT_ALLOWED"
+	CodeOverdraftInUse        Code = "OVERDRAFT_IN_USE"
+	CodeInvalidPeriod         Code = "INVALID_PERIOD"
+	CodeDayNotClosed          Code = "DAY_NOT_CLOSED"
+	CodeStatementUnavailable  Code = "STATEMENT_UNAVAILABLE"
+	CodeRecipientNotFound     Code = "RECIPIENT_NOT_FOUND"
+	CodeNoDefaultAccount      Code = "NO_DEFAULT_ACCOUNT"
+	CodeCurrencyMismatch      Code = "CURRENCY_MISMATCH"
+	CodeInvalidQR             Code = "INVALID_QR"
+	CodeQRExpired             Code = "QR_EXPIRED"
+	CodeQRAlreadyPaid         Code = "QR_ALREADY_PAID"
+	CodeQRAmountMismatch      Code = "QR_AMOUNT_MISMATCH"
+	CodeQRAmountRequired      Code = "QR_AMOUNT_REQUIRED"
+	CodeCardNotFound          Code = "CARD_NOT_FOUND"
+	CodeInvalidCard           Code = "INVALID_CARD"
+	CodeCardBlocked           Code = "CARD_BLOCKED"
+)
+
+// Коды вкладов, кредитов и платежей
+const (
+	CodeDepositNotFound        Code = "DEPOSIT_NOT_FOUND"
+	CodeInvalidDeposit         Code = "INVALID_DEPOSIT"
+	CodeDepositClosed          Code = "DEPOSIT_CLOSED"
+	CodeDepositRateUnavailable Code = "DEPOSIT_RATE_UNAVAILABLE"
+	CodeCreditNotFound         Code = "CREDIT_NOT_FOUND"
+	CodeInvalidCredit          Code = "INVALID_CREDIT"
+	CodeCreditState            Code = "CREDIT_STATE"
+	CodeCreditArrears          Code = "CREDIT_ARREARS"
+	CodeInvalidPrepayment      Code = "INVALID_PREPAYMENT"
+	CodeCreditScheduleStale    Code = "CREDIT_SCHEDULE_STALE"
+	CodeKeyRateUnavailable     Code = "KEY_RATE_UNAVAILABLE"
+	CodePaymentTooSmall        Code = "PAYMENT_TOO_SMALL"
+	CodeApplicationNotFound    Code = "APPLICATION_NOT_FOUND"
+	CodeInvalidApplication     Code = "INVALID_APPLICATION"
+	CodeApplicationState       Code = "APPLICATION_STATE"
+	CodeApplicationExpired     Code = "APPLICATION_EXPIRED"
+	CodeInvalidDecision        Code = "INVALID_DECISION"
+	CodeApplicationReviewed    Code = "APPLICATION_NOT_IN_REVIEW"
+	CodePaymentRequestNotFound Code = "PAYMENT_REQUEST_NOT_FOUND"
+	CodeInvalidPaymentRequest  Code = "INVALID_PAYMENT_REQUEST"
+	CodePaymentRequestState    Code = "PAYMENT_REQUEST_STATE"
+	CodePaymentRequestExpired  Code = "PAYMENT_REQUEST_EXPIRED"
+	CodePaymentExceedsBalance  Code = "PAYMENT_EXCEEDS_BALANCE"
+	CodePayerNotFound          Code = "PAYER_NOT_FOUND"
+	CodeStandingOrderNotFound  Code = "STANDING_ORDER_NOT_FOUND"
+	CodeInvalidSchedule        Code = "INVALID_SCHEDULE"
+	CodeStandingOrderState     Code = "STANDING_ORDER_STATE"
+	CodeBatchNotFound          Code = "BATCH_NOT_FOUND"
+	CodeInvalidBatch           Code = "INVALID_BATCH"
+	CodeEmptyBatch             Code = "EMPTY_BATCH"
+	CodeBatchRejected          Code = "BATCH_REJECTED"
+	CodeDuplicateBatch         Code = "DUPLICATE_BATCH"
+)
+
+// text содержит сообщение об ошибке на поддерживаемых языках
+type text struct {
+	ru string
+	en string
+}
+
+// messages — сообщения для клиентов по кодам ошибок
+var messages = map[Code]text{
+	CodeInternal:             {"Внутренняя ошибка сервера", "Internal server error"},
+	CodeMalformedRequest:     {"Неверный формат запроса", "Malformed request body"},
+	CodeValidationFailed:     {"Ошибка валидации данных", "Request validation failed"},
+	CodeInvalidParameter:     {"Неверный параметр запроса", "Invalid request parameter"},
+	CodeUnsupportedFormat:    {"Неподдерживаемый формат", "Unsupported format"},
+	CodeUnsupportedMediaType: {"Неподдерживаемый тип содержимого", "Unsupported media type"},
+	CodeTooLarge:             {"Тело запроса слишком большое", "Request body is too large"},
+	CodeUnauthenticated:      {"Требуется авторизация", "Authentication required"},
+	CodeInvalidAccessToken:   {"Неверный или просроченный токен", "Invalid or expired access token"},
+	CodeAccessTokenRevoked:   {"Токен отозван", "Access token has been revoked"},
+	CodeForbidden:            {"Недостаточно прав", "Insufficient permissions"},
+	CodeEmailNotVerified:     {"Подтвердите email, чтобы выполнять операции", "Verify your email address to perform operations"},
+	CodeNotFound:             {"Объект не найден", "Resource not found"},
+
+	CodeInvalidCredentials:   {"Неверный email или пароль", "Invalid email or password"},
+	CodeUserExists:           {"Пользователь с таким email уже существует", "A user with this email already exists"},
+	CodeUserNotFound:         {"Пользователь не найден", "User not found"},
+	CodeInvalidRefreshToken:  {"Неверный или просроченный refresh-токен", "Invalid or expired refresh token"},
+	CodeRefreshTokenReused:   {"Refresh-токен уже был использован, сессия завершена. Войдите заново", "Refresh token has already been used; the session was terminated. Please sign in again"},
+	CodeSessionNotFound:      {"Сессия не найдена или уже завершена", "Session not found or already terminated"},
+	CodeLoginBlocked:         {"Слишком много неудачных попыток входа, повторите позже", "Too many failed sign-in attempts, try again later"},
+	CodeLoginLocked:          {"Вход временно заблокирован из-за большого количества неудачных попыток", "Sign-in is temporarily locked after too many failed attempts"},
+	CodeUnlockTargetRequired: {"Укажите email или IP-адрес", "Specify an email or an IP address"},
+	CodeMFAAlreadyEnabled:    {"Двухфакторная аутентификация уже включена", "Two-factor authentication is already enabled"},
+	CodeMFANotEnrolled:       {"Приложение-аутентификатор не подключено: вызовите POST /mfa/totp/enroll", "Authenticator app is not enrolled: call POST /mfa/totp/enroll"},
+	CodeMFANotEnabled:        {"Двухфакторная аутентификация не включена", "Two-factor authentication is not enabled"},
+	CodeInvalidMFACode:       {"Неверный или уже использованный код", "Invalid or already used code"},
+	CodeInvalidMFAToken:      {"Неверный или просроченный MFA-токен. Войдите заново", "Invalid or expired MFA token. Please sign in again"},
+	CodeMFARequired:          {"Операция требует подтверждения кодом: POST /mfa/assert", "The operation requires confirmation with a code: POST /mfa/assert"},
+	CodeMFALocked:            {"Ввод кодов временно заблокирован из-за большого количества неверных кодов", "Code entry is temporarily locked after too many invalid codes"},
+	CodeInvalidUserToken:     {"Неверная, просроченная или уже использованная ссылка", "Invalid, expired or already used link"},
+	CodeEmailAlreadyVerified: {"Email уже подтвержден", "Email address is already verified"},
+	CodeWrongPassword:        {"Неверный текущий пароль", "Current password is incorrect"},
+
+	CodeUsernameTaken:     {"Имя пользователя уже занято", "This username is already taken"},
+	CodeProfileLocked:     {"Имя и дату рождения нельзя изменить во время проверки и после подтверждения личности", "Full name and date of birth cannot be changed while identity verification is pending or after it is approved"},
+	CodeProfileIncomplete: {"Заполните профиль: имя, дату рождения, телефон и адрес", "Complete your profile: full name, date of birth, phone and address"},
+	CodeKYCState:          {"Операция недоступна в текущем статусе проверки личности", "Operation is not allowed in the current identity verification status"},
+	CodeKYCRequired:       {"Операция доступна после подтверждения личности: POST /me/kyc", "The operation requires identity verification: POST /me/kyc"},
+	CodeKYCLimitExceeded:  {"Превышен лимит операций без подтверждения личности", "The limit for operations without identity verification is exceeded"},
+
+	CodeAccountNotFound:       {"Счет не найден", "Account not found"},
+	CodeAccountRestricted:     {"Расходные операции по счету ограничены из-за просрочки по кредиту", "Debits from the account are restricted due to overdue credit payments"},
+	CodeInsufficientFunds:     {"Недостаточно средств", "Insufficient funds"},
+	CodeSameAccount:           {"Нельзя переводить на тот же счет", "Cannot transfer to the same account"},
+	CodeNonPositiveAmount:     {"Сумма должна быть положительной", "Amount must be positive"},
+	CodeUnsupportedCurrency:   {"Поддерживается только валюта RUB", "Only RUB is supported"},
+	CodeInvalidAccountType:    {"Неизвестный тип счета", "Unknown account type"},
+	CodeNotSavingsAccount:     {"Счет не является накопительным", "Account is not a savings account"},
+	CodeInvalidOverdraftLimit: {"Некорректный лимит овердрафта", "Invalid overdraft limit"},
+	CodeOverdraftNotAllowed:   {"Овердрафт доступен только для текущих счетов", "Overdraft is available only for current accounts"},
+	CodeOverdraftInUse:        {"Лимит меньше использованной суммы овердрафта", "The limit is lower than the overdraft in use"},
+	CodeInvalidPeriod:         {"Дата начала периода позже даты окончания", "Period start is after period end"},
+	CodeDayNotClosed:          {"Выписка camt.053 формируется только за завершенные дни, для текущего дня используйте camt.052", "A camt.053 statement covers closed days only, use camt.052 for the current day"},
+	CodeStatementUnavailable:  {"Выписка за период до исправления типов переводов недоступна", "The statement is unavailable for periods before the transfer type fix"},
+	CodeRecipientNotFound:     {"Получатель не найден", "Recipient not found"},
+	CodeNoDefaultAccount:      {"Не выбран счет по умолчанию", "No default account is selected"},
+	CodeCurrencyMismatch:      {"Валюта счета получателя не совпадает с валютой счета отправителя", "Recipient account currency differs from the sender account currency"},
+	CodeInvalidQR:             {"Недействительный QR-код", "Invalid QR code"},
+	CodeQRExpired:             {"Срок действия QR-кода истек", "QR code has expired"},
+	CodeQRAlreadyPaid:         {"QR-код уже оплачен", "QR code has already been paid"},
+	CodeQRAmountMismatch:      {"Сумма не совпадает с суммой в QR-коде", "Amount differs from the amount in the QR code"},
+	CodeQRAmountRequired:      {"В QR-коде нет суммы: укажите сумму оплаты", "The QR code has no amount: specify the payment amount"},
+	CodeCardNotFound:          {"Карта не найдена", "Card not found"},
+	CodeInvalidCard:           {"Неверные данные карты", "Invalid card details"},
+	CodeCardBlocked:           {"Карта заблокирована после неверных вводов CVV", "Card is blocked after wrong CVV attempts"},
+
+	CodeDepositNotFound:        {"Вклад не найден", "Deposit not found"},
+	CodeInvalidDeposit:         {"Некорректные параметры вклада", "Invalid deposit parameters"},
+	CodeDepositClosed:          {"Вклад уже закрыт", "Deposit is already closed"},
+	CodeDepositRateUnavailable: {"Не удалось определить ставку по вкладу", "Deposit rate is unavailable"},
+	CodeCreditNotFound:         {"Кредит не найден", "Credit not found"},
+	CodeInvalidCredit:          {"Некорректные параметры кредита", "Invalid credit parameters"},
+	CodeCreditState:            {"Операция недоступна в текущем статусе кредита", "Operation is not allowed in the current credit status"},
+	CodeCreditArrears:          {"Сначала погасите просроченные платежи", "Pay off overdue payments first"},
+	CodeInvalidPrepayment:      {"Некорректные параметры досрочного погашения", "Invalid prepayment parameters"},
+	CodeCreditScheduleStale:    {"График платежей изменился, повторите операцию", "The payment schedule has changed, retry the operation"},
+	CodeKeyRateUnavailable:     {"Ключевая ставка ЦБ РФ недоступна", "The Bank of Russia key rate is unavailable"},
+	CodePaymentTooSmall:        {"Платеж по графику не покрывает проценты", "The scheduled payment does not cover interest"},
+	CodeApplicationNotFound:    {"Кредитная заявка не найдена", "Credit application not found"},
+	CodeInvalidApplication:     {"Некорректные параметры кредитной заявки", "Invalid credit application parameters"},
+	CodeApplicationState:       {"Заявка не одобрена или кредит по ней уже оформлен", "The application is not approved or the credit has already been issued"},
+	CodeApplicationExpired:     {"Срок действия одобрения истек, подайте новую заявку", "The approval has expired, submit a new application"},
+	CodeInvalidDecision:        {"Решение должно быть APPROVED или REJECTED с обоснованием", "The decision must be APPROVED or REJECTED with a reason"},
+	CodeApplicationReviewed:    {"Заявка не ожидает ручной проверки", "The application is not awaiting manual review"},
+	CodePaymentRequestNotFound: {"Запрос на оплату не найден", "Payment request not found"},
+	CodeInvalidPaymentRequest:  {"Некорректные параметры запроса на оплату", "Invalid payment request parameters"},
+	CodePaymentRequestState:    {"Операция недоступна в текущем статусе запроса", "Operation is not allowed in the current payment request status"},
+	CodePaymentRequestExpired:  {"Срок оплаты запроса истек", "The payment request has expired"},
+	CodePaymentExceedsBalance:  {"Сумма оплаты превышает неоплаченный остаток", "The payment exceeds the outstanding balance"},
+	CodePayerNotFound:          {"Плательщик не найден", "Payer not found"},
+	CodeStandingOrderNotFound:  {"Платежное поручение не найдено", "Standing order not found"},
+	CodeInvalidSchedule:        {"Некорректное расписание поручения", "Invalid standing order schedule"},
+	CodeStandingOrderState:     {"Операция недоступна в текущем статусе поручения", "Operation is not allowed in the current standing order status"},
+	CodeBatchNotFound:          {"Пакет платежей не найден", "Payment batch not found"},
+	CodeInvalidBatch:           {"Некорректный файл пакета платежей", "Invalid payment batch file"},
+	CodeEmptyBatch:             {"Файл не содержит платежей", "The file contains no payments"},
+	CodeBatchRejected:          {"Пакет отклонен: найдены ошибки в платежах", "The batch was rejected: some payments are invalid"},
+	CodeDuplicateBatch:         {"Пакет с таким идентификатором сообщения уже принят", "A batch with this message identifier has already been accepted"},
+}
+
+// Message возвращает сообщение для кода code на языке lang. Для неизвестного кода возвращается
+// сообщение о внутренней ошибке
+func Message(code Code, lang Lang) string {
+	t, ok := messages[code]
+	if !ok {
+		t = messages[CodeInternal]
+	}
+	if lang == EN {
+		return t.en
+	}
+	return t.ru
+}
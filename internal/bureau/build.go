@@ -0,0 +1,128 @@
+package bureau
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/collection"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// NewCredit собирает кредит для кредитной истории на дату reportDate. Просрочка платежа считается в днях
+// от даты по графику до даты оплаты, а для неоплаченного платежа — до даты отчета; в корзинах учитываются
+// только платежи, дата которых наступила. Смены статуса восстанавливаются по дате выдачи, журналу работы
+// с просрочкой и дате закрытия
+func NewCredit(c *credit.Credit, schedule []*models.PaymentSchedule, payments []*credit.Payment,
+	actions []*collection.Log, reportDate time.Time) Credit {
+	report := truncateDay(reportDate)
+	out := Credit{
+		ID:            c.ID,
+		AccountID:     c.AccountID,
+		Principal:     c.Principal,
+		InterestRate:  decimal.NewFromFloat(c.InterestRate),
+		TermMonths:    c.TermMonths,
+		Scheme:        c.Scheme,
+		RateType:      c.RateType,
+		StartDate:     c.StartDate,
+		Status:        c.Status,
+		ClosedAt:      c.ClosedAt,
+		Outstanding:   decimal.Zero,
+		OverdueAmount: decimal.Zero,
+		Bucket:        CURRENT,
+		WorstBucket:   CURRENT,
+		Buckets:       make(map[Bucket]int, len(Buckets)),
+		Schedule:      make([]Installment, 0, len(schedule)),
+		Payments:      make([]Payment, 0, len(payments)),
+		TotalPaid:     decimal.Zero,
+	}
+	for _, b := range Buckets {
+		out.Buckets[b] = 0
+	}
+
+	for i, p := range schedule {
+		in := Installment{
+			Number:    i + 1,
+			DueDate:   p.DueDate,
+			Amount:    p.Amount,
+			Principal: p.Principal,
+			Interest:  p.Interest,
+			Penalty:   p.Penalty,
+			Paid:      p.Paid,
+			PaidAt:    p.PaidAt,
+		}
+		due := truncateDay(p.DueDate)
+		matured := p.Paid || due.Before(report)
+		switch {
+		case p.Paid && p.PaidAt != nil:
+			in.DaysLate = daysBetween(due, truncateDay(*p.PaidAt))
+		case !p.Paid && due.Before(report):
+			in.DaysLate = daysBetween(due, report)
+			out.OverdueAmount = out.OverdueAmount.Add(p.Amount)
+			if out.DaysPastDue == 0 {
+				out.DaysPastDue = in.DaysLate
+			}
+		}
+		in.Bucket = BucketOf(in.DaysLate)
+		if !p.Paid {
+			out.Outstanding = out.Outstanding.Add(p.Principal)
+		}
+		if matured {
+			out.Buckets[in.Bucket]++
+			if worse(in.Bucket, out.WorstBucket) {
+				out.WorstBucket = in.Bucket
+			}
+		}
+		out.Schedule = append(out.Schedule, in)
+	}
+	out.Bucket = BucketOf(out.DaysPastDue)
+
+	for _, p := range payments {
+		out.Payments = append(out.Payments, Payment{
+			Date:      p.CreatedAt,
+			Amount:    p.Amount,
+			Principal: p.Principal,
+			Interest:  p.Interest,
+			Penalty:   p.Penalty,
+			Kind:      p.Kind,
+		})
+		out.TotalPaid = out.TotalPaid.Add(p.Amount)
+	}
+
+	out.Statuses = append(out.Statuses, StatusChange{Date: c.StartDate, Status: credit.ACTIVE, Reason: "кредит выдан"})
+	for _, a := range actions {
+		switch a.Action {
+		case collection.OVERDUE:
+			out.Statuses = append(out.Statuses, StatusChange{Date: a.ActionDate, Status: credit.OVERDUE, Reason: a.Details})
+		case collection.CLEARED:
+			out.Statuses = append(out.Statuses, StatusChange{Date: a.ActionDate, Status: credit.ACTIVE, Reason: a.Details})
+		}
+	}
+	if c.ClosedAt != nil {
+		reason := "кредит погашен по графику"
+		if len(payments) > 0 && payments[len(payments)-1].Kind == credit.EARLY_CLOSURE {
+			reason = "кредит погашен досрочно"
+		}
+		out.Statuses = append(out.Statuses, StatusChange{Date: truncateDay(*c.ClosedAt), Status: credit.CLOSED, Reason: reason})
+	}
+	return out
+}
+
+// worse сообщает, хуже ли корзина a корзины b
+func worse(a, b Bucket) bool {
+	return a.Code() > b.Code()
+}
+
+// daysBetween возвращает число дней от from до to; отрицательный результат заменяется нулем
+func daysBetween(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// truncateDay отбрасывает время суток, сохраняя календарную дату
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,210 @@
+// Package bureau формирует кредитную историю заемщиков: кредиты, графики платежей, внесенные платежи,
+// корзины просрочки и смены статусов в форматах JSON, XML и с фиксированной шириной полей для передачи
+// в бюро кредитных историй.
+//
+// Отчет записывается потоково: сначала заголовок с реквизитами банка, затем субъекты (заемщики) по одному,
+// затем итоги. Поэтому выгрузка по всем заемщикам не ограничена памятью.
+package bureau
+
+import (
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// ErrUnsupportedFormat возвращается при запросе кредитной истории в неизвестном формате
+var ErrUnsupportedFormat = apperr.New(apperr.Invalid, apperr.CodeUnsupportedFormat, "неподдерживаемый формат кредитной истории")
+
+// Format представляет формат кредитной истории
+type Format string
+
+const (
+	JSON  Format = "json"  // Документ JSON
+	XML   Format = "xml"   // Документ XML
+	FIXED Format = "fixed" // Текстовый файл с записями фиксированной ширины
+)
+
+// ParseFormat разбирает формат кредитной истории из строки; пустая строка означает JSON
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", JSON:
+		return JSON, nil
+	case XML, FIXED:
+		return Format(s), nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ContentType возвращает MIME-тип для формата
+func (f Format) ContentType() string {
+	switch f {
+	case XML:
+		return "application/xml; charset=utf-8"
+	case FIXED:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// Extension возвращает расширение файла для формата
+func (f Format) Extension() string {
+	if f == FIXED {
+		return "txt"
+	}
+	return string(f)
+}
+
+// Bucket представляет корзину просрочки по числу дней
+type Bucket string
+
+const (
+	CURRENT  Bucket = "0"     // Без просрочки
+	DPD1_30  Bucket = "1-30"  // Просрочка от 1 до 30 дней
+	DPD31_60 Bucket = "31-60" // Просрочка от 31 до 60 дней
+	DPD61_90 Bucket = "61-90" // Просрочка от 61 до 90 дней
+	DPD90    Bucket = "90+"   // Просрочка более 90 дней
+)
+
+// Buckets перечисляет корзины просрочки в порядке возрастания
+var Buckets = []Bucket{CURRENT, DPD1_30, DPD31_60, DPD61_90, DPD90}
+
+// BucketOf возвращает корзину для числа дней просрочки
+func BucketOf(days int) Bucket {
+	switch {
+	case days <= 0:
+		return CURRENT
+	case days <= 30:
+		return DPD1_30
+	case days <= 60:
+		return DPD31_60
+	case days <= 90:
+		return DPD61_90
+	default:
+		return DPD90
+	}
+}
+
+// Code возвращает однозначный код корзины для записей фиксированной ширины: от 0 до 4
+func (b Bucket) Code() string {
+	for i, bucket := range Buckets {
+		if bucket == b {
+			return string(rune('0' + i))
+		}
+	}
+	return "0"
+}
+
+// Header содержит заголовочные данные отчета
+type Header struct {
+	BankName    string    // Наименование банка
+	BankBIC     string    // БИК банка
+	ReportDate  time.Time // Дата, на которую рассчитана просрочка
+	GeneratedAt time.Time // Дата и время формирования отчета
+}
+
+// Subject представляет заемщика и его кредиты
+type Subject struct {
+	UserID   int64    // ID пользователя
+	FullName string   // Имя и фамилия
+	Email    string   // Электронная почта
+	Credits  []Credit // Кредиты в порядке оформления
+}
+
+// Credit представляет кредит в кредитной истории
+type Credit struct {
+	ID            int64           // ID кредита
+	AccountID     int64           // Счет кредита
+	Principal     decimal.Decimal // Сумма кредита
+	InterestRate  decimal.Decimal // Текущая годовая ставка
+	TermMonths    int             // Срок по договору
+	Scheme        credit.Scheme   // Схема погашения
+	RateType      credit.RateType // Вид ставки
+	StartDate     time.Time       // Дата выдачи
+	Status        credit.Status   // Текущий статус
+	ClosedAt      *time.Time      // Дата и время закрытия
+	Outstanding   decimal.Decimal // Остаток основного долга
+	OverdueAmount decimal.Decimal // Просроченная задолженность с неустойкой
+	DaysPastDue   int             // Текущее число дней просрочки
+	Bucket        Bucket          // Текущая корзина просрочки
+	WorstBucket   Bucket          // Худшая корзина за всю историю
+	Buckets       map[Bucket]int  // Число платежей по корзинам просрочки
+	Schedule      []Installment   // График платежей
+	Payments      []Payment       // Внесенные платежи
+	Statuses      []StatusChange  // Смены статуса в хронологическом порядке
+	TotalPaid     decimal.Decimal // Сумма внесенных платежей
+}
+
+// Installment представляет строку графика с фактической датой оплаты и просрочкой
+type Installment struct {
+	Number    int             // Номер платежа
+	DueDate   time.Time       // Дата платежа по графику
+	Amount    decimal.Decimal // Сумма платежа с неустойкой
+	Principal decimal.Decimal // Основной долг
+	Interest  decimal.Decimal // Проценты
+	Penalty   decimal.Decimal // Неустойка
+	Paid      bool            // Платеж внесен
+	PaidAt    *time.Time      // Дата и время оплаты
+	DaysLate  int             // Дней просрочки: на дату оплаты или на дату отчета
+	Bucket    Bucket          // Корзина просрочки платежа
+}
+
+// Payment представляет внесенный платеж по кредиту
+type Payment struct {
+	Date      time.Time          // Дата и время платежа
+	Amount    decimal.Decimal    // Сумма
+	Principal decimal.Decimal    // Основной долг
+	Interest  decimal.Decimal    // Проценты
+	Penalty   decimal.Decimal    // Неустойка
+	Kind      credit.PaymentKind // Вид платежа
+}
+
+// StatusChange представляет смену статуса кредита
+type StatusChange struct {
+	Date   time.Time     // Дата смены статуса
+	Status credit.Status // Новый статус
+	Reason string        // Причина
+}
+
+// Totals содержит итоги отчета
+type Totals struct {
+	Subjects int // Количество заемщиков
+	Credits  int // Количество кредитов
+}
+
+// Add учитывает заемщика в итогах
+func (t *Totals) Add(s *Subject) {
+	t.Subjects++
+	t.Credits += len(s.Credits)
+}
+
+// Writer записывает кредитную историю в определенном формате
+type Writer interface {
+	Begin(h *Header) error    // Записывает заголовок отчета
+	Subject(s *Subject) error // Записывает заемщика с кредитами
+	End(totals Totals) error  // Записывает итоги и завершает документ
+}
+
+// NewWriter создает Writer для указанного формата
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case JSON:
+		return newJSONWriter(w), nil
+	case XML:
+		return newXMLWriter(w), nil
+	case FIXED:
+		return newFixedWriter(w), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Дата и время в отчетах
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04:05Z"
+)
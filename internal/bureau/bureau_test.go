@@ -0,0 +1,193 @@
+package bureau
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/collection"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// update перезаписывает эталонные файлы testdata/*.golden результатами текущей реализации
+var update = flag.Bool("update", false, "перезаписать эталонные файлы testdata/*.golden")
+
+// reportDate — дата, на которую рассчитывается просрочка в тестовом отчете
+var reportDate = time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+
+// TestWriteGolden формирует кредитную историю двух заемщиков во всех форматах и сравнивает
+// результат с эталонными файлами
+func TestWriteGolden(t *testing.T) {
+	tests := []struct {
+		format Format
+		golden string
+	}{
+		{JSON, "credit_history.golden.json"},
+		{XML, "credit_history.golden.xml"},
+		{FIXED, "credit_history.golden.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			compareGolden(t, tt.golden, writeReport(t, tt.format))
+		})
+	}
+}
+
+// TestFixedRecords проверяет длину записей фиксированной ширины, их типы и поле ставки
+func TestFixedRecords(t *testing.T) {
+	var types []string
+	var trRate string
+	scanner := bufio.NewScanner(bytes.NewReader(writeReport(t, FIXED)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if n := utf8.RuneCountInString(line); n != fixedRecordLength {
+			t.Errorf("длина записи %q: %d, ожидается %d", line[:2], n, fixedRecordLength)
+		}
+		types = append(types, line[:2])
+		if line[:2] == "TR" {
+			// Тип, ID заемщика, кредита и счета, дата выдачи, сумма кредита
+			trRate = line[2+12+12+12+8+15 : 2+12+12+12+8+15+7]
+		}
+	}
+	want := []string{"HD", "ID", "TR", "PS", "PS", "PS", "PM", "ST", "ST", "ID", "TL"}
+	if len(types) != len(want) {
+		t.Fatalf("записи %v, ожидается %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("запись %d: %s, ожидается %s", i, types[i], want[i])
+		}
+	}
+	if trRate != "0001500" {
+		t.Errorf("ставка %q, ожидается %q", trRate, "0001500")
+	}
+}
+
+// TestNewCredit проверяет расчет просрочки, корзин и остатка по графику
+func TestNewCredit(t *testing.T) {
+	c := testCredit()
+	if c.DaysPastDue != 5 || c.Bucket != DPD1_30 {
+		t.Errorf("просрочка %d дней (%s), ожидается 5 (%s)", c.DaysPastDue, c.Bucket, DPD1_30)
+	}
+	if c.WorstBucket != DPD1_30 {
+		t.Errorf("худшая корзина %s, ожидается %s", c.WorstBucket, DPD1_30)
+	}
+	if c.Buckets[CURRENT] != 0 || c.Buckets[DPD1_30] != 2 {
+		t.Errorf("корзины %v, ожидается два платежа в %s", c.Buckets, DPD1_30)
+	}
+	if want := decimal.RequireFromString("6666.67"); !c.Outstanding.Equal(want) {
+		t.Errorf("остаток %s, ожидается %s", c.Outstanding, want)
+	}
+	if want := decimal.RequireFromString("3483.33"); !c.OverdueAmount.Equal(want) {
+		t.Errorf("просрочено %s, ожидается %s", c.OverdueAmount, want)
+	}
+}
+
+// TestBucketOf проверяет границы корзин просрочки
+func TestBucketOf(t *testing.T) {
+	tests := map[int]Bucket{-1: CURRENT, 0: CURRENT, 1: DPD1_30, 30: DPD1_30, 31: DPD31_60, 60: DPD31_60,
+		61: DPD61_90, 90: DPD61_90, 91: DPD90}
+	for days, want := range tests {
+		if got := BucketOf(days); got != want {
+			t.Errorf("BucketOf(%d) = %s, ожидается %s", days, got, want)
+		}
+	}
+}
+
+// writeReport записывает тестовый отчет в формате
+func writeReport(t *testing.T, format Format) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &Header{
+		BankName:    "ООО «Тестовый банк»",
+		BankBIC:     "044525000",
+		ReportDate:  reportDate,
+		GeneratedAt: time.Date(2024, 5, 20, 9, 30, 0, 0, time.UTC),
+	}
+	subjects := []*Subject{
+		{UserID: 7, FullName: "Иван Петров", Email: "ivan@example.com", Credits: []Credit{testCredit()}},
+		{UserID: 8, FullName: "Анна Смирнова", Email: "anna@example.com", Credits: []Credit{}},
+	}
+
+	var totals Totals
+	if err := w.Begin(header); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range subjects {
+		if err := w.Subject(s); err != nil {
+			t.Fatal(err)
+		}
+		totals.Add(s)
+	}
+	if err := w.End(totals); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testCredit собирает кредит на 3 месяца: первый платеж внесен с опозданием на 3 дня, второй просрочен
+// на 5 дней, третий еще не наступил
+func testCredit() Credit {
+	start := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2024, 4, 18, 12, 0, 0, 0, time.UTC)
+	c := &credit.Credit{
+		ID:           101,
+		AccountID:    55,
+		Principal:    decimal.NewFromInt(10000),
+		InterestRate: 0.15,
+		TermMonths:   3,
+		Scheme:       credit.DIFFERENTIATED,
+		RateType:     credit.FIXED,
+		StartDate:    start,
+		Status:       credit.OVERDUE,
+	}
+	schedule := []*models.PaymentSchedule{
+		{DueDate: time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), Amount: decimal.RequireFromString("3458.33"),
+			Principal: decimal.RequireFromString("3333.33"), Interest: decimal.RequireFromString("125.00"),
+			Penalty: decimal.Zero, Paid: true, PaidAt: &paidAt},
+		{DueDate: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), Amount: decimal.RequireFromString("3483.33"),
+			Principal: decimal.RequireFromString("3333.33"), Interest: decimal.RequireFromString("83.33"),
+			Penalty: decimal.RequireFromString("66.67")},
+		{DueDate: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), Amount: decimal.RequireFromString("3375.01"),
+			Principal: decimal.RequireFromString("3333.34"), Interest: decimal.RequireFromString("41.67"),
+			Penalty: decimal.Zero},
+	}
+	payments := []*credit.Payment{
+		{Amount: decimal.RequireFromString("3458.33"), Principal: decimal.RequireFromString("3333.33"),
+			Interest: decimal.RequireFromString("125.00"), Penalty: decimal.Zero, Kind: credit.SCHEDULED, CreatedAt: paidAt},
+	}
+	actions := []*collection.Log{
+		{Action: collection.OVERDUE, ActionDate: time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC),
+			Details: "платеж от 15.05.2024 не внесен"},
+	}
+	return NewCredit(c, schedule, payments, actions, reportDate)
+}
+
+// compareGolden сравнивает got с эталонным файлом testdata/name или перезаписывает его при -update
+func compareGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("эталон %s: %v (для создания запустите go test -update)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("результат не совпадает с эталоном %s\nполучено:\n%s\nожидается:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,175 @@
+package bureau
+
+import (
+	"encoding/xml"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// headerDoc описывает заголовок отчета в JSON и XML
+type headerDoc struct {
+	XMLName     xml.Name `json:"-"            xml:"Header"`
+	BankName    string   `json:"bank_name"    xml:"BankName"`
+	BankBIC     string   `json:"bank_bic"     xml:"BankBIC"`
+	ReportDate  string   `json:"report_date"  xml:"ReportDate"`
+	GeneratedAt string   `json:"generated_at" xml:"GeneratedAt"`
+}
+
+// totalsDoc описывает итоги отчета в JSON и XML
+type totalsDoc struct {
+	XMLName  xml.Name `json:"-"        xml:"Totals"`
+	Subjects int      `json:"subjects" xml:"Subjects,attr"`
+	Credits  int      `json:"credits"  xml:"Credits,attr"`
+}
+
+// subjectDoc описывает заемщика в JSON и XML
+type subjectDoc struct {
+	XMLName  xml.Name    `json:"-"         xml:"Subject"`
+	UserID   int64       `json:"user_id"   xml:"UserId,attr"`
+	FullName string      `json:"full_name" xml:"FullName"`
+	Email    string      `json:"email"     xml:"Email"`
+	Credits  []creditDoc `json:"credits"   xml:"Credits>Credit"`
+}
+
+// creditDoc описывает кредит в JSON и XML
+type creditDoc struct {
+	ID            int64            `json:"id"                  xml:"Id,attr"`
+	AccountID     int64            `json:"account_id"          xml:"AccountId"`
+	Principal     decimal.Decimal  `json:"principal"           xml:"Principal"`
+	InterestRate  decimal.Decimal  `json:"interest_rate"       xml:"InterestRate"`
+	TermMonths    int              `json:"term_months"         xml:"TermMonths"`
+	Scheme        credit.Scheme    `json:"scheme"              xml:"Scheme"`
+	RateType      credit.RateType  `json:"rate_type"           xml:"RateType"`
+	StartDate     string           `json:"start_date"          xml:"StartDate"`
+	Status        credit.Status    `json:"status"              xml:"Status"`
+	ClosedAt      string           `json:"closed_at,omitempty" xml:"ClosedAt,omitempty"`
+	Outstanding   decimal.Decimal  `json:"outstanding"         xml:"Outstanding"`
+	OverdueAmount decimal.Decimal  `json:"overdue_amount"      xml:"OverdueAmount"`
+	DaysPastDue   int              `json:"days_past_due"       xml:"DaysPastDue"`
+	Bucket        Bucket           `json:"bucket"              xml:"Bucket"`
+	WorstBucket   Bucket           `json:"worst_bucket"        xml:"WorstBucket"`
+	TotalPaid     decimal.Decimal  `json:"total_paid"          xml:"TotalPaid"`
+	Buckets       []bucketDoc      `json:"buckets"             xml:"Buckets>Bucket"`
+	Schedule      []installmentDoc `json:"schedule"            xml:"Schedule>Installment"`
+	Payments      []paymentDoc     `json:"payments"            xml:"Payments>Payment"`
+	Statuses      []statusDoc      `json:"statuses"            xml:"Statuses>Status"`
+}
+
+// bucketDoc описывает число платежей в корзине просрочки
+type bucketDoc struct {
+	Bucket Bucket `json:"bucket" xml:"Days,attr"`
+	Count  int    `json:"count"  xml:"Count,attr"`
+}
+
+// installmentDoc описывает строку графика платежей
+type installmentDoc struct {
+	Number    int             `json:"number"            xml:"Number,attr"`
+	DueDate   string          `json:"due_date"          xml:"DueDate"`
+	Amount    decimal.Decimal `json:"amount"            xml:"Amount"`
+	Principal decimal.Decimal `json:"principal"         xml:"Principal"`
+	Interest  decimal.Decimal `json:"interest"          xml:"Interest"`
+	Penalty   decimal.Decimal `json:"penalty"           xml:"Penalty"`
+	Paid      bool            `json:"paid"              xml:"Paid"`
+	PaidAt    string          `json:"paid_at,omitempty" xml:"PaidAt,omitempty"`
+	DaysLate  int             `json:"days_late"         xml:"DaysLate"`
+	Bucket    Bucket          `json:"bucket"            xml:"Bucket"`
+}
+
+// paymentDoc описывает внесенный платеж
+type paymentDoc struct {
+	Date      string             `json:"date"      xml:"Date"`
+	Amount    decimal.Decimal    `json:"amount"    xml:"Amount"`
+	Principal decimal.Decimal    `json:"principal" xml:"Principal"`
+	Interest  decimal.Decimal    `json:"interest"  xml:"Interest"`
+	Penalty   decimal.Decimal    `json:"penalty"   xml:"Penalty"`
+	Kind      credit.PaymentKind `json:"kind"      xml:"Kind"`
+}
+
+// statusDoc описывает смену статуса кредита
+type statusDoc struct {
+	Date   string        `json:"date"   xml:"Date,attr"`
+	Status credit.Status `json:"status" xml:"Code,attr"`
+	Reason string        `json:"reason" xml:",chardata"`
+}
+
+// newHeaderDoc преобразует заголовок отчета для JSON и XML
+func newHeaderDoc(h *Header) headerDoc {
+	return headerDoc{
+		BankName:    h.BankName,
+		BankBIC:     h.BankBIC,
+		ReportDate:  h.ReportDate.Format(dateLayout),
+		GeneratedAt: h.GeneratedAt.UTC().Format(dateTimeLayout),
+	}
+}
+
+// newSubjectDoc преобразует заемщика и его кредиты для JSON и XML
+func newSubjectDoc(s *Subject) subjectDoc {
+	doc := subjectDoc{
+		UserID:   s.UserID,
+		FullName: s.FullName,
+		Email:    s.Email,
+		Credits:  make([]creditDoc, 0, len(s.Credits)),
+	}
+	for _, c := range s.Credits {
+		cd := creditDoc{
+			ID:            c.ID,
+			AccountID:     c.AccountID,
+			Principal:     c.Principal,
+			InterestRate:  c.InterestRate,
+			TermMonths:    c.TermMonths,
+			Scheme:        c.Scheme,
+			RateType:      c.RateType,
+			StartDate:     c.StartDate.Format(dateLayout),
+			Status:        c.Status,
+			Outstanding:   c.Outstanding,
+			OverdueAmount: c.OverdueAmount,
+			DaysPastDue:   c.DaysPastDue,
+			Bucket:        c.Bucket,
+			WorstBucket:   c.WorstBucket,
+			TotalPaid:     c.TotalPaid,
+			Buckets:       make([]bucketDoc, 0, len(Buckets)),
+			Schedule:      make([]installmentDoc, 0, len(c.Schedule)),
+			Payments:      make([]paymentDoc, 0, len(c.Payments)),
+			Statuses:      make([]statusDoc, 0, len(c.Statuses)),
+		}
+		if c.ClosedAt != nil {
+			cd.ClosedAt = c.ClosedAt.UTC().Format(dateTimeLayout)
+		}
+		for _, b := range Buckets {
+			cd.Buckets = append(cd.Buckets, bucketDoc{Bucket: b, Count: c.Buckets[b]})
+		}
+		for _, in := range c.Schedule {
+			row := installmentDoc{
+				Number:    in.Number,
+				DueDate:   in.DueDate.Format(dateLayout),
+				Amount:    in.Amount,
+				Principal: in.Principal,
+				Interest:  in.Interest,
+				Penalty:   in.Penalty,
+				Paid:      in.Paid,
+				DaysLate:  in.DaysLate,
+				Bucket:    in.Bucket,
+			}
+			if in.PaidAt != nil {
+				row.PaidAt = in.PaidAt.UTC().Format(dateTimeLayout)
+			}
+			cd.Schedule = append(cd.Schedule, row)
+		}
+		for _, p := range c.Payments {
+			cd.Payments = append(cd.Payments, paymentDoc{
+				Date:      p.Date.UTC().Format(dateTimeLayout),
+				Amount:    p.Amount,
+				Principal: p.Principal,
+				Interest:  p.Interest,
+				Penalty:   p.Penalty,
+				Kind:      p.Kind,
+			})
+		}
+		for _, st := range c.Statuses {
+			cd.Statuses = append(cd.Statuses, statusDoc{Date: st.Date.Format(dateLayout), Status: st.Status, Reason: st.Reason})
+		}
+		doc.Credits = append(doc.Credits, cd)
+	}
+	return doc
+}
@@ -0,0 +1,171 @@
+package bureau
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// fixedRecordLength — длина каждой записи в символах без перевода строки
+const fixedRecordLength = 200
+
+// fixedWriter записывает отчет текстовыми записями фиксированной ширины. Каждая запись начинается
+// с двухбуквенного типа и дополняется пробелами до fixedRecordLength символов:
+//
+//	HD — заголовок: версия формата, БИК, наименование банка, дата отчета, дата и время формирования;
+//	ID — заемщик: ID пользователя, имя, электронная почта;
+//	TR — кредит: реквизиты, статус, остаток, просрочка, коды корзин и число платежей в каждой корзине;
+//	PS — строка графика платежей;
+//	PM — внесенный платеж;
+//	ST — смена статуса кредита;
+//	TL — итоги: число заемщиков, кредитов и записей в файле, включая HD и TL.
+//
+// Текстовые поля выравниваются по левому краю и дополняются пробелами, числовые — по правому краю
+// и дополняются нулями. Суммы записываются в копейках, ставка — в базисных пунктах (0.15 — 0001500),
+// даты — в виде ГГГГММДД
+type fixedWriter struct {
+	w       *bufio.Writer // Буферизованный поток вывода
+	records int           // Число записанных записей
+}
+
+// newFixedWriter создает запись отчета с полями фиксированной ширины
+func newFixedWriter(w io.Writer) *fixedWriter {
+	return &fixedWriter{w: bufio.NewWriter(w)}
+}
+
+// Begin записывает заголовок HD
+func (f *fixedWriter) Begin(h *Header) error {
+	var r record
+	r.alpha("HD", 2).alpha("0100", 4).alpha(h.BankBIC, 9).alpha(h.BankName, 80).
+		date(h.ReportDate).alpha(h.GeneratedAt.UTC().Format("20060102150405"), 14)
+	return f.write(&r)
+}
+
+// Subject записывает заемщика ID и для каждого кредита записи TR, PS, PM и ST
+func (f *fixedWriter) Subject(s *Subject) error {
+	var r record
+	r.alpha("ID", 2).num(s.UserID, 12).alpha(s.FullName, 80).alpha(s.Email, 80)
+	if err := f.write(&r); err != nil {
+		return err
+	}
+
+	for _, c := range s.Credits {
+		var tr record
+		tr.alpha("TR", 2).num(s.UserID, 12).num(c.ID, 12).num(c.AccountID, 12).date(c.StartDate).
+			amount(c.Principal, 15).num(c.InterestRate.Shift(4).Round(0).IntPart(), 7).num(int64(c.TermMonths), 3).
+			alpha(string(c.Scheme), 15).alpha(string(c.RateType), 10).alpha(string(c.Status), 10).
+			datePtr(c.ClosedAt).amount(c.Outstanding, 15).amount(c.OverdueAmount, 15).num(int64(c.DaysPastDue), 4).
+			alpha(c.Bucket.Code(), 1).alpha(c.WorstBucket.Code(), 1).amount(c.TotalPaid, 15)
+		for _, b := range Buckets {
+			tr.num(int64(c.Buckets[b]), 4)
+		}
+		if err := f.write(&tr); err != nil {
+			return err
+		}
+
+		for _, in := range c.Schedule {
+			paid := "N"
+			if in.Paid {
+				paid = "Y"
+			}
+			var ps record
+			ps.alpha("PS", 2).num(c.ID, 12).num(int64(in.Number), 3).date(in.DueDate).amount(in.Amount, 15).
+				amount(in.Principal, 15).amount(in.Interest, 15).amount(in.Penalty, 15).alpha(paid, 1).
+				datePtr(in.PaidAt).num(int64(in.DaysLate), 4).alpha(in.Bucket.Code(), 1)
+			if err := f.write(&ps); err != nil {
+				return err
+			}
+		}
+		for _, p := range c.Payments {
+			var pm record
+			pm.alpha("PM", 2).num(c.ID, 12).date(p.Date).amount(p.Amount, 15).amount(p.Principal, 15).
+				amount(p.Interest, 15).amount(p.Penalty, 15).alpha(string(p.Kind), 15)
+			if err := f.write(&pm); err != nil {
+				return err
+			}
+		}
+		for _, st := range c.Statuses {
+			var sr record
+			sr.alpha("ST", 2).num(c.ID, 12).date(st.Date).alpha(string(st.Status), 10).alpha(st.Reason, 120)
+			if err := f.write(&sr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// End записывает итоги TL и сбрасывает буфер
+func (f *fixedWriter) End(totals Totals) error {
+	var r record
+	r.alpha("TL", 2).num(int64(totals.Subjects), 9).num(int64(totals.Credits), 9).num(int64(f.records+1), 9)
+	if err := f.write(&r); err != nil {
+		return err
+	}
+	return f.w.Flush()
+}
+
+// write дополняет запись до фиксированной длины и записывает ее с переводом строки
+func (f *fixedWriter) write(r *record) error {
+	line := r.b.String()
+	if n := utf8.RuneCountInString(line); n < fixedRecordLength {
+		line += strings.Repeat(" ", fixedRecordLength-n)
+	}
+	f.records++
+	_, err := f.w.WriteString(line + "\n")
+	return err
+}
+
+// record собирает запись из полей фиксированной ширины
+type record struct {
+	b strings.Builder
+}
+
+// alpha добавляет текстовое поле шириной width символов: обрезает длинное значение и дополняет пробелами
+func (r *record) alpha(s string, width int) *record {
+	runes := []rune(s)
+	if len(runes) > width {
+		runes = runes[:width]
+	}
+	r.b.WriteString(string(runes))
+	r.b.WriteString(strings.Repeat(" ", width-len(runes)))
+	return r
+}
+
+// num добавляет числовое поле шириной width, дополненное нулями слева; отрицательные значения
+// записываются как ноль, слишком большие — обрезаются слева
+func (r *record) num(n int64, width int) *record {
+	if n < 0 {
+		n = 0
+	}
+	s := strconv.FormatInt(n, 10)
+	if len(s) > width {
+		s = s[len(s)-width:]
+	}
+	r.b.WriteString(strings.Repeat("0", width-len(s)))
+	r.b.WriteString(s)
+	return r
+}
+
+// amount добавляет сумму в копейках
+func (r *record) amount(d decimal.Decimal, width int) *record {
+	return r.num(d.Shift(2).Round(0).IntPart(), width)
+}
+
+// date добавляет дату в виде ГГГГММДД
+func (r *record) date(t time.Time) *record {
+	return r.alpha(t.UTC().Format("20060102"), 8)
+}
+
+// datePtr добавляет дату или восемь пробелов, если дата не задана
+func (r *record) datePtr(t *time.Time) *record {
+	if t == nil {
+		return r.alpha("", 8)
+	}
+	return r.date(*t)
+}
@@ -0,0 +1,60 @@
+package bureau
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter потоково записывает отчет в виде JSON-объекта {"header", "subjects": [...], "totals"}
+type jsonWriter struct {
+	w     io.Writer // Поток вывода
+	first bool      // Следующий заемщик — первый в массиве
+}
+
+// newJSONWriter создает запись отчета в JSON
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: w, first: true}
+}
+
+// Begin записывает заголовок и открывает массив заемщиков
+func (j *jsonWriter) Begin(h *Header) error {
+	header, err := json.Marshal(newHeaderDoc(h))
+	if err != nil {
+		return err
+	}
+	return j.write([]byte(`{"header":`), header, []byte(`,"subjects":[`))
+}
+
+// Subject записывает заемщика элементом массива
+func (j *jsonWriter) Subject(s *Subject) error {
+	data, err := json.Marshal(newSubjectDoc(s))
+	if err != nil {
+		return err
+	}
+	if !j.first {
+		if err := j.write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	j.first = false
+	return j.write(data)
+}
+
+// End закрывает массив заемщиков и записывает итоги
+func (j *jsonWriter) End(totals Totals) error {
+	data, err := json.Marshal(totalsDoc{Subjects: totals.Subjects, Credits: totals.Credits})
+	if err != nil {
+		return err
+	}
+	return j.write([]byte(`],"totals":`), data, []byte("}\n"))
+}
+
+// write последовательно записывает фрагменты документа
+func (j *jsonWriter) write(parts ...[]byte) error {
+	for _, p := range parts {
+		if _, err := j.w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
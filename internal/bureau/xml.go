@@ -0,0 +1,57 @@
+package bureau
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlWriter потоково записывает отчет в XML: <CreditHistory><Header/><Subjects>...</Subjects><Totals/></CreditHistory>
+type xmlWriter struct {
+	enc *xml.Encoder // XML-кодировщик
+}
+
+// newXMLWriter создает запись отчета в XML
+func newXMLWriter(w io.Writer) *xmlWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &xmlWriter{enc: enc}
+}
+
+// Begin записывает пролог, заголовок и открывает список заемщиков
+func (x *xmlWriter) Begin(h *Header) error {
+	if err := x.enc.EncodeToken(xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)}); err != nil {
+		return err
+	}
+	if err := x.enc.EncodeToken(xml.CharData("\n")); err != nil {
+		return err
+	}
+	if err := x.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "CreditHistory"}}); err != nil {
+		return err
+	}
+	if err := x.enc.Encode(newHeaderDoc(h)); err != nil {
+		return err
+	}
+	return x.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "Subjects"}})
+}
+
+// Subject записывает заемщика; буфер кодировщика сбрасывается после каждого заемщика
+func (x *xmlWriter) Subject(s *Subject) error {
+	if err := x.enc.Encode(newSubjectDoc(s)); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
+
+// End закрывает список заемщиков, записывает итоги и завершает документ
+func (x *xmlWriter) End(totals Totals) error {
+	if err := x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "Subjects"}}); err != nil {
+		return err
+	}
+	if err := x.enc.Encode(totalsDoc{Subjects: totals.Subjects, Credits: totals.Credits}); err != nil {
+		return err
+	}
+	if err := x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "CreditHistory"}}); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
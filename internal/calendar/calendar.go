@@ -0,0 +1,113 @@
+// Package calendar реализует производственный календарь: определяет рабочие дни с учетом выходных,
+// праздников и переносов и переносит даты платежей, выпадающие на нерабочие дни
+package calendar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat возвращается, если формат файла календаря не удалось определить
+var ErrUnknownFormat = errors.New("неизвестный формат производственного календаря")
+
+// Calendar хранит исключения из правила «суббота и воскресенье — выходные»: праздничные дни и дни,
+// перенесенные на выходные. Для дат вне загруженных файлов действует только это правило
+type Calendar struct {
+	holidays map[time.Time]bool // Нерабочие будни: праздники и перенесенные выходные
+	workdays map[time.Time]bool // Рабочие субботы и воскресенья
+}
+
+// New создает календарь без праздников: рабочими считаются дни с понедельника по пятницу
+func New() *Calendar {
+	return &Calendar{
+		holidays: make(map[time.Time]bool),
+		workdays: make(map[time.Time]bool),
+	}
+}
+
+// Load загружает календарь из файлов производственного календаря (по одному на год). Поддерживаются
+// форматы xmlcalendar.ru: XML (<calendar><days><day d="MM.DD" t="1"/>) и JSON ({"year", "months": [{"month", "days"}]}).
+// Формат определяется по расширению файла, а при его отсутствии — по содержимому
+func Load(paths ...string) (*Calendar, error) {
+	c := New()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.parse(filepath.Ext(path), data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// parse разбирает содержимое файла календаря в формате, определенном по расширению ext или по данным
+func (c *Calendar) parse(ext string, data []byte) error {
+	switch strings.ToLower(ext) {
+	case ".xml":
+		return c.parseXML(data)
+	case ".json":
+		return c.parseJSON(data)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return c.parseXML(data)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return c.parseJSON(data)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// IsBusinessDay сообщает, является ли день даты date рабочим
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	day := truncateDay(date)
+	if c.workdays[day] {
+		return true
+	}
+	if c.holidays[day] {
+		return false
+	}
+	return !isWeekend(day)
+}
+
+// NextBusinessDay возвращает date, если это рабочий день, иначе ближайший следующий рабочий день.
+// Время суток отбрасывается
+func (c *Calendar) NextBusinessDay(date time.Time) time.Time {
+	day := truncateDay(date)
+	for !c.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// setDay отмечает день как рабочий или нерабочий, сохраняя только отличия от правила выходных
+func (c *Calendar) setDay(day time.Time, working bool) {
+	delete(c.holidays, day)
+	delete(c.workdays, day)
+	switch {
+	case working && isWeekend(day):
+		c.workdays[day] = true
+	case !working && !isWeekend(day):
+		c.holidays[day] = true
+	}
+}
+
+// isWeekend сообщает, приходится ли день на субботу или воскресенье
+func isWeekend(day time.Time) bool {
+	return day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+}
+
+// truncateDay отбрасывает время суток, сохраняя календарную дату
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package calendar
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// calendarXML — фрагмент календаря на 2024 год в XML-формате: майские праздники с переносом выходного
+// на субботу 27 апреля и сокращенный день 22 февраля
+const calendarXML = `<?xml version="1.0" encoding="UTF-8"?>
+<calendar year="2024" lang="ru">
+	<days>
+		<day d="02.22" t="2"/>
+		<day d="04.27" t="3"/>
+		<day d="04.29" t="1"/>
+		<day d="04.30" t="1"/>
+		<day d="05.01" t="1"/>
+	</days>
+</calendar>`
+
+// calendarJSON описывает апрель 2024 года в JSON-формате: все нерабочие дни месяца, 27 апреля не указано
+const calendarJSON = `{"year": 2024, "months": [{"month": 4, "days": "6,7,13,14,20,21,28,29+,30+"}]}`
+
+// TestWeekendRule проверяет, что без файлов календаря нерабочими считаются только суббота и воскресенье
+func TestWeekendRule(t *testing.T) {
+	c := New()
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{day(2024, 3, 1), true},  // Пятница
+		{day(2024, 3, 2), false}, // Суббота
+		{day(2024, 3, 3), false}, // Воскресенье
+		{day(2024, 3, 4), true},  // Понедельник
+		{day(2024, 4, 29), true}, // Праздник не загружен
+		{time.Date(2024, 3, 2, 23, 59, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := c.IsBusinessDay(tt.date); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, ожидается %v", tt.date, got, tt.want)
+		}
+	}
+
+	if got := c.NextBusinessDay(time.Date(2024, 3, 2, 15, 30, 0, 0, time.UTC)); !got.Equal(day(2024, 3, 4)) {
+		t.Errorf("NextBusinessDay(суббота) = %s, ожидается понедельник 2024-03-04", got)
+	}
+	if got := c.NextBusinessDay(time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)); !got.Equal(day(2024, 3, 1)) {
+		t.Errorf("NextBusinessDay(пятница) = %s, ожидается та же дата без времени", got)
+	}
+}
+
+// TestLoadXML проверяет праздники, рабочую субботу и сокращенный день из XML-файла
+func TestLoadXML(t *testing.T) {
+	c, err := Load(writeFile(t, "2024.xml", calendarXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{day(2024, 2, 22), true},  // Сокращенный день
+		{day(2024, 4, 27), true},  // Суббота, рабочий день
+		{day(2024, 4, 28), false}, // Воскресенье
+		{day(2024, 4, 29), false}, // Перенесенный выходной
+		{day(2024, 5, 1), false},  // Праздник
+		{day(2024, 5, 2), true},
+	}
+	for _, tt := range tests {
+		if got := c.IsBusinessDay(tt.date); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, ожидается %v", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	if got := c.NextBusinessDay(day(2024, 4, 28)); !got.Equal(day(2024, 5, 2)) {
+		t.Errorf("NextBusinessDay(2024-04-28) = %s, ожидается 2024-05-02", got.Format("2006-01-02"))
+	}
+}
+
+// TestLoadJSON проверяет, что описанный в JSON месяц задается полностью, а остальные месяцы — правилом выходных
+func TestLoadJSON(t *testing.T) {
+	c, err := Load(writeFile(t, "2024.json", calendarJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{day(2024, 4, 27), true},  // Суббота не указана среди нерабочих дней
+		{day(2024, 4, 29), false}, // Перенесенный выходной
+		{day(2024, 4, 30), false},
+		{day(2024, 4, 26), true},
+		{day(2024, 5, 1), true}, // Май не описан
+		{day(2024, 5, 4), false},
+	}
+	for _, tt := range tests {
+		if got := c.IsBusinessDay(tt.date); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, ожидается %v", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+// TestLoadDetectsFormat проверяет определение формата по содержимому файла без расширения
+func TestLoadDetectsFormat(t *testing.T) {
+	if _, err := Load(writeFile(t, "calendar-xml", calendarXML), writeFile(t, "calendar-json", calendarJSON)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(writeFile(t, "calendar", "2024")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ошибка %v, ожидается ErrUnknownFormat", err)
+	}
+}
+
+// TestLoadInvalid проверяет отказ при ошибках в файле календаря
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name, file, data string
+	}{
+		{"XML без года", "a.xml", `<calendar><days><day d="01.01" t="1"/></days></calendar>`},
+		{"неизвестный тип дня", "b.xml", `<calendar year="2024"><days><day d="01.01" t="9"/></days></calendar>`},
+		{"неверная дата", "c.xml", `<calendar year="2024"><days><day d="02.30" t="1"/></days></calendar>`},
+		{"JSON без месяцев", "d.json", `{"year": 2024, "months": []}`},
+		{"неверный месяц", "e.json", `{"year": 2024, "months": [{"month": 13, "days": "1"}]}`},
+		{"несуществующий день", "f.json", `{"year": 2024, "months": [{"month": 2, "days": "30"}]}`},
+		{"неверный день", "g.json", `{"year": 2024, "months": [{"month": 2, "days": "x"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeFile(t, tt.file, tt.data)); err == nil {
+				t.Error("ожидается ошибка")
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("ожидается ошибка для отсутствующего файла")
+	}
+}
+
+// writeFile записывает data во временный файл name и возвращает путь к нему
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// day возвращает полночь даты в UTC
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
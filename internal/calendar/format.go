@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Типы дней в XML-формате производственного календаря
+const (
+	xmlHoliday   = 1 // Выходной (праздничный) день
+	xmlShortened = 2 // Рабочий сокращенный день
+	xmlWorkday   = 3 // Рабочий день, перенесенный на субботу или воскресенье
+)
+
+// xmlCalendar описывает XML-файл производственного календаря: перечислены только дни, отличающиеся
+// от правила выходных
+type xmlCalendar struct {
+	Year int `xml:"year,attr"`
+	Days []struct {
+		Date string `xml:"d,attr"` // Дата в формате MM.DD
+		Type int    `xml:"t,attr"` // Тип дня
+	} `xml:"days>day"`
+}
+
+// parseXML загружает исключения из XML-файла календаря на один год
+func (c *Calendar) parseXML(data []byte) error {
+	var doc xmlCalendar
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Year == 0 {
+		return fmt.Errorf("%w: не указан год", ErrUnknownFormat)
+	}
+
+	for _, d := range doc.Days {
+		day, err := time.Parse("2006.01.02", fmt.Sprintf("%d.%s", doc.Year, d.Date))
+		if err != nil {
+			return fmt.Errorf("неверная дата %q: %w", d.Date, err)
+		}
+		switch d.Type {
+		case xmlHoliday:
+			c.setDay(day, false)
+		case xmlShortened, xmlWorkday:
+			c.setDay(day, true)
+		default:
+			return fmt.Errorf("неизвестный тип дня %d для %s", d.Type, d.Date)
+		}
+	}
+	return nil
+}
+
+// jsonCalendar описывает JSON-файл производственного календаря: для каждого месяца перечислены все
+// нерабочие дни через запятую; суффикс «*» отмечает сокращенный рабочий день, «+» — перенесенный выходной
+type jsonCalendar struct {
+	Year   int `json:"year"`
+	Months []struct {
+		Month int    `json:"month"`
+		Days  string `json:"days"`
+	} `json:"months"`
+}
+
+// parseJSON загружает календарь на год из JSON-файла. Перечисленные месяцы описаны полностью, поэтому
+// каждый их день явно отмечается рабочим или нерабочим
+func (c *Calendar) parseJSON(data []byte) error {
+	var doc jsonCalendar
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Year == 0 || len(doc.Months) == 0 {
+		return fmt.Errorf("%w: не указан год или месяцы", ErrUnknownFormat)
+	}
+
+	nonWorking := make(map[time.Time]bool)
+	months := make(map[time.Month]bool)
+	for _, m := range doc.Months {
+		if m.Month < 1 || m.Month > 12 {
+			return fmt.Errorf("неверный номер месяца %d", m.Month)
+		}
+		months[time.Month(m.Month)] = true
+		for _, item := range strings.Split(m.Days, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" || strings.HasSuffix(item, "*") {
+				// Сокращенный день остается рабочим
+				continue
+			}
+			n, err := strconv.Atoi(strings.TrimSuffix(item, "+"))
+			if err != nil {
+				return fmt.Errorf("неверный день %q в месяце %d", item, m.Month)
+			}
+			day := time.Date(doc.Year, time.Month(m.Month), n, 0, 0, 0, 0, time.UTC)
+			if day.Month() != time.Month(m.Month) {
+				return fmt.Errorf("неверный день %q в месяце %d", item, m.Month)
+			}
+			nonWorking[day] = true
+		}
+	}
+
+	for day := time.Date(doc.Year, time.January, 1, 0, 0, 0, 0, time.UTC); day.Year() == doc.Year; day = day.AddDate(0, 0, 1) {
+		if months[day.Month()] {
+			c.setDay(day, !nonWorking[day])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package cbr реализует клиент SOAP-сервиса DailyInfo Банка России
+package cbr
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultURL — адрес веб-сервиса DailyInfo
+const DefaultURL = "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx"
+
+// keyRateLookback задает глубину поиска последнего установленного значения ключевой ставки
+const keyRateLookback = 30 * 24 * time.Hour
+
+// ErrNoKeyRate возвращается, если сервис не вернул значений ключевой ставки за период
+var ErrNoKeyRate = errors.New("ЦБ РФ не вернул значение ключевой ставки")
+
+// Client запрашивает данные у веб-сервиса DailyInfo
+type Client struct {
+	url        string       // Адрес веб-сервиса
+	httpClient *http.Client // HTTP-клиент с таймаутом
+}
+
+// NewClient создает новый клиент веб-сервиса ЦБ РФ
+func NewClient(url string, timeout time.Duration) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// keyRateRequest — тело SOAP-запроса метода KeyRate
+const keyRateRequest = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <KeyRate xmlns="http://web.cbr.ru/">
+      <fromDate>%s</fromDate>
+      <ToDate>%s</ToDate>
+    </KeyRate>
+  </soap:Body>
+</soap:Envelope>`
+
+// keyRateResponse описывает нужную часть ответа метода KeyRate (diffgram с записями KR)
+type keyRateResponse struct {
+	Rows []struct {
+		Date string `xml:"DT"`
+		Rate string `xml:"Rate"`
+	} `xml:"Body>KeyRateResponse>KeyRateResult>diffgram>KeyRate>KR"`
+}
+
+// KeyRate возвращает ключевую ставку в процентах (например, 16.00), действующую на дату date
+func (c *Client) KeyRate(ctx context.Context, date time.Time) (decimal.Decimal, error) {
+	from := date.Add(-keyRateLookback)
+	body := fmt.Sprintf(keyRateRequest, from.Format("2006-01-02T00:00:00"), date.Format("2006-01-02T23:59:59"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewBufferString(body))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", `"http://web.cbr.ru/KeyRate"`)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("запрос ключевой ставки: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decimal.Zero, fmt.Errorf("запрос ключевой ставки: ответ %s", resp.Status)
+	}
+
+	var parsed keyRateResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return decimal.Zero, fmt.Errorf("разбор ответа ЦБ РФ: %w", err)
+	}
+	if len(parsed.Rows) == 0 {
+		return decimal.Zero, ErrNoKeyRate
+	}
+
+	// Даты передаются в формате xs:dateTime, поэтому их можно сравнивать как строки
+	sort.Slice(parsed.Rows, func(i, j int) bool { return parsed.Rows[i].Date < parsed.Rows[j].Date })
+	rate, err := decimal.NewFromString(parsed.Rows[len(parsed.Rows)-1].Rate)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("разбор ответа ЦБ РФ: %w", err)
+	}
+	return rate, nil
+}
@@ -0,0 +1,17 @@
+package config
+
+// BankConfig содержит реквизиты банка, которые выводятся в выписках и отчетах
+type BankConfig struct {
+	Name    string // Наименование банка
+	BIC     string // БИК банка
+	Address string // Адрес банка
+}
+
+// LoadBank загружает реквизиты банка из переменных окружения
+func LoadBank() BankConfig {
+	return BankConfig{
+		Name:    getEnv("BANK_NAME", "Bank API"),                      // Значение по умолчанию: Bank API
+		BIC:     getEnv("BANK_BIC", "044525000"),                      // Значение по умолчанию: 044525000
+		Address: getEnv("BANK_ADDRESS", "Moscow, Russian Federation"), // Значение по умолчанию: Moscow, Russian Federation
+	}
+}
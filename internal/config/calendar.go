@@ -0,0 +1,20 @@
+package config
+
+import "strings"
+
+// CalendarConfig содержит параметры производственного календаря
+type CalendarConfig struct {
+	Files []string // Файлы производственного календаря (XML или JSON, по одному на год)
+}
+
+// LoadCalendar загружает параметры производственного календаря из переменных окружения.
+// CALENDAR_FILES содержит пути к файлам через запятую; без файлов нерабочими считаются только суббота и воскресенье
+func LoadCalendar() CalendarConfig {
+	var files []string
+	for _, path := range strings.Split(getEnv("CALENDAR_FILES", ""), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			files = append(files, path)
+		}
+	}
+	return CalendarConfig{Files: files}
+}
@@ -0,0 +1,13 @@
+package config
+
+// CardConfig содержит параметры защиты оплаты картой
+type CardConfig struct {
+	MaxCVVFailures int // Неверных вводов CVV подряд, после которых карта блокируется
+}
+
+// LoadCard загружает параметры карт из переменных окружения
+func LoadCard() CardConfig {
+	return CardConfig{
+		MaxCVVFailures: getEnvInt("CARD_CVV_MAX_FAILURES", 3), // Значение по умолчанию: 3 попытки
+	}
+}
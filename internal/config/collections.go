@@ -0,0 +1,53 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/collection"
+)
+
+// defaultCollectionStages — этапы эскалации по умолчанию: напоминание и неустойка с первого дня просрочки,
+// списание с других счетов с 10-го дня, ограничение счетов с 30-го дня
+const defaultCollectionStages = "1:REMINDER,1:PENALTY,10:SWEEP,30:RESTRICT"
+
+// CollectionsConfig содержит параметры работы с просроченной задолженностью по кредитам
+type CollectionsConfig struct {
+	Stages      []collection.Stage // Этапы эскалации в порядке дней просрочки
+	PenaltyRate decimal.Decimal    // Годовая ставка неустойки на просроченную сумму (доля, 0.2 = 20%)
+}
+
+// LoadCollections загружает параметры работы с просроченной задолженностью из переменных окружения.
+// Этапы задаются в COLLECTION_STAGES списком «день:действие» через запятую
+func LoadCollections() CollectionsConfig {
+	stages, ok := parseCollectionStages(getEnv("COLLECTION_STAGES", defaultCollectionStages))
+	if !ok {
+		stages, _ = parseCollectionStages(defaultCollectionStages)
+	}
+	return CollectionsConfig{
+		Stages:      stages,                                          // Значение по умолчанию: defaultCollectionStages
+		PenaltyRate: getEnvDecimal("COLLECTION_PENALTY_RATE", "0.2"), // Значение по умолчанию: 20% годовых
+	}
+}
+
+// parseCollectionStages разбирает список этапов «день:действие»; день должен быть положительным,
+// а действие — допустимым этапом эскалации
+func parseCollectionStages(value string) ([]collection.Stage, bool) {
+	var stages []collection.Stage
+	for _, item := range strings.Split(value, ",") {
+		days, action, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found {
+			return nil, false
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		stage := collection.Stage{Days: n, Action: collection.Action(strings.ToUpper(strings.TrimSpace(action)))}
+		if err != nil || n < 1 || !stage.Action.Valid() {
+			return nil, false
+		}
+		stages = append(stages, stage)
+	}
+	sort.SliceStable(stages, func(i, j int) bool { return stages[i].Days < stages[j].Days })
+	return stages, true
+}
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// CredentialsConfig содержит параметры подтверждения email и восстановления пароля
+type CredentialsConfig struct {
+	AppURL               string        // Адрес клиентского приложения для ссылок в письмах
+	EmailVerificationTTL time.Duration // Срок действия ссылки подтверждения email
+	PasswordResetTTL     time.Duration // Срок действия ссылки сброса пароля
+}
+
+// LoadCredentials загружает параметры подтверждения email и восстановления пароля из переменных окружения
+func LoadCredentials() CredentialsConfig {
+	return CredentialsConfig{
+		AppURL:               getEnv("APP_URL", "http://localhost:8080"),             // Значение по умолчанию: http://localhost:8080
+		EmailVerificationTTL: getEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour), // Значение по умолчанию: 24 часа
+		PasswordResetTTL:     getEnvDuration("PASSWORD_RESET_TTL", time.Hour),        // Значение по умолчанию: 1 час
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import "github.com/shopspring/decimal"
+
+// CreditConfig содержит параметры выдачи кредитов
+type CreditConfig struct {
+	Rate          decimal.Decimal // Годовая ставка по кредитам (доля, 0.18 = 18%)
+	MaxAmount     decimal.Decimal // Максимальная сумма кредита
+	MaxTermMonths int             // Максимальный срок кредита в месяцах
+	KeyRateMargin decimal.Decimal // Надбавка к ключевой ставке для кредитов с плавающей ставкой (доля)
+}
+
+// LoadCredit загружает параметры кредитов из переменных окружения
+func LoadCredit() CreditConfig {
+	return CreditConfig{
+		Rate:          getEnvDecimal("CREDIT_INTEREST_RATE", "0.18"),   // Значение по умолчанию: 18% годовых
+		MaxAmount:     getEnvDecimal("CREDIT_MAX_AMOUNT", "5000000"),   // Значение по умолчанию: 5 000 000
+		MaxTermMonths: getEnvInt("CREDIT_MAX_TERM_MONTHS", 60),         // Значение по умолчанию: 60 месяцев
+		KeyRateMargin: getEnvDecimal("CREDIT_KEY_RATE_MARGIN", "0.03"), // Значение по умолчанию: ключевая ставка плюс 3%
+	}
+}
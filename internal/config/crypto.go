@@ -1,20 +1,39 @@
 package config
 
 import (
+	"errors"
+	"os"
+
 	"github.com/sirupsen/logrus"
 )
 
+// devPGPKey — ключ PGP-шифрования для локальной разработки; используется только при APP_ENV=dev
+const devPGPKey = "bankDefaultPGPKey2024"
+
+// ErrPGPKeyRequired возвращается, если BANK_PGP_KEY не задан вне среды разработки
+var ErrPGPKeyRequired = errors.New("не задан BANK_PGP_KEY: без ключа сервис запускается только при APP_ENV=dev")
+
 // CryptoConfig содержит криптографические ключи для шифрования и подписи данных
 type CryptoConfig struct {
 	PGPKey  string // Ключ для PGP-шифрования данных
 	HMACKey string // Ключ для генерации HMAC-подписей
 }
 
-// LoadCrypto загружает конфигурацию криптографических ключей из переменных окружения
-func LoadCrypto() CryptoConfig {
+// LoadCrypto загружает конфигурацию криптографических ключей из переменных окружения.
+// Ключом PGP зашифрованы данные карт, секреты и ключи подписи JWT, поэтому общеизвестный ключ
+// по умолчанию допускается только в среде разработки (APP_ENV=dev)
+func LoadCrypto() (CryptoConfig, error) {
+	pgpKey := os.Getenv("BANK_PGP_KEY")
+	if pgpKey == "" {
+		if getEnv("APP_ENV", "production") != "dev" {
+			return CryptoConfig{}, ErrPGPKeyRequired
+		}
+		logrus.Warn("BANK_PGP_KEY не задан: используется ключ для разработки")
+		pgpKey = devPGPKey
+	}
+
 	cfg := CryptoConfig{
-		// Получение PGP-ключ из переменной окружения или использование значения по умолчанию
-		PGPKey: getEnv("BANK_PGP_KEY", "bankDefaultPGPKey2024"),
+		PGPKey: pgpKey,
 		// Получение HMAC-ключ из переменной окружения или использование значения по умолчанию
 		HMACKey: getEnv("BANK_HMAC_KEY", "bankDefaultHMACKey2024"),
 	}
@@ -22,5 +41,5 @@ func LoadCrypto() CryptoConfig {
 	// Логирование успешной загрузки конфигурации (сам ключи не выводятся)
 	logrus.Info("Конфигурация криптографических ключей успешно загружена")
 
-	return cfg
+	return cfg, nil
 }
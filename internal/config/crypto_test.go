@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLoadCryptoPGPKey проверяет, что ключ PGP по умолчанию используется только в среде разработки
+func TestLoadCryptoPGPKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		env     string
+		want    string
+		wantErr error
+	}{
+		{"ключ задан", "secret", "", "secret", nil},
+		{"ключ задан в разработке", "secret", "dev", "secret", nil},
+		{"без ключа в разработке", "", "dev", devPGPKey, nil},
+		{"без ключа по умолчанию", "", "", "", ErrPGPKeyRequired},
+		{"без ключа в production", "", "production", "", ErrPGPKeyRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BANK_PGP_KEY", tt.key)
+			t.Setenv("APP_ENV", tt.env)
+
+			cfg, err := LoadCrypto()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидается %v", err, tt.wantErr)
+			}
+			if cfg.PGPKey != tt.want {
+				t.Errorf("ключ %q, ожидается %q", cfg.PGPKey, tt.want)
+			}
+		})
+	}
+}
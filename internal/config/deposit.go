@@ -0,0 +1,52 @@
+package config
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/cbr"
+)
+
+// DepositConfig содержит параметры срочных вкладов
+type DepositConfig struct {
+	Rate          decimal.Decimal // Фиксированная годовая ставка (доля, 0.12 = 12%)
+	UseKeyRate    bool            // Рассчитывать ставку от ключевой ставки ЦБ РФ
+	KeyRateMargin decimal.Decimal // Надбавка к ключевой ставке (доля, может быть отрицательной)
+	PenaltyRate   decimal.Decimal // Ставка при досрочном закрытии (доля)
+	MinAmount     decimal.Decimal // Минимальная сумма вклада
+	MaxTermMonths int             // Максимальный срок вклада в месяцах
+}
+
+// CBRConfig содержит параметры подключения к веб-сервису ЦБ РФ
+type CBRConfig struct {
+	URL     string        // Адрес веб-сервиса DailyInfo
+	Timeout time.Duration // Таймаут запроса
+}
+
+// LoadDeposit загружает параметры срочных вкладов из переменных окружения
+func LoadDeposit() DepositConfig {
+	return DepositConfig{
+		Rate:          getEnvDecimal("DEPOSIT_INTEREST_RATE", "0.12"),    // Значение по умолчанию: 12% годовых
+		UseKeyRate:    getEnv("DEPOSIT_USE_KEY_RATE", "false") == "true", // Значение по умолчанию: фиксированная ставка
+		KeyRateMargin: getEnvDecimal("DEPOSIT_KEY_RATE_MARGIN", "-0.02"), // Значение по умолчанию: ключевая ставка минус 2%
+		PenaltyRate:   getEnvDecimal("DEPOSIT_PENALTY_RATE", "0.001"),    // Значение по умолчанию: 0.1% годовых
+		MinAmount:     getEnvDecimal("DEPOSIT_MIN_AMOUNT", "1000"),       // Значение по умолчанию: 1000
+		MaxTermMonths: getEnvInt("DEPOSIT_MAX_TERM_MONTHS", 36),          // Значение по умолчанию: 36 месяцев
+	}
+}
+
+// LoadCBR загружает параметры подключения к ЦБ РФ из переменных окружения
+func LoadCBR() CBRConfig {
+	return CBRConfig{
+		URL:     getEnv("CBR_URL", cbr.DefaultURL),             // Значение по умолчанию: официальный адрес DailyInfo
+		Timeout: getEnvDuration("CBR_TIMEOUT", 10*time.Second), // Значение по умолчанию: 10 секунд
+	}
+}
+
+// getEnvDecimal получает десятичное число из переменной окружения или возвращает значение по умолчанию
+func getEnvDecimal(key string, defaultValue string) decimal.Decimal {
+	if value, err := decimal.NewFromString(getEnv(key, "")); err == nil {
+		return value
+	}
+	return decimal.RequireFromString(defaultValue)
+}
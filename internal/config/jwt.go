@@ -1,26 +1,32 @@
 package config
 
 import (
-	"os"
 	"time"
+
+	"github.com/yujihn/bank_API/internal/keyset"
 )
 
-// JWTConfig содержит настройки для JWT-токенов, используемых для аутентификации
+// JWTConfig содержит настройки для JWT-токенов, используемых для аутентификации.
+// Ключи подписи генерируются сервисом, хранятся в базе данных в зашифрованном виде и регулярно ротируются
 type JWTConfig struct {
-	Secret    string        // Секретный ключ для подписи JWT
-	ExpiresIn time.Duration // Время жизни токена
+	Algorithm         keyset.Algorithm // Алгоритм подписи новых токенов
+	KeyRotationPeriod time.Duration    // Срок, после которого ключ подписи заменяется новым
+	ExpiresIn         time.Duration    // Время жизни access-токена
+	RefreshExpiresIn  time.Duration    // Время жизни refresh-токена; продлевается при каждом обновлении
 }
 
-// LoadJWT загружает конфигурацию JWT из переменных окружения или использует значения по умолчанию
+// LoadJWT загружает конфигурацию JWT из переменных окружения или использует значения по умолчанию.
+// Неизвестный JWT_ALGORITHM заменяется на HS256
 func LoadJWT() JWTConfig {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// Значение по умолчанию, если переменная окружения не установлена
-		secret = "default-bank-api-jwt-secret-key"
+	alg, err := keyset.ParseAlgorithm(getEnv("JWT_ALGORITHM", string(keyset.HS256)))
+	if err != nil {
+		alg = keyset.HS256
 	}
 
 	return JWTConfig{
-		Secret:    secret,         // Секретный ключ
-		ExpiresIn: 24 * time.Hour, // Время жизни токена: 24 часа
+		Algorithm:         alg,                                                        // Значение по умолчанию: HS256
+		KeyRotationPeriod: getEnvDuration("JWT_KEY_ROTATION_PERIOD", 30*24*time.Hour), // Значение по умолчанию: 30 дней
+		ExpiresIn:         getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),           // Значение по умолчанию: 15 минут
+		RefreshExpiresIn:  getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),         // Значение по умолчанию: 30 дней
 	}
 }
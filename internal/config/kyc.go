@@ -0,0 +1,20 @@
+package config
+
+import "github.com/shopspring/decimal"
+
+// KYCConfig содержит ограничения для пользователей, чья личность не подтверждена (статус KYC не VERIFIED).
+// Выпуск карт и кредитные заявки им недоступны, а расходные операции ограничены лимитами
+type KYCConfig struct {
+	OperationLimit decimal.Decimal // Максимальная сумма одной расходной операции
+	MonthlyLimit   decimal.Decimal // Максимальная сумма расходных операций за календарный месяц
+	MinAge         int             // Минимальный возраст клиента в годах
+}
+
+// LoadKYC загружает ограничения для пользователей без подтвержденной личности из переменных окружения
+func LoadKYC() KYCConfig {
+	return KYCConfig{
+		OperationLimit: getEnvDecimal("KYC_UNVERIFIED_OPERATION_LIMIT", "15000"), // Значение по умолчанию: 15 000
+		MonthlyLimit:   getEnvDecimal("KYC_UNVERIFIED_MONTHLY_LIMIT", "40000"),   // Значение по умолчанию: 40 000
+		MinAge:         getEnvInt("KYC_MIN_AGE", 14),                             // Значение по умолчанию: 14 лет
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// LockoutConfig содержит параметры защиты входа от подбора пароля. Неудачные попытки считаются отдельно
+// по email и по IP-адресу; счетчик сбрасывается, если с последней неудачной попытки прошло больше Window
+type LockoutConfig struct {
+	BackoffAfter    int           // Неудачных попыток, после которых вводится задержка перед следующей попыткой
+	BackoffBase     time.Duration // Первая задержка; каждая следующая неудачная попытка удваивает ее
+	BackoffMax      time.Duration // Максимальная задержка
+	MaxFailures     int           // Неудачных попыток по email до временной блокировки учетной записи
+	IPMaxFailures   int           // Неудачных попыток с одного IP-адреса до его временной блокировки
+	LockoutDuration time.Duration // Длительность временной блокировки
+	Window          time.Duration // Период, после которого счетчик неудачных попыток сбрасывается
+}
+
+// LoadLockout загружает параметры защиты входа от подбора пароля из переменных окружения
+func LoadLockout() LockoutConfig {
+	return LockoutConfig{
+		BackoffAfter:    getEnvInt("LOGIN_BACKOFF_AFTER", 3),                      // Значение по умолчанию: 3 попытки
+		BackoffBase:     getEnvDuration("LOGIN_BACKOFF_BASE", time.Second),        // Значение по умолчанию: 1 секунда
+		BackoffMax:      getEnvDuration("LOGIN_BACKOFF_MAX", 5*time.Minute),       // Значение по умолчанию: 5 минут
+		MaxFailures:     getEnvInt("LOGIN_MAX_FAILURES", 10),                      // Значение по умолчанию: 10 попыток
+		IPMaxFailures:   getEnvInt("LOGIN_IP_MAX_FAILURES", 100),                  // Значение по умолчанию: 100 попыток
+		LockoutDuration: getEnvDuration("LOGIN_LOCKOUT_DURATION", 30*time.Minute), // Значение по умолчанию: 30 минут
+		Window:          getEnvDuration("LOGIN_FAILURE_WINDOW", 24*time.Hour),     // Значение по умолчанию: 24 часа
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MFAConfig содержит параметры двухфакторной аутентификации по одноразовым кодам (TOTP)
+type MFAConfig struct {
+	Issuer          string          // Название сервиса, которое показывает приложение-аутентификатор
+	ChallengeTTL    time.Duration   // Срок действия MFA-токена, выданного после проверки пароля
+	MaxAttempts     int             // Количество попыток ввода кода по одному MFA-токену
+	MaxFailures     int             // Неверных кодов подряд, после которых ввод кодов пользователем блокируется
+	LockoutDuration time.Duration   // Длительность блокировки ввода кодов
+	AssertionTTL    time.Duration   // Сколько действует подтверждение кодом для операций с деньгами
+	AmountThreshold decimal.Decimal // Сумма операции, выше которой требуется свежее подтверждение кодом
+}
+
+// LoadMFA загружает параметры двухфакторной аутентификации из переменных окружения
+func LoadMFA() MFAConfig {
+	return MFAConfig{
+		Issuer:          getEnv("MFA_ISSUER", "Bank API"),                       // Значение по умолчанию: Bank API
+		ChallengeTTL:    getEnvDuration("MFA_CHALLENGE_TTL", 5*time.Minute),     // Значение по умолчанию: 5 минут
+		MaxAttempts:     getEnvInt("MFA_MAX_ATTEMPTS", 5),                       // Значение по умолчанию: 5 попыток
+		MaxFailures:     getEnvInt("MFA_MAX_FAILURES", 5),                       // Значение по умолчанию: 5 попыток
+		LockoutDuration: getEnvDuration("MFA_LOCKOUT_DURATION", 15*time.Minute), // Значение по умолчанию: 15 минут
+		AssertionTTL:    getEnvDuration("MFA_ASSERTION_TTL", 5*time.Minute),     // Значение по умолчанию: 5 минут
+		AmountThreshold: getEnvDecimal("MFA_AMOUNT_THRESHOLD", "50000"),         // Значение по умолчанию: 50000
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "github.com/shopspring/decimal"
+
+// OverdraftConfig содержит параметры овердрафта по текущим счетам
+type OverdraftConfig struct {
+	Rate     decimal.Decimal // Годовая ставка за пользование овердрафтом (доля, 0.25 = 25%)
+	MaxLimit decimal.Decimal // Максимальный лимит овердрафта, одобряемый по счету
+}
+
+// LoadOverdraft загружает параметры овердрафта из переменных окружения
+func LoadOverdraft() OverdraftConfig {
+	return OverdraftConfig{
+		Rate:     getEnvDecimal("OVERDRAFT_INTEREST_RATE", "0.25"), // Значение по умолчанию: 25% годовых
+		MaxLimit: getEnvDecimal("OVERDRAFT_MAX_LIMIT", "50000"),    // Значение по умолчанию: 50000
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// ProxyConfig содержит параметры обратных прокси перед сервисом
+type ProxyConfig struct {
+	TrustedProxies []netip.Prefix // Подсети прокси, которым разрешено передавать адрес клиента в X-Forwarded-For
+}
+
+// LoadProxy загружает параметры прокси из переменных окружения. TRUSTED_PROXIES содержит подсети в нотации CIDR
+// или отдельные IP-адреса через запятую; неверные значения пропускаются. Без значения X-Forwarded-For не учитывается
+func LoadProxy() ProxyConfig {
+	var prefixes []netip.Prefix
+	for _, value := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+	return ProxyConfig{TrustedProxies: prefixes}
+}
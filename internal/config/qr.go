@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// QRConfig содержит параметры платежных QR-кодов
+type QRConfig struct {
+	TTL time.Duration // Срок действия QR-кода с момента формирования
+}
+
+// LoadQR загружает параметры платежных QR-кодов из переменных окружения
+func LoadQR() QRConfig {
+	return QRConfig{
+		TTL: getEnvDuration("QR_TTL", 15*time.Minute), // Значение по умолчанию: 15 минут
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SavingsConfig содержит параметры начисления процентов по накопительным счетам
+type SavingsConfig struct {
+	Rate          decimal.Decimal // Годовая ставка (доля, 0.16 = 16%)
+	DayCount      string          // Конвенция подсчета дней: ACT/365 или ACT/ACT
+	EffectiveDate time.Time       // Дата, с которой действует ставка (нулевая — с текущего дня)
+}
+
+// LoadSavings загружает параметры накопительных счетов из переменных окружения
+func LoadSavings() SavingsConfig {
+	cfg := SavingsConfig{
+		Rate:     decimal.RequireFromString("0.10"),      // Значение по умолчанию: 10% годовых
+		DayCount: getEnv("SAVINGS_DAY_COUNT", "ACT/365"), // Значение по умолчанию: ACT/365
+	}
+	if rate, err := decimal.NewFromString(getEnv("SAVINGS_INTEREST_RATE", "")); err == nil && !rate.IsNegative() {
+		cfg.Rate = rate
+	}
+	if date, err := time.Parse("2006-01-02", getEnv("SAVINGS_RATE_EFFECTIVE_DATE", "")); err == nil {
+		cfg.EffectiveDate = date
+	}
+	return cfg
+}
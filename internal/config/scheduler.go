@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// SchedulerConfig содержит параметры фоновых задач
+type SchedulerConfig struct {
+	StandingOrdersInterval    time.Duration // Период проверки платежных поручений к исполнению
+	StandingOrderMaxFailures  int           // Количество неудачных исполнений подряд до приостановки поручения
+	PaymentRequestsInterval   time.Duration // Период проверки просроченных запросов на оплату
+	SavingsAccrualInterval    time.Duration // Период запуска начисления процентов по накопительным счетам
+	DepositMaturityInterval   time.Duration // Период проверки вкладов с наступившим сроком окончания
+	OverdraftInterestInterval time.Duration // Период запуска начисления процентов по овердрафту
+	CreditAutoDebitInterval   time.Duration // Период запуска списания платежей по кредитам
+	CreditRateResetInterval   time.Duration // Период проверки ключевой ставки для кредитов с плавающей ставкой
+	CollectionsInterval       time.Duration // Период обработки просроченной задолженности по кредитам
+	JWTKeysInterval           time.Duration // Период ротации и синхронизации ключей подписи JWT
+	RevokedTokensInterval     time.Duration // Период очистки списка отозванных токенов от истекших записей
+	MFAChallengesInterval     time.Duration // Период удаления истекших незавершенных входов с кодом
+	LoginThrottlesInterval    time.Duration // Период удаления устаревших счетчиков неудачных попыток входа
+	UserTokensInterval        time.Duration // Период удаления истекших токенов подтверждения email и сброса пароля
+}
+
+// LoadScheduler загружает параметры фоновых задач из переменных окружения
+func LoadScheduler() SchedulerConfig {
+	return SchedulerConfig{
+		StandingOrdersInterval:    getEnvDuration("STANDING_ORDERS_INTERVAL", 15*time.Minute), // Значение по умолчанию: 15 минут
+		StandingOrderMaxFailures:  getEnvInt("STANDING_ORDER_MAX_FAILURES", 3),                // Значение по умолчанию: 3
+		PaymentRequestsInterval:   getEnvDuration("PAYMENT_REQUESTS_INTERVAL", time.Hour),     // Значение по умолчанию: 1 час
+		SavingsAccrualInterval:    getEnvDuration("SAVINGS_ACCRUAL_INTERVAL", time.Hour),      // Значение по умолчанию: 1 час
+		DepositMaturityInterval:   getEnvDuration("DEPOSIT_MATURITY_INTERVAL", time.Hour),     // Значение по умолчанию: 1 час
+		OverdraftInterestInterval: getEnvDuration("OVERDRAFT_INTEREST_INTERVAL", time.Hour),   // Значение по умолчанию: 1 час
+		CreditAutoDebitInterval:   getEnvDuration("CREDIT_AUTO_DEBIT_INTERVAL", time.Hour),    // Значение по умолчанию: 1 час
+		CreditRateResetInterval:   getEnvDuration("CREDIT_RATE_RESET_INTERVAL", time.Hour),    // Значение по умолчанию: 1 час
+		CollectionsInterval:       getEnvDuration("COLLECTIONS_INTERVAL", time.Hour),          // Значение по умолчанию: 1 час
+		JWTKeysInterval:           getEnvDuration("JWT_KEYS_INTERVAL", time.Hour),             // Значение по умолчанию: 1 час
+		RevokedTokensInterval:     getEnvDuration("REVOKED_TOKENS_INTERVAL", time.Hour),       // Значение по умолчанию: 1 час
+		MFAChallengesInterval:     getEnvDuration("MFA_CHALLENGES_INTERVAL", time.Hour),       // Значение по умолчанию: 1 час
+		LoginThrottlesInterval:    getEnvDuration("LOGIN_THROTTLES_INTERVAL", time.Hour),      // Значение по умолчанию: 1 час
+		UserTokensInterval:        getEnvDuration("USER_TOKENS_INTERVAL", time.Hour),          // Значение по умолчанию: 1 час
+	}
+}
+
+// getEnvDuration получает длительность из переменной окружения (например, "15m") или возвращает значение по умолчанию
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(getEnv(key, "")); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt получает целое число из переменной окружения или возвращает значение по умолчанию
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(getEnv(key, "")); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ScoringConfig содержит параметры рассмотрения кредитных заявок
+type ScoringConfig struct {
+	IncomeMonths       int             // Период оценки поступлений на счета в месяцах
+	MaxDebtToIncome    decimal.Decimal // Долговая нагрузка, выше которой заявка отклоняется
+	ReviewDebtToIncome decimal.Decimal // Долговая нагрузка, выше которой заявка направляется на ручную проверку
+	IncomeTolerance    decimal.Decimal // Минимальная доля заявленного дохода, подтвержденная поступлениями
+	MaxLatePayments    int             // Допустимое число платежей, внесенных с опозданием
+	ApprovalTTL        time.Duration   // Срок действия одобрения
+}
+
+// LoadScoring загружает параметры рассмотрения кредитных заявок из переменных окружения
+func LoadScoring() ScoringConfig {
+	return ScoringConfig{
+		IncomeMonths:       getEnvInt("SCORING_INCOME_MONTHS", 3),                    // Значение по умолчанию: 3 месяца
+		MaxDebtToIncome:    getEnvDecimal("SCORING_MAX_DEBT_TO_INCOME", "0.5"),       // Значение по умолчанию: 50% дохода
+		ReviewDebtToIncome: getEnvDecimal("SCORING_REVIEW_DEBT_TO_INCOME", "0.4"),    // Значение по умолчанию: 40% дохода
+		IncomeTolerance:    getEnvDecimal("SCORING_INCOME_TOLERANCE", "0.8"),         // Значение по умолчанию: 80% заявленного дохода
+		MaxLatePayments:    getEnvInt("SCORING_MAX_LATE_PAYMENTS", 2),                // Значение по умолчанию: 2 платежа
+		ApprovalTTL:        getEnvDuration("CREDIT_APPLICATION_TTL", 7*24*time.Hour), // Значение по умолчанию: 7 дней
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+// SMTPConfig содержит параметры отправки электронной почты
+type SMTPConfig struct {
+	Host     string // Адрес SMTP-сервера; пустое значение отключает отправку писем
+	Port     string // Порт SMTP-сервера
+	Username string // Имя пользователя для аутентификации
+	Password string // Пароль для аутентификации
+	From     string // Адрес отправителя
+}
+
+// LoadSMTP загружает параметры SMTP-сервера из переменных окружения
+func LoadSMTP() SMTPConfig {
+	return SMTPConfig{
+		Host:     getEnv("SMTP_HOST", ""),                   // Значение по умолчанию: письма только логируются
+		Port:     getEnv("SMTP_PORT", "587"),                // Значение по умолчанию: 587 (submission)
+		Username: getEnv("SMTP_USER", ""),                   // Значение по умолчанию: без аутентификации
+		Password: getEnv("SMTP_PASSWORD", ""),               // Значение по умолчанию: без аутентификации
+		From:     getEnv("SMTP_FROM", "noreply@bank.local"), // Значение по умолчанию: noreply@bank.local
+	}
+}
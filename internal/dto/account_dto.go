@@ -8,28 +8,57 @@ import (
 
 // CreateAccountRequest представляет запрос на создание нового счета
 type CreateAccountRequest struct {
-	Currency account.Currency `json:"currency"` // Валюта счета
+	Currency account.Currency `json:"currency" binding:"required,currency"` // Валюта счета (обязательное поле, код ISO 4217)
+	Type     account.Type     `json:"type,omitempty"`                       // Тип счета: CURRENT (по умолчанию) или SAVINGS
 }
 
 // UpdateBalanceRequest представляет запрос на пополнение или списание средств со счета
 type UpdateBalanceRequest struct {
-	Amount decimal.Decimal `json:"amount"` // Сумма для пополнения или списания
+	Amount decimal.Decimal `json:"amount" binding:"required"` // Сумма для пополнения (положительная) или списания (отрицательная)
 }
 
 // TransferRequest представляет запрос на перевод средств между счетами
 type TransferRequest struct {
-	FromAccountID int64           `json:"from_account_id"` // ID счета отправителя
-	ToAccountID   int64           `json:"to_account_id"`   // ID счета получателя
-	Amount        decimal.Decimal `json:"amount"`          // Сумма перевода
+	FromAccountID int64           `json:"from_account_id" binding:"required"` // ID счета отправителя (обязательное поле)
+	ToAccountID   int64           `json:"to_account_id" binding:"required"`   // ID счета получателя (обязательное поле)
+	Amount        decimal.Decimal `json:"amount" binding:"required,positive"` // Сумма перевода (обязательное поле, больше нуля)
 }
 
 // AccountResponse представляет ответ с информацией о счете
 type AccountResponse struct {
-	ID        int64            `json:"id"`         // ID счета
-	UserID    int64            `json:"user_id"`    // ID пользователя, владельца счета
-	Balance   decimal.Decimal  `json:"balance"`    // Текущий баланс
-	Currency  account.Currency `json:"currency"`   // Валюта счета
-	CreatedAt string           `json:"created_at"` // Дата и время создания счета
+	ID             int64            `json:"id"`              // ID счета
+	UserID         int64            `json:"user_id"`         // ID пользователя, владельца счета
+	Type           account.Type     `json:"type"`            // Тип счета
+	Balance        decimal.Decimal  `json:"balance"`         // Текущий баланс
+	OverdraftLimit decimal.Decimal  `json:"overdraft_limit"` // Лимит овердрафта
+	OverdraftUsed  decimal.Decimal  `json:"overdraft_used"`  // Использованная сумма овердрафта
+	Available      decimal.Decimal  `json:"available"`       // Доступно для списания с учетом овердрафта
+	Restricted     bool             `json:"restricted"`      // Расходные операции ограничены из-за просрочки по кредиту
+	Currency       account.Currency `json:"currency"`        // Валюта счета
+	CreatedAt      string           `json:"created_at"`      // Дата и время создания счета
+}
+
+// SetOverdraftRequest представляет запрос на установку лимита овердрафта
+type SetOverdraftRequest struct {
+	Limit decimal.Decimal `json:"limit" binding:"min=0"` // Лимит овердрафта (0 — отключить)
+}
+
+// OverdraftResponse представляет ответ с состоянием овердрафта по счету
+type OverdraftResponse struct {
+	AccountID int64           `json:"account_id"` // ID счета
+	Limit     decimal.Decimal `json:"limit"`      // Лимит овердрафта
+	Used      decimal.Decimal `json:"used"`       // Использованная сумма овердрафта
+	Available decimal.Decimal `json:"available"`  // Доступно для списания с учетом овердрафта
+	Rate      decimal.Decimal `json:"rate"`       // Годовая ставка по овердрафту
+}
+
+// OverdraftSummary представляет сводку использования овердрафта по всем счетам пользователя
+type OverdraftSummary struct {
+	TotalLimit        decimal.Decimal `json:"total_limit"`         // Суммарный лимит овердрафта
+	TotalUsed         decimal.Decimal `json:"total_used"`          // Суммарная задолженность по овердрафту
+	TotalAvailable    decimal.Decimal `json:"total_available"`     // Неиспользованная часть лимитов
+	Rate              decimal.Decimal `json:"rate"`                // Годовая ставка по овердрафту
+	InterestThisMonth decimal.Decimal `json:"interest_this_month"` // Проценты, начисленные с начала месяца
 }
 
 // TransactionResponse представляет ответ с информацией о транзакции
@@ -44,10 +73,66 @@ type TransactionResponse struct {
 
 // AccountsListResponse представляет список счетов
 type AccountsListResponse struct {
-	Accounts []AccountResponse `json:"accounts"` // Массив счетов
+	Accounts  []AccountResponse `json:"accounts"`  // Массив счетов
+	Overdraft OverdraftSummary  `json:"overdraft"` // Сводка по овердрафту
 }
 
 // TransactionListResponse представляет список транзакций
 type TransactionListResponse struct {
 	Transactions []TransactionResponse `json:"transactions"` // Массив транзакций
 }
+
+// EmailTransferRequest представляет запрос на перевод другому пользователю по email
+type EmailTransferRequest struct {
+	FromAccountID int64           `json:"from_account_id" binding:"required"` // ID счета отправителя (обязательное поле)
+	ToEmail       string          `json:"to_email" binding:"required,email"`  // Email получателя (обязательное поле, формат email)
+	Amount        decimal.Decimal `json:"amount" binding:"required,positive"` // Сумма перевода (обязательное поле, больше нуля)
+}
+
+// RecipientPreviewResponse представляет данные получателя перевода, показываемые до подтверждения
+type RecipientPreviewResponse struct {
+	MaskedName string           `json:"masked_name"` // Замаскированное имя получателя
+	Currency   account.Currency `json:"currency"`    // Валюта счета зачисления
+}
+
+// QRPayRequest представляет запрос на оплату по QR-коду
+type QRPayRequest struct {
+	Payload       string          `json:"payload" binding:"required"`                    // Платежная строка из QR-кода (обязательное поле)
+	FromAccountID int64           `json:"from_account_id" binding:"required"`            // ID счета плательщика (обязательное поле)
+	Amount        decimal.Decimal `json:"amount,omitempty" binding:"omitempty,positive"` // Сумма (обязательна, если в QR-коде нет суммы)
+}
+
+// QRPayloadResponse представляет платежную строку QR-кода в формате JSON
+type QRPayloadResponse struct {
+	Payload   string `json:"payload"`    // Подписанная платежная строка
+	ExpiresAt string `json:"expires_at"` // Срок действия QR-кода
+}
+
+// QRPaymentResponse представляет результат оплаты по QR-коду
+type QRPaymentResponse struct {
+	ID        int64           `json:"id"`         // ID оплаты
+	Amount    decimal.Decimal `json:"amount"`     // Сумма оплаты
+	Recipient string          `json:"recipient"`  // Замаскированное имя получателя
+	CreatedAt string          `json:"created_at"` // Дата и время оплаты
+}
+
+// InterestAccrualResponse представляет дневное начисление процентов
+type InterestAccrualResponse struct {
+	Date        string          `json:"date"`        // День начисления
+	Balance     decimal.Decimal `json:"balance"`     // Остаток на конец дня
+	Rate        decimal.Decimal `json:"rate"`        // Годовая ставка
+	DayCount    string          `json:"day_count"`   // Конвенция подсчета дней
+	Amount      decimal.Decimal `json:"amount"`      // Начисленные проценты
+	Capitalized bool            `json:"capitalized"` // Проценты зачислены на счет
+}
+
+// SavingsInterestResponse представляет проценты по накопительному счету за период
+type SavingsInterestResponse struct {
+	AccountID    int64                     `json:"account_id"`          // ID счета
+	Rate         *decimal.Decimal          `json:"rate,omitempty"`      // Действующая годовая ставка
+	DayCount     string                    `json:"day_count,omitempty"` // Действующая конвенция подсчета дней
+	From         string                    `json:"from"`                // Начало периода
+	To           string                    `json:"to"`                  // Конец периода
+	TotalAccrued decimal.Decimal           `json:"total_accrued"`       // Начислено за период
+	Accruals     []InterestAccrualResponse `json:"accruals"`            // Дневные начисления
+}
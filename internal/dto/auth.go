@@ -2,8 +2,10 @@ package dto
 
 // RegisterRequest представляет запрос на регистрацию нового пользователя
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required,email"`    // Электронная почта (обязательное поле, формат email)
-	Password string `json:"password" binding:"required,min=6"` // Пароль (обязательное поле, минимум 6 символов)
+	Email    string `json:"email" binding:"required,email"`        // Электронная почта (обязательное поле, формат email)
+	Password string `json:"password" binding:"required,min=6"`     // Пароль (обязательное поле, минимум 6 символов)
+	FullName string `json:"full_name"`                             // Имя и фамилия (необязательное поле)
+	Username string `json:"username" binding:"omitempty,username"` // Уникальное имя пользователя (необязательное поле)
 }
 
 // LoginRequest представляет запрос на аутентификацию пользователя
@@ -12,7 +14,80 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`    // Пароль (обязательное поле)
 }
 
-// AuthResponse представляет ответ с JWT-токеном после успешной аутентификации
+// AuthResponse представляет ответ с парой токенов после входа или обновления
 type AuthResponse struct {
-	Token string `json:"token"` // JWT-токен для последующих запросов
+	Token            string `json:"token"`              // Access-токен (JWT) для последующих запросов
+	TokenType        string `json:"token_type"`         // Тип токена: Bearer
+	ExpiresIn        int64  `json:"expires_in"`         // Время жизни access-токена в секундах
+	RefreshToken     string `json:"refresh_token"`      // Одноразовый токен для POST /token/refresh
+	RefreshExpiresAt string `json:"refresh_expires_at"` // Срок действия refresh-токена
+	SessionID        int64  `json:"session_id"`         // ID сессии
+}
+
+// RefreshRequest представляет запрос на обновление пары токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // Refresh-токен, выданный при входе или предыдущем обновлении (обязательное поле)
+}
+
+// SessionResponse представляет активную сессию пользователя
+type SessionResponse struct {
+	ID         int64  `json:"id"`           // ID сессии
+	UserAgent  string `json:"user_agent"`   // User-Agent устройства при входе
+	IPAddress  string `json:"ip_address"`   // IP-адрес при входе
+	LastIP     string `json:"last_ip"`      // IP-адрес при последнем обновлении токенов
+	CreatedAt  string `json:"created_at"`   // Дата и время входа
+	LastUsedAt string `json:"last_used_at"` // Дата и время последнего обновления токенов
+	ExpiresAt  string `json:"expires_at"`   // Срок действия refresh-токена
+	Current    bool   `json:"current"`      // Сессия текущего запроса
+}
+
+// MFAChallengeResponse представляет ответ на вход пользователя с включенной двухфакторной аутентификацией
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"` // Всегда true: требуется второй шаг входа
+	MFAToken    string `json:"mfa_token"`    // Токен для POST /login/mfa
+	ExpiresAt   string `json:"expires_at"`   // Срок действия токена
+}
+
+// LoginMFARequest представляет запрос на второй шаг входа
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"` // Токен, выданный POST /login (обязательное поле)
+	Code     string `json:"code" binding:"required"`      // Код из приложения-аутентификатора или код восстановления (обязательное поле)
+}
+
+// UnlockLoginRequest представляет запрос администратора на снятие ограничений входа
+type UnlockLoginRequest struct {
+	Email string `json:"email" binding:"omitempty,email"` // Email учетной записи (необязательное поле, формат email)
+	IP    string `json:"ip"`                              // IP-адрес (необязательное поле)
+}
+
+// LoginLockoutResponse представляет действующее ограничение входа
+type LoginLockoutResponse struct {
+	Scope         string `json:"scope"`           // EMAIL или IP
+	Key           string `json:"key"`             // Email или IP-адрес
+	Failures      int    `json:"failures"`        // Неудачных попыток подряд
+	LastFailureAt string `json:"last_failure_at"` // Дата и время последней неудачной попытки
+	BlockedUntil  string `json:"blocked_until"`   // До какого момента попытки отклоняются
+	Locked        bool   `json:"locked"`          // Блокировка после превышения лимита, а не задержка
+}
+
+// VerifyEmailRequest представляет запрос на подтверждение email по ссылке из письма
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"` // Токен из ссылки (обязательное поле)
+}
+
+// ForgotPasswordRequest представляет запрос письма со ссылкой сброса пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"` // Электронная почта (обязательное поле, формат email)
+}
+
+// ResetPasswordRequest представляет запрос на сброс пароля по ссылке из письма
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`              // Токен из ссылки (обязательное поле)
+	NewPassword string `json:"new_password" binding:"required,min=6"` // Новый пароль (обязательное поле, минимум 6 символов)
+}
+
+// ChangePasswordRequest представляет запрос на смену пароля
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`   // Текущий пароль (обязательное поле)
+	NewPassword     string `json:"new_password" binding:"required,min=6"` // Новый пароль (обязательное поле, минимум 6 символов)
 }
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/batch"
+)
+
+// BatchItemResponse представляет статус отдельного платежа пакета
+type BatchItemResponse struct {
+	ID          int64            `json:"id"`                    // ID платежа
+	EndToEndID  string           `json:"end_to_end_id"`         // Сквозной идентификатор платежа
+	ToAccountID int64            `json:"to_account_id"`         // Счет получателя
+	Amount      decimal.Decimal  `json:"amount"`                // Сумма платежа
+	Status      batch.ItemStatus `json:"status"`                // Статус платежа
+	Error       string           `json:"error,omitempty"`       // Причина отклонения
+	ExecutedAt  string           `json:"executed_at,omitempty"` // Дата и время исполнения
+}
+
+// BatchResponse представляет пакет платежей со статусами всех платежей
+type BatchResponse struct {
+	ID            int64               `json:"id"`                     // ID пакета
+	FromAccountID int64               `json:"from_account_id"`        // Счет списания
+	MessageID     string              `json:"message_id"`             // Идентификатор исходного сообщения
+	SourceFormat  string              `json:"source_format"`          // Формат исходного файла
+	ItemCount     int                 `json:"item_count"`             // Количество платежей
+	TotalAmount   decimal.Decimal     `json:"total_amount"`           // Общая сумма пакета
+	Status        batch.Status        `json:"status"`                 // Статус пакета
+	CreatedAt     string              `json:"created_at"`             // Дата и время приема
+	CompletedAt   string              `json:"completed_at,omitempty"` // Дата и время завершения
+	Items         []BatchItemResponse `json:"items"`                  // Платежи пакета
+}
@@ -2,7 +2,7 @@ package dto
 
 // CreateCardRequest представляет запрос на создание новой карты
 type CreateCardRequest struct {
-	PGPKey string `json:"pgp_key"` // Публичный ключ PGP для шифрования данных карты
+	PGPKey string `json:"pgp_key" binding:"required"` // Публичный ключ PGP для шифрования данных карты (обязательное поле)
 }
 
 // CreateCardResponse содержит данные созданной карты
@@ -19,6 +19,7 @@ type CreateCardResponse struct {
 type CardResponse struct {
 	ID        int64  `json:"id"`         // ID карты
 	UserID    int64  `json:"user_id"`    // ID владельца
+	Blocked   bool   `json:"blocked"`    // Карта заблокирована после неверных вводов CVV
 	CreatedAt string `json:"created_at"` // Дата и время создания
 }
 
@@ -36,10 +37,10 @@ type CardListResponse struct {
 
 // CardPaymentRequest представляет запрос на оплату с карты
 type CardPaymentRequest struct {
-	CardID int64  `json:"card_id"` // ID карты для оплаты
-	Amount string `json:"amount"`  // Сумма платежа
-	CVV    string `json:"cvv"`     // CVV-код карты
-	PGPKey string `json:"pgp_key"` // Публичный ключ PGP для шифрования данных
+	CardID int64  `json:"card_id" binding:"required"`         // ID карты для оплаты (обязательное поле)
+	Amount string `json:"amount" binding:"required,positive"` // Сумма платежа (обязательное поле, больше нуля)
+	CVV    string `json:"cvv" binding:"required"`             // CVV-код карты (обязательное поле)
+	PGPKey string `json:"pgp_key" binding:"required"`         // Публичный ключ PGP для шифрования данных (обязательное поле)
 }
 
 // CardPaymentResponse содержит результат операции оплаты
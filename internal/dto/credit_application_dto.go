@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/application"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// CreateCreditApplicationRequest представляет кредитную заявку
+type CreateCreditApplicationRequest struct {
+	AccountID      int64           `json:"account_id" binding:"required"`               // ID счета зачисления и погашения кредита (обязательное поле)
+	Amount         decimal.Decimal `json:"amount" binding:"required,positive"`          // Запрошенная сумма (обязательное поле, больше нуля)
+	TermMonths     int             `json:"term_months" binding:"required,min=1"`        // Запрошенный срок в месяцах (обязательное поле)
+	Scheme         credit.Scheme   `json:"scheme,omitempty"`                            // ANNUITY (по умолчанию) или DIFFERENTIATED
+	RateType       credit.RateType `json:"rate_type,omitempty"`                         // FIXED (по умолчанию) или FLOATING
+	DeclaredIncome decimal.Decimal `json:"declared_income" binding:"required,positive"` // Заявленный ежемесячный доход (обязательное поле, больше нуля)
+}
+
+// CreditApplicationResponse представляет заявку и решение по ней
+type CreditApplicationResponse struct {
+	ID             int64                `json:"id"`                        // ID заявки
+	UserID         int64                `json:"user_id"`                   // ID заявителя
+	AccountID      int64                `json:"account_id"`                // ID счета кредита
+	Amount         decimal.Decimal      `json:"amount"`                    // Запрошенная сумма
+	TermMonths     int                  `json:"term_months"`               // Запрошенный срок в месяцах
+	Scheme         credit.Scheme        `json:"scheme"`                    // Схема погашения
+	RateType       credit.RateType      `json:"rate_type"`                 // Вид ставки
+	DeclaredIncome decimal.Decimal      `json:"declared_income"`           // Заявленный ежемесячный доход
+	ObservedIncome decimal.Decimal      `json:"observed_income"`           // Средние ежемесячные поступления на счета
+	DebtPayments   decimal.Decimal      `json:"debt_payments"`             // Платежи по действующим кредитам
+	Payment        decimal.Decimal      `json:"payment"`                   // Ежемесячный платеж по новому кредиту
+	DebtToIncome   decimal.Decimal      `json:"debt_to_income"`            // Долговая нагрузка
+	Status         application.Status   `json:"status"`                    // Решение по заявке
+	Reasons        []application.Reason `json:"reasons"`                   // Коды причин решения
+	CreditID       *int64               `json:"credit_id,omitempty"`       // ID оформленного кредита
+	AcceptedAt     string               `json:"accepted_at,omitempty"`     // Дата и время принятия одобрения
+	DecisionReason string               `json:"decision_reason,omitempty"` // Обоснование ручного решения
+	ReviewedAt     string               `json:"reviewed_at,omitempty"`     // Дата и время ручного решения
+	CreatedAt      string               `json:"created_at"`                // Дата и время подачи заявки
+}
+
+// CreditApplicationListResponse представляет список заявок
+type CreditApplicationListResponse struct {
+	Applications []CreditApplicationResponse `json:"applications"` // Массив заявок
+}
+
+// CreditApplicationDecisionRequest представляет ручное решение администратора по заявке
+type CreditApplicationDecisionRequest struct {
+	Status application.Status `json:"status" binding:"required"` // APPROVED или REJECTED (обязательное поле)
+	Reason string             `json:"reason" binding:"required"` // Обоснование решения (обязательное поле)
+}
+
+// AcceptCreditApplicationResponse представляет принятую заявку и оформленный по ней кредит
+type AcceptCreditApplicationResponse struct {
+	Application CreditApplicationResponse `json:"application"` // Принятая заявка
+	Credit      CreditScheduleResponse    `json:"credit"`      // Оформленный кредит с графиком платежей
+}
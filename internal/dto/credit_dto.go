@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/collection"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// CreateCreditRequest представляет запрос на оформление кредита
+type CreateCreditRequest struct {
+	AccountID  int64           `json:"account_id" binding:"required"`        // ID счета зачисления и погашения кредита (обязательное поле)
+	Amount     decimal.Decimal `json:"amount" binding:"required,positive"`   // Сумма кредита (обязательное поле, больше нуля)
+	TermMonths int             `json:"term_months" binding:"required,min=1"` // Срок кредита в месяцах (обязательное поле)
+	Scheme     credit.Scheme   `json:"scheme,omitempty"`                     // ANNUITY (по умолчанию) или DIFFERENTIATED
+	RateType   credit.RateType `json:"rate_type,omitempty"`                  // FIXED (по умолчанию) или FLOATING — ключевая ставка плюс надбавка
+}
+
+// CreditCalculationRequest представляет запрос на расчет кредита без оформления
+type CreditCalculationRequest struct {
+	Amount     decimal.Decimal  `json:"amount" binding:"required,positive"`            // Сумма кредита (обязательное поле, больше нуля)
+	Rate       *decimal.Decimal `json:"rate,omitempty" binding:"min=0"`                // Годовая ставка (доля); по умолчанию — текущая ставка банка
+	TermMonths int              `json:"term_months" binding:"required,min=1"`          // Срок кредита в месяцах (обязательное поле)
+	Scheme     credit.Scheme    `json:"scheme,omitempty"`                              // ANNUITY (по умолчанию) или DIFFERENTIATED
+	StartDate  string           `json:"start_date,omitempty" binding:"omitempty,date"` // Дата выдачи (YYYY-MM-DD), по умолчанию — сегодня
+}
+
+// CalculatedPaymentResponse представляет платеж рассчитанного графика
+type CalculatedPaymentResponse struct {
+	Number    int             `json:"number"`    // Номер платежа
+	DueDate   string          `json:"due_date"`  // Дата платежа
+	Payment   decimal.Decimal `json:"payment"`   // Сумма платежа
+	Principal decimal.Decimal `json:"principal"` // Погашение основного долга
+	Interest  decimal.Decimal `json:"interest"`  // Погашение процентов
+	Balance   decimal.Decimal `json:"balance"`   // Остаток долга после платежа
+}
+
+// CreditCalculationResponse представляет результат расчета кредита
+type CreditCalculationResponse struct {
+	Amount        decimal.Decimal             `json:"amount"`         // Сумма кредита
+	Rate          decimal.Decimal             `json:"rate"`           // Годовая ставка
+	TermMonths    int                         `json:"term_months"`    // Срок в месяцах
+	Scheme        credit.Scheme               `json:"scheme"`         // Схема погашения
+	StartDate     string                      `json:"start_date"`     // Дата выдачи
+	TotalPayment  decimal.Decimal             `json:"total_payment"`  // Сумма всех платежей
+	TotalInterest decimal.Decimal             `json:"total_interest"` // Переплата по процентам
+	PSK           decimal.Decimal             `json:"psk"`            // Полная стоимость кредита (доля в год)
+	EffectiveRate decimal.Decimal             `json:"effective_rate"` // Эффективная годовая ставка с учетом сложного процента
+	Schedule      []CalculatedPaymentResponse `json:"schedule"`       // График платежей
+}
+
+// PrepayCreditRequest представляет запрос на досрочное погашение кредита
+type PrepayCreditRequest struct {
+	Amount decimal.Decimal   `json:"amount" binding:"omitempty,positive"` // Сумма частичного погашения (для FULL не указывается)
+	Mode   credit.PrepayMode `json:"mode" binding:"required"`             // REDUCE_TERM, REDUCE_PAYMENT или FULL (обязательное поле)
+}
+
+// CreditResponse представляет ответ с информацией о кредите
+type CreditResponse struct {
+	ID              int64            `json:"id"`                          // ID кредита
+	AccountID       int64            `json:"account_id"`                  // ID счета кредита
+	Principal       decimal.Decimal  `json:"principal"`                   // Сумма кредита
+	InterestRate    float64          `json:"interest_rate"`               // Годовая ставка
+	TermMonths      int              `json:"term_months"`                 // Срок по договору в месяцах
+	Scheme          credit.Scheme    `json:"scheme"`                      // Схема погашения
+	RateType        credit.RateType  `json:"rate_type"`                   // Вид ставки
+	RateMargin      *decimal.Decimal `json:"rate_margin,omitempty"`       // Надбавка к ключевой ставке
+	StartDate       string           `json:"start_date"`                  // Дата выдачи
+	Status          credit.Status    `json:"status"`                      // Статус кредита
+	OverdueSince    string           `json:"overdue_since,omitempty"`     // День перевода в статус OVERDUE
+	Outstanding     decimal.Decimal  `json:"outstanding"`                 // Остаток основного долга
+	NextPaymentDate string           `json:"next_payment_date,omitempty"` // Дата ближайшего платежа
+	NextPayment     *decimal.Decimal `json:"next_payment,omitempty"`      // Сумма ближайшего платежа
+	ClosedAt        string           `json:"closed_at,omitempty"`         // Дата и время закрытия
+	CreatedAt       string           `json:"created_at"`                  // Дата и время оформления
+}
+
+// CreditListResponse представляет список кредитов
+type CreditListResponse struct {
+	Credits []CreditResponse `json:"credits"` // Массив кредитов
+}
+
+// PaymentScheduleResponse представляет строку графика платежей
+type PaymentScheduleResponse struct {
+	DueDate   string          `json:"due_date"`          // Дата платежа
+	Amount    decimal.Decimal `json:"amount"`            // Сумма платежа
+	Principal decimal.Decimal `json:"principal"`         // Погашение основного долга
+	Interest  decimal.Decimal `json:"interest"`          // Погашение процентов
+	Penalty   decimal.Decimal `json:"penalty"`           // Неустойка за просрочку
+	Paid      bool            `json:"paid"`              // Платеж внесен
+	PaidAt    string          `json:"paid_at,omitempty"` // Дата и время оплаты
+}
+
+// CreditPaymentResponse представляет фактически внесенный платеж по кредиту
+type CreditPaymentResponse struct {
+	Amount    decimal.Decimal    `json:"amount"`     // Сумма платежа
+	Principal decimal.Decimal    `json:"principal"`  // Погашение основного долга
+	Interest  decimal.Decimal    `json:"interest"`   // Погашение процентов
+	Penalty   decimal.Decimal    `json:"penalty"`    // Погашение неустойки
+	Kind      credit.PaymentKind `json:"kind"`       // Вид платежа
+	CreatedAt string             `json:"created_at"` // Дата и время платежа
+}
+
+// CreditScheduleResponse содержит кредит, его график и внесенные платежи
+type CreditScheduleResponse struct {
+	Credit   CreditResponse            `json:"credit"`   // Кредит
+	Schedule []PaymentScheduleResponse `json:"schedule"` // График платежей
+	Payments []CreditPaymentResponse   `json:"payments"` // Внесенные платежи
+}
+
+// CreditRateResponse представляет запись истории ставки по кредиту
+type CreditRateResponse struct {
+	EffectiveDate string          `json:"effective_date"` // Дата, после платежа в которую действует ставка
+	KeyRate       decimal.Decimal `json:"key_rate"`       // Ключевая ставка ЦБ РФ в процентах
+	Margin        decimal.Decimal `json:"margin"`         // Надбавка к ключевой ставке
+	Rate          decimal.Decimal `json:"rate"`           // Годовая ставка по кредиту
+	Payment       decimal.Decimal `json:"payment"`        // Ежемесячный платеж по новой ставке
+	CreatedAt     string          `json:"created_at"`     // Дата и время пересмотра
+}
+
+// CreditRateHistoryResponse представляет историю ставки по кредиту
+type CreditRateHistoryResponse struct {
+	CreditID int64                `json:"credit_id"` // ID кредита
+	RateType credit.RateType      `json:"rate_type"` // Вид ставки
+	Rates    []CreditRateResponse `json:"rates"`     // Изменения ставки в хронологическом порядке
+}
+
+// CollectionActionResponse представляет запись журнала работы с просроченной задолженностью
+type CollectionActionResponse struct {
+	Action      collection.Action `json:"action"`               // Действие
+	StageDays   int               `json:"stage_days"`           // День просрочки этапа (0 — смена статуса)
+	DaysPastDue int               `json:"days_past_due"`        // Дней просрочки на момент действия
+	ActionDate  string            `json:"action_date"`          // День действия
+	Amount      *decimal.Decimal  `json:"amount,omitempty"`     // Сумма неустойки, списания или задолженности
+	AccountID   *int64            `json:"account_id,omitempty"` // Счет, с которого списаны средства
+	Details     string            `json:"details"`              // Описание действия
+	CreatedAt   string            `json:"created_at"`           // Дата и время действия
+}
+
+// CreditCollectionsResponse представляет состояние просроченной задолженности по кредиту и журнал действий
+type CreditCollectionsResponse struct {
+	CreditID      int64                      `json:"credit_id"`               // ID кредита
+	Status        credit.Status              `json:"status"`                  // Статус кредита
+	OverdueSince  string                     `json:"overdue_since,omitempty"` // День перевода в статус OVERDUE
+	DaysPastDue   int                        `json:"days_past_due"`           // Дней с даты самого раннего неоплаченного платежа
+	OverdueAmount decimal.Decimal            `json:"overdue_amount"`          // Просроченная задолженность с неустойкой
+	Penalty       decimal.Decimal            `json:"penalty"`                 // Начисленная неоплаченная неустойка
+	Actions       []CollectionActionResponse `json:"actions"`                 // Журнал действий в хронологическом порядке
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/deposit"
+)
+
+// OpenDepositRequest представляет запрос на открытие срочного вклада
+type OpenDepositRequest struct {
+	AccountID      int64                  `json:"account_id" binding:"required"`        // ID счета, с которого открывается вклад (обязательное поле)
+	Amount         decimal.Decimal        `json:"amount" binding:"required,positive"`   // Сумма вклада (обязательное поле, больше нуля)
+	TermMonths     int                    `json:"term_months" binding:"required,min=1"` // Срок вклада в месяцах (обязательное поле)
+	MaturityAction deposit.MaturityAction `json:"maturity_action,omitempty"`            // PAYOUT (по умолчанию) или ROLLOVER
+}
+
+// DepositResponse представляет ответ с информацией о вкладе
+type DepositResponse struct {
+	ID               int64                  `json:"id"`                          // ID вклада
+	AccountID        int64                  `json:"account_id"`                  // ID счета вклада
+	Principal        decimal.Decimal        `json:"principal"`                   // Сумма вклада в текущем сроке
+	Rate             decimal.Decimal        `json:"rate"`                        // Годовая ставка
+	RateSource       deposit.RateSource     `json:"rate_source"`                 // Источник ставки
+	PenaltyRate      decimal.Decimal        `json:"penalty_rate"`                // Ставка при досрочном закрытии
+	TermMonths       int                    `json:"term_months"`                 // Срок вклада в месяцах
+	MaturityAction   deposit.MaturityAction `json:"maturity_action"`             // Действие по окончании срока
+	StartDate        string                 `json:"start_date"`                  // Начало текущего срока
+	MaturityDate     string                 `json:"maturity_date"`               // Окончание текущего срока
+	ExpectedInterest *decimal.Decimal       `json:"expected_interest,omitempty"` // Проценты за текущий срок при закрытии в дату окончания
+	Rollovers        int                    `json:"rollovers"`                   // Количество продлений
+	InterestPaid     decimal.Decimal        `json:"interest_paid"`               // Выплаченные и причисленные проценты
+	Status           deposit.Status         `json:"status"`                      // Статус вклада
+	ClosedAt         string                 `json:"closed_at,omitempty"`         // Дата и время закрытия
+	CreatedAt        string                 `json:"created_at"`                  // Дата и время открытия
+}
+
+// DepositListResponse представляет список вкладов
+type DepositListResponse struct {
+	Deposits []DepositResponse `json:"deposits"` // Массив вкладов
+}
+
+// DepositRateResponse представляет текущую ставку по вкладам
+type DepositRateResponse struct {
+	Rate        decimal.Decimal    `json:"rate"`         // Годовая ставка для новых вкладов
+	RateSource  deposit.RateSource `json:"rate_source"`  // Источник ставки
+	PenaltyRate decimal.Decimal    `json:"penalty_rate"` // Ставка при досрочном закрытии
+}
@@ -0,0 +1,29 @@
+package dto
+
+// TOTPEnrollResponse представляет данные для подключения приложения-аутентификатора
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`      // Секрет в base32 для ручного ввода
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth URI
+	QRPNG      []byte `json:"qr_png"`      // QR-код с otpauth URI в формате PNG (base64)
+}
+
+// MFACodeRequest представляет запрос с кодом двухфакторной аутентификации
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"` // Код из приложения-аутентификатора или код восстановления (обязательное поле)
+}
+
+// TOTPConfirmResponse представляет ответ на включение двухфакторной аутентификации
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // Одноразовые коды восстановления; показываются один раз
+}
+
+// MFAStatusResponse представляет состояние двухфакторной аутентификации пользователя
+type MFAStatusResponse struct {
+	Enabled           bool `json:"enabled"`             // Двухфакторная аутентификация включена
+	RecoveryCodesLeft int  `json:"recovery_codes_left"` // Количество неиспользованных кодов восстановления
+}
+
+// MFAAssertResponse представляет ответ на подтверждение сессии кодом
+type MFAAssertResponse struct {
+	ExpiresAt string `json:"expires_at"` // Срок действия подтверждения для операций с крупными суммами
+}
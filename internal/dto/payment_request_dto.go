@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/paymentrequest"
+)
+
+// CreatePaymentRequestRequest представляет запрос на выставление счета на оплату другому пользователю
+type CreatePaymentRequestRequest struct {
+	PayerEmail  string          `json:"payer_email" binding:"required,email"` // Email плательщика (обязательное поле, формат email)
+	ToAccountID int64           `json:"to_account_id,omitempty"`              // Счет зачисления (по умолчанию — счет по умолчанию)
+	Amount      decimal.Decimal `json:"amount" binding:"required,positive"`   // Запрошенная сумма (обязательное поле, больше нуля)
+	DueDate     string          `json:"due_date" binding:"required,date"`     // Последний день оплаты (YYYY-MM-DD, обязательное поле)
+	Memo        string          `json:"memo,omitempty" binding:"max=140"`     // Назначение платежа (не длиннее 140 символов)
+}
+
+// ApprovePaymentRequestRequest представляет запрос на оплату выставленного счета
+type ApprovePaymentRequestRequest struct {
+	FromAccountID int64           `json:"from_account_id" binding:"required"`            // ID счета плательщика (обязательное поле)
+	Amount        decimal.Decimal `json:"amount,omitempty" binding:"omitempty,positive"` // Сумма оплаты (по умолчанию — весь остаток)
+}
+
+// PaymentRequestResponse представляет ответ с информацией о запросе на оплату
+type PaymentRequestResponse struct {
+	ID              int64                 `json:"id"`               // ID запроса
+	RequesterEmail  string                `json:"requester_email"`  // Email получателя денег
+	PayerEmail      string                `json:"payer_email"`      // Email плательщика
+	Amount          decimal.Decimal       `json:"amount"`           // Запрошенная сумма
+	PaidAmount      decimal.Decimal       `json:"paid_amount"`      // Оплаченная сумма
+	RemainingAmount decimal.Decimal       `json:"remaining_amount"` // Неоплаченный остаток
+	Memo            string                `json:"memo,omitempty"`   // Назначение платежа
+	DueDate         string                `json:"due_date"`         // Последний день оплаты
+	Status          paymentrequest.Status `json:"status"`           // Статус запроса
+	CreatedAt       string                `json:"created_at"`       // Дата и время создания
+	UpdatedAt       string                `json:"updated_at"`       // Дата и время последнего изменения
+}
+
+// PaymentRequestPaymentResponse представляет оплату по запросу
+type PaymentRequestPaymentResponse struct {
+	Amount    decimal.Decimal `json:"amount"`     // Сумма оплаты
+	CreatedAt string          `json:"created_at"` // Дата и время оплаты
+}
+
+// PaymentRequestDetailsResponse содержит запрос и историю его оплат
+type PaymentRequestDetailsResponse struct {
+	PaymentRequestResponse
+	Payments []PaymentRequestPaymentResponse `json:"payments"` // Оплаты по запросу
+}
+
+// PaymentRequestListResponse представляет список запросов на оплату
+type PaymentRequestListResponse struct {
+	PaymentRequests []PaymentRequestResponse `json:"payment_requests"` // Массив запросов
+}
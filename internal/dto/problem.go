@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"github.com/yujihn/bank_API/internal/pain"
+	"github.com/yujihn/bank_API/internal/validation"
+)
+
+// ProblemResponse представляет ответ с ошибкой в формате RFC 7807 (application/problem+json)
+type ProblemResponse struct {
+	Type     string                  `json:"type"`               // Идентификатор вида ошибки: /problems/<код>
+	Title    string                  `json:"title"`              // Описание ошибки на языке клиента
+	Status   int                     `json:"status"`             // HTTP-статус ответа
+	Detail   string                  `json:"detail,omitempty"`   // Уточнение для конкретного запроса
+	Instance string                  `json:"instance,omitempty"` // Путь запроса
+	Code     string                  `json:"code"`               // Стабильный код ошибки
+	Errors   []validation.FieldError `json:"errors,omitempty"`   // Ошибки по полям
+	Items    []pain.ItemError        `json:"items,omitempty"`    // Ошибки по платежам отклоненного пакета
+}
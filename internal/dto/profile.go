@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// ProfileResponse представляет профиль текущего пользователя
+type ProfileResponse struct {
+	ID            int64              `json:"id"`                       // ID пользователя
+	Email         string             `json:"email"`                    // Электронная почта
+	EmailVerified bool               `json:"email_verified"`           // Email подтвержден
+	Username      string             `json:"username,omitempty"`       // Уникальное имя пользователя
+	FullName      string             `json:"full_name"`                // Имя и фамилия
+	DateOfBirth   string             `json:"date_of_birth,omitempty"`  // Дата рождения в формате YYYY-MM-DD
+	Phone         string             `json:"phone"`                    // Номер телефона
+	Address       string             `json:"address"`                  // Адрес регистрации
+	KYCStatus     string             `json:"kyc_status"`               // Статус проверки личности: UNVERIFIED, PENDING, VERIFIED, REJECTED
+	KYCUpdatedAt  string             `json:"kyc_updated_at,omitempty"` // Дата и время последней смены статуса проверки
+	Limits        *KYCLimitsResponse `json:"limits,omitempty"`         // Лимиты операций; только пока личность не подтверждена
+	CreatedAt     string             `json:"created_at"`               // Дата и время регистрации
+}
+
+// KYCLimitsResponse представляет лимиты расходных операций пользователя без подтвержденной личности
+type KYCLimitsResponse struct {
+	OperationLimit decimal.Decimal `json:"operation_limit"` // Максимальная сумма одной операции
+	MonthlyLimit   decimal.Decimal `json:"monthly_limit"`   // Максимальная сумма операций за календарный месяц
+	MonthlyUsed    decimal.Decimal `json:"monthly_used"`    // Сумма операций с начала месяца
+}
+
+// UpdateProfileRequest представляет запрос на изменение профиля. Непереданные поля не меняются,
+// пустая строка очищает поле
+type UpdateProfileRequest struct {
+	Username    *string `json:"username" binding:"omitempty,username"`  // Имя пользователя: 3–32 латинские буквы, цифры, точки и подчеркивания
+	FullName    *string `json:"full_name" binding:"omitempty,max=255"`  // Имя и фамилия (не длиннее 255 символов)
+	DateOfBirth *string `json:"date_of_birth" binding:"omitempty,date"` // Дата рождения (формат YYYY-MM-DD)
+	Phone       *string `json:"phone" binding:"omitempty,phone"`        // Номер телефона (формат E.164, например +79991234567)
+	Address     *string `json:"address" binding:"omitempty,max=512"`    // Адрес регистрации (не длиннее 512 символов)
+}
+
+// KYCReviewRequest представляет решение администратора по проверке личности
+type KYCReviewRequest struct {
+	Status string `json:"status" binding:"required"` // Решение: VERIFIED или REJECTED (обязательное поле)
+}
+
+// KYCPendingResponse представляет профиль, ожидающий проверки личности
+type KYCPendingResponse struct {
+	UserID      int64  `json:"user_id"`                 // ID пользователя
+	Email       string `json:"email"`                   // Электронная почта
+	FullName    string `json:"full_name"`               // Имя и фамилия
+	DateOfBirth string `json:"date_of_birth,omitempty"` // Дата рождения в формате YYYY-MM-DD
+	Phone       string `json:"phone"`                   // Номер телефона
+	Address     string `json:"address"`                 // Адрес регистрации
+	SubmittedAt string `json:"submitted_at"`            // Дата и время отправки на проверку
+}
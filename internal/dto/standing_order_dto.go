@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/standingorder"
+)
+
+// CreateStandingOrderRequest представляет запрос на создание регулярного или отложенного перевода
+type CreateStandingOrderRequest struct {
+	FromAccountID  int64                   `json:"from_account_id" binding:"required"`                      // ID счета отправителя (обязательное поле)
+	ToAccountID    int64                   `json:"to_account_id" binding:"required"`                        // ID счета получателя (обязательное поле)
+	Amount         decimal.Decimal         `json:"amount" binding:"required,positive"`                      // Сумма перевода (обязательное поле, больше нуля)
+	Frequency      standingorder.Frequency `json:"frequency" binding:"required"`                            // Периодичность: ONCE, DAILY, WEEKLY, MONTHLY (обязательное поле)
+	DayOfMonth     int                     `json:"day_of_month,omitempty" binding:"omitempty,min=1,max=31"` // День месяца для MONTHLY (по умолчанию — день даты начала)
+	StartDate      string                  `json:"start_date" binding:"required,date"`                      // Дата первого исполнения (YYYY-MM-DD, обязательное поле)
+	EndDate        string                  `json:"end_date,omitempty" binding:"omitempty,date"`             // Дата окончания (YYYY-MM-DD, необязательно)
+	MaxOccurrences *int                    `json:"max_occurrences,omitempty" binding:"min=1"`               // Количество исполнений (необязательно)
+	Description    string                  `json:"description,omitempty"`                                   // Назначение перевода
+}
+
+// StandingOrderResponse представляет ответ с информацией о платежном поручении
+type StandingOrderResponse struct {
+	ID                  int64                   `json:"id"`                        // ID поручения
+	FromAccountID       int64                   `json:"from_account_id"`           // ID счета отправителя
+	ToAccountID         int64                   `json:"to_account_id"`             // ID счета получателя
+	Amount              decimal.Decimal         `json:"amount"`                    // Сумма перевода
+	Frequency           standingorder.Frequency `json:"frequency"`                 // Периодичность
+	DayOfMonth          int                     `json:"day_of_month,omitempty"`    // День месяца для MONTHLY
+	StartDate           string                  `json:"start_date"`                // Дата начала
+	EndDate             string                  `json:"end_date,omitempty"`        // Дата окончания
+	MaxOccurrences      *int                    `json:"max_occurrences,omitempty"` // Количество исполнений
+	Occurrences         int                     `json:"occurrences"`               // Обработано дат исполнения
+	NextRunDate         string                  `json:"next_run_date,omitempty"`   // Следующая дата исполнения
+	ConsecutiveFailures int                     `json:"consecutive_failures"`      // Неудачных исполнений подряд
+	Status              standingorder.Status    `json:"status"`                    // Статус поручения
+	Description         string                  `json:"description,omitempty"`     // Назначение перевода
+	CreatedAt           string                  `json:"created_at"`                // Дата и время создания
+}
+
+// StandingOrderExecutionResponse представляет исполнение поручения за одну дату
+type StandingOrderExecutionResponse struct {
+	ScheduledDate string                        `json:"scheduled_date"`  // Дата исполнения по расписанию
+	Amount        decimal.Decimal               `json:"amount"`          // Сумма перевода
+	Status        standingorder.ExecutionStatus `json:"status"`          // Результат исполнения
+	Error         string                        `json:"error,omitempty"` // Описание ошибки
+	Attempts      int                           `json:"attempts"`        // Количество попыток перевода
+	CreatedAt     string                        `json:"created_at"`      // Дата и время первой попытки
+}
+
+// StandingOrderDetailsResponse содержит поручение и историю его исполнения
+type StandingOrderDetailsResponse struct {
+	StandingOrderResponse
+	Executions []StandingOrderExecutionResponse `json:"executions"` // История исполнения
+}
+
+// StandingOrderListResponse представляет список платежных поручений
+type StandingOrderListResponse struct {
+	StandingOrders []StandingOrderResponse `json:"standing_orders"` // Массив поручений
+}
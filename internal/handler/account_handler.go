@@ -2,27 +2,34 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
 	"github.com/yujihn/bank_API/internal/dto"
 	"github.com/yujihn/bank_API/internal/middleware"
 	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/problem"
 	"github.com/yujihn/bank_API/internal/service"
 )
 
 type AccountHandler struct {
-	accountService *service.AccountService
-	logger         *logrus.Logger
+	accountService   *service.AccountService
+	overdraftService *service.OverdraftService
+	mfaService       *service.MFAService
+	logger           *logrus.Logger
 }
 
-func NewAccountHandler(accountService *service.AccountService, logger *logrus.Logger) *AccountHandler {
+func NewAccountHandler(accountService *service.AccountService, overdraftService *service.OverdraftService,
+	mfaService *service.MFAService, logger *logrus.Logger) *AccountHandler {
 	return &AccountHandler{
-		accountService: accountService,
-		logger:         logger,
+		accountService:   accountService,
+		overdraftService: overdraftService,
+		mfaService:       mfaService,
+		logger:           logger,
 	}
 }
 
@@ -31,42 +38,30 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	// Получаем userID из контекста (установлен middleware)
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
 	// Декодируем запрос
 	var req dto.CreateAccountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("Ошибка декодирования запроса: %v", err)
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
 		return
 	}
 
-	// Проверяем валютный код (пока только RUB)
-	if req.Currency != account.RUB {
-		h.logger.Warnf("Попытка создать счет в неподдерживаемой валюте: %s", req.Currency)
-		http.Error(w, "Поддерживается только валюта RUB", http.StatusBadRequest)
-		return
+	// Тип счета по умолчанию — текущий
+	if req.Type == "" {
+		req.Type = account.CURRENT
 	}
 
 	// Создаем счет
-	newAccount, err := h.accountService.CreateAccount(r.Context(), userID, req.Currency)
+	newAccount, err := h.accountService.CreateAccount(r.Context(), userID, req.Currency, req.Type)
 	if err != nil {
-		h.logger.Errorf("Ошибка создания счета: %v", err)
-		http.Error(w, "Не удалось создать счет", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
 	// Формируем ответ
-	resp := dto.AccountResponse{
-		ID:        newAccount.ID,
-		UserID:    newAccount.UserID,
-		Balance:   newAccount.Balance,
-		Currency:  newAccount.Currency,
-		CreatedAt: newAccount.CreatedAt.Format("2006-01-02T15:04:05Z"),
-	}
+	resp := toAccountResponse(newAccount)
 
 	// Отправляем ответ
 	w.Header().Set("Content-Type", "application/json")
@@ -81,32 +76,44 @@ func (h *AccountHandler) GetAccounts(w http.ResponseWriter, r *http.Request) {
 	// Получаем userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
 	// Получаем счета
 	accounts, err := h.accountService.GetAccountsByUserID(r.Context(), userID)
 	if err != nil {
-		h.logger.Errorf("Ошибка получения счетов: %v", err)
-		http.Error(w, "Не удалось получить счета", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Проценты по овердрафту с начала месяца
+	charged, err := h.overdraftService.GetMonthCharges(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
 	// Формируем ответ
 	resp := dto.AccountsListResponse{
 		Accounts: make([]dto.AccountResponse, 0, len(accounts)),
+		Overdraft: dto.OverdraftSummary{
+			TotalLimit:        decimal.Zero,
+			TotalUsed:         decimal.Zero,
+			TotalAvailable:    decimal.Zero,
+			Rate:              h.overdraftService.Rate(),
+			InterestThisMonth: decimal.Zero,
+		},
 	}
 
 	for _, acc := range accounts {
-		resp.Accounts = append(resp.Accounts, dto.AccountResponse{
-			ID:        acc.ID,
-			UserID:    acc.UserID,
-			Balance:   acc.Balance,
-			Currency:  acc.Currency,
-			CreatedAt: acc.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		resp.Accounts = append(resp.Accounts, toAccountResponse(acc))
+
+		summary := &resp.Overdraft
+		summary.TotalLimit = summary.TotalLimit.Add(acc.OverdraftLimit)
+		summary.TotalUsed = summary.TotalUsed.Add(acc.OverdraftUsed())
+		summary.TotalAvailable = summary.TotalAvailable.Add(decimal.Max(acc.OverdraftLimit.Sub(acc.OverdraftUsed()), decimal.Zero))
+		summary.InterestThisMonth = summary.InterestThisMonth.Add(charged[acc.ID])
 	}
 
 	// Отправляем ответ
@@ -121,8 +128,7 @@ func (h *AccountHandler) UpdateBalance(w http.ResponseWriter, r *http.Request) {
 	// Получаем userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
@@ -130,49 +136,74 @@ func (h *AccountHandler) UpdateBalance(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	accountID, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		h.logger.Warnf("Неверный формат ID счета: %v", err)
-		http.Error(w, "Неверный ID счета", http.StatusBadRequest)
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
 		return
 	}
 
 	// Декодируем запрос
 	var req dto.UpdateBalanceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("Ошибка декодирования запроса: %v", err)
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Списание крупной суммы требует свежего подтверждения кодом
+	if req.Amount.IsNegative() && !requireMFA(w, r, h.mfaService, h.logger, req.Amount) {
 		return
 	}
 
 	// Обновляем баланс
 	err = h.accountService.UpdateBalance(r.Context(), accountID, userID, req.Amount)
 	if err != nil {
-		// Определяем тип ошибки для возврата подходящего HTTP-статуса
-		switch {
-		case errors.Is(err, service.ErrInsufficientFunds):
-			h.logger.Warnf("Недостаточно средств для операции: %v", err)
-			http.Error(w, "Недостаточно средств", http.StatusBadRequest)
-		default:
-			h.logger.Errorf("Ошибка обновления баланса: %v", err)
-			http.Error(w, "Не удалось обновить баланс", http.StatusInternalServerError)
-		}
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
 		return
 	}
 
 	// Получаем обновленный счет для ответа
 	updatedAccount, err := h.accountService.GetAccountByID(r.Context(), accountID, userID)
 	if err != nil {
-		h.logger.Errorf("Ошибка получения обновленного счета: %v", err)
-		http.Error(w, "Операция выполнена, но не удалось получить данные счета", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
 	// Формируем ответ
-	resp := dto.AccountResponse{
-		ID:        updatedAccount.ID,
-		UserID:    updatedAccount.UserID,
-		Balance:   updatedAccount.Balance,
-		Currency:  updatedAccount.Currency,
-		CreatedAt: updatedAccount.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	resp := toAccountResponse(updatedAccount)
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetOverdraft обработчик для просмотра лимита и использования овердрафта по счету.
+// Лимит устанавливает администратор (PUT /admin/accounts/{id}/overdraft)
+func (h *AccountHandler) GetOverdraft(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	acc, err := h.accountService.GetAccountByID(r.Context(), accountID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	resp := dto.OverdraftResponse{
+		AccountID: acc.ID,
+		Limit:     acc.OverdraftLimit,
+		Used:      acc.OverdraftUsed(),
+		Available: acc.Available(),
+		Rate:      h.overdraftService.Rate(),
 	}
 
 	// Отправляем ответ
@@ -187,37 +218,25 @@ func (h *AccountHandler) Transfer(w http.ResponseWriter, r *http.Request) {
 	// Получаем userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
 	// Декодируем запрос
 	var req dto.TransferRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("Ошибка декодирования запроса: %v", err)
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Перевод крупной суммы требует свежего подтверждения кодом
+	if !requireMFA(w, r, h.mfaService, h.logger, req.Amount) {
 		return
 	}
 
 	// Выполняем перевод
 	err = h.accountService.Transfer(r.Context(), req.FromAccountID, req.ToAccountID, userID, req.Amount)
 	if err != nil {
-		// Определяем тип ошибки
-		switch {
-		case errors.Is(err, service.ErrInsufficientFunds):
-			h.logger.Warnf("Недостаточно средств для перевода: %v", err)
-			http.Error(w, "Недостаточно средств", http.StatusBadRequest)
-		case errors.Is(err, service.ErrSameAccount):
-			h.logger.Warnf("Попытка перевода на тот же счет: %v", err)
-			http.Error(w, "Нельзя переводить на тот же счет", http.StatusBadRequest)
-		case errors.Is(err, service.ErrNegativeAmount):
-			h.logger.Warnf("Попытка перевода отрицательной суммы: %v", err)
-			http.Error(w, "Сумма перевода должна быть положительной", http.StatusBadRequest)
-		default:
-			h.logger.Errorf("Ошибка выполнения перевода: %v", err)
-			http.Error(w, "Не удалось выполнить перевод", http.StatusInternalServerError)
-		}
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
 		return
 	}
 
@@ -234,8 +253,7 @@ func (h *AccountHandler) GetTransactions(w http.ResponseWriter, r *http.Request)
 	// Получаем userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
@@ -243,16 +261,14 @@ func (h *AccountHandler) GetTransactions(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	accountID, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		h.logger.Warnf("Неверный формат ID счета: %v", err)
-		http.Error(w, "Неверный ID счета", http.StatusBadRequest)
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
 		return
 	}
 
 	// Получаем транзакции
 	transactions, err := h.accountService.GetTransactionsByAccountID(r.Context(), accountID, userID)
 	if err != nil {
-		h.logger.Errorf("Ошибка получения транзакций: %v", err)
-		http.Error(w, "Не удалось получить транзакции", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
 		return
 	}
 
@@ -278,3 +294,19 @@ func (h *AccountHandler) GetTransactions(w http.ResponseWriter, r *http.Request)
 		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
 	}
 }
+
+// toAccountResponse формирует ответ с информацией о счете
+func toAccountResponse(acc *account.Account) dto.AccountResponse {
+	return dto.AccountResponse{
+		ID:             acc.ID,
+		UserID:         acc.UserID,
+		Type:           acc.Type,
+		Balance:        acc.Balance,
+		OverdraftLimit: acc.OverdraftLimit,
+		OverdraftUsed:  acc.OverdraftUsed(),
+		Available:      acc.Available(),
+		Restricted:     acc.Restricted,
+		Currency:       acc.Currency,
+		CreatedAt:      acc.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/application"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// AdminHandler обрабатывает запросы администраторов
+type AdminHandler struct {
+	overdraftService   *service.OverdraftService         // Сервис овердрафтов
+	applicationService *service.CreditApplicationService // Сервис кредитных заявок
+	loginGuard         *service.LoginGuardService        // Сервис защиты входа от подбора пароля
+	profileService     *service.ProfileService           // Сервис профиля и проверки личности
+	logger             *logrus.Logger                    // Логгер для логирования событий
+}
+
+// NewAdminHandler создает новый обработчик запросов администраторов
+func NewAdminHandler(overdraftService *service.OverdraftService, applicationService *service.CreditApplicationService,
+	loginGuard *service.LoginGuardService, profileService *service.ProfileService, logger *logrus.Logger) *AdminHandler {
+	return &AdminHandler{
+		overdraftService:   overdraftService,
+		applicationService: applicationService,
+		loginGuard:         loginGuard,
+		profileService:     profileService,
+		logger:             logger,
+	}
+}
+
+// SetOverdraftLimit обрабатывает запрос на установку лимита овердрафта по текущему счету клиента
+// @Summary Установка лимита овердрафта
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param request body dto.SetOverdraftRequest true "Лимит овердрафта (0 — отключить)"
+// @Success 200 {object} dto.AccountResponse "Счет с новым лимитом"
+// @Failure 400 {object} dto.ProblemResponse "Неверный лимит или тип счета"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Failure 404 {object} dto.ProblemResponse "Счет не найден"
+// @Failure 409 {object} dto.ProblemResponse "Лимит меньше использованной суммы овердрафта"
+// @Router /admin/accounts/{id}/overdraft [put]
+func (h *AdminHandler) SetOverdraftLimit(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.SetOverdraftRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	acc, err := h.overdraftService.SetLimit(r.Context(), accountID, req.Limit)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"admin_id": adminID, "account_id": accountID, "limit": req.Limit}).Info("Установлен лимит овердрафта")
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toAccountResponse(acc)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditApplications обрабатывает запрос списка кредитных заявок в статусе
+// @Summary Кредитные заявки по статусу
+// @Tags admin
+// @Produce json
+// @Param status query string false "Статус заявки (по умолчанию MANUAL_REVIEW)"
+// @Success 200 {object} dto.CreditApplicationListResponse "Заявки, начиная с самых ранних"
+// @Failure 400 {object} dto.ProblemResponse "Неизвестный статус"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Router /admin/credit-applications [get]
+func (h *AdminHandler) GetCreditApplications(w http.ResponseWriter, r *http.Request) {
+	status := application.Status(r.URL.Query().Get("status"))
+	applications, err := h.applicationService.GetApplicationsByStatus(r.Context(), status)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	resp := dto.CreditApplicationListResponse{Applications: make([]dto.CreditApplicationResponse, 0, len(applications))}
+	for _, a := range applications {
+		resp.Applications = append(resp.Applications, toCreditApplicationResponse(a))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// DecideCreditApplication обрабатывает ручное решение по кредитной заявке на ручной проверке
+// @Summary Решение по кредитной заявке
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID заявки"
+// @Param request body dto.CreditApplicationDecisionRequest true "Решение APPROVED/REJECTED и обоснование"
+// @Success 200 {object} dto.CreditApplicationResponse "Заявка с решением"
+// @Failure 400 {object} dto.ProblemResponse "Некорректное решение"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Failure 404 {object} dto.ProblemResponse "Заявка не найдена"
+// @Failure 409 {object} dto.ProblemResponse "Заявка не ожидает ручной проверки"
+// @Router /admin/credit-applications/{id}/decision [post]
+func (h *AdminHandler) DecideCreditApplication(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID заявки из URL
+	applicationID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID заявки"))
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.CreditApplicationDecisionRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	decided, err := h.applicationService.Decide(r.Context(), applicationID, adminID, req.Status, req.Reason)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toCreditApplicationResponse(decided)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetLoginLockouts обрабатывает запрос списка действующих ограничений входа
+// @Summary Действующие ограничения входа
+// @Tags admin
+// @Produce json
+// @Success 200 {array} dto.LoginLockoutResponse "Ограничения по email и IP-адресам"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Router /admin/login-lockouts [get]
+func (h *AdminHandler) GetLoginLockouts(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.loginGuard.GetBlocked(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	now := time.Now()
+	response := make([]dto.LoginLockoutResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, dto.LoginLockoutResponse{
+			Scope:         string(e.Scope),
+			Key:           e.Key,
+			Failures:      e.Failures,
+			LastFailureAt: e.LastFailureAt.UTC().Format("2006-01-02T15:04:05Z"),
+			BlockedUntil:  e.BlockedUntil.UTC().Format("2006-01-02T15:04:05Z"),
+			Locked:        e.Locked(now),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// UnlockLogin обрабатывает запрос на снятие ограничений входа по email и (или) IP-адресу
+// @Summary Снятие ограничений входа
+// @Tags admin
+// @Accept json
+// @Param request body dto.UnlockLoginRequest true "Email и (или) IP-адрес"
+// @Success 204 "Ограничения сняты"
+// @Failure 400 {object} dto.ProblemResponse "Не указаны email и IP-адрес"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Failure 404 {object} dto.ProblemResponse "Ограничений нет"
+// @Router /admin/login-lockouts/unlock [post]
+func (h *AdminHandler) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.UnlockLoginRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	unlocked, err := h.loginGuard.Unlock(r.Context(), req.Email, strings.TrimSpace(req.IP))
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+	if !unlocked {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: ограничений входа нет", apperr.ErrNotFound))
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"admin_id": adminID, "email": req.Email, "ip": req.IP}).Info("Ограничения входа сняты")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPendingKYC обрабатывает запрос списка профилей, ожидающих проверки личности
+// @Summary Профили на проверке личности
+// @Tags admin
+// @Produce json
+// @Success 200 {array} dto.KYCPendingResponse "Профили в порядке отправки на проверку"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Router /admin/kyc [get]
+func (h *AdminHandler) GetPendingKYC(w http.ResponseWriter, r *http.Request) {
+	users, err := h.profileService.GetPendingKYC(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	response := make([]dto.KYCPendingResponse, 0, len(users))
+	for _, u := range users {
+		item := dto.KYCPendingResponse{
+			UserID:   u.ID,
+			Email:    u.Email,
+			FullName: u.FullName,
+			Phone:    u.Phone,
+			Address:  u.Address,
+		}
+		if u.DateOfBirth != nil {
+			item.DateOfBirth = u.DateOfBirth.Format("2006-01-02")
+		}
+		if u.KYCUpdatedAt != nil {
+			item.SubmittedAt = u.KYCUpdatedAt.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		response = append(response, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// ReviewKYC обрабатывает решение администратора по проверке личности пользователя
+// @Summary Решение по проверке личности
+// @Tags admin
+// @Accept json
+// @Param id path int true "ID пользователя"
+// @Param request body dto.KYCReviewRequest true "Решение: VERIFIED или REJECTED"
+// @Success 204 "Решение сохранено"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Недостаточно прав"
+// @Failure 404 {object} dto.ProblemResponse "Пользователь не найден"
+// @Failure 409 {object} dto.ProblemResponse "Профиль не на проверке"
+// @Router /admin/users/{id}/kyc [post]
+func (h *AdminHandler) ReviewKYC(w http.ResponseWriter, r *http.Request) {
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID пользователя"))
+		return
+	}
+
+	var req dto.KYCReviewRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	decision := models.KYCStatus(strings.ToUpper(strings.TrimSpace(req.Status)))
+	if err := h.profileService.ReviewKYC(r.Context(), userID, decision); err != nil {
+		problem.Write(w, r, h.logger, notFound(err, repository.ErrUserNotFound))
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"admin_id": adminID, "user_id": userID, "status": decision}).Info("Решение по проверке личности")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,24 +3,36 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
 	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/session"
+	"github.com/yujihn/bank_API/internal/problem"
 	"github.com/yujihn/bank_API/internal/service"
 )
 
 // AuthHandler обрабатывает HTTP-запросы, связанные с аутентификацией и регистрацией
 type AuthHandler struct {
-	authService service.AuthService // Сервис для работы с аутентификацией
-	logger      *logrus.Logger      // Логгер для логирования событий
+	authService    service.AuthService // Сервис для работы с аутентификацией
+	trustedProxies []netip.Prefix      // Подсети прокси, которым доверяется X-Forwarded-For
+	logger         *logrus.Logger      // Логгер для логирования событий
 }
 
 // NewAuthHandler создает новый экземпляр обработчика для аутентификации
-func NewAuthHandler(authService service.AuthService, logger *logrus.Logger) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, proxyCfg config.ProxyConfig, logger *logrus.Logger) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		logger:      logger,
+		authService:    authService,
+		trustedProxies: proxyCfg.TrustedProxies,
+		logger:         logger,
 	}
 }
 
@@ -32,31 +44,22 @@ func NewAuthHandler(authService service.AuthService, logger *logrus.Logger) *Aut
 // @Produce json
 // @Param request body dto.RegisterRequest true "Данные для регистрации"
 // @Success 201 {string} string "Пользователь успешно зарегистрирован"
-// @Failure 400 {string} string "Ошибка валидации данных"
-// @Failure 409 {string} string "Пользователь с таким email или username уже существует"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 409 {object} dto.ProblemResponse "Пользователь с таким email или username уже существует"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
 // @Router /register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req dto.RegisterRequest
 
 	// Декодирование тела запроса
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.WithError(err).Warn("Ошибка декодирования запроса регистрации")
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
 		return
 	}
 
 	// Попытка зарегистрировать пользователя
 	userID, err := h.authService.Register(r.Context(), req)
 	if err != nil {
-		h.logger.WithError(err).Warn("Ошибка при регистрации пользователя")
-
-		if errors.Is(err, service.ErrUserExists) {
-			http.Error(w, "Пользователь с таким email или username уже существует", http.StatusConflict)
-			return
-		}
-
-		http.Error(w, "Ошибка при регистрации пользователя", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
@@ -70,61 +73,277 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	// Отправка ответа
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.WithError(err).Error("Ошибка при формировании ответа")
-		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-		return
 	}
 }
 
 // Login обрабатывает HTTP-запрос на вход в систему
 // @Summary Вход в систему
-// @Description Аутентифицирует пользователя и возвращает JWT токен
+// @Description Аутентифицирует пользователя, создает сессию и возвращает access- и refresh-токены.
+// @Description Если включена двухфакторная аутентификация, возвращает MFA-токен для POST /login/mfa
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Данные для входа"
-// @Success 200 {object} dto.AuthResponse "JWT токен"
-// @Failure 400 {string} string "Ошибка валидации данных"
-// @Failure 401 {string} string "Неверные учетные данные"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Success 200 {object} dto.AuthResponse "Пара токенов"
+// @Success 202 {object} dto.MFAChallengeResponse "Требуется код двухфакторной аутентификации"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Неверные учетные данные"
+// @Failure 429 {object} dto.ProblemResponse "Слишком много неудачных попыток; заголовок Retry-After"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
 // @Router /login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req dto.LoginRequest
 
 	// Декодирование тела запроса
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.WithError(err).Warn("Ошибка декодирования запроса авторизации")
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Аутентификация и создание сессии
+	result, err := h.authService.Login(r.Context(), req, h.clientInfo(r))
+	if err != nil {
+		var blocked *service.LoginBlockedError
+		if errors.As(err, &blocked) {
+			setRetryAfter(w, blocked.Until)
+		}
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
-	// Проверка обязательных полей
-	if req.Email == "" || req.Password == "" {
-		http.Error(w, "Email и пароль обязательны", http.StatusBadRequest)
+	if result.Challenge != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		response := dto.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.Challenge.Token,
+			ExpiresAt:   result.Challenge.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			h.logger.WithError(err).Error("Ошибка при формировании ответа авторизации")
+		}
 		return
 	}
 
-	// Аутентификация и получение JWT
-	token, err := h.authService.Login(r.Context(), req)
-	if err != nil {
-		h.logger.WithError(err).Warn("Ошибка при авторизации пользователя")
+	h.writeTokens(w, result.Tokens)
+}
+
+// LoginMFA обрабатывает HTTP-запрос на второй шаг входа
+// @Summary Второй шаг входа
+// @Description Обменивает MFA-токен и код из приложения-аутентификатора или код восстановления на пару токенов.
+// @Description После нескольких неверных кодов токен перестает действовать
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginMFARequest true "MFA-токен и код"
+// @Success 200 {object} dto.AuthResponse "Пара токенов"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Неверный код или просроченный MFA-токен"
+// @Failure 429 {object} dto.ProblemResponse "Ввод кодов временно заблокирован"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /login/mfa [post]
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginMFARequest
+
+	// Декодирование тела запроса
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
 
-		if errors.Is(err, service.ErrInvalidCredentials) {
-			http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
-			return
+	tokens, err := h.authService.CompleteMFA(r.Context(), req.MFAToken, req.Code, h.clientInfo(r))
+	if err != nil {
+		// Отключение 2FA между шагами входа не раскрывается: для клиента это неверный код
+		if errors.Is(err, service.ErrMFANotEnabled) {
+			err = service.ErrInvalidMFACode
 		}
+		mfaLocked(w, err)
+		problem.Write(w, r, h.logger, err)
+		return
+	}
 
-		http.Error(w, "Ошибка авторизации", http.StatusInternalServerError)
+	h.writeTokens(w, tokens)
+}
+
+// Refresh обрабатывает HTTP-запрос на обновление пары токенов
+// @Summary Обновление токенов
+// @Description Погашает refresh-токен и выдает новую пару токенов в той же сессии.
+// @Description Повторное предъявление погашенного refresh-токена завершает сессию
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} dto.AuthResponse "Новая пара токенов"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Неверный, просроченный или повторно использованный refresh-токен"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /token/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+
+	// Декодирование тела запроса
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken, h.clientInfo(r))
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
-	// Формирование и отправка ответа с токеном
+	h.writeTokens(w, tokens)
+}
+
+// Logout обрабатывает HTTP-запрос на выход: завершает сессию, в которой выпущен access-токен, и отзывает токен
+// @Summary Выход из системы
+// @Tags auth
+// @Success 204 "Сессия завершена"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 404 {object} dto.ProblemResponse "Сессия не найдена или уже завершена"
+// @Router /logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, err := middleware.GetClaims(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), claims); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSessions обрабатывает HTTP-запрос на получение активных сессий пользователя
+// @Summary Активные сессии
+// @Tags auth
+// @Produce json
+// @Success 200 {array} dto.SessionResponse "Сессии, начиная с последних использованных"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /sessions [get]
+func (h *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+	currentID, _ := middleware.GetSessionID(r.Context())
+
+	sessions, err := h.authService.GetSessions(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	response := make([]dto.SessionResponse, 0, len(sessions))
+	for _, sess := range sessions {
+		response = append(response, toSessionResponse(sess, currentID))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// RevokeSession обрабатывает HTTP-запрос на завершение сессии, например на потерянном устройстве
+// @Summary Завершение сессии
+// @Tags auth
+// @Param id path int true "ID сессии"
+// @Success 204 "Сессия завершена"
+// @Failure 400 {object} dto.ProblemResponse "Неверный ID сессии"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 404 {object} dto.ProblemResponse "Сессия не найдена или уже завершена"
+// @Router /sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID сессии"))
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTokens отправляет пару токенов в ответе
+func (h *AuthHandler) writeTokens(w http.ResponseWriter, tokens *service.TokenPair) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	response := dto.AuthResponse{Token: token}
+	response := dto.AuthResponse{
+		Token:            tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(tokens.ExpiresIn.Seconds()),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+		SessionID:        tokens.SessionID,
+	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.WithError(err).Error("Ошибка при формировании ответа авторизации")
-		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-		return
+	}
+}
+
+// clientInfo извлекает User-Agent и IP-адрес клиента. X-Forwarded-For учитывается, только если запрос пришел
+// от доверенного прокси: адреса просматриваются справа налево, и адресом клиента считается первый
+// недоверенный. Иначе адресом клиента считается адрес соединения
+func (h *AuthHandler) clientInfo(r *http.Request) service.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err == nil && h.trusted(addr) {
+		hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// Адрес добавлен не доверенным прокси: левее доверять нечему
+				break
+			}
+			ip = hop.Unmap().String()
+			if !h.trusted(hop) {
+				break
+			}
+		}
+	}
+	return service.ClientInfo{UserAgent: r.UserAgent(), IP: ip}
+}
+
+// trusted сообщает, входит ли адрес в подсети доверенных прокси
+func (h *AuthHandler) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// toSessionResponse преобразует сессию в DTO; currentID — сессия текущего запроса
+func toSessionResponse(s *session.Session, currentID int64) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		LastIP:     s.LastIP,
+		CreatedAt:  s.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		LastUsedAt: s.LastUsedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		ExpiresAt:  s.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Current:    s.ID == currentID,
 	}
 }
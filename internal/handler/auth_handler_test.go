@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yujihn/bank_API/internal/config"
+)
+
+// TestClientInfoTrustedProxies проверяет, что X-Forwarded-For учитывается только от доверенного прокси
+// и адресом клиента считается самый правый недоверенный адрес
+func TestClientInfoTrustedProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.5, garbage")
+	h := NewAuthHandler(nil, config.LoadProxy(), nil)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"без прокси", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"подделка от недоверенного адреса", "203.0.113.7:5000", []string{"1.1.1.1"}, "203.0.113.7"},
+		{"один доверенный прокси", "10.0.0.2:443", []string{"198.51.100.4"}, "198.51.100.4"},
+		{"подделка слева от прокси", "10.0.0.2:443", []string{"1.1.1.1, 198.51.100.4"}, "198.51.100.4"},
+		{"цепочка доверенных прокси", "10.0.0.2:443", []string{"1.1.1.1, 198.51.100.4, 192.168.1.5, 10.1.2.3"}, "198.51.100.4"},
+		{"несколько заголовков", "10.0.0.2:443", []string{"1.1.1.1", "198.51.100.4"}, "198.51.100.4"},
+		{"мусор в заголовке", "10.0.0.2:443", []string{"198.51.100.4, not-an-ip"}, "10.0.0.2"},
+		{"пустой заголовок", "10.0.0.2:443", nil, "10.0.0.2"},
+		{"IPv4 в IPv6", "[::ffff:10.0.0.2]:443", []string{"198.51.100.4"}, "198.51.100.4"},
+		{"только доверенные адреса", "10.0.0.2:443", []string{"10.9.9.9"}, "10.9.9.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := h.clientInfo(r).IP; got != tt.want {
+				t.Errorf("IP = %s, ожидается %s", got, tt.want)
+			}
+		})
+	}
+}
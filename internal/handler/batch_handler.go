@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/batch"
+	"github.com/yujihn/bank_API/internal/pain"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// maxBatchFileSize ограничивает размер загружаемого файла пакета
+const maxBatchFileSize = 10 << 20
+
+// BatchHandler обрабатывает запросы на пакетные платежи
+type BatchHandler struct {
+	batchService *service.BatchService // Сервис пакетных платежей
+	mfaService   *service.MFAService   // Сервис двухфакторной аутентификации
+	logger       *logrus.Logger        // Логгер для логирования событий
+}
+
+// NewBatchHandler создает новый обработчик пакетных платежей
+func NewBatchHandler(batchService *service.BatchService, mfaService *service.MFAService, logger *logrus.Logger) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+		mfaService:   mfaService,
+		logger:       logger,
+	}
+}
+
+// SubmitBatch принимает файл пакета переводов: XML pain.001.001.03 (Content-Type: application/xml)
+// или CSV (Content-Type: text/csv, счет списания передается параметром from_account_id)
+func (h *BatchHandler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxBatchFileSize)
+
+	// Определяем формат файла по заголовку Content-Type
+	var (
+		in     *pain.Instructions
+		format string
+	)
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "xml"):
+		format = pain.FormatPain001
+		in, err = pain.ParsePain001(body)
+	case strings.Contains(contentType, "csv"):
+		fromID, parseErr := strconv.ParseInt(r.URL.Query().Get("from_account_id"), 10, 64)
+		if parseErr != nil {
+			problem.Write(w, r, h.logger, invalidParam("для CSV требуется параметр from_account_id"))
+			return
+		}
+		format = pain.FormatCSV
+		in, err = pain.ParseCSV(body, fromID)
+	default:
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются файлы application/xml (pain.001) и text/csv", apperr.ErrUnsupportedMedia))
+		return
+	}
+	if err != nil {
+		h.writeBatchError(w, r, err)
+		return
+	}
+
+	// Пакет на крупную общую сумму требует свежего подтверждения кодом
+	if !requireMFA(w, r, h.mfaService, h.logger, in.Total()) {
+		return
+	}
+
+	// Проверяем и ставим пакет в очередь на исполнение
+	b, items, err := h.batchService.Submit(r.Context(), userID, format, in)
+	if err != nil {
+		h.writeBatchError(w, r, err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"batch_id": b.ID, "items": b.ItemCount}).Info("Пакет платежей принят")
+
+	// Отправляем ответ: пакет принят и исполняется асинхронно
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/api/payments/batch/%d", b.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(toBatchResponse(b, items)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetBatch возвращает статус пакета и каждого платежа
+func (h *BatchHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	b, items, ok := h.loadBatch(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toBatchResponse(b, items)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetBatchReport возвращает отчет о статусе пакета в формате ISO 20022 pain.002
+func (h *BatchHandler) GetBatchReport(w http.ResponseWriter, r *http.Request) {
+	b, items, ok := h.loadBatch(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	if err := pain.WritePain002(w, b, items); err != nil {
+		h.logger.Errorf("Ошибка формирования отчета pain.002: %v", err)
+	}
+}
+
+// loadBatch получает пакет из URL с проверкой владения и пишет ошибку в ответ при неудаче
+func (h *BatchHandler) loadBatch(w http.ResponseWriter, r *http.Request) (*batch.Batch, []*batch.Item, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return nil, nil, false
+	}
+
+	batchID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID пакета"))
+		return nil, nil, false
+	}
+
+	b, items, err := h.batchService.GetBatch(r.Context(), batchID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return nil, nil, false
+	}
+	return b, items, true
+}
+
+// writeBatchError формирует ответ для ошибок разбора и проверки пакета
+func (h *BatchHandler) writeBatchError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		tooLong   *http.MaxBytesError
+		duplicate *service.DuplicateBatchError
+	)
+	switch {
+	// Превышение размера обнаруживается при чтении и приходит обернутым в ошибку разбора
+	case errors.As(err, &tooLong):
+		err = fmt.Errorf("%w: файл пакета больше %d байт", apperr.ErrTooLarge, tooLong.Limit)
+	// При повторной загрузке клиент получает ссылку на ранее принятый пакет
+	case errors.As(err, &duplicate):
+		w.Header().Set("Location", fmt.Sprintf("/api/payments/batch/%d", duplicate.BatchID))
+	}
+	problem.Write(w, r, h.logger, err)
+}
+
+// toBatchResponse формирует ответ со статусами пакета и платежей
+func toBatchResponse(b *batch.Batch, items []*batch.Item) dto.BatchResponse {
+	resp := dto.BatchResponse{
+		ID:            b.ID,
+		FromAccountID: b.FromAccountID,
+		MessageID:     b.MessageID,
+		SourceFormat:  b.SourceFormat,
+		ItemCount:     b.ItemCount,
+		TotalAmount:   b.TotalAmount,
+		Status:        b.Status,
+		CreatedAt:     b.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		Items:         make([]dto.BatchItemResponse, 0, len(items)),
+	}
+	if b.CompletedAt != nil {
+		resp.CompletedAt = b.CompletedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	for _, item := range items {
+		itemResp := dto.BatchItemResponse{
+			ID:          item.ID,
+			EndToEndID:  item.EndToEndID,
+			ToAccountID: item.ToAccountID,
+			Amount:      item.Amount,
+			Status:      item.Status,
+			Error:       item.Error,
+		}
+		if item.ExecutedAt != nil {
+			itemResp.ExecutedAt = item.ExecutedAt.Format("2006-01-02T15:04:05Z")
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+	return resp
+}
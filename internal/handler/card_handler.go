@@ -2,25 +2,31 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
 	"github.com/yujihn/bank_API/internal/dto"
 	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
 	"github.com/yujihn/bank_API/internal/service"
 )
 
 type CardHandler struct {
 	cardService *service.CardService
+	mfaService  *service.MFAService
 	logger      *logrus.Logger
 }
 
-func NewCardHandler(cardService *service.CardService, logger *logrus.Logger) *CardHandler {
+func NewCardHandler(cardService *service.CardService, mfaService *service.MFAService, logger *logrus.Logger) *CardHandler {
 	return &CardHandler{
 		cardService: cardService,
+		mfaService:  mfaService,
 		logger:      logger,
 	}
 }
@@ -30,31 +36,20 @@ func (h *CardHandler) CreateCard(w http.ResponseWriter, r *http.Request) {
 	// Получение ID пользователя из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
 	// Декодирование тела запроса
 	var req dto.CreateCardRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("Ошибка декодирования запроса: %v", err)
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
-		return
-	}
-
-	// Проверка наличия PGP ключа
-	if req.PGPKey == "" {
-		h.logger.Warn("Отсутствует PGP ключ")
-		http.Error(w, "PGP ключ обязателен", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
 		return
 	}
 
 	// Создание карты
 	card, cardDetails, err := h.cardService.CreateCard(r.Context(), userID, req.PGPKey)
 	if err != nil {
-		h.logger.Errorf("Ошибка создания карты: %v", err)
-		http.Error(w, "Не удалось создать карту", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
@@ -81,16 +76,14 @@ func (h *CardHandler) GetCards(w http.ResponseWriter, r *http.Request) {
 	// Получение userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
 	// Получение списка карт пользователя
 	cards, err := h.cardService.GetUserCards(r.Context(), userID)
 	if err != nil {
-		h.logger.Errorf("Ошибка получения списка карт: %v", err)
-		http.Error(w, "Не удалось получить список карт", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
@@ -103,6 +96,7 @@ func (h *CardHandler) GetCards(w http.ResponseWriter, r *http.Request) {
 		resp.Cards = append(resp.Cards, dto.CardResponse{
 			ID:        card.ID,
 			UserID:    card.UserID,
+			Blocked:   card.BlockedAt != nil,
 			CreatedAt: card.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		})
 	}
@@ -119,8 +113,7 @@ func (h *CardHandler) GetCardDetails(w http.ResponseWriter, r *http.Request) {
 	// Получение userID из контекста
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
-		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
-		http.Error(w, "Ошибка авторизации", http.StatusUnauthorized)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
 		return
 	}
 
@@ -128,24 +121,21 @@ func (h *CardHandler) GetCardDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	cardID, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		h.logger.Warnf("Неверный формат ID карты: %v", err)
-		http.Error(w, "Неверный ID карты", http.StatusBadRequest)
+		problem.Write(w, r, h.logger, invalidParam("неверный ID карты"))
 		return
 	}
 
 	// Получение PGP ключа из параметров запроса
 	pgpKey := r.URL.Query().Get("pgp_key")
 	if pgpKey == "" {
-		h.logger.Warn("Отсутствует PGP ключ в запросе")
-		http.Error(w, "PGP ключ обязателен", http.StatusBadRequest)
+		problem.Write(w, r, h.logger, invalidParam("PGP ключ обязателен"))
 		return
 	}
 
 	// Получение деталей карты
 	cardDetails, err := h.cardService.GetCardDetails(r.Context(), cardID, userID, pgpKey)
 	if err != nil {
-		h.logger.Errorf("Ошибка получения данных карты: %v", err)
-		http.Error(w, "Не удалось получить данные карты", http.StatusInternalServerError)
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
@@ -163,36 +153,35 @@ func (h *CardHandler) GetCardDetails(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ProcessPayment обрабатывает запрос на оплату картой
+// ProcessPayment обрабатывает запрос на оплату картой. Оплатить можно только своей картой; после
+// CARD_CVV_MAX_FAILURES неверных вводов CVV подряд карта блокируется
 func (h *CardHandler) ProcessPayment(w http.ResponseWriter, r *http.Request) {
-	// Для платежа не требуется быть владельцем карты, только корректные данные карты
+	// Получение ID пользователя из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.logger.Errorf("Ошибка получения userID из контекста: %v", err)
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
 
 	// Декодирование запроса
 	var req dto.CardPaymentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Errorf("Ошибка декодирования запроса: %v", err)
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	if !decodeRequest(w, r, h.logger, &req) {
 		return
 	}
 
-	// Проверка обязательных полей
-	if req.CardID == 0 || req.CVV == "" || req.Amount == "" || req.PGPKey == "" {
-		h.logger.Warn("Отсутствуют обязательные поля")
-		http.Error(w, "Все поля обязательны", http.StatusBadRequest)
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: неверный формат суммы", apperr.ErrMalformedRequest))
 		return
 	}
-
-	// Проверка данных карты для платежа
-	isValid, err := h.cardService.VerifyCardPayment(r.Context(), req.CardID, req.CVV, req.PGPKey)
-	if err != nil {
-		h.logger.Errorf("Ошибка проверки данных карты: %v", err)
-		http.Error(w, "Ошибка проверки данных карты", http.StatusBadRequest)
+	if !requireMFA(w, r, h.mfaService, h.logger, amount) {
 		return
 	}
 
-	if !isValid {
-		h.logger.Warn("Неверные данные карты")
-		http.Error(w, "Неверные данные карты", http.StatusBadRequest)
+	// Проверка данных карты и списание со счета по умолчанию владельца карты
+	if err := h.cardService.ProcessPayment(r.Context(), userID, req.CardID, req.CVV, req.PGPKey, amount); err != nil {
+		problem.Write(w, r, h.logger, err)
 		return
 	}
 
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// CredentialHandler обрабатывает запросы подтверждения email, восстановления и смены пароля
+type CredentialHandler struct {
+	credentialService *service.CredentialService // Сервис подтверждения email и управления паролями
+	logger            *logrus.Logger             // Логгер для логирования событий
+}
+
+// NewCredentialHandler создает новый обработчик запросов подтверждения email и управления паролями
+func NewCredentialHandler(credentialService *service.CredentialService, logger *logrus.Logger) *CredentialHandler {
+	return &CredentialHandler{
+		credentialService: credentialService,
+		logger:            logger,
+	}
+}
+
+// VerifyEmail обрабатывает запрос подтверждения email по токену из письма
+// @Summary Подтверждение email
+// @Tags auth
+// @Accept json
+// @Param request body dto.VerifyEmailRequest true "Токен из ссылки"
+// @Success 204 "Email подтвержден"
+// @Failure 400 {object} dto.ProblemResponse "Неверная, просроченная или уже использованная ссылка"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /email/verify [post]
+func (h *CredentialHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyEmailRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	if err := h.credentialService.VerifyEmail(r.Context(), req.Token); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification обрабатывает запрос повторной отправки письма подтверждения email
+// @Summary Повторная отправка письма подтверждения email
+// @Tags auth
+// @Success 202 "Письмо отправлено"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 409 {object} dto.ProblemResponse "Email уже подтвержден"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /email/verify/resend [post]
+func (h *CredentialHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.credentialService.ResendVerification(r.Context(), userID); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ForgotPassword обрабатывает запрос письма со ссылкой сброса пароля. Ответ не зависит от того,
+// зарегистрирован ли email
+// @Summary Восстановление пароля
+// @Tags auth
+// @Accept json
+// @Param request body dto.ForgotPasswordRequest true "Email учетной записи"
+// @Success 202 "Если email зарегистрирован, на него отправлено письмо"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /password/forgot [post]
+func (h *CredentialHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	if err := h.credentialService.ForgotPassword(r.Context(), req.Email); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword обрабатывает запрос сброса пароля по токену из письма
+// @Summary Сброс пароля
+// @Description Задает новый пароль и завершает все сессии пользователя
+// @Tags auth
+// @Accept json
+// @Param request body dto.ResetPasswordRequest true "Токен из ссылки и новый пароль"
+// @Success 204 "Пароль изменен"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных или неверная ссылка"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /password/reset [post]
+func (h *CredentialHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	if err := h.credentialService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePassword обрабатывает запрос смены пароля
+// @Summary Смена пароля
+// @Description Меняет пароль после проверки текущего и завершает все сессии пользователя, включая текущую
+// @Tags auth
+// @Accept json
+// @Param request body dto.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 204 "Пароль изменен"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 403 {object} dto.ProblemResponse "Неверный текущий пароль"
+// @Failure 500 {object} dto.ProblemResponse "Внутренняя ошибка сервера"
+// @Router /password/change [post]
+func (h *CredentialHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	if err := h.credentialService.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
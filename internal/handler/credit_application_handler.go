@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/application"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// CreditApplicationHandler обрабатывает запросы по кредитным заявкам
+type CreditApplicationHandler struct {
+	applicationService *service.CreditApplicationService // Сервис кредитных заявок
+	logger             *logrus.Logger                    // Логгер для логирования событий
+}
+
+// NewCreditApplicationHandler создает новый обработчик кредитных заявок
+func NewCreditApplicationHandler(applicationService *service.CreditApplicationService, logger *logrus.Logger) *CreditApplicationHandler {
+	return &CreditApplicationHandler{
+		applicationService: applicationService,
+		logger:             logger,
+	}
+}
+
+// CreateCreditApplication обрабатывает подачу кредитной заявки; решение возвращается сразу
+func (h *CreditApplicationHandler) CreateCreditApplication(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.CreateCreditApplicationRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	a, err := h.applicationService.Submit(r.Context(), userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toCreditApplicationResponse(a)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditApplications обрабатывает запрос на получение списка заявок пользователя
+func (h *CreditApplicationHandler) GetCreditApplications(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	applications, err := h.applicationService.GetUserApplications(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.CreditApplicationListResponse{
+		Applications: make([]dto.CreditApplicationResponse, 0, len(applications)),
+	}
+	for _, a := range applications {
+		resp.Applications = append(resp.Applications, toCreditApplicationResponse(a))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditApplication обрабатывает запрос на получение заявки
+func (h *CreditApplicationHandler) GetCreditApplication(w http.ResponseWriter, r *http.Request) {
+	userID, applicationID, ok := h.applicationParams(w, r)
+	if !ok {
+		return
+	}
+
+	a, err := h.applicationService.GetApplication(r.Context(), applicationID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toCreditApplicationResponse(a)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// AcceptCreditApplication обрабатывает принятие одобренной заявки и оформление кредита
+func (h *CreditApplicationHandler) AcceptCreditApplication(w http.ResponseWriter, r *http.Request) {
+	userID, applicationID, ok := h.applicationParams(w, r)
+	if !ok {
+		return
+	}
+
+	a, c, schedule, err := h.applicationService.Accept(r.Context(), applicationID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	resp := dto.AcceptCreditApplicationResponse{
+		Application: toCreditApplicationResponse(a),
+		Credit:      toCreditScheduleResponse(c, schedule, nil),
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// applicationParams извлекает userID из контекста и ID заявки из URL
+func (h *CreditApplicationHandler) applicationParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	applicationID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID заявки"))
+		return 0, 0, false
+	}
+	return userID, applicationID, true
+}
+
+// toCreditApplicationResponse формирует ответ с заявкой и решением по ней
+func toCreditApplicationResponse(a *application.Application) dto.CreditApplicationResponse {
+	resp := dto.CreditApplicationResponse{
+		ID:             a.ID,
+		UserID:         a.UserID,
+		AccountID:      a.AccountID,
+		Amount:         a.Amount,
+		TermMonths:     a.TermMonths,
+		Scheme:         a.Scheme,
+		RateType:       a.RateType,
+		DeclaredIncome: a.DeclaredIncome,
+		ObservedIncome: a.ObservedIncome,
+		DebtPayments:   a.DebtPayments,
+		Payment:        a.Payment,
+		DebtToIncome:   a.DebtToIncome,
+		Status:         a.Status,
+		Reasons:        a.Reasons,
+		CreditID:       a.CreditID,
+		CreatedAt:      a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if a.AcceptedAt != nil {
+		resp.AcceptedAt = a.AcceptedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if a.DecisionReason != nil {
+		resp.DecisionReason = *a.DecisionReason
+	}
+	if a.ReviewedAt != nil {
+		resp.ReviewedAt = a.ReviewedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// CreditHandler обрабатывает запросы по кредитам
+type CreditHandler struct {
+	creditService     *service.CreditService     // Сервис кредитов
+	collectionService *service.CollectionService // Сервис работы с просроченной задолженностью
+	logger            *logrus.Logger             // Логгер для логирования событий
+}
+
+// NewCreditHandler создает новый обработчик кредитов
+func NewCreditHandler(creditService *service.CreditService, collectionService *service.CollectionService,
+	logger *logrus.Logger) *CreditHandler {
+	return &CreditHandler{
+		creditService:     creditService,
+		collectionService: collectionService,
+		logger:            logger,
+	}
+}
+
+// CalculateCredit обрабатывает запрос на расчет графика платежей и полной стоимости кредита без его оформления
+func (h *CreditHandler) CalculateCredit(w http.ResponseWriter, r *http.Request) {
+	// Декодируем запрос
+	var req dto.CreditCalculationRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	resp, err := h.creditService.Calculate(req)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCredits обрабатывает запрос на получение списка кредитов пользователя
+func (h *CreditHandler) GetCredits(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	credits, schedules, err := h.creditService.GetUserCredits(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.CreditListResponse{
+		Credits: make([]dto.CreditResponse, 0, len(credits)),
+	}
+	for _, c := range credits {
+		resp.Credits = append(resp.Credits, toCreditResponse(c, schedules[c.ID]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditSchedule обрабатывает запрос на получение графика платежей и внесенных платежей по кредиту
+func (h *CreditHandler) GetCreditSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, creditID, ok := h.creditParams(w, r)
+	if !ok {
+		return
+	}
+
+	c, schedule, payments, err := h.creditService.GetCredit(r.Context(), creditID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toCreditScheduleResponse(c, schedule, payments)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditRates обрабатывает запрос на получение истории ставки по кредиту
+func (h *CreditHandler) GetCreditRates(w http.ResponseWriter, r *http.Request) {
+	userID, creditID, ok := h.creditParams(w, r)
+	if !ok {
+		return
+	}
+
+	c, history, err := h.creditService.GetRateHistory(r.Context(), creditID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.CreditRateHistoryResponse{
+		CreditID: c.ID,
+		RateType: c.RateType,
+		Rates:    make([]dto.CreditRateResponse, 0, len(history)),
+	}
+	for _, change := range history {
+		resp.Rates = append(resp.Rates, dto.CreditRateResponse{
+			EffectiveDate: change.EffectiveDate.Format("2006-01-02"),
+			KeyRate:       change.KeyRate,
+			Margin:        change.Margin,
+			Rate:          change.Rate,
+			Payment:       change.Payment,
+			CreatedAt:     change.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetCreditCollections обрабатывает запрос на получение просроченной задолженности по кредиту
+// и журнала действий по ней
+func (h *CreditHandler) GetCreditCollections(w http.ResponseWriter, r *http.Request) {
+	userID, creditID, ok := h.creditParams(w, r)
+	if !ok {
+		return
+	}
+
+	c, overdue, dpd, actions, err := h.collectionService.GetCollections(r.Context(), creditID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.CreditCollectionsResponse{
+		CreditID:      c.ID,
+		Status:        c.Status,
+		DaysPastDue:   dpd,
+		OverdueAmount: decimal.Zero,
+		Penalty:       decimal.Zero,
+		Actions:       make([]dto.CollectionActionResponse, 0, len(actions)),
+	}
+	if c.OverdueSince != nil {
+		resp.OverdueSince = c.OverdueSince.Format("2006-01-02")
+	}
+	for _, p := range overdue {
+		resp.OverdueAmount = resp.OverdueAmount.Add(p.Amount)
+		resp.Penalty = resp.Penalty.Add(p.Penalty)
+	}
+	for _, a := range actions {
+		resp.Actions = append(resp.Actions, dto.CollectionActionResponse{
+			Action:      a.Action,
+			StageDays:   a.StageDays,
+			DaysPastDue: a.DaysPastDue,
+			ActionDate:  a.ActionDate.Format("2006-01-02"),
+			Amount:      a.Amount,
+			AccountID:   a.AccountID,
+			Details:     a.Details,
+			CreatedAt:   a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// PrepayCredit обрабатывает запрос на частичное или полное досрочное погашение кредита
+func (h *CreditHandler) PrepayCredit(w http.ResponseWriter, r *http.Request) {
+	userID, creditID, ok := h.creditParams(w, r)
+	if !ok {
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.PrepayCreditRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	c, payment, schedule, err := h.creditService.Prepay(r.Context(), creditID, userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toCreditScheduleResponse(c, schedule, []*credit.Payment{payment})); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// creditParams извлекает userID из контекста и ID кредита из URL
+func (h *CreditHandler) creditParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	creditID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID кредита"))
+		return 0, 0, false
+	}
+	return userID, creditID, true
+}
+
+// toCreditResponse формирует ответ с информацией о кредите; остаток долга и ближайший платеж
+// определяются по неоплаченной части графика
+func toCreditResponse(c *credit.Credit, schedule []*models.PaymentSchedule) dto.CreditResponse {
+	resp := dto.CreditResponse{
+		ID:           c.ID,
+		AccountID:    c.AccountID,
+		Principal:    c.Principal,
+		InterestRate: c.InterestRate,
+		TermMonths:   c.TermMonths,
+		Scheme:       c.Scheme,
+		RateType:     c.RateType,
+		RateMargin:   c.RateMargin,
+		StartDate:    c.StartDate.Format("2006-01-02"),
+		Status:       c.Status,
+		Outstanding:  decimal.Zero,
+		CreatedAt:    c.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if c.OverdueSince != nil {
+		resp.OverdueSince = c.OverdueSince.Format("2006-01-02")
+	}
+	for _, p := range schedule {
+		if p.Paid {
+			continue
+		}
+		resp.Outstanding = resp.Outstanding.Add(p.Principal)
+		if resp.NextPayment == nil {
+			amount := p.Amount
+			resp.NextPayment = &amount
+			resp.NextPaymentDate = p.DueDate.Format("2006-01-02")
+		}
+	}
+	if c.ClosedAt != nil {
+		resp.ClosedAt = c.ClosedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
+
+// toCreditScheduleResponse формирует ответ с кредитом, графиком и внесенными платежами
+func toCreditScheduleResponse(c *credit.Credit, schedule []*models.PaymentSchedule, payments []*credit.Payment) dto.CreditScheduleResponse {
+	resp := dto.CreditScheduleResponse{
+		Credit:   toCreditResponse(c, schedule),
+		Schedule: make([]dto.PaymentScheduleResponse, 0, len(schedule)),
+		Payments: make([]dto.CreditPaymentResponse, 0, len(payments)),
+	}
+	for _, p := range schedule {
+		row := dto.PaymentScheduleResponse{
+			DueDate:   p.DueDate.Format("2006-01-02"),
+			Amount:    p.Amount,
+			Principal: p.Principal,
+			Interest:  p.Interest,
+			Penalty:   p.Penalty,
+			Paid:      p.Paid,
+		}
+		if p.PaidAt != nil {
+			row.PaidAt = p.PaidAt.Format("2006-01-02T15:04:05Z")
+		}
+		resp.Schedule = append(resp.Schedule, row)
+	}
+	for _, p := range payments {
+		resp.Payments = append(resp.Payments, dto.CreditPaymentResponse{
+			Amount:    p.Amount,
+			Principal: p.Principal,
+			Interest:  p.Interest,
+			Penalty:   p.Penalty,
+			Kind:      p.Kind,
+			CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+	return resp
+}
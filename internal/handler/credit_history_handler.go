@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/bureau"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// CreditHistoryHandler обрабатывает запросы на получение кредитной истории
+type CreditHistoryHandler struct {
+	creditHistoryService *service.CreditHistoryService // Сервис кредитных историй
+	logger               *logrus.Logger                // Логгер для логирования событий
+}
+
+// NewCreditHistoryHandler создает новый обработчик кредитной истории
+func NewCreditHistoryHandler(creditHistoryService *service.CreditHistoryService, logger *logrus.Logger) *CreditHistoryHandler {
+	return &CreditHistoryHandler{
+		creditHistoryService: creditHistoryService,
+		logger:               logger,
+	}
+}
+
+// GetCreditHistory формирует кредитную историю пользователя по всем его кредитам: графики, внесенные платежи,
+// корзины просрочки и смены статуса. Параметр format=json|xml|fixed (по умолчанию json)
+func (h *CreditHistoryHandler) GetCreditHistory(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	format, err := bureau.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются форматы json, xml и fixed", err))
+		return
+	}
+
+	// Собираем историю до начала передачи данных, чтобы ошибки вернулись с корректным статусом
+	header := h.creditHistoryService.Header()
+	subject, err := h.creditHistoryService.Report(r.Context(), userID, header.ReportDate)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	bw, err := bureau.NewWriter(format, w)
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются форматы json, xml и fixed", err))
+		return
+	}
+
+	filename := fmt.Sprintf("credit_history_%d_%s.%s", userID, header.ReportDate.Format("20060102"), format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
+	if format != bureau.JSON {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	if err := h.creditHistoryService.Write(header, subject, bw); err != nil {
+		h.logger.Errorf("Ошибка записи кредитной истории пользователя %d: %v", userID, err)
+	}
+}
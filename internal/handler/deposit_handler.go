@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/deposit"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// DepositHandler обрабатывает запросы по срочным вкладам
+type DepositHandler struct {
+	depositService *service.DepositService // Сервис срочных вкладов
+	logger         *logrus.Logger          // Логгер для логирования событий
+}
+
+// NewDepositHandler создает новый обработчик срочных вкладов
+func NewDepositHandler(depositService *service.DepositService, logger *logrus.Logger) *DepositHandler {
+	return &DepositHandler{
+		depositService: depositService,
+		logger:         logger,
+	}
+}
+
+// GetDepositRate обрабатывает запрос на получение текущей ставки по новым вкладам
+func (h *DepositHandler) GetDepositRate(w http.ResponseWriter, r *http.Request) {
+	rate, source, err := h.depositService.CurrentRate(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: %w", service.ErrDepositRate, err))
+		return
+	}
+
+	resp := dto.DepositRateResponse{
+		Rate:        rate,
+		RateSource:  source,
+		PenaltyRate: h.depositService.PenaltyRate(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// OpenDeposit обрабатывает запрос на открытие срочного вклада
+func (h *DepositHandler) OpenDeposit(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.OpenDepositRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Открываем вклад
+	d, err := h.depositService.Open(r.Context(), userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toDepositResponse(d)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetDeposits обрабатывает запрос на получение списка вкладов пользователя
+func (h *DepositHandler) GetDeposits(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	deposits, err := h.depositService.GetUserDeposits(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.DepositListResponse{
+		Deposits: make([]dto.DepositResponse, 0, len(deposits)),
+	}
+	for _, d := range deposits {
+		resp.Deposits = append(resp.Deposits, toDepositResponse(d))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetDeposit обрабатывает запрос на получение вклада
+func (h *DepositHandler) GetDeposit(w http.ResponseWriter, r *http.Request) {
+	userID, depositID, ok := h.depositParams(w, r)
+	if !ok {
+		return
+	}
+
+	d, err := h.depositService.GetDeposit(r.Context(), depositID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toDepositResponse(d)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// CloseDeposit обрабатывает запрос на досрочное закрытие вклада
+func (h *DepositHandler) CloseDeposit(w http.ResponseWriter, r *http.Request) {
+	userID, depositID, ok := h.depositParams(w, r)
+	if !ok {
+		return
+	}
+
+	d, err := h.depositService.CloseEarly(r.Context(), depositID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toDepositResponse(d)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// depositParams извлекает userID из контекста и ID вклада из URL
+func (h *DepositHandler) depositParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	depositID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID вклада"))
+		return 0, 0, false
+	}
+	return userID, depositID, true
+}
+
+// toDepositResponse формирует ответ с информацией о вкладе
+func toDepositResponse(d *deposit.Deposit) dto.DepositResponse {
+	resp := dto.DepositResponse{
+		ID:             d.ID,
+		AccountID:      d.AccountID,
+		Principal:      d.Principal,
+		Rate:           d.Rate,
+		RateSource:     d.RateSource,
+		PenaltyRate:    d.PenaltyRate,
+		TermMonths:     d.TermMonths,
+		MaturityAction: d.MaturityAction,
+		StartDate:      d.StartDate.Format("2006-01-02"),
+		MaturityDate:   d.MaturityDate.Format("2006-01-02"),
+		Rollovers:      d.Rollovers,
+		InterestPaid:   d.InterestPaid,
+		Status:         d.Status,
+		CreatedAt:      d.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if d.Status == deposit.ACTIVE {
+		expected := d.Interest(d.Rate, d.MaturityDate)
+		resp.ExpectedInterest = &expected
+	}
+	if d.ClosedAt != nil {
+		resp.ClosedAt = d.ClosedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// JWKSHandler публикует открытые ключи подписи JWT
+type JWKSHandler struct {
+	keyService *service.JWTKeyService // Сервис ключей подписи JWT
+	logger     *logrus.Logger         // Логгер для логирования событий
+}
+
+// NewJWKSHandler создает новый обработчик набора открытых ключей
+func NewJWKSHandler(keyService *service.JWTKeyService, logger *logrus.Logger) *JWKSHandler {
+	return &JWKSHandler{
+		keyService: keyService,
+		logger:     logger,
+	}
+}
+
+// GetJWKS возвращает открытые ключи RS256 и EdDSA в формате JWKS (RFC 7517), включая ключи, выведенные
+// из оборота, пока ими подписаны действующие токены. При подписи HS256 набор пуст
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.keyService.JWKS()); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/qr"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// MFAHandler обрабатывает запросы на подключение и отключение двухфакторной аутентификации
+// и подтверждение сессии кодом
+type MFAHandler struct {
+	mfaService *service.MFAService // Сервис двухфакторной аутентификации
+	logger     *logrus.Logger      // Логгер для логирования событий
+}
+
+// NewMFAHandler создает новый обработчик двухфакторной аутентификации
+func NewMFAHandler(mfaService *service.MFAService, logger *logrus.Logger) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+		logger:     logger,
+	}
+}
+
+// GetStatus обрабатывает запрос состояния двухфакторной аутентификации
+// @Summary Состояние двухфакторной аутентификации
+// @Tags mfa
+// @Produce json
+// @Success 200 {object} dto.MFAStatusResponse "Состояние"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Router /mfa [get]
+func (h *MFAHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	enabled, err := h.mfaService.Enabled(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+	left, err := h.mfaService.RecoveryCodesLeft(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dto.MFAStatusResponse{Enabled: enabled, RecoveryCodesLeft: left}); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// EnrollTOTP обрабатывает запрос на подключение приложения-аутентификатора: выпускает секрет и возвращает
+// otpauth URI и QR-код с ним. Двухфакторная аутентификация включается после POST /mfa/totp/confirm
+// @Summary Подключение приложения-аутентификатора
+// @Tags mfa
+// @Produce json
+// @Success 200 {object} dto.TOTPEnrollResponse "Секрет, otpauth URI и QR-код"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 409 {object} dto.ProblemResponse "Двухфакторная аутентификация уже включена"
+// @Router /mfa/totp/enroll [post]
+func (h *MFAHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	code, err := qr.Encode([]byte(enrollment.URI), qr.M)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+	var png bytes.Buffer
+	if err := code.WritePNG(&png, qrModuleSize); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	response := dto.TOTPEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+		QRPNG:      png.Bytes(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// ConfirmTOTP обрабатывает запрос на включение двухфакторной аутентификации первым кодом из приложения
+// @Summary Включение двухфакторной аутентификации
+// @Description Возвращает одноразовые коды восстановления; повторно они не показываются
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "Код из приложения"
+// @Success 200 {object} dto.TOTPConfirmResponse "Коды восстановления"
+// @Failure 400 {object} dto.ProblemResponse "Неверный код"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 404 {object} dto.ProblemResponse "Приложение не подключено"
+// @Failure 409 {object} dto.ProblemResponse "Двухфакторная аутентификация уже включена"
+// @Router /mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.decodeCode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(r.Context(), userID, req.Code)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(dto.TOTPConfirmResponse{RecoveryCodes: codes}); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// DisableTOTP обрабатывает запрос на отключение двухфакторной аутентификации
+// @Summary Отключение двухфакторной аутентификации
+// @Tags mfa
+// @Accept json
+// @Param request body dto.MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 204 "Двухфакторная аутентификация отключена"
+// @Failure 400 {object} dto.ProblemResponse "Неверный код"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 404 {object} dto.ProblemResponse "Двухфакторная аутентификация не включена"
+// @Failure 429 {object} dto.ProblemResponse "Ввод кодов временно заблокирован"
+// @Router /mfa/totp [delete]
+func (h *MFAHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.decodeCode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.Disable(r.Context(), userID, req.Code); err != nil {
+		mfaLocked(w, err)
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Assert обрабатывает запрос на подтверждение текущей сессии кодом перед операцией с крупной суммой
+// @Summary Подтверждение сессии кодом
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 200 {object} dto.MFAAssertResponse "Срок действия подтверждения"
+// @Failure 400 {object} dto.ProblemResponse "Неверный код"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 404 {object} dto.ProblemResponse "Двухфакторная аутентификация не включена"
+// @Failure 429 {object} dto.ProblemResponse "Ввод кодов временно заблокирован"
+// @Router /mfa/assert [post]
+func (h *MFAHandler) Assert(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.decodeCode(w, r)
+	if !ok {
+		return
+	}
+	sessionID, err := middleware.GetSessionID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	expiresAt, err := h.mfaService.Assert(r.Context(), userID, sessionID, req.Code)
+	if err != nil {
+		mfaLocked(w, err)
+		problem.Write(w, r, h.logger, sessionEnded(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := dto.MFAAssertResponse{ExpiresAt: expiresAt.UTC().Format("2006-01-02T15:04:05Z")}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// decodeCode получает userID из контекста и декодирует запрос с кодом; при ошибке отправляет ответ
+func (h *MFAHandler) decodeCode(w http.ResponseWriter, r *http.Request) (int64, dto.MFACodeRequest, bool) {
+	var req dto.MFACodeRequest
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, req, false
+	}
+
+	if !decodeRequest(w, r, h.logger, &req) {
+		return 0, req, false
+	}
+	return userID, req, true
+}
+
+// mfaLocked устанавливает заголовок Retry-After, если ввод кодов заблокирован после серии неверных кодов
+func mfaLocked(w http.ResponseWriter, err error) {
+	var locked *service.MFALockedError
+	if errors.As(err, &locked) {
+		setRetryAfter(w, locked.Until)
+	}
+}
+
+// setRetryAfter устанавливает заголовок Retry-After в секундах до момента until, но не меньше одной секунды
+func setRetryAfter(w http.ResponseWriter, until time.Time) {
+	seconds := int64(math.Ceil(time.Until(until).Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+}
+
+// requireMFA проверяет, что операция на сумму amount разрешена в текущей сессии без повторного ввода кода.
+// Если сумма выше порога и сессия давно не подтверждалась, отправляет 403 и возвращает false
+func requireMFA(w http.ResponseWriter, r *http.Request, mfaService *service.MFAService, logger *logrus.Logger,
+	amount decimal.Decimal) bool {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, logger, apperr.ErrUnauthenticated)
+		return false
+	}
+	sessionID, err := middleware.GetSessionID(r.Context())
+	if err != nil {
+		problem.Write(w, r, logger, apperr.ErrUnauthenticated)
+		return false
+	}
+
+	if err := mfaService.RequireFresh(r.Context(), userID, sessionID, amount); err != nil {
+		problem.Write(w, r, logger, sessionEnded(err))
+		return false
+	}
+	return true
+}
+
+// sessionEnded заменяет ErrSessionNotFound на ошибку авторизации: сессия текущего запроса завершена,
+// и клиенту нужно войти заново
+func sessionEnded(err error) error {
+	if errors.Is(err, service.ErrSessionNotFound) {
+		return fmt.Errorf("%w: %v", apperr.ErrUnauthenticated, err)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// P2PHandler обрабатывает запросы на переводы другим пользователям по email
+type P2PHandler struct {
+	p2pService *service.P2PService // Сервис переводов по email
+	mfaService *service.MFAService // Сервис двухфакторной аутентификации
+	logger     *logrus.Logger      // Логгер для логирования событий
+}
+
+// NewP2PHandler создает новый обработчик переводов по email
+func NewP2PHandler(p2pService *service.P2PService, mfaService *service.MFAService, logger *logrus.Logger) *P2PHandler {
+	return &P2PHandler{
+		p2pService: p2pService,
+		mfaService: mfaService,
+		logger:     logger,
+	}
+}
+
+// SetDefaultAccount обрабатывает запрос на выбор счета для зачисления переводов по email
+func (h *P2PHandler) SetDefaultAccount(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	acc, err := h.p2pService.SetDefaultAccount(r.Context(), userID, accountID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Формируем ответ
+	resp := toAccountResponse(acc)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// PreviewRecipient обрабатывает запрос на просмотр получателя перевода по email
+func (h *P2PHandler) PreviewRecipient(w http.ResponseWriter, r *http.Request) {
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		problem.Write(w, r, h.logger, invalidParam("не указан email получателя"))
+		return
+	}
+
+	recipient, err := h.p2pService.PreviewRecipient(r.Context(), email)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	resp := dto.RecipientPreviewResponse{
+		MaskedName: recipient.MaskedName,
+		Currency:   recipient.Currency,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// TransferByEmail обрабатывает запрос на перевод другому пользователю по email
+func (h *P2PHandler) TransferByEmail(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.EmailTransferRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Перевод крупной суммы требует свежего подтверждения кодом
+	if !requireMFA(w, r, h.mfaService, h.logger, req.Amount) {
+		return
+	}
+
+	recipient, err := h.p2pService.TransferByEmail(r.Context(), userID, req.FromAccountID, req.ToEmail, req.Amount)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	// Отправляем успешный ответ
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"status":    "success",
+		"recipient": recipient.MaskedName,
+	}); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// writeError отправляет ответ с ошибкой перевода по email. Получатель без счета для переводов
+// для отправителя не отличается от незарегистрированного
+func (h *P2PHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrNoDefaultAccount) {
+		err = service.ErrRecipientNotFound
+	}
+	problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+}
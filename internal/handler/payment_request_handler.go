@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/paymentrequest"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// PaymentRequestHandler обрабатывает запросы на выставление и оплату счетов между пользователями
+type PaymentRequestHandler struct {
+	requestService *service.PaymentRequestService // Сервис запросов на оплату
+	mfaService     *service.MFAService            // Сервис двухфакторной аутентификации
+	logger         *logrus.Logger                 // Логгер для логирования событий
+}
+
+// NewPaymentRequestHandler создает новый обработчик запросов на оплату
+func NewPaymentRequestHandler(requestService *service.PaymentRequestService, mfaService *service.MFAService,
+	logger *logrus.Logger) *PaymentRequestHandler {
+	return &PaymentRequestHandler{
+		requestService: requestService,
+		mfaService:     mfaService,
+		logger:         logger,
+	}
+}
+
+// CreatePaymentRequest обрабатывает запрос на выставление счета другому пользователю
+func (h *PaymentRequestHandler) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.CreatePaymentRequestRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	pr, err := h.requestService.Create(r.Context(), userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toPaymentRequestResponse(pr)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetIncoming обрабатывает запрос на получение счетов, выставленных пользователю
+func (h *PaymentRequestHandler) GetIncoming(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.requestService.GetIncoming)
+}
+
+// GetOutgoing обрабатывает запрос на получение счетов, выставленных пользователем
+func (h *PaymentRequestHandler) GetOutgoing(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.requestService.GetOutgoing)
+}
+
+// GetPaymentRequest обрабатывает запрос на получение счета и истории его оплат
+func (h *PaymentRequestHandler) GetPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requestID, ok := h.requestParams(w, r)
+	if !ok {
+		return
+	}
+
+	pr, payments, err := h.requestService.GetRequest(r.Context(), requestID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.PaymentRequestDetailsResponse{
+		PaymentRequestResponse: toPaymentRequestResponse(pr),
+		Payments:               make([]dto.PaymentRequestPaymentResponse, 0, len(payments)),
+	}
+	for _, p := range payments {
+		resp.Payments = append(resp.Payments, dto.PaymentRequestPaymentResponse{
+			Amount:    p.Amount,
+			CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// ApprovePaymentRequest обрабатывает запрос плательщика на полную или частичную оплату счета
+func (h *PaymentRequestHandler) ApprovePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requestID, ok := h.requestParams(w, r)
+	if !ok {
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.ApprovePaymentRequestRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Оплата крупной суммы требует свежего подтверждения кодом; нулевая сумма означает весь остаток
+	amount := req.Amount
+	if amount.IsZero() {
+		pr, _, err := h.requestService.GetRequest(r.Context(), requestID, userID)
+		if err != nil {
+			problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+			return
+		}
+		amount = pr.Remaining()
+	}
+	if !requireMFA(w, r, h.mfaService, h.logger, amount) {
+		return
+	}
+
+	pr, err := h.requestService.Approve(r.Context(), requestID, userID, req.FromAccountID, req.Amount)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+	h.writeRequest(w, pr)
+}
+
+// DeclinePaymentRequest обрабатывает запрос плательщика на отклонение счета
+func (h *PaymentRequestHandler) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requestID, ok := h.requestParams(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.requestService.Decline(r.Context(), requestID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+	h.writeRequest(w, pr)
+}
+
+// CancelPaymentRequest обрабатывает запрос получателя денег на отзыв счета
+func (h *PaymentRequestHandler) CancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requestID, ok := h.requestParams(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.requestService.Cancel(r.Context(), requestID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+	h.writeRequest(w, pr)
+}
+
+// list отправляет список счетов, полученный функцией fetch
+func (h *PaymentRequestHandler) list(w http.ResponseWriter, r *http.Request,
+	fetch func(ctx context.Context, userID int64) ([]*paymentrequest.PaymentRequest, error)) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	requests, err := fetch(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.PaymentRequestListResponse{
+		PaymentRequests: make([]dto.PaymentRequestResponse, 0, len(requests)),
+	}
+	for _, pr := range requests {
+		resp.PaymentRequests = append(resp.PaymentRequests, toPaymentRequestResponse(pr))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// requestParams извлекает userID из контекста и ID запроса на оплату из URL
+func (h *PaymentRequestHandler) requestParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	requestID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID запроса на оплату"))
+		return 0, 0, false
+	}
+	return userID, requestID, true
+}
+
+// writeRequest отправляет информацию о запросе на оплату
+func (h *PaymentRequestHandler) writeRequest(w http.ResponseWriter, pr *paymentrequest.PaymentRequest) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toPaymentRequestResponse(pr)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// toPaymentRequestResponse формирует ответ с информацией о запросе на оплату
+func toPaymentRequestResponse(pr *paymentrequest.PaymentRequest) dto.PaymentRequestResponse {
+	return dto.PaymentRequestResponse{
+		ID:              pr.ID,
+		RequesterEmail:  pr.RequesterEmail,
+		PayerEmail:      pr.PayerEmail,
+		Amount:          pr.Amount,
+		PaidAmount:      pr.PaidAmount,
+		RemainingAmount: pr.Remaining(),
+		Memo:            pr.Memo,
+		DueDate:         pr.DueDate.Format("2006-01-02"),
+		Status:          pr.Status,
+		CreatedAt:       pr.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       pr.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
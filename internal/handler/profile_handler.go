@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// ProfileHandler обрабатывает запросы профиля текущего пользователя и отправку его на проверку личности
+type ProfileHandler struct {
+	profileService *service.ProfileService // Сервис профиля и проверки личности
+	logger         *logrus.Logger          // Логгер для логирования событий
+}
+
+// NewProfileHandler создает новый обработчик запросов профиля
+func NewProfileHandler(profileService *service.ProfileService, logger *logrus.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// GetMe обрабатывает запрос профиля текущего пользователя
+// @Summary Профиль пользователя
+// @Description Пока личность не подтверждена, в ответе есть лимиты расходных операций
+// @Tags profile
+// @Produce json
+// @Success 200 {object} dto.ProfileResponse "Профиль"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Router /me [get]
+func (h *ProfileHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	h.writeProfile(w, r, userID, http.StatusOK)
+}
+
+// UpdateMe обрабатывает запрос на изменение профиля текущего пользователя
+// @Summary Изменение профиля
+// @Description Меняются только переданные поля; пустая строка очищает поле. Имя и дату рождения нельзя
+// @Description изменить во время проверки личности и после подтверждения
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} dto.ProfileResponse "Профиль"
+// @Failure 400 {object} dto.ProblemResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 409 {object} dto.ProblemResponse "Имя пользователя занято или данные личности нельзя изменить"
+// @Router /me [patch]
+func (h *ProfileHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	if err := h.profileService.UpdateProfile(r.Context(), userID, req); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	h.writeProfile(w, r, userID, http.StatusOK)
+}
+
+// SubmitKYC обрабатывает запрос на отправку профиля на проверку личности
+// @Summary Отправка профиля на проверку личности
+// @Description Требуются имя, дата рождения, телефон и адрес. Отправить можно непроверенный или отклоненный профиль
+// @Tags profile
+// @Produce json
+// @Success 202 {object} dto.ProfileResponse "Профиль на проверке"
+// @Failure 401 {object} dto.ProblemResponse "Ошибка авторизации"
+// @Failure 409 {object} dto.ProblemResponse "Профиль уже на проверке или личность подтверждена"
+// @Failure 422 {object} dto.ProblemResponse "Профиль заполнен не полностью"
+// @Router /me/kyc [post]
+func (h *ProfileHandler) SubmitKYC(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.profileService.SubmitKYC(r.Context(), userID); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	h.writeProfile(w, r, userID, http.StatusAccepted)
+}
+
+// writeProfile отправляет текущий профиль пользователя с кодом ответа status
+func (h *ProfileHandler) writeProfile(w http.ResponseWriter, r *http.Request, userID int64, status int) {
+	user, limits, err := h.profileService.GetProfile(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(toProfileResponse(user, limits)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// toProfileResponse формирует ответ с профилем пользователя
+func toProfileResponse(user *models.User, limits *service.KYCLimits) dto.ProfileResponse {
+	resp := dto.ProfileResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		FullName:      user.FullName,
+		Phone:         user.Phone,
+		Address:       user.Address,
+		KYCStatus:     string(user.KYCStatus),
+		CreatedAt:     user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if user.Username != nil {
+		resp.Username = *user.Username
+	}
+	if user.DateOfBirth != nil {
+		resp.DateOfBirth = user.DateOfBirth.Format("2006-01-02")
+	}
+	if user.KYCUpdatedAt != nil {
+		resp.KYCUpdatedAt = user.KYCUpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if limits != nil {
+		resp.Limits = &dto.KYCLimitsResponse{
+			OperationLimit: limits.OperationLimit,
+			MonthlyLimit:   limits.MonthlyLimit,
+			MonthlyUsed:    limits.MonthlyUsed,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/qr"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// qrModuleSize задает размер модуля QR-кода в пикселях
+const qrModuleSize = 8
+
+// QRHandler обрабатывает запросы на формирование и оплату платежных QR-кодов
+type QRHandler struct {
+	qrService  *service.QRPaymentService // Сервис оплаты по QR-кодам
+	mfaService *service.MFAService       // Сервис двухфакторной аутентификации
+	logger     *logrus.Logger            // Логгер для логирования событий
+}
+
+// NewQRHandler создает новый обработчик платежных QR-кодов
+func NewQRHandler(qrService *service.QRPaymentService, mfaService *service.MFAService, logger *logrus.Logger) *QRHandler {
+	return &QRHandler{
+		qrService:  qrService,
+		mfaService: mfaService,
+		logger:     logger,
+	}
+}
+
+// GetQR обрабатывает запрос на формирование QR-кода для зачисления на счет.
+// Параметры: amount — сумма (необязательно), format — png (по умолчанию) или json
+func (h *QRHandler) GetQR(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	// Разбираем сумму
+	var amount *decimal.Decimal
+	if raw := r.URL.Query().Get("amount"); raw != "" {
+		value, err := decimal.NewFromString(raw)
+		if err != nil || !value.IsPositive() || !value.Equal(value.Round(2)) {
+			problem.Write(w, r, h.logger, invalidParam("сумма должна быть положительной, не более двух знаков после запятой"))
+			return
+		}
+		amount = &value
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "png" && format != "json" {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются форматы png и json", apperr.ErrInvalidParameter))
+		return
+	}
+
+	payload, err := h.qrService.Generate(r.Context(), userID, accountID, amount)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	expiresAt := payload.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z")
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.QRPayloadResponse{Payload: payload.Payload, ExpiresAt: expiresAt}); err != nil {
+			h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+		}
+		return
+	}
+
+	code, err := qr.Encode([]byte(payload.Payload), service.QRLevel)
+	if errors.Is(err, qr.ErrDataTooLong) {
+		err = invalidParam("сумма слишком велика для QR-кода")
+	}
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-QR-Expires-At", expiresAt)
+	if err := code.WritePNG(w, qrModuleSize); err != nil {
+		h.logger.Errorf("Ошибка записи QR-кода: %v", err)
+	}
+}
+
+// PayQR обрабатывает запрос на оплату по QR-коду
+func (h *QRHandler) PayQR(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.QRPayRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Оплата крупной суммы требует свежего подтверждения кодом; сумма, зашитая в QR-код, важнее указанной
+	// в запросе. Недействительный QR-код отклонит Pay
+	amount := req.Amount
+	if parsed, err := h.qrService.Parse(req.Payload); err == nil && parsed.Amount != nil {
+		amount = *parsed.Amount
+	}
+	if !requireMFA(w, r, h.mfaService, h.logger, amount) {
+		return
+	}
+
+	payment, payload, err := h.qrService.Pay(r.Context(), userID, req.FromAccountID, req.Payload, req.Amount)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	resp := dto.QRPaymentResponse{
+		ID:        payment.ID,
+		Amount:    payment.Amount,
+		Recipient: payload.Name,
+		CreatedAt: payment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
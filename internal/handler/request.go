@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/validation"
+)
+
+// decodeRequest декодирует JSON-тело запроса в dst и проверяет поля по тегам binding. Если тело
+// не разбирается или значения полей неверны, отправляет ответ 400 с ошибками по полям и возвращает false
+func decodeRequest(w http.ResponseWriter, r *http.Request, logger *logrus.Logger, dst any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		// Значение неверного типа относится к конкретному полю
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			err = validation.Errors{validation.NewFieldError(typeErr.Field, "type", "неверный тип значения", "invalid value type")}
+		} else {
+			err = fmt.Errorf("%w: %v", apperr.ErrMalformedRequest, err)
+		}
+		problem.Write(w, r, logger, err)
+		return false
+	}
+
+	if err := validation.Struct(dst); err != nil {
+		problem.Write(w, r, logger, err)
+		return false
+	}
+	return true
+}
+
+// invalidParam возвращает ошибку неверного параметра пути или строки запроса с уточнением detail
+func invalidParam(detail string) error {
+	return fmt.Errorf("%w: %s", apperr.ErrInvalidParameter, detail)
+}
+
+// notFound заменяет отсутствие строки в базе (pgx.ErrNoRows) на доменную ошибку target, чтобы клиент
+// получил код отсутствующего объекта, а не общий NOT_FOUND
+func notFound(err, target error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return target
+	}
+	return err
+}
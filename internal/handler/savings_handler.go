@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// SavingsHandler обрабатывает запросы по накопительным счетам
+type SavingsHandler struct {
+	savingsService *service.SavingsService // Сервис накопительных счетов
+	logger         *logrus.Logger          // Логгер для логирования событий
+}
+
+// NewSavingsHandler создает новый обработчик накопительных счетов
+func NewSavingsHandler(savingsService *service.SavingsService, logger *logrus.Logger) *SavingsHandler {
+	return &SavingsHandler{
+		savingsService: savingsService,
+		logger:         logger,
+	}
+}
+
+// GetInterest обрабатывает запрос на получение начисленных процентов по накопительному счету.
+// Период задается параметрами from и to (YYYY-MM-DD), по умолчанию — с начала текущего месяца
+func (h *SavingsHandler) GetInterest(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	// Разбираем период
+	now := time.Now().UTC()
+	from, err := parseDateParam(r.URL.Query().Get("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный формат даты from, ожидается YYYY-MM-DD"))
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"), now)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный формат даты to, ожидается YYYY-MM-DD"))
+		return
+	}
+
+	rate, accruals, err := h.savingsService.GetInterest(r.Context(), accountID, userID, from, to)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.SavingsInterestResponse{
+		AccountID:    accountID,
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		TotalAccrued: decimal.Zero,
+		Accruals:     make([]dto.InterestAccrualResponse, 0, len(accruals)),
+	}
+	if rate != nil {
+		resp.Rate = &rate.Rate
+		resp.DayCount = string(rate.DayCount)
+	}
+	for _, a := range accruals {
+		resp.TotalAccrued = resp.TotalAccrued.Add(a.Amount)
+		resp.Accruals = append(resp.Accruals, dto.InterestAccrualResponse{
+			Date:        a.AccrualDate.Format("2006-01-02"),
+			Balance:     a.Balance,
+			Rate:        a.Rate,
+			DayCount:    string(a.DayCount),
+			Amount:      a.Amount,
+			Capitalized: a.CapitalizedAt != nil,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
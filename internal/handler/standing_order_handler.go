@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/models/standingorder"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+)
+
+// StandingOrderHandler обрабатывает запросы на регулярные и отложенные переводы
+type StandingOrderHandler struct {
+	orderService *service.StandingOrderService // Сервис платежных поручений
+	mfaService   *service.MFAService           // Сервис двухфакторной аутентификации
+	logger       *logrus.Logger                // Логгер для логирования событий
+}
+
+// NewStandingOrderHandler создает новый обработчик платежных поручений
+func NewStandingOrderHandler(orderService *service.StandingOrderService, mfaService *service.MFAService,
+	logger *logrus.Logger) *StandingOrderHandler {
+	return &StandingOrderHandler{
+		orderService: orderService,
+		mfaService:   mfaService,
+		logger:       logger,
+	}
+}
+
+// CreateStandingOrder обрабатывает запрос на создание регулярного или отложенного перевода
+func (h *StandingOrderHandler) CreateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Декодируем запрос
+	var req dto.CreateStandingOrderRequest
+	if !decodeRequest(w, r, h.logger, &req) {
+		return
+	}
+
+	// Регулярный перевод крупной суммы другому пользователю требует свежего подтверждения кодом
+	external, err := h.orderService.ToOtherUser(r.Context(), userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+	if external && !requireMFA(w, r, h.mfaService, h.logger, req.Amount) {
+		return
+	}
+
+	// Создаем поручение
+	order, err := h.orderService.Create(r.Context(), userID, req)
+	if err != nil {
+		problem.Write(w, r, h.logger, notFound(err, service.ErrAccountNotOwned))
+		return
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toStandingOrderResponse(order)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetStandingOrders обрабатывает запрос на получение списка поручений пользователя
+func (h *StandingOrderHandler) GetStandingOrders(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	orders, err := h.orderService.GetUserOrders(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.StandingOrderListResponse{
+		StandingOrders: make([]dto.StandingOrderResponse, 0, len(orders)),
+	}
+	for _, order := range orders {
+		resp.StandingOrders = append(resp.StandingOrders, toStandingOrderResponse(order))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// GetStandingOrder обрабатывает запрос на получение поручения и истории его исполнения
+func (h *StandingOrderHandler) GetStandingOrder(w http.ResponseWriter, r *http.Request) {
+	userID, orderID, ok := h.orderParams(w, r)
+	if !ok {
+		return
+	}
+
+	order, executions, err := h.orderService.GetOrder(r.Context(), orderID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Формируем ответ
+	resp := dto.StandingOrderDetailsResponse{
+		StandingOrderResponse: toStandingOrderResponse(order),
+		Executions:            make([]dto.StandingOrderExecutionResponse, 0, len(executions)),
+	}
+	for _, e := range executions {
+		resp.Executions = append(resp.Executions, dto.StandingOrderExecutionResponse{
+			ScheduledDate: e.ScheduledDate.Format("2006-01-02"),
+			Amount:        e.Amount,
+			Status:        e.Status,
+			Error:         e.Error,
+			Attempts:      e.Attempts,
+			CreatedAt:     e.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// CancelStandingOrder обрабатывает запрос на отмену поручения
+func (h *StandingOrderHandler) CancelStandingOrder(w http.ResponseWriter, r *http.Request) {
+	userID, orderID, ok := h.orderParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orderService.Cancel(r.Context(), orderID, userID); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResumeStandingOrder обрабатывает запрос на возобновление приостановленного поручения
+func (h *StandingOrderHandler) ResumeStandingOrder(w http.ResponseWriter, r *http.Request) {
+	userID, orderID, ok := h.orderParams(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.Resume(r.Context(), orderID, userID)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toStandingOrderResponse(order)); err != nil {
+		h.logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// orderParams извлекает userID из контекста и ID поручения из URL
+func (h *StandingOrderHandler) orderParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID поручения"))
+		return 0, 0, false
+	}
+	return userID, orderID, true
+}
+
+// toStandingOrderResponse формирует ответ с информацией о поручении
+func toStandingOrderResponse(o *standingorder.StandingOrder) dto.StandingOrderResponse {
+	resp := dto.StandingOrderResponse{
+		ID:                  o.ID,
+		FromAccountID:       o.FromAccountID,
+		ToAccountID:         o.ToAccountID,
+		Amount:              o.Amount,
+		Frequency:           o.Frequency,
+		DayOfMonth:          o.DayOfMonth,
+		StartDate:           o.StartDate.Format("2006-01-02"),
+		MaxOccurrences:      o.MaxOccurrences,
+		Occurrences:         o.Occurrences,
+		ConsecutiveFailures: o.ConsecutiveFailures,
+		Status:              o.Status,
+		Description:         o.Description,
+		CreatedAt:           o.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if o.EndDate != nil {
+		resp.EndDate = o.EndDate.Format("2006-01-02")
+	}
+	if o.Status == standingorder.ACTIVE || o.Status == standingorder.PAUSED {
+		resp.NextRunDate = o.NextRunDate.Format("2006-01-02")
+	}
+	return resp
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/middleware"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/service"
+	"github.com/yujihn/bank_API/internal/statement"
+)
+
+// statementWriteTimeout ограничивает время потоковой передачи одной выписки
+const statementWriteTimeout = 5 * time.Minute
+
+// StatementHandler обрабатывает запросы на получение выписок по счетам
+type StatementHandler struct {
+	statementService *service.StatementService // Сервис выписок
+	logger           *logrus.Logger            // Логгер для логирования событий
+}
+
+// NewStatementHandler создает новый обработчик выписок
+func NewStatementHandler(statementService *service.StatementService, logger *logrus.Logger) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+		logger:           logger,
+	}
+}
+
+// GetStatement формирует выписку по счету за период в формате CSV, PDF или ISO 20022 (camt.053, camt.052).
+// Параметры: from и to в формате YYYY-MM-DD (по умолчанию — с начала текущего месяца по сегодня,
+// для camt.053 — по вчерашний день), format=csv|pdf|camt.053|camt.052
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	// Получаем userID из контекста
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, h.logger, apperr.ErrUnauthenticated)
+		return
+	}
+
+	// Получаем ID счета из URL
+	accountID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный ID счета"))
+		return
+	}
+
+	// Разбираем параметры периода и формата
+	query := r.URL.Query()
+	format, err := statement.ParseFormat(query.Get("format"))
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются форматы csv, pdf, camt.053 и camt.052", err))
+		return
+	}
+
+	// По умолчанию выписка формируется с начала месяца, внутридневной отчет camt.052 — за сегодня,
+	// а выписка на конец дня camt.053 — по последний завершенный день
+	now := time.Now().UTC()
+	defaultTo := now
+	if format == statement.CAMT053 {
+		defaultTo = now.AddDate(0, 0, -1)
+	}
+	defaultFrom := time.Date(defaultTo.Year(), defaultTo.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if format == statement.CAMT052 {
+		defaultFrom = now
+	}
+	from, err := parseDateParam(query.Get("from"), defaultFrom)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный формат даты from, ожидается YYYY-MM-DD"))
+		return
+	}
+	to, err := parseDateParam(query.Get("to"), defaultTo)
+	if err != nil {
+		problem.Write(w, r, h.logger, invalidParam("неверный формат даты to, ожидается YYYY-MM-DD"))
+		return
+	}
+	if err := format.CheckPeriod(to, now); err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	// Проверяем владение счетом и рассчитываем остатки до начала передачи данных
+	st, err := h.statementService.Prepare(r.Context(), accountID, userID, from, to)
+	if err != nil {
+		problem.Write(w, r, h.logger, err)
+		return
+	}
+
+	sw, err := statement.NewWriter(format, w)
+	if err != nil {
+		problem.Write(w, r, h.logger, fmt.Errorf("%w: поддерживаются форматы csv, pdf, camt.053 и camt.052", err))
+		return
+	}
+
+	// Большие выписки формируются дольше общего таймаута записи сервера
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(statementWriteTimeout)); err != nil {
+		h.logger.Warnf("Не удалось продлить таймаут записи выписки: %v", err)
+	}
+
+	// Выписка передается потоково: после начала записи статус ответа изменить нельзя
+	filename := fmt.Sprintf("statement_%d_%s_%s.%s", accountID, st.From.Format("20060102"), st.To.Format("20060102"), format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := h.statementService.Write(r.Context(), st, sw); err != nil {
+		h.logger.Errorf("Ошибка записи выписки по счету %d: %v", accountID, err)
+	}
+}
+
+// parseDateParam разбирает дату в формате YYYY-MM-DD или возвращает значение по умолчанию
+func parseDateParam(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,58 @@
+package keyset
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWK представляет открытый ключ в формате JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`           // Тип ключа: RSA или OKP
+	Use string `json:"use"`           // Назначение: sig
+	Alg string `json:"alg"`           // Алгоритм подписи
+	Kid string `json:"kid"`           // Идентификатор ключа
+	N   string `json:"n,omitempty"`   // Модуль RSA
+	E   string `json:"e,omitempty"`   // Открытая экспонента RSA
+	Crv string `json:"crv,omitempty"` // Кривая OKP: Ed25519
+	X   string `json:"x,omitempty"`   // Открытый ключ Ed25519
+}
+
+// JWKS представляет набор открытых ключей для /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"` // Открытые ключи
+}
+
+// JWKS возвращает открытые ключи набора, начиная с самого нового. Секреты HS256 не публикуются
+func (s *Keyset) JWKS() JWKS {
+	keys := s.Keys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+
+	set := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		if jwk, ok := k.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// JWK возвращает открытый ключ в формате JWK; для HS256 возвращает false
+func (k *Key) JWK() (JWK, bool) {
+	jwk := JWK{Use: "sig", Alg: string(k.Algorithm), Kid: k.ID}
+	switch public := k.VerificationKey().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
@@ -0,0 +1,233 @@
+// Package keyset хранит ключи подписи JWT. Каждый ключ имеет идентификатор kid, который записывается
+// в заголовок токена, поэтому одновременно могут действовать несколько ключей: текущий подписывает новые
+// токены, выведенные из оборота проверяют ранее выданные до истечения их срока.
+//
+// Поддерживаются HS256 (общий секрет), RS256 и EdDSA (Ed25519). Открытые ключи RS256 и EdDSA
+// публикуются в формате JWKS, чтобы внешние сервисы могли проверять токены без общего секрета.
+package keyset
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Ошибки работы с ключами
+var (
+	ErrUnsupportedAlgorithm = errors.New("неподдерживаемый алгоритм подписи JWT") // Алгоритм не из списка Algorithms
+	ErrInvalidKey           = errors.New("некорректные данные ключа подписи")     // Данные ключа не соответствуют алгоритму
+)
+
+// Algorithm представляет алгоритм подписи JWT
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256" // HMAC-SHA256 с общим секретом
+	RS256 Algorithm = "RS256" // RSA PKCS #1 v1.5 с SHA-256
+	EdDSA Algorithm = "EdDSA" // Ed25519
+)
+
+// Algorithms перечисляет поддерживаемые алгоритмы подписи
+var Algorithms = []Algorithm{HS256, RS256, EdDSA}
+
+// ParseAlgorithm разбирает алгоритм подписи из строки; пустая строка означает HS256
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch Algorithm(s) {
+	case "", HS256:
+		return HS256, nil
+	case RS256, EdDSA:
+		return Algorithm(s), nil
+	default:
+		return "", ErrUnsupportedAlgorithm
+	}
+}
+
+// Method возвращает метод подписи golang-jwt для алгоритма
+func (a Algorithm) Method() jwt.SigningMethod {
+	switch a {
+	case RS256:
+		return jwt.SigningMethodRS256
+	case EdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// Symmetric сообщает, использует ли алгоритм общий секрет; такие ключи не публикуются
+func (a Algorithm) Symmetric() bool {
+	return a == HS256
+}
+
+// Длины генерируемых ключей
+const (
+	hmacKeyBytes = 32   // Секрет HS256: 256 бит
+	rsaKeyBits   = 2048 // Модуль RS256
+	kidBytes     = 12   // Идентификатор ключа до кодирования в hex
+)
+
+// Key представляет ключ подписи JWT
+type Key struct {
+	ID        string     // Идентификатор ключа (kid)
+	Algorithm Algorithm  // Алгоритм подписи
+	CreatedAt time.Time  // Дата и время создания
+	RetiredAt *time.Time // Дата и время вывода из оборота; после этого ключ только проверяет подписи
+	ExpiresAt *time.Time // Срок, до которого ключ проверяет подписи после вывода из оборота
+
+	secret  []byte        // Секрет HS256
+	private crypto.Signer // Закрытый ключ RS256 или EdDSA
+}
+
+// Generate создает новый ключ для алгоритма alg со случайным идентификатором
+func Generate(alg Algorithm) (*Key, error) {
+	kid := make([]byte, kidBytes)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+	k := &Key{ID: hex.EncodeToString(kid), Algorithm: alg, CreatedAt: time.Now().UTC()}
+
+	switch alg {
+	case HS256:
+		k.secret = make([]byte, hmacKeyBytes)
+		if _, err := rand.Read(k.secret); err != nil {
+			return nil, err
+		}
+	case RS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		k.private = private
+	case EdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		k.private = private
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return k, nil
+}
+
+// Parse восстанавливает ключ из данных, полученных от Marshal
+func Parse(id string, alg Algorithm, data []byte) (*Key, error) {
+	k := &Key{ID: id, Algorithm: alg}
+	switch alg {
+	case HS256:
+		if len(data) == 0 {
+			return nil, ErrInvalidKey
+		}
+		k.secret = data
+	case RS256, EdDSA:
+		parsed, err := x509.ParsePKCS8PrivateKey(data)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		switch private := parsed.(type) {
+		case *rsa.PrivateKey:
+			if alg != RS256 {
+				return nil, ErrInvalidKey
+			}
+			k.private = private
+		case ed25519.PrivateKey:
+			if alg != EdDSA {
+				return nil, ErrInvalidKey
+			}
+			k.private = private
+		default:
+			return nil, ErrInvalidKey
+		}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return k, nil
+}
+
+// Marshal возвращает секретные данные ключа: секрет HS256 или закрытый ключ в PKCS #8
+func (k *Key) Marshal() ([]byte, error) {
+	if k.Algorithm.Symmetric() {
+		return k.secret, nil
+	}
+	return x509.MarshalPKCS8PrivateKey(k.private)
+}
+
+// SigningKey возвращает ключ для подписи токена методом Algorithm.Method
+func (k *Key) SigningKey() interface{} {
+	if k.Algorithm.Symmetric() {
+		return k.secret
+	}
+	return k.private
+}
+
+// VerificationKey возвращает ключ для проверки подписи токена
+func (k *Key) VerificationKey() interface{} {
+	if k.Algorithm.Symmetric() {
+		return k.secret
+	}
+	return k.private.Public()
+}
+
+// Keyset хранит действующие ключи. Безопасен для одновременного использования; набор заменяется целиком
+// методом Replace при ротации и синхронизации с другими экземплярами сервиса
+type Keyset struct {
+	mu      sync.RWMutex    // Защищает keys и signing
+	keys    map[string]*Key // Ключи по идентификатору
+	signing *Key            // Текущий ключ подписи
+}
+
+// New создает пустой набор ключей
+func New() *Keyset {
+	return &Keyset{keys: make(map[string]*Key)}
+}
+
+// Replace заменяет набор ключей. Ключом подписи становится самый новый ключ, не выведенный из оборота
+func (s *Keyset) Replace(keys []*Key) {
+	byID := make(map[string]*Key, len(keys))
+	var signing *Key
+	for _, k := range keys {
+		byID[k.ID] = k
+		if k.RetiredAt == nil && (signing == nil || k.CreatedAt.After(signing.CreatedAt)) {
+			signing = k
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = byID
+	s.signing = signing
+}
+
+// Signing возвращает текущий ключ подписи или nil, если набор пуст
+func (s *Keyset) Signing() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing
+}
+
+// Lookup находит ключ по идентификатору
+func (s *Keyset) Lookup(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// Keys возвращает все ключи набора
+func (s *Keyset) Keys() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,214 @@
+package keyset
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestParseAlgorithm проверяет разбор алгоритма подписи
+func TestParseAlgorithm(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Algorithm
+		err  error
+	}{
+		{"", HS256, nil},
+		{"HS256", HS256, nil},
+		{"RS256", RS256, nil},
+		{"EdDSA", EdDSA, nil},
+		{"none", "", ErrUnsupportedAlgorithm},
+		{"hs256", "", ErrUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlgorithm(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("ParseAlgorithm(%q) = %q, %v; ожидается %q, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+// TestKeyRoundTrip проверяет, что ключ, восстановленный из Marshal, проверяет подписи исходного ключа
+func TestKeyRoundTrip(t *testing.T) {
+	for _, alg := range Algorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			k, err := Generate(alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(k.ID) != 2*kidBytes {
+				t.Errorf("длина kid %d, ожидается %d", len(k.ID), 2*kidBytes)
+			}
+
+			data, err := k.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := Parse(k.ID, alg, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signed := sign(t, k)
+			token, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return parsed.VerificationKey(), nil },
+				jwt.WithValidMethods([]string{alg.Method().Alg()}))
+			if err != nil || !token.Valid {
+				t.Fatalf("подпись не проверена восстановленным ключом: %v", err)
+			}
+			if token.Header["kid"] != k.ID {
+				t.Errorf("kid в заголовке %v, ожидается %s", token.Header["kid"], k.ID)
+			}
+		})
+	}
+}
+
+// TestParseInvalid проверяет отказ для данных, не соответствующих алгоритму
+func TestParseInvalid(t *testing.T) {
+	rsaKey, err := Generate(RS256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaData, err := rsaKey.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		alg  Algorithm
+		data []byte
+		err  error
+	}{
+		{"пустой секрет HS256", HS256, nil, ErrInvalidKey},
+		{"ключ RSA как EdDSA", EdDSA, rsaData, ErrInvalidKey},
+		{"не PKCS #8", RS256, []byte("not a key"), ErrInvalidKey},
+		{"неизвестный алгоритм", "ES256", rsaData, ErrUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse("kid", tt.alg, tt.data); !errors.Is(err, tt.err) {
+				t.Errorf("ошибка %v, ожидается %v", err, tt.err)
+			}
+		})
+	}
+	if _, err := Generate("ES256"); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("Generate: ошибка %v, ожидается ErrUnsupportedAlgorithm", err)
+	}
+}
+
+// TestKeysetReplace проверяет выбор ключа подписи и поиск выведенных из оборота ключей
+func TestKeysetReplace(t *testing.T) {
+	now := time.Now().UTC()
+	retired := now.Add(-time.Hour)
+	old := &Key{ID: "old", Algorithm: HS256, CreatedAt: now.Add(-48 * time.Hour)}
+	current := &Key{ID: "current", Algorithm: HS256, CreatedAt: now.Add(-24 * time.Hour)}
+	newest := &Key{ID: "newest", Algorithm: HS256, CreatedAt: now, RetiredAt: &retired}
+
+	s := New()
+	if s.Signing() != nil {
+		t.Error("у пустого набора не должно быть ключа подписи")
+	}
+
+	s.Replace([]*Key{old, newest, current})
+	if got := s.Signing(); got != current {
+		t.Errorf("ключ подписи %v, ожидается current", got)
+	}
+	if k, ok := s.Lookup("newest"); !ok || k != newest {
+		t.Error("выведенный из оборота ключ должен находиться по kid")
+	}
+	if _, ok := s.Lookup("missing"); ok {
+		t.Error("неизвестный kid не должен находиться")
+	}
+	if len(s.Keys()) != 3 {
+		t.Errorf("ключей %d, ожидается 3", len(s.Keys()))
+	}
+
+	s.Replace([]*Key{newest})
+	if s.Signing() != nil {
+		t.Error("набор только из выведенных ключей не должен иметь ключа подписи")
+	}
+	if _, ok := s.Lookup("old"); ok {
+		t.Error("ключ, удаленный при замене набора, не должен находиться")
+	}
+}
+
+// TestJWKS проверяет публикацию открытых ключей: порядок от нового к старому, отсутствие секретов HS256
+// и проверку подписи ключом, восстановленным из JWK
+func TestJWKS(t *testing.T) {
+	now := time.Now().UTC()
+	keys := make(map[Algorithm]*Key)
+	for i, alg := range Algorithms {
+		k, err := Generate(alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		keys[alg] = k
+	}
+	s := New()
+	s.Replace([]*Key{keys[HS256], keys[RS256], keys[EdDSA]})
+
+	set := s.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("опубликовано ключей %d, ожидается 2", len(set.Keys))
+	}
+	if set.Keys[0].Kid != keys[EdDSA].ID || set.Keys[1].Kid != keys[RS256].ID {
+		t.Errorf("порядок ключей %s, %s; ожидается сначала EdDSA", set.Keys[0].Kid, set.Keys[1].Kid)
+	}
+
+	for _, jwk := range set.Keys {
+		if jwk.Use != "sig" {
+			t.Errorf("%s: use %q, ожидается sig", jwk.Kid, jwk.Use)
+		}
+		var public interface{}
+		var signer *Key
+		switch jwk.Kty {
+		case "RSA":
+			signer = keys[RS256]
+			if jwk.E != "AQAB" {
+				t.Errorf("экспонента %q, ожидается AQAB", jwk.E)
+			}
+			public = &rsa.PublicKey{N: new(big.Int).SetBytes(decode(t, jwk.N)), E: int(new(big.Int).SetBytes(decode(t, jwk.E)).Int64())}
+		case "OKP":
+			signer = keys[EdDSA]
+			if jwk.Crv != "Ed25519" {
+				t.Errorf("кривая %q, ожидается Ed25519", jwk.Crv)
+			}
+			public = ed25519.PublicKey(decode(t, jwk.X))
+		default:
+			t.Fatalf("неожиданный тип ключа %q", jwk.Kty)
+		}
+
+		if _, err := jwt.Parse(sign(t, signer), func(*jwt.Token) (interface{}, error) { return public, nil }); err != nil {
+			t.Errorf("%s: подпись не проверена ключом из JWKS: %v", jwk.Kty, err)
+		}
+	}
+}
+
+// sign подписывает токен ключом k с kid в заголовке
+func sign(t *testing.T, k *Key) string {
+	t.Helper()
+	token := jwt.NewWithClaims(k.Algorithm.Method(), jwt.MapClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = k.ID
+	signed, err := token.SignedString(k.SigningKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// decode декодирует значение JWK в base64url без дополнения
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
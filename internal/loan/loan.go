@@ -0,0 +1,198 @@
+// Package loan реализует расчет графиков платежей по кредитам (аннуитетных и дифференцированных)
+// и полной стоимости кредита.
+//
+// Политика округления: проценты за каждый период рассчитываются от остатка долга по ставке ставка/12
+// и округляются до копеек (банковское округление не применяется, половина округляется от нуля);
+// аннуитетный платеж и часть основного долга дифференцированного платежа округляются до копеек;
+// в последнем платеже погашается весь оставшийся долг, поэтому он поглощает накопленную погрешность
+// округления и может отличаться от остальных.
+package loan
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+)
+
+// ErrPaymentTooSmall возвращается, если платеж не покрывает проценты за период и долг не уменьшается
+var ErrPaymentTooSmall = apperr.New(apperr.Invalid, apperr.CodePaymentTooSmall, "платеж не покрывает проценты за период")
+
+// Installment представляет один платеж графика
+type Installment struct {
+	DueDate   time.Time       // Дата платежа
+	Payment   decimal.Decimal // Сумма платежа
+	Principal decimal.Decimal // Погашение основного долга
+	Interest  decimal.Decimal // Погашение процентов
+	Balance   decimal.Decimal // Остаток основного долга после платежа
+}
+
+// MonthlyRate возвращает месячную ставку для годовой ставки annualRate (доля)
+func MonthlyRate(annualRate decimal.Decimal) decimal.Decimal {
+	return annualRate.Div(decimal.NewFromInt(12))
+}
+
+// AnnuityPayment рассчитывает аннуитетный платеж P × r / (1 − (1 + r)^−n), округленный до копеек
+func AnnuityPayment(principal, annualRate decimal.Decimal, months int) decimal.Decimal {
+	r := MonthlyRate(annualRate)
+	if r.IsZero() {
+		return principal.Div(decimal.NewFromInt(int64(months))).Round(2)
+	}
+	growth := decimal.NewFromInt(1).Add(r).Pow(decimal.NewFromInt(int64(months)))
+	return principal.Mul(r).Mul(growth).Div(growth.Sub(decimal.NewFromInt(1))).Round(2)
+}
+
+// Annuity строит аннуитетный график из months платежей. Платежи назначаются ежемесячно после start
+// в день dayOfMonth (для коротких месяцев — последний день месяца)
+func Annuity(principal, annualRate decimal.Decimal, months int, start time.Time, dayOfMonth int) []Installment {
+	payment := AnnuityPayment(principal, annualRate, months)
+	r := MonthlyRate(annualRate)
+
+	schedule := make([]Installment, 0, months)
+	balance := principal
+	for i := 1; i <= months; i++ {
+		interest := balance.Mul(r).Round(2)
+		principalPart := payment.Sub(interest)
+		if i == months || principalPart.GreaterThan(balance) {
+			principalPart = balance
+		}
+		balance = balance.Sub(principalPart)
+		schedule = append(schedule, Installment{
+			DueDate:   DueDate(start, i, dayOfMonth),
+			Payment:   principalPart.Add(interest),
+			Principal: principalPart,
+			Interest:  interest,
+			Balance:   balance,
+		})
+		if balance.IsZero() {
+			break
+		}
+	}
+	return schedule
+}
+
+// Differentiated строит дифференцированный график из months платежей: основной долг гасится равными
+// частями P / n, проценты начисляются на убывающий остаток. Даты платежей — как в Annuity
+func Differentiated(principal, annualRate decimal.Decimal, months int, start time.Time, dayOfMonth int) []Installment {
+	part := principal.Div(decimal.NewFromInt(int64(months))).Round(2)
+	r := MonthlyRate(annualRate)
+
+	schedule := make([]Installment, 0, months)
+	balance := principal
+	for i := 1; i <= months; i++ {
+		interest := balance.Mul(r).Round(2)
+		principalPart := part
+		if i == months || principalPart.GreaterThan(balance) {
+			principalPart = balance
+		}
+		balance = balance.Sub(principalPart)
+		schedule = append(schedule, Installment{
+			DueDate:   DueDate(start, i, dayOfMonth),
+			Payment:   principalPart.Add(interest),
+			Principal: principalPart,
+			Interest:  interest,
+			Balance:   balance,
+		})
+		if balance.IsZero() {
+			break
+		}
+	}
+	return schedule
+}
+
+// FixedPayment строит график с заданным ежемесячным платежом: количество платежей определяется тем,
+// сколько периодов нужно для погашения principal; последний платеж меньше или равен payment
+func FixedPayment(principal, annualRate, payment decimal.Decimal, start time.Time, dayOfMonth int) ([]Installment, error) {
+	r := MonthlyRate(annualRate)
+
+	var schedule []Installment
+	balance := principal
+	for i := 1; balance.IsPositive(); i++ {
+		interest := balance.Mul(r).Round(2)
+		principalPart := payment.Sub(interest)
+		if !principalPart.IsPositive() {
+			return nil, ErrPaymentTooSmall
+		}
+		if principalPart.GreaterThan(balance) {
+			principalPart = balance
+		}
+		balance = balance.Sub(principalPart)
+		schedule = append(schedule, Installment{
+			DueDate:   DueDate(start, i, dayOfMonth),
+			Payment:   principalPart.Add(interest),
+			Principal: principalPart,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return schedule, nil
+}
+
+// DueDate возвращает дату n-го ежемесячного платежа после start в день dayOfMonth;
+// если в месяце меньше дней, берется последний день месяца
+func DueDate(start time.Time, n int, dayOfMonth int) time.Time {
+	month := start.Month() + time.Month(n)
+	lastDay := time.Date(start.Year(), month+1, 0, 0, 0, 0, 0, start.Location()).Day()
+	day := dayOfMonth
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(start.Year(), month, day, 0, 0, 0, 0, start.Location())
+}
+
+// AccruedInterest рассчитывает проценты на остаток principal за дни с from до to по годовой ставке (ACT/365),
+// округленные до копеек
+func AccruedInterest(principal, annualRate decimal.Decimal, from, to time.Time) decimal.Decimal {
+	days := int64(to.Sub(from).Hours() / 24)
+	if days <= 0 {
+		return decimal.Zero
+	}
+	return principal.Mul(annualRate).Mul(decimal.NewFromInt(days)).Div(decimal.NewFromInt(365)).Round(2)
+}
+
+// pskIterations задает число делений отрезка пополам при поиске месячной ставки: погрешность 2^−40 < 10^−12
+// значительно меньше точности округления ПСК
+const pskIterations = 40
+
+// PSK рассчитывает полную стоимость кредита по графику schedule при выдаче principal (доли, округленные
+// до 5 знаков, то есть до тысячных долей процента). Для ежемесячных платежей базовый период — месяц,
+// поэтому ПСК = i × 12, где i — ставка за месяц, при которой приведенная стоимость платежей равна сумме кредита.
+// Вторым значением возвращается эффективная годовая ставка (1 + i)^12 − 1
+func PSK(principal decimal.Decimal, schedule []Installment) (decimal.Decimal, decimal.Decimal) {
+	total := decimal.Zero
+	for _, in := range schedule {
+		total = total.Add(in.Payment)
+	}
+	if !principal.IsPositive() || total.LessThanOrEqual(principal) {
+		return decimal.Zero, decimal.Zero
+	}
+
+	// Приведенная стоимость убывает с ростом ставки: ищем корень делением отрезка пополам
+	one := decimal.NewFromInt(1)
+	low, high := decimal.Zero, one
+	for n := 0; n < pskIterations; n++ {
+		mid := low.Add(high).Div(decimal.NewFromInt(2))
+		if presentValue(schedule, mid).GreaterThan(principal) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	monthly := low.Add(high).Div(decimal.NewFromInt(2))
+	psk := monthly.Mul(decimal.NewFromInt(12)).Round(5)
+	effective := one.Add(monthly).Pow(decimal.NewFromInt(12)).Sub(one).Round(5)
+	return psk, effective
+}
+
+// presentValue рассчитывает приведенную стоимость платежей графика при ставке rate за месяц
+func presentValue(schedule []Installment, rate decimal.Decimal) decimal.Decimal {
+	pv := decimal.Zero
+	discount := decimal.NewFromInt(1)
+	factor := decimal.NewFromInt(1).Add(rate)
+	for _, in := range schedule {
+		discount = discount.Mul(factor)
+		pv = pv.Add(in.Payment.Div(discount))
+	}
+	return pv
+}
@@ -0,0 +1,254 @@
+package loan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TestAnnuityPayment проверяет аннуитетный платеж по формуле и при нулевой ставке
+func TestAnnuityPayment(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal string
+		rate      string
+		months    int
+		want      string
+	}{
+		{"12% на год", "100000", "0.12", 12, "8884.88"},
+		{"24% на два года", "500000", "0.24", 24, "26435.55"},
+		{"нулевая ставка", "1000", "0", 3, "333.33"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnnuityPayment(dec(tt.principal), dec(tt.rate), tt.months)
+			if !got.Equal(dec(tt.want)) {
+				t.Errorf("платеж = %s, ожидается %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAnnuitySchedule проверяет, что аннуитетный график гасит весь долг, все платежи кроме последнего равны,
+// а последний поглощает погрешность округления
+func TestAnnuitySchedule(t *testing.T) {
+	principal, rate := dec("100000"), dec("0.12")
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	schedule := Annuity(principal, rate, 12, start, 15)
+
+	if len(schedule) != 12 {
+		t.Fatalf("платежей %d, ожидается 12", len(schedule))
+	}
+	payment := AnnuityPayment(principal, rate, 12)
+	paid := decimal.Zero
+	balance := principal
+	for i, in := range schedule {
+		if !in.Payment.Equal(in.Principal.Add(in.Interest)) {
+			t.Errorf("платеж %d: %s не равен сумме долга %s и процентов %s", i+1, in.Payment, in.Principal, in.Interest)
+		}
+		if want := balance.Mul(MonthlyRate(rate)).Round(2); !in.Interest.Equal(want) {
+			t.Errorf("платеж %d: проценты %s, ожидается %s", i+1, in.Interest, want)
+		}
+		balance = balance.Sub(in.Principal)
+		if !in.Balance.Equal(balance) {
+			t.Errorf("платеж %d: остаток %s, ожидается %s", i+1, in.Balance, balance)
+		}
+		if i < len(schedule)-1 && !in.Payment.Equal(payment) {
+			t.Errorf("платеж %d: %s, ожидается %s", i+1, in.Payment, payment)
+		}
+		if want := time.Date(2024, time.Month(2+i), 15, 0, 0, 0, 0, time.UTC); !in.DueDate.Equal(want) {
+			t.Errorf("платеж %d: дата %s, ожидается %s", i+1, in.DueDate, want)
+		}
+		paid = paid.Add(in.Principal)
+	}
+	if !paid.Equal(principal) {
+		t.Errorf("погашено %s, ожидается %s", paid, principal)
+	}
+	last := schedule[len(schedule)-1]
+	if !last.Balance.IsZero() {
+		t.Errorf("остаток после последнего платежа %s", last.Balance)
+	}
+	if last.Payment.Sub(payment).Abs().GreaterThan(dec("0.12")) {
+		t.Errorf("последний платеж %s слишком отличается от %s", last.Payment, payment)
+	}
+}
+
+// TestAnnuityZeroRate проверяет, что при нулевой ставке долг гасится равными частями без процентов
+func TestAnnuityZeroRate(t *testing.T) {
+	schedule := Annuity(dec("1000"), decimal.Zero, 3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	want := []string{"333.33", "333.33", "333.34"}
+	if len(schedule) != len(want) {
+		t.Fatalf("платежей %d, ожидается %d", len(schedule), len(want))
+	}
+	for i, in := range schedule {
+		if !in.Payment.Equal(dec(want[i])) || !in.Interest.IsZero() {
+			t.Errorf("платеж %d: %s (проценты %s), ожидается %s без процентов", i+1, in.Payment, in.Interest, want[i])
+		}
+	}
+}
+
+// TestFixedPayment проверяет график с заданным платежом и отказ, если платеж не покрывает проценты
+func TestFixedPayment(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	schedule, err := FixedPayment(dec("100000"), dec("0.12"), dec("8884.88"), start, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 12 {
+		t.Fatalf("платежей %d, ожидается 12", len(schedule))
+	}
+	last := schedule[len(schedule)-1]
+	if !last.Balance.IsZero() || last.Payment.GreaterThan(dec("8884.88")) {
+		t.Errorf("последний платеж %s, остаток %s", last.Payment, last.Balance)
+	}
+
+	schedule, err = FixedPayment(dec("10000"), dec("0.12"), dec("3000"), start, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 4 || !schedule[3].Payment.LessThan(dec("3000")) {
+		t.Errorf("ожидается 4 платежа с меньшим последним, получено %d", len(schedule))
+	}
+
+	if _, err := FixedPayment(dec("100000"), dec("0.12"), dec("1000"), start, 15); !errors.Is(err, ErrPaymentTooSmall) {
+		t.Errorf("ошибка %v, ожидается ErrPaymentTooSmall", err)
+	}
+}
+
+// TestDueDate проверяет перенос дня платежа на последний день короткого месяца и переход через год
+func TestDueDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		n     int
+		day   int
+		want  time.Time
+	}{
+		{"обычный месяц", date(2024, 1, 10), 1, 10, date(2024, 2, 10)},
+		{"февраль високосного года", date(2024, 1, 31), 1, 31, date(2024, 2, 29)},
+		{"февраль невисокосного года", date(2023, 1, 31), 1, 31, date(2023, 2, 28)},
+		{"после короткого месяца", date(2024, 1, 31), 2, 31, date(2024, 3, 31)},
+		{"30-дневный месяц", date(2024, 3, 31), 1, 31, date(2024, 4, 30)},
+		{"переход через год", date(2024, 11, 30), 3, 30, date(2025, 2, 28)},
+		{"через год", date(2024, 2, 29), 12, 29, date(2025, 2, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DueDate(tt.start, tt.n, tt.day); !got.Equal(tt.want) {
+				t.Errorf("дата %s, ожидается %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+// TestAccruedInterest проверяет начисление процентов ACT/365
+func TestAccruedInterest(t *testing.T) {
+	from := date(2024, 3, 1)
+	if got := AccruedInterest(dec("100000"), dec("0.365"), from, from.AddDate(0, 0, 10)); !got.Equal(dec("1000")) {
+		t.Errorf("проценты %s, ожидается 1000", got)
+	}
+	if got := AccruedInterest(dec("100000"), dec("0.12"), from, from); !got.IsZero() {
+		t.Errorf("проценты за 0 дней %s, ожидается 0", got)
+	}
+	if got := AccruedInterest(dec("100000"), dec("0.12"), from, from.AddDate(0, 0, -1)); !got.IsZero() {
+		t.Errorf("проценты за отрицательный период %s, ожидается 0", got)
+	}
+}
+
+// TestDifferentiatedSchedule проверяет равные части основного долга и проценты на убывающий остаток
+func TestDifferentiatedSchedule(t *testing.T) {
+	schedule := Differentiated(dec("120000"), dec("0.12"), 12, date(2024, 1, 31), 31)
+
+	if len(schedule) != 12 {
+		t.Fatalf("платежей %d, ожидается 12", len(schedule))
+	}
+	totalInterest := decimal.Zero
+	for i, in := range schedule {
+		if !in.Principal.Equal(dec("10000")) {
+			t.Errorf("платеж %d: долг %s, ожидается 10000", i+1, in.Principal)
+		}
+		// Остаток перед платежом i — (12 − i + 1) × 10000, проценты — 1% от него
+		if want := decimal.NewFromInt(int64(12-i) * 100); !in.Interest.Equal(want) {
+			t.Errorf("платеж %d: проценты %s, ожидается %s", i+1, in.Interest, want)
+		}
+		if i > 0 && !in.Payment.LessThan(schedule[i-1].Payment) {
+			t.Errorf("платеж %d: %s не меньше предыдущего %s", i+1, in.Payment, schedule[i-1].Payment)
+		}
+		totalInterest = totalInterest.Add(in.Interest)
+	}
+	if !totalInterest.Equal(dec("7800")) {
+		t.Errorf("проценты всего %s, ожидается 7800", totalInterest)
+	}
+	if !schedule[11].Balance.IsZero() {
+		t.Errorf("остаток после последнего платежа %s", schedule[11].Balance)
+	}
+	if want := date(2024, 2, 29); !schedule[0].DueDate.Equal(want) {
+		t.Errorf("дата первого платежа %s, ожидается %s", schedule[0].DueDate, want)
+	}
+}
+
+// TestDifferentiatedRounding проверяет, что последний платеж гасит остаток, накопленный округлением части долга
+func TestDifferentiatedRounding(t *testing.T) {
+	schedule := Differentiated(dec("1000"), dec("0.12"), 3, date(2024, 1, 1), 1)
+
+	want := []string{"333.33", "333.33", "333.34"}
+	for i, in := range schedule {
+		if !in.Principal.Equal(dec(want[i])) {
+			t.Errorf("платеж %d: долг %s, ожидается %s", i+1, in.Principal, want[i])
+		}
+	}
+	if !schedule[len(schedule)-1].Balance.IsZero() {
+		t.Errorf("остаток после последнего платежа %s", schedule[len(schedule)-1].Balance)
+	}
+}
+
+// TestPSK проверяет, что ПСК графика без комиссий равна номинальной ставке, а эффективная ставка
+// учитывает ежемесячную капитализацию
+func TestPSK(t *testing.T) {
+	// Проценты дифференцированного графика начисляются без округления, поэтому ПСК равна ставке точно
+	psk, effective := PSK(dec("120000"), Differentiated(dec("120000"), dec("0.12"), 12, date(2024, 1, 1), 1))
+	if !psk.Equal(dec("0.12")) {
+		t.Errorf("ПСК дифференцированного графика %s, ожидается 0.12", psk)
+	}
+	// (1 + 0.01)^12 − 1 = 0.126825...
+	if !effective.Equal(dec("0.12683")) {
+		t.Errorf("эффективная ставка %s, ожидается 0.12683", effective)
+	}
+
+	// Аннуитетный платеж округлен до копеек, поэтому ПСК отличается от ставки не больше чем на тысячную процента
+	psk, _ = PSK(dec("100000"), Annuity(dec("100000"), dec("0.12"), 12, date(2024, 1, 1), 1))
+	if psk.Sub(dec("0.12")).Abs().GreaterThan(dec("0.00001")) {
+		t.Errorf("ПСК аннуитетного графика %s, ожидается около 0.12", psk)
+	}
+
+	// Комиссия при выдаче (выдано меньше, чем нужно вернуть) увеличивает ПСК
+	withFee, _ := PSK(dec("98000"), Annuity(dec("100000"), dec("0.12"), 12, date(2024, 1, 1), 1))
+	if !withFee.GreaterThan(psk) {
+		t.Errorf("ПСК с комиссией %s не больше ПСК без комиссии %s", withFee, psk)
+	}
+}
+
+// TestPSKNoCost проверяет, что ПСК равна нулю, если платежи не превышают сумму кредита
+func TestPSKNoCost(t *testing.T) {
+	schedule := Annuity(dec("1000"), decimal.Zero, 3, date(2024, 1, 1), 1)
+	if psk, effective := PSK(dec("1000"), schedule); !psk.IsZero() || !effective.IsZero() {
+		t.Errorf("ПСК %s, эффективная ставка %s, ожидается 0", psk, effective)
+	}
+	if psk, _ := PSK(decimal.Zero, schedule); !psk.IsZero() {
+		t.Errorf("ПСК при нулевой сумме %s, ожидается 0", psk)
+	}
+}
+
+// dec разбирает десятичное число из строки
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// date возвращает полночь даты в UTC
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// AdminMiddleware пропускает к маршрутам только администраторов. Роль читается из базы данных при каждом
+// запросе, поэтому ее снятие действует сразу, а не после истечения токена. Должен подключаться после JWTMiddleware
+type AdminMiddleware struct {
+	userRepo repository.UserRepository // Репозиторий пользователей
+	logger   *logrus.Logger            // Логгер для логирования
+}
+
+// NewAdminMiddleware создает новый middleware для проверки роли администратора
+func NewAdminMiddleware(userRepo repository.UserRepository, logger *logrus.Logger) *AdminMiddleware {
+	return &AdminMiddleware{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// Middleware проверяет, что пользователь из контекста запроса — администратор
+func (m *AdminMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := GetUserID(r.Context())
+		if err != nil {
+			problem.Write(w, r, m.logger, apperr.ErrUnauthenticated)
+			return
+		}
+
+		user, err := m.userRepo.GetByID(r.Context(), userID)
+		if err != nil {
+			problem.Write(w, r, m.logger, fmt.Errorf("ошибка получения пользователя для проверки роли: %w", err))
+			return
+		}
+		if user.Role != models.RoleAdmin {
+			m.logger.WithField("user_id", userID).Warn("Попытка доступа к маршруту администратора")
+			problem.Write(w, r, m.logger, apperr.ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -3,17 +3,24 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/problem"
 	"github.com/yujihn/bank_API/internal/service"
 )
 
-// Ключ для хранения ID пользователя в контексте
+// Ключи для хранения ID пользователя, сессии и данных токена в контексте
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey    contextKey = "userID"
+	SessionIDKey contextKey = "sessionID"
+	ClaimsKey    contextKey = "claims"
+)
 
 // JWTMiddleware обеспечивает проверку JWT-токена и добавление ID пользователя в контекст
 type JWTMiddleware struct {
@@ -29,36 +36,49 @@ func NewJWTMiddleware(authService service.AuthService, logger *logrus.Logger) *J
 	}
 }
 
-// Middleware проверяет наличие и валидность JWT-токена, добавляя ID пользователя в контекст
+// Middleware проверяет наличие, валидность и отсутствие в списке отозванных JWT-токена, добавляя ID
+// пользователя в контекст
 func (m *JWTMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Получение заголовка Authorization
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+			problem.Write(w, r, m.logger, apperr.ErrUnauthenticated)
 			return
 		}
 
 		// Проверка формата: Bearer <токен>
 		const bearerPrefix = "Bearer "
 		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			http.Error(w, "Неверный формат токена", http.StatusUnauthorized)
+			problem.Write(w, r, m.logger, fmt.Errorf("%w: ожидается заголовок Authorization: Bearer <токен>", apperr.ErrInvalidAccessToken))
 			return
 		}
 
 		// Извлечение токена из заголовка
 		tokenString := strings.TrimPrefix(authHeader, bearerPrefix)
 
-		// Проверка и разбор токена, получение ID пользователя
-		userID, err := m.authService.ParseToken(tokenString)
+		// Проверка и разбор токена, получение ID пользователя и сессии
+		claims, err := m.authService.ParseToken(r.Context(), tokenString)
+		if err != nil {
+			problem.Write(w, r, m.logger, fmt.Errorf("%w: %w", apperr.ErrInvalidAccessToken, err))
+			return
+		}
+
+		// Проверка списка отозванных токенов
+		revoked, err := m.authService.IsRevoked(r.Context(), claims.TokenID)
 		if err != nil {
-			m.logger.WithError(err).Warn("Ошибка проверки токена")
-			http.Error(w, "Неверный или просроченный токен", http.StatusUnauthorized)
+			problem.Write(w, r, m.logger, fmt.Errorf("ошибка проверки отзыва токена: %w", err))
+			return
+		}
+		if revoked {
+			problem.Write(w, r, m.logger, apperr.ErrAccessTokenRevoked)
 			return
 		}
 
-		// Добавление ID пользователя в контекст запроса
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		// Добавление ID пользователя и сессии в контекст запроса
+		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+		ctx = context.WithValue(ctx, ClaimsKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -73,3 +93,21 @@ func GetUserID(ctx context.Context) (int64, error) {
 	}
 	return userID, nil
 }
+
+// GetSessionID извлекает ID сессии, в которой выпущен access-токен, из контекста
+func GetSessionID(ctx context.Context) (int64, error) {
+	sessionID, ok := ctx.Value(SessionIDKey).(int64)
+	if !ok {
+		return 0, errors.New("ID сессии не найден или имеет неверный тип в контексте")
+	}
+	return sessionID, nil
+}
+
+// GetClaims извлекает данные access-токена текущего запроса из контекста
+func GetClaims(ctx context.Context) (*service.AccessClaims, error) {
+	claims, ok := ctx.Value(ClaimsKey).(*service.AccessClaims)
+	if !ok {
+		return nil, errors.New("данные токена не найдены или имеют неверный тип в контексте")
+	}
+	return claims, nil
+}
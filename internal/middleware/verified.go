@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/problem"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// VerifiedMiddleware пропускает к маршрутам только пользователей с подтвержденным email. До подтверждения
+// пользователь может входить в систему, управлять сессиями и паролем, но не может работать со счетами.
+// Должен подключаться после JWTMiddleware
+type VerifiedMiddleware struct {
+	userRepo repository.UserRepository // Репозиторий пользователей
+	logger   *logrus.Logger            // Логгер для логирования
+}
+
+// NewVerifiedMiddleware создает новый middleware для проверки подтверждения email
+func NewVerifiedMiddleware(userRepo repository.UserRepository, logger *logrus.Logger) *VerifiedMiddleware {
+	return &VerifiedMiddleware{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// Middleware проверяет, что email пользователя из контекста запроса подтвержден
+func (m *VerifiedMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := GetUserID(r.Context())
+		if err != nil {
+			problem.Write(w, r, m.logger, apperr.ErrUnauthenticated)
+			return
+		}
+
+		user, err := m.userRepo.GetByID(r.Context(), userID)
+		if err != nil {
+			problem.Write(w, r, m.logger, fmt.Errorf("ошибка получения пользователя для проверки подтверждения email: %w", err))
+			return
+		}
+		if user.EmailVerifiedAt == nil {
+			problem.Write(w, r, m.logger, apperr.ErrEmailNotVerified)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -7,9 +7,25 @@ import (
 
 // Account представляет модель банковского счета
 type Account struct {
-	ID        int64           `db:"id"       json:"id"`           // Уникальный идентификатор счета
-	UserID    int64           `db:"user_id"  json:"user_id"`      // Идентификатор владельца счета
-	Balance   decimal.Decimal `db:"balance"  json:"balance"`      // Текущий баланс счета
-	Currency  Currency        `db:"currency" json:"currency"`     // Валюта счета
-	CreatedAt time.Time       `db:"created_at" json:"created_at"` // Дата и время создания счета
+	ID             int64           `db:"id"       json:"id"`                     // Уникальный идентификатор счета
+	UserID         int64           `db:"user_id"  json:"user_id"`                // Идентификатор владельца счета
+	Type           Type            `db:"type"     json:"type"`                   // Тип счета (текущий или накопительный)
+	Balance        decimal.Decimal `db:"balance"  json:"balance"`                // Текущий баланс счета
+	OverdraftLimit decimal.Decimal `db:"overdraft_limit" json:"overdraft_limit"` // Одобренный лимит овердрафта
+	Restricted     bool            `db:"restricted" json:"restricted"`           // Расходные операции ограничены из-за просрочки по кредиту
+	Currency       Currency        `db:"currency" json:"currency"`               // Валюта счета
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`           // Дата и время создания счета
+}
+
+// Available возвращает сумму, доступную для списания с учетом лимита овердрафта
+func (a *Account) Available() decimal.Decimal {
+	return a.Balance.Add(a.OverdraftLimit)
+}
+
+// OverdraftUsed возвращает использованную часть овердрафта (модуль отрицательного баланса)
+func (a *Account) OverdraftUsed() decimal.Decimal {
+	if a.Balance.IsNegative() {
+		return a.Balance.Neg()
+	}
+	return decimal.Zero
 }
@@ -0,0 +1,14 @@
+package account
+
+// Type представляет тип счета
+type Type string
+
+const (
+	CURRENT Type = "CURRENT" // Текущий счет
+	SAVINGS Type = "SAVINGS" // Накопительный счет с ежедневным начислением процентов
+)
+
+// Valid сообщает, является ли тип счета допустимым
+func (t Type) Valid() bool {
+	return t == CURRENT || t == SAVINGS
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"time"
+)
+
+// Status представляет статус кредитной заявки
+type Status string
+
+const (
+	APPROVED      Status = "APPROVED"      // Заявка одобрена, кредит можно оформить
+	REJECTED      Status = "REJECTED"      // Заявка отклонена
+	MANUAL_REVIEW Status = "MANUAL_REVIEW" // Заявка требует ручной проверки
+	ACCEPTED      Status = "ACCEPTED"      // Заемщик принял одобрение, кредит оформлен
+	EXPIRED       Status = "EXPIRED"       // Срок действия одобрения истек
+)
+
+// Reason представляет код причины решения по заявке
+type Reason string
+
+const (
+	CURRENT_ARREARS      Reason = "CURRENT_ARREARS"      // Есть непогашенная просрочка по кредитам
+	DEBT_LOAD_TOO_HIGH   Reason = "DEBT_LOAD_TOO_HIGH"   // Долговая нагрузка выше допустимой
+	DEBT_LOAD_ELEVATED   Reason = "DEBT_LOAD_ELEVATED"   // Долговая нагрузка выше порога ручной проверки
+	NO_INCOME_HISTORY    Reason = "NO_INCOME_HISTORY"    // Нет поступлений на счета за период оценки
+	INCOME_NOT_CONFIRMED Reason = "INCOME_NOT_CONFIRMED" // Поступления на счета ниже заявленного дохода
+	LATE_PAYMENT_HISTORY Reason = "LATE_PAYMENT_HISTORY" // Платежи по кредитам вносились с опозданием
+)
+
+// Application представляет кредитную заявку и результат ее скоринга
+type Application struct {
+	ID             int64           `db:"id"              json:"id"`              // Уникальный идентификатор заявки
+	UserID         int64           `db:"user_id"         json:"user_id"`         // Заявитель
+	AccountID      int64           `db:"account_id"      json:"account_id"`      // Счет зачисления и погашения кредита
+	Amount         decimal.Decimal `db:"amount"          json:"amount"`          // Запрошенная сумма
+	TermMonths     int             `db:"term_months"     json:"term_months"`     // Запрошенный срок в месяцах
+	Scheme         credit.Scheme   `db:"scheme"          json:"scheme"`          // Схема погашения
+	RateType       credit.RateType `db:"rate_type"       json:"rate_type"`       // Вид ставки
+	DeclaredIncome decimal.Decimal `db:"declared_income" json:"declared_income"` // Заявленный ежемесячный доход
+	ObservedIncome decimal.Decimal `db:"observed_income" json:"observed_income"` // Средние ежемесячные поступления на счета
+	DebtPayments   decimal.Decimal `db:"debt_payments"   json:"debt_payments"`   // Ежемесячные платежи по действующим кредитам
+	Payment        decimal.Decimal `db:"payment"         json:"payment"`         // Ежемесячный платеж по новому кредиту
+	DebtToIncome   decimal.Decimal `db:"debt_to_income"  json:"debt_to_income"`  // Долговая нагрузка с учетом нового кредита
+	Status         Status          `db:"status"          json:"status"`          // Решение по заявке
+	Reasons        []Reason        `db:"reasons"         json:"reasons"`         // Коды причин решения
+	CreditID       *int64          `db:"credit_id"       json:"credit_id"`       // Оформленный кредит
+	AcceptedAt     *time.Time      `db:"accepted_at"     json:"accepted_at"`     // Дата и время принятия одобрения
+	ReviewedBy     *int64          `db:"reviewed_by"     json:"reviewed_by"`     // Администратор, принявший решение вручную
+	ReviewedAt     *time.Time      `db:"reviewed_at"     json:"reviewed_at"`     // Дата и время ручного решения
+	DecisionReason *string         `db:"decision_reason" json:"decision_reason"` // Обоснование ручного решения
+	CreatedAt      time.Time       `db:"created_at"      json:"created_at"`      // Дата и время подачи заявки
+}
+
+// DecidedAt возвращает момент решения по заявке: ручного, если заявка проверялась администратором,
+// иначе автоматического при подаче
+func (a *Application) DecidedAt() time.Time {
+	if a.ReviewedAt != nil {
+		return *a.ReviewedAt
+	}
+	return a.CreatedAt
+}
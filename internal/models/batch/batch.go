@@ -0,0 +1,33 @@
+package batch
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Batch представляет пакет переводов, загруженный из файла
+type Batch struct {
+	ID            int64           `db:"id"              json:"id"`              // Уникальный идентификатор пакета
+	UserID        int64           `db:"user_id"         json:"user_id"`         // Идентификатор владельца пакета
+	FromAccountID int64           `db:"from_account_id" json:"from_account_id"` // Счет списания для всех платежей
+	MessageID     string          `db:"message_id"      json:"message_id"`      // Идентификатор исходного сообщения (MsgId)
+	SourceFormat  string          `db:"source_format"   json:"source_format"`   // Формат исходного файла (pain.001 или csv)
+	ItemCount     int             `db:"item_count"      json:"item_count"`      // Количество платежей
+	TotalAmount   decimal.Decimal `db:"total_amount"    json:"total_amount"`    // Общая сумма платежей
+	Status        Status          `db:"status"          json:"status"`          // Статус пакета
+	CreatedAt     time.Time       `db:"created_at"      json:"created_at"`      // Дата и время приема пакета
+	CompletedAt   *time.Time      `db:"completed_at"    json:"completed_at"`    // Дата и время завершения исполнения
+}
+
+// Item представляет отдельный платеж в пакете
+type Item struct {
+	ID          int64           `db:"id"            json:"id"`            // Уникальный идентификатор платежа
+	BatchID     int64           `db:"batch_id"      json:"batch_id"`      // Идентификатор пакета
+	EndToEndID  string          `db:"end_to_end_id" json:"end_to_end_id"` // Сквозной идентификатор платежа (EndToEndId)
+	ToAccountID int64           `db:"to_account_id" json:"to_account_id"` // Счет получателя
+	Amount      decimal.Decimal `db:"amount"        json:"amount"`        // Сумма платежа
+	Description string          `db:"description"   json:"description"`   // Назначение платежа
+	Status      ItemStatus      `db:"status"        json:"status"`        // Статус платежа
+	Error       string          `db:"error"         json:"error"`         // Причина отклонения
+	ExecutedAt  *time.Time      `db:"executed_at"   json:"executed_at"`   // Дата и время исполнения
+}
@@ -0,0 +1,22 @@
+package batch
+
+// Status представляет статус пакета платежей
+type Status string
+
+const (
+	PENDING             Status = "PENDING"             // Пакет принят и ожидает исполнения
+	PROCESSING          Status = "PROCESSING"          // Пакет исполняется
+	COMPLETED           Status = "COMPLETED"           // Все платежи пакета исполнены
+	PARTIALLY_COMPLETED Status = "PARTIALLY_COMPLETED" // Часть платежей пакета отклонена
+	FAILED              Status = "FAILED"              // Ни один платеж пакета не исполнен
+)
+
+// ItemStatus представляет статус отдельного платежа в пакете
+type ItemStatus string
+
+const (
+	ITEM_PENDING    ItemStatus = "PENDING"    // Платеж ожидает исполнения
+	ITEM_PROCESSING ItemStatus = "PROCESSING" // Платеж захвачен обработчиком и исполняется
+	ITEM_COMPLETED  ItemStatus = "COMPLETED"  // Платеж исполнен
+	ITEM_FAILED     ItemStatus = "FAILED"     // Платеж отклонен
+)
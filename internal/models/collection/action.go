@@ -0,0 +1,54 @@
+package collection
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Action представляет вид действия по работе с просроченной задолженностью
+type Action string
+
+const (
+	REMINDER Action = "REMINDER" // Уведомление заемщика о просрочке
+	PENALTY  Action = "PENALTY"  // Начисление неустойки за день просрочки
+	SWEEP    Action = "SWEEP"    // Списание средств с других счетов заемщика на счет кредита
+	RESTRICT Action = "RESTRICT" // Ограничение расходных операций по счетам заемщика
+	OVERDUE  Action = "OVERDUE"  // Перевод кредита в статус OVERDUE
+	CLEARED  Action = "CLEARED"  // Погашение просрочки и снятие ограничений
+)
+
+// Valid сообщает, может ли действие быть этапом эскалации
+func (a Action) Valid() bool {
+	switch a {
+	case REMINDER, PENALTY, SWEEP, RESTRICT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Recurring сообщает, повторяется ли действие каждый день просрочки после наступления этапа.
+// Остальные этапы выполняются один раз за период просрочки
+func (a Action) Recurring() bool {
+	return a == PENALTY || a == SWEEP
+}
+
+// Stage представляет этап эскалации: действие, выполняемое начиная с указанного дня просрочки
+type Stage struct {
+	Days   int    // День просрочки, с которого выполняется действие
+	Action Action // Действие
+}
+
+// Log представляет запись журнала действий по просроченному кредиту
+type Log struct {
+	ID          int64            `db:"id"            json:"id"`            // Уникальный идентификатор записи
+	CreditID    int64            `db:"credit_id"     json:"credit_id"`     // Идентификатор кредита
+	Action      Action           `db:"action"        json:"action"`        // Выполненное действие
+	StageDays   int              `db:"stage_days"    json:"stage_days"`    // День просрочки этапа (0 — смена статуса)
+	DaysPastDue int              `db:"days_past_due" json:"days_past_due"` // Дней просрочки на момент действия
+	ActionDate  time.Time        `db:"action_date"   json:"action_date"`   // День действия
+	Amount      *decimal.Decimal `db:"amount"        json:"amount"`        // Сумма неустойки или списания
+	AccountID   *int64           `db:"account_id"    json:"account_id"`    // Счет, с которого списаны средства
+	Details     string           `db:"details"       json:"details"`       // Описание действия
+	CreatedAt   time.Time        `db:"created_at"    json:"created_at"`    // Дата и время записи
+}
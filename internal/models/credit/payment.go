@@ -0,0 +1,47 @@
+package credit
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// PaymentKind представляет вид платежа по кредиту
+type PaymentKind string
+
+const (
+	SCHEDULED     PaymentKind = "SCHEDULED"     // Платеж по графику
+	PREPAYMENT    PaymentKind = "PREPAYMENT"    // Частичное досрочное погашение
+	EARLY_CLOSURE PaymentKind = "EARLY_CLOSURE" // Полное досрочное погашение
+)
+
+// PrepayMode представляет способ пересчета графика после досрочного погашения
+type PrepayMode string
+
+const (
+	REDUCE_TERM    PrepayMode = "REDUCE_TERM"    // Сохранить платеж и сократить срок
+	REDUCE_PAYMENT PrepayMode = "REDUCE_PAYMENT" // Сохранить срок и уменьшить платеж
+	FULL           PrepayMode = "FULL"           // Погасить кредит полностью
+)
+
+// Valid сообщает, поддерживается ли способ досрочного погашения
+func (m PrepayMode) Valid() bool {
+	switch m {
+	case REDUCE_TERM, REDUCE_PAYMENT, FULL:
+		return true
+	default:
+		return false
+	}
+}
+
+// Payment представляет фактически внесенный платеж по кредиту
+type Payment struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор платежа
+	CreditID      int64           `db:"credit_id"      json:"credit_id"`      // Идентификатор кредита
+	Amount        decimal.Decimal `db:"amount"         json:"amount"`         // Сумма платежа
+	Principal     decimal.Decimal `db:"principal"      json:"principal"`      // Погашение основного долга
+	Interest      decimal.Decimal `db:"interest"       json:"interest"`       // Погашение процентов
+	Penalty       decimal.Decimal `db:"penalty"        json:"penalty"`        // Погашение неустойки за просрочку
+	Kind          PaymentKind     `db:"kind"           json:"kind"`           // Вид платежа
+	TransactionID *int64          `db:"transaction_id" json:"transaction_id"` // Транзакция списания со счета
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время платежа
+}
@@ -0,0 +1,38 @@
+package credit
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// RateType представляет вид процентной ставки по кредиту
+type RateType string
+
+const (
+	FIXED    RateType = "FIXED"    // Ставка фиксирована на весь срок
+	FLOATING RateType = "FLOATING" // Ключевая ставка ЦБ РФ плюс надбавка, пересматривается при изменении ключевой
+)
+
+// Valid сообщает, поддерживается ли вид ставки
+func (t RateType) Valid() bool {
+	return t == FIXED || t == FLOATING
+}
+
+// RateChange представляет запись истории ставки по кредиту с плавающей ставкой
+type RateChange struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор записи
+	CreditID      int64           `db:"credit_id"      json:"credit_id"`      // Идентификатор кредита
+	EffectiveDate time.Time       `db:"effective_date" json:"effective_date"` // Дата, с которой действует ставка
+	KeyRate       decimal.Decimal `db:"key_rate"       json:"key_rate"`       // Ключевая ставка ЦБ РФ в процентах
+	Margin        decimal.Decimal `db:"margin"         json:"margin"`         // Надбавка к ключевой ставке (доля)
+	Rate          decimal.Decimal `db:"rate"           json:"rate"`           // Итоговая годовая ставка (доля)
+	Payment       decimal.Decimal `db:"payment"        json:"payment"`        // Ежемесячный платеж по новой ставке
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время записи
+}
+
+// KeyRate представляет сохраненное значение ключевой ставки ЦБ РФ
+type KeyRate struct {
+	ID        int64           `db:"id"         json:"id"`         // Уникальный идентификатор записи
+	Rate      decimal.Decimal `db:"rate"       json:"rate"`       // Ключевая ставка в процентах
+	FetchedAt time.Time       `db:"fetched_at" json:"fetched_at"` // Дата и время получения значения
+}
@@ -0,0 +1,14 @@
+package credit
+
+// Scheme представляет схему погашения кредита
+type Scheme string
+
+const (
+	ANNUITY        Scheme = "ANNUITY"        // Равные ежемесячные платежи
+	DIFFERENTIATED Scheme = "DIFFERENTIATED" // Равные части основного долга и проценты на остаток
+)
+
+// Valid сообщает, поддерживается ли схема погашения
+func (s Scheme) Valid() bool {
+	return s == ANNUITY || s == DIFFERENTIATED
+}
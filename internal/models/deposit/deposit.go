@@ -0,0 +1,36 @@
+package deposit
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Deposit представляет срочный вклад, открытый со счета пользователя
+type Deposit struct {
+	ID             int64           `db:"id"                json:"id"`              // Уникальный идентификатор вклада
+	UserID         int64           `db:"user_id"           json:"user_id"`         // Идентификатор владельца вклада
+	AccountID      int64           `db:"account_id"        json:"account_id"`      // Счет, с которого открыт вклад и на который он возвращается
+	Principal      decimal.Decimal `db:"principal"         json:"principal"`       // Сумма вклада в текущем сроке
+	Rate           decimal.Decimal `db:"rate"              json:"rate"`            // Годовая ставка (доля)
+	RateSource     RateSource      `db:"rate_source"       json:"rate_source"`     // Источник ставки
+	PenaltyRate    decimal.Decimal `db:"penalty_rate"      json:"penalty_rate"`    // Ставка при досрочном закрытии (доля)
+	TermMonths     int             `db:"term_months"       json:"term_months"`     // Срок вклада в месяцах
+	MaturityAction MaturityAction  `db:"maturity_action"   json:"maturity_action"` // Действие по окончании срока
+	StartDate      time.Time       `db:"start_date"        json:"start_date"`      // Начало текущего срока
+	MaturityDate   time.Time       `db:"maturity_date"     json:"maturity_date"`   // Окончание текущего срока
+	Rollovers      int             `db:"rollovers"         json:"rollovers"`       // Количество продлений
+	InterestPaid   decimal.Decimal `db:"interest_paid"     json:"interest_paid"`   // Выплаченные и причисленные проценты за все сроки
+	Status         Status          `db:"status"            json:"status"`          // Статус вклада
+	ClosedAt       *time.Time      `db:"closed_at"         json:"closed_at"`       // Дата и время закрытия
+	CreatedAt      time.Time       `db:"created_at"        json:"created_at"`      // Дата и время открытия
+}
+
+// Interest рассчитывает простые проценты на сумму вклада по ставке rate за дни с начала срока до date
+// (ACT/365) с округлением до копеек
+func (d *Deposit) Interest(rate decimal.Decimal, date time.Time) decimal.Decimal {
+	days := int64(date.Sub(d.StartDate).Hours() / 24)
+	if days <= 0 {
+		return decimal.Zero
+	}
+	return d.Principal.Mul(rate).Mul(decimal.NewFromInt(days)).Div(decimal.NewFromInt(365)).Round(2)
+}
@@ -0,0 +1,31 @@
+package deposit
+
+// Status представляет статус вклада
+type Status string
+
+const (
+	ACTIVE       Status = "ACTIVE"       // Вклад открыт
+	CLOSED       Status = "CLOSED"       // Вклад закрыт по окончании срока
+	CLOSED_EARLY Status = "CLOSED_EARLY" // Вклад закрыт досрочно
+)
+
+// MaturityAction представляет действие по окончании срока вклада
+type MaturityAction string
+
+const (
+	PAYOUT   MaturityAction = "PAYOUT"   // Вклад и проценты возвращаются на счет
+	ROLLOVER MaturityAction = "ROLLOVER" // Вклад продлевается на тот же срок с причислением процентов
+)
+
+// Valid сообщает, является ли действие допустимым
+func (a MaturityAction) Valid() bool {
+	return a == PAYOUT || a == ROLLOVER
+}
+
+// RateSource представляет источник ставки вклада
+type RateSource string
+
+const (
+	FIXED    RateSource = "FIXED"    // Ставка из настроек банка
+	KEY_RATE RateSource = "KEY_RATE" // Ключевая ставка ЦБ РФ с надбавкой
+)
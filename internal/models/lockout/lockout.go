@@ -0,0 +1,32 @@
+package lockout
+
+import "time"
+
+// Scope представляет, по какому признаку считаются неудачные попытки входа
+type Scope string
+
+const (
+	EMAIL Scope = "EMAIL" // По email, указанному при входе
+	IP    Scope = "IP"    // По IP-адресу клиента
+)
+
+// Entry представляет счетчик неудачных попыток входа по email или IP-адресу. Пока не наступило
+// BlockedUntil, попытки входа отклоняются без проверки пароля
+type Entry struct {
+	Scope         Scope      `db:"scope"           json:"scope"`           // Признак: email или IP-адрес
+	Key           string     `db:"key"             json:"key"`             // Email в нижнем регистре или IP-адрес
+	Failures      int        `db:"failures"        json:"failures"`        // Неудачных попыток подряд
+	LastFailureAt time.Time  `db:"last_failure_at" json:"last_failure_at"` // Дата и время последней неудачной попытки
+	BlockedUntil  *time.Time `db:"blocked_until"   json:"blocked_until"`   // До какого момента попытки отклоняются
+	LockedAt      *time.Time `db:"locked_at"       json:"locked_at"`       // Дата и время блокировки после превышения лимита
+}
+
+// Blocked сообщает, отклоняются ли попытки входа в момент now
+func (e *Entry) Blocked(now time.Time) bool {
+	return e.BlockedUntil != nil && now.Before(*e.BlockedUntil)
+}
+
+// Locked сообщает, действует ли в момент now блокировка после превышения лимита неудачных попыток
+func (e *Entry) Locked(now time.Time) bool {
+	return e.LockedAt != nil && e.Blocked(now)
+}
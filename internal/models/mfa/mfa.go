@@ -0,0 +1,33 @@
+package mfa
+
+import "time"
+
+// TOTP представляет секрет приложения-аутентификатора пользователя. До подтверждения первым кодом
+// двухфакторная аутентификация не включена и секрет можно перевыпустить
+type TOTP struct {
+	UserID      int64      `db:"user_id"         json:"user_id"`      // Идентификатор пользователя
+	Secret      []byte     `db:"secret"          json:"-"`            // Секрет TOTP (хранится в зашифрованном виде)
+	ConfirmedAt *time.Time `db:"confirmed_at"    json:"confirmed_at"` // Дата и время подтверждения первым кодом
+	LastStep    int64      `db:"last_step"       json:"-"`            // Шаг времени последнего принятого кода
+	Failures    int        `db:"failed_attempts" json:"-"`            // Неверных кодов подряд
+	LockedUntil *time.Time `db:"locked_until"    json:"-"`            // До какого времени ввод кодов заблокирован
+	CreatedAt   time.Time  `db:"created_at"      json:"created_at"`   // Дата и время выпуска секрета
+}
+
+// Enabled сообщает, включена ли двухфакторная аутентификация
+func (t *TOTP) Enabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// Challenge представляет незавершенный вход: пароль проверен, ожидается код из приложения
+// или код восстановления. Клиент получает токен, в базе данных хранится только его хеш
+type Challenge struct {
+	ID          int64      `db:"id"           json:"id"`           // Уникальный идентификатор
+	UserID      int64      `db:"user_id"      json:"user_id"`      // Идентификатор пользователя
+	UserAgent   string     `db:"user_agent"   json:"user_agent"`   // User-Agent клиента при входе
+	IPAddress   string     `db:"ip_address"   json:"ip_address"`   // IP-адрес клиента при входе
+	Attempts    int        `db:"attempts"     json:"attempts"`     // Количество попыток ввода кода
+	ExpiresAt   time.Time  `db:"expires_at"   json:"expires_at"`   // Срок действия токена
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at"` // Дата и время успешного ввода кода
+	CreatedAt   time.Time  `db:"created_at"   json:"created_at"`   // Дата и время создания
+}
@@ -0,0 +1,18 @@
+package overdraft
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Charge представляет проценты за пользование овердрафтом за один день
+type Charge struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор начисления
+	AccountID     int64           `db:"account_id"     json:"account_id"`     // Идентификатор счета
+	ChargeDate    time.Time       `db:"charge_date"    json:"charge_date"`    // День начисления
+	Balance       decimal.Decimal `db:"balance"        json:"balance"`        // Остаток на конец дня
+	Rate          decimal.Decimal `db:"rate"           json:"rate"`           // Годовая ставка по овердрафту
+	Amount        decimal.Decimal `db:"amount"         json:"amount"`         // Начисленные проценты
+	TransactionID *int64          `db:"transaction_id" json:"transaction_id"` // Транзакция списания процентов
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время начисления
+}
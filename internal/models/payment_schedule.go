@@ -10,7 +10,11 @@ type PaymentSchedule struct {
 	ID        int64           `db:"id"         json:"id"`         // Уникальный идентификатор платежа
 	CreditID  int64           `db:"credit_id"  json:"credit_id"`  // Идентификатор связанного кредита
 	DueDate   time.Time       `db:"due_date"   json:"due_date"`   // Дата погашения платежа
-	Amount    decimal.Decimal `db:"amount"     json:"amount"`     // Сумма платежа
+	Amount    decimal.Decimal `db:"amount"     json:"amount"`     // Сумма платежа, включая неустойку
+	Principal decimal.Decimal `db:"principal"  json:"principal"`  // Погашение основного долга
+	Interest  decimal.Decimal `db:"interest"   json:"interest"`   // Погашение процентов
+	Penalty   decimal.Decimal `db:"penalty"    json:"penalty"`    // Неустойка, начисленная за просрочку платежа
 	Paid      bool            `db:"paid"       json:"paid"`       // Статус оплаты (оплачен/не оплачен)
+	PaidAt    *time.Time      `db:"paid_at"    json:"paid_at"`    // Дата и время оплаты
 	CreatedAt time.Time       `db:"created_at" json:"created_at"` // Дата и время создания записи о платеже
 }
@@ -0,0 +1,37 @@
+package paymentrequest
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// PaymentRequest представляет запрос одного пользователя к другому на оплату (счет на оплату)
+type PaymentRequest struct {
+	ID             int64           `db:"id"              json:"id"`              // Уникальный идентификатор запроса
+	RequesterID    int64           `db:"requester_id"    json:"requester_id"`    // Пользователь, выставивший запрос
+	RequesterEmail string          `db:"requester_email" json:"requester_email"` // Email получателя денег
+	PayerID        int64           `db:"payer_id"        json:"payer_id"`        // Пользователь, которому выставлен запрос
+	PayerEmail     string          `db:"payer_email"     json:"payer_email"`     // Email плательщика
+	ToAccountID    int64           `db:"to_account_id"   json:"to_account_id"`   // Счет зачисления оплаты
+	Amount         decimal.Decimal `db:"amount"          json:"amount"`          // Запрошенная сумма
+	PaidAmount     decimal.Decimal `db:"paid_amount"     json:"paid_amount"`     // Оплаченная сумма
+	Memo           string          `db:"memo"            json:"memo"`            // Назначение платежа
+	DueDate        time.Time       `db:"due_date"        json:"due_date"`        // Последний день оплаты
+	Status         Status          `db:"status"          json:"status"`          // Статус запроса
+	CreatedAt      time.Time       `db:"created_at"      json:"created_at"`      // Дата и время создания
+	UpdatedAt      time.Time       `db:"updated_at"      json:"updated_at"`      // Дата и время последнего изменения
+}
+
+// Remaining возвращает неоплаченный остаток запроса
+func (r *PaymentRequest) Remaining() decimal.Decimal {
+	return r.Amount.Sub(r.PaidAmount)
+}
+
+// Payment представляет оплату по запросу
+type Payment struct {
+	ID            int64           `db:"id"              json:"id"`              // Уникальный идентификатор оплаты
+	RequestID     int64           `db:"request_id"      json:"request_id"`      // Идентификатор запроса
+	FromAccountID int64           `db:"from_account_id" json:"from_account_id"` // Счет списания плательщика
+	Amount        decimal.Decimal `db:"amount"          json:"amount"`          // Сумма оплаты
+	CreatedAt     time.Time       `db:"created_at"      json:"created_at"`      // Дата и время оплаты
+}
@@ -0,0 +1,18 @@
+package paymentrequest
+
+// Status представляет статус запроса на оплату
+type Status string
+
+const (
+	PENDING        Status = "PENDING"        // Ожидает решения плательщика
+	PARTIALLY_PAID Status = "PARTIALLY_PAID" // Оплачен частично
+	PAID           Status = "PAID"           // Оплачен полностью
+	DECLINED       Status = "DECLINED"       // Отклонен плательщиком
+	CANCELLED      Status = "CANCELLED"      // Отозван получателем
+	EXPIRED        Status = "EXPIRED"        // Срок оплаты истек
+)
+
+// Open сообщает, можно ли еще оплатить, отклонить или отозвать запрос
+func (s Status) Open() bool {
+	return s == PENDING || s == PARTIALLY_PAID
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// QRPayment представляет оплату по QR-коду; одноразовый код QR-кода (nonce) не может быть оплачен повторно
+type QRPayment struct {
+	ID            int64           `db:"id" json:"id"`                           // Уникальный идентификатор оплаты
+	Nonce         string          `db:"nonce" json:"nonce"`                     // Одноразовый код QR-кода
+	FromAccountID int64           `db:"from_account_id" json:"from_account_id"` // Счет плательщика
+	ToAccountID   int64           `db:"to_account_id" json:"to_account_id"`     // Счет получателя
+	Amount        decimal.Decimal `db:"amount" json:"amount"`                   // Сумма оплаты
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`           // Дата и время оплаты
+}
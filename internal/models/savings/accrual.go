@@ -0,0 +1,20 @@
+package savings
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Accrual представляет проценты, начисленные на остаток накопительного счета за один день
+type Accrual struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор начисления
+	AccountID     int64           `db:"account_id"     json:"account_id"`     // Идентификатор счета
+	AccrualDate   time.Time       `db:"accrual_date"   json:"accrual_date"`   // День начисления
+	Balance       decimal.Decimal `db:"balance"        json:"balance"`        // Остаток на конец дня
+	Rate          decimal.Decimal `db:"rate"           json:"rate"`           // Годовая ставка, действовавшая в этот день
+	DayCount      DayCount        `db:"day_count"      json:"day_count"`      // Конвенция подсчета дней
+	Amount        decimal.Decimal `db:"amount"         json:"amount"`         // Начисленные проценты (без округления до копеек)
+	TransactionID *int64          `db:"transaction_id" json:"transaction_id"` // Транзакция капитализации
+	CapitalizedAt *time.Time      `db:"capitalized_at" json:"capitalized_at"` // Дата и время капитализации
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время начисления
+}
@@ -0,0 +1,52 @@
+package savings
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// DayCount представляет конвенцию подсчета дней для начисления процентов
+type DayCount string
+
+const (
+	ACT365 DayCount = "ACT/365" // Фактическое число дней, год — 365 дней
+	ACTACT DayCount = "ACT/ACT" // Фактическое число дней, год — фактическая длина года (365 или 366 дней)
+)
+
+// Valid сообщает, является ли конвенция допустимой
+func (d DayCount) Valid() bool {
+	return d == ACT365 || d == ACTACT
+}
+
+// DaysInYear возвращает базу начисления для дня date
+func (d DayCount) DaysInYear(date time.Time) int64 {
+	if d == ACTACT {
+		year := date.Year()
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 366
+		}
+	}
+	return 365
+}
+
+// Rate представляет ставку по накопительным счетам, действующую с указанной даты
+type Rate struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор ставки
+	EffectiveDate time.Time       `db:"effective_date" json:"effective_date"` // Дата, с которой действует ставка
+	Rate          decimal.Decimal `db:"rate"           json:"rate"`           // Годовая ставка (доля, 0.16 = 16%)
+	DayCount      DayCount        `db:"day_count"      json:"day_count"`      // Конвенция подсчета дней
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время создания записи
+}
+
+// RateOn возвращает ставку, действующую в день date, из списка ставок, упорядоченного по дате начала действия.
+// Возвращает nil, если на эту дату ставка еще не установлена
+func RateOn(rates []*Rate, date time.Time) *Rate {
+	var current *Rate
+	for _, r := range rates {
+		if r.EffectiveDate.After(date) {
+			break
+		}
+		current = r
+	}
+	return current
+}
@@ -0,0 +1,54 @@
+package session
+
+import "time"
+
+// RevokeReason представляет причину завершения сессии
+type RevokeReason string
+
+const (
+	LOGOUT  RevokeReason = "LOGOUT"  // Выход из системы на устройстве сессии
+	REVOKED RevokeReason = "REVOKED" // Сессия завершена пользователем с другого устройства
+	REUSE   RevokeReason = "REUSE"   // Повторно предъявлен уже использованный refresh-токен
+	ROTATED RevokeReason = "ROTATED" // Access-токен заменен новым при обновлении токенов сессии
+
+	PASSWORD_CHANGE RevokeReason = "PASSWORD_CHANGE" // Пароль изменен пользователем
+	PASSWORD_RESET  RevokeReason = "PASSWORD_RESET"  // Пароль сброшен по ссылке из письма
+)
+
+// Session представляет сессию пользователя на устройстве. Сессия объединяет цепочку refresh-токенов,
+// выпущенных при входе и последующих обновлениях
+type Session struct {
+	ID           int64         `db:"id"            json:"id"`            // Уникальный идентификатор сессии
+	UserID       int64         `db:"user_id"       json:"user_id"`       // Идентификатор пользователя
+	UserAgent    string        `db:"user_agent"    json:"user_agent"`    // User-Agent клиента при входе
+	IPAddress    string        `db:"ip_address"    json:"ip_address"`    // IP-адрес клиента при входе
+	LastIP       string        `db:"last_ip"       json:"last_ip"`       // IP-адрес клиента при последнем обновлении
+	CreatedAt    time.Time     `db:"created_at"    json:"created_at"`    // Дата и время входа
+	LastUsedAt   time.Time     `db:"last_used_at"  json:"last_used_at"`  // Дата и время последнего обновления токенов
+	ExpiresAt    time.Time     `db:"expires_at"    json:"expires_at"`    // Срок действия текущего refresh-токена
+	RevokedAt    *time.Time    `db:"revoked_at"    json:"revoked_at"`    // Дата и время завершения сессии
+	RevokeReason *RevokeReason `db:"revoke_reason" json:"revoke_reason"` // Причина завершения сессии
+
+	AccessJTI       *string    `db:"access_jti"        json:"-"` // Идентификатор последнего выданного access-токена
+	AccessExpiresAt *time.Time `db:"access_expires_at" json:"-"` // Срок действия последнего access-токена
+	MFAVerifiedAt   *time.Time `db:"mfa_verified_at"   json:"-"` // Время последнего подтверждения кодом двухфакторной аутентификации
+}
+
+// Rotation описывает данные, сохраняемые при обновлении пары токенов в сессии
+type Rotation struct {
+	TokenHash       string    // Хеш нового refresh-токена
+	ExpiresAt       time.Time // Срок действия нового refresh-токена
+	IP              string    // IP-адрес клиента
+	AccessJTI       string    // Идентификатор нового access-токена
+	AccessExpiresAt time.Time // Срок действия нового access-токена
+}
+
+// MFAFresh сообщает, подтверждена ли сессия кодом двухфакторной аутентификации не раньше чем за ttl до now
+func (s *Session) MFAFresh(now time.Time, ttl time.Duration) bool {
+	return s.MFAVerifiedAt != nil && now.Before(s.MFAVerifiedAt.Add(ttl))
+}
+
+// Active сообщает, действует ли сессия в момент now
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
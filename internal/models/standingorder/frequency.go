@@ -0,0 +1,58 @@
+package standingorder
+
+import "time"
+
+// Frequency представляет периодичность исполнения поручения
+type Frequency string
+
+const (
+	ONCE    Frequency = "ONCE"    // Однократный перевод с отложенной датой
+	DAILY   Frequency = "DAILY"   // Ежедневно
+	WEEKLY  Frequency = "WEEKLY"  // Еженедельно в день недели даты начала
+	MONTHLY Frequency = "MONTHLY" // Ежемесячно в указанный день месяца
+)
+
+// Valid сообщает, поддерживается ли периодичность
+func (f Frequency) Valid() bool {
+	switch f {
+	case ONCE, DAILY, WEEKLY, MONTHLY:
+		return true
+	default:
+		return false
+	}
+}
+
+// FirstDate возвращает первую дату исполнения не раньше start
+func (f Frequency) FirstDate(start time.Time, dayOfMonth int) time.Time {
+	if f != MONTHLY {
+		return start
+	}
+	date := monthDay(start.Year(), start.Month(), dayOfMonth, start.Location())
+	if date.Before(start) {
+		date = monthDay(start.Year(), start.Month()+1, dayOfMonth, start.Location())
+	}
+	return date
+}
+
+// NextDate возвращает дату исполнения, следующую за current; для ONCE следующей даты нет
+func (f Frequency) NextDate(current time.Time, dayOfMonth int) (time.Time, bool) {
+	switch f {
+	case DAILY:
+		return current.AddDate(0, 0, 1), true
+	case WEEKLY:
+		return current.AddDate(0, 0, 7), true
+	case MONTHLY:
+		return monthDay(current.Year(), current.Month()+1, dayOfMonth, current.Location()), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// monthDay возвращает день day указанного месяца; если в месяце меньше дней, берется последний день
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,39 @@
+package standingorder
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// StandingOrder представляет регулярное или отложенное платежное поручение
+type StandingOrder struct {
+	ID                  int64           `db:"id"                   json:"id"`                   // Уникальный идентификатор поручения
+	UserID              int64           `db:"user_id"              json:"user_id"`              // Идентификатор владельца поручения
+	FromAccountID       int64           `db:"from_account_id"      json:"from_account_id"`      // Счет списания
+	ToAccountID         int64           `db:"to_account_id"        json:"to_account_id"`        // Счет зачисления
+	Amount              decimal.Decimal `db:"amount"               json:"amount"`               // Сумма перевода
+	Frequency           Frequency       `db:"frequency"            json:"frequency"`            // Периодичность
+	DayOfMonth          int             `db:"day_of_month"         json:"day_of_month"`         // День месяца для ежемесячных поручений
+	StartDate           time.Time       `db:"start_date"           json:"start_date"`           // Дата начала
+	EndDate             *time.Time      `db:"end_date"             json:"end_date"`             // Дата окончания (необязательно)
+	MaxOccurrences      *int            `db:"max_occurrences"      json:"max_occurrences"`      // Количество исполнений (необязательно)
+	Occurrences         int             `db:"occurrences"          json:"occurrences"`          // Количество обработанных дат исполнения
+	NextRunDate         time.Time       `db:"next_run_date"        json:"next_run_date"`        // Следующая дата исполнения
+	ConsecutiveFailures int             `db:"consecutive_failures" json:"consecutive_failures"` // Количество неудачных исполнений подряд
+	Status              Status          `db:"status"               json:"status"`               // Статус поручения
+	Description         string          `db:"description"          json:"description"`          // Назначение перевода
+	CreatedAt           time.Time       `db:"created_at"           json:"created_at"`           // Дата и время создания
+}
+
+// Execution представляет исполнение поручения за одну дату по расписанию
+type Execution struct {
+	ID            int64           `db:"id"             json:"id"`             // Уникальный идентификатор исполнения
+	OrderID       int64           `db:"order_id"       json:"order_id"`       // Идентификатор поручения
+	ScheduledDate time.Time       `db:"scheduled_date" json:"scheduled_date"` // Дата исполнения по расписанию
+	Amount        decimal.Decimal `db:"amount"         json:"amount"`         // Сумма перевода
+	Status        ExecutionStatus `db:"status"         json:"status"`         // Результат исполнения
+	Error         string          `db:"error"          json:"error"`          // Описание ошибки
+	Attempts      int             `db:"attempts"       json:"attempts"`       // Количество попыток перевода
+	StartedAt     *time.Time      `db:"started_at"     json:"started_at"`     // Время захвата последней попытки
+	CreatedAt     time.Time       `db:"created_at"     json:"created_at"`     // Дата и время первой попытки
+}
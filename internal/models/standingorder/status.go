@@ -0,0 +1,21 @@
+package standingorder
+
+// Status представляет статус платежного поручения
+type Status string
+
+const (
+	ACTIVE    Status = "ACTIVE"    // Поручение исполняется по расписанию
+	PAUSED    Status = "PAUSED"    // Поручение приостановлено после повторных неудач
+	COMPLETED Status = "COMPLETED" // Все исполнения выполнены или достигнута дата окончания
+	CANCELLED Status = "CANCELLED" // Поручение отменено пользователем
+)
+
+// ExecutionStatus представляет результат попытки исполнения поручения
+type ExecutionStatus string
+
+const (
+	EXECUTION_PROCESSING ExecutionStatus = "PROCESSING" // Дата захвачена обработчиком, перевод выполняется
+	EXECUTION_SUCCESS    ExecutionStatus = "SUCCESS"    // Перевод выполнен
+	EXECUTION_RETRY      ExecutionStatus = "RETRY"      // Недостаточно средств, попытка будет повторена в течение дня
+	EXECUTION_FAILED     ExecutionStatus = "FAILED"     // Перевод за дату исполнения не выполнен
+)
@@ -0,0 +1,17 @@
+package transaction
+
+// Kind представляет вид операции, в результате которой создана транзакция. Записывается при создании
+// транзакции и позволяет отличать доходы и расходы клиента от внутренних движений средств
+type Kind string
+
+const (
+	KIND_EXTERNAL            Kind = "EXTERNAL"            // Пополнение или списание без счета-контрагента в банке
+	KIND_TRANSFER            Kind = "TRANSFER"            // Перевод между счетами банка; счет-контрагент указан в транзакции
+	KIND_CREDIT_DISBURSEMENT Kind = "CREDIT_DISBURSEMENT" // Выдача кредита
+	KIND_CREDIT_PAYMENT      Kind = "CREDIT_PAYMENT"      // Платеж по кредиту
+	KIND_DEPOSIT_OPENING     Kind = "DEPOSIT_OPENING"     // Перевод средств во вклад при открытии
+	KIND_DEPOSIT_CLOSING     Kind = "DEPOSIT_CLOSING"     // Возврат суммы вклада при закрытии
+	KIND_INTEREST            Kind = "INTEREST"            // Начисление процентов
+	KIND_OVERDRAFT_INTEREST  Kind = "OVERDRAFT_INTEREST"  // Списание процентов за пользование овердрафтом
+	KIND_UNKNOWN             Kind = "UNKNOWN"             // Транзакция создана до учета вида операции, и вид не удалось установить
+)
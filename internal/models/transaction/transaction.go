@@ -7,10 +7,20 @@ import (
 
 // Transaction представляет модель банковской транзакции
 type Transaction struct {
-	ID        int64           `db:"id"          json:"id"`         // Уникальный идентификатор транзакции
-	AccountID int64           `db:"account_id"  json:"account_id"` // Идентификатор связанного счета
-	Amount    decimal.Decimal `db:"amount"      json:"amount"`     // Сумма транзакции
-	Type      Type            `db:"type"        json:"type"`       // Тип транзакции (например, перевод, пополнение)
-	Status    Status          `db:"status"      json:"status"`     // Статус транзакции (например, выполнена, ошибка)
-	CreatedAt time.Time       `db:"created_at"  json:"created_at"` // Дата и время создания транзакции
+	ID                    int64           `db:"id"                      json:"id"`                                // Уникальный идентификатор транзакции
+	AccountID             int64           `db:"account_id"              json:"account_id"`                        // Идентификатор связанного счета
+	Amount                decimal.Decimal `db:"amount"                  json:"amount"`                            // Сумма транзакции
+	Type                  Type            `db:"type"                    json:"type"`                              // Тип транзакции (например, перевод, пополнение)
+	Kind                  Kind            `db:"kind"                    json:"kind"`                              // Вид операции, в результате которой создана транзакция
+	CounterpartyAccountID *int64          `db:"counterparty_account_id" json:"counterparty_account_id,omitempty"` // Счет-контрагент перевода
+	Status                Status          `db:"status"                  json:"status"`                            // Статус транзакции (например, выполнена, ошибка)
+	CreatedAt             time.Time       `db:"created_at"              json:"created_at"`                        // Дата и время создания транзакции
+}
+
+// SignedAmount возвращает сумму транзакции со знаком: положительную для зачислений и отрицательную для списаний
+func (t *Transaction) SignedAmount() decimal.Decimal {
+	if t.Type.IsCredit() {
+		return t.Amount
+	}
+	return t.Amount.Neg()
 }
@@ -4,7 +4,19 @@ package transaction
 type Type string
 
 const (
-	DEPOSIT    Type = "DEPOSIT"    // Пополнение счета
-	WITHDRAWAL Type = "WITHDRAWAL" // Снятие средств
-	TRANSFER   Type = "TRANSFER"   // Перевод между счетами
+	DEPOSIT            Type = "DEPOSIT"            // Пополнение счета
+	WITHDRAWAL         Type = "WITHDRAWAL"         // Снятие средств
+	TRANSFER           Type = "TRANSFER"           // Перевод между счетами
+	INTEREST           Type = "INTEREST"           // Капитализация процентов
+	OVERDRAFT_INTEREST Type = "OVERDRAFT_INTEREST" // Списание процентов за пользование овердрафтом
 )
+
+// IsCredit сообщает, увеличивает ли транзакция данного типа баланс счета
+func (t Type) IsCredit() bool {
+	switch t {
+	case DEPOSIT, INTEREST:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,10 +2,68 @@ package models
 
 import "time"
 
+// Role определяет роль пользователя
+type Role string
+
+const (
+	RoleUser  Role = "USER"  // Клиент банка
+	RoleAdmin Role = "ADMIN" // Администратор: доступ к маршрутам /admin
+)
+
+// KYCStatus представляет статус проверки личности пользователя
+type KYCStatus string
+
+const (
+	KYCUnverified KYCStatus = "UNVERIFIED" // Личность не подтверждена: операции ограничены лимитами
+	KYCPending    KYCStatus = "PENDING"    // Данные профиля отправлены на проверку
+	KYCVerified   KYCStatus = "VERIFIED"   // Личность подтверждена: ограничения сняты
+	KYCRejected   KYCStatus = "REJECTED"   // Проверка не пройдена; данные можно исправить и отправить повторно
+)
+
+// Valid сообщает, является ли статус одним из известных
+func (s KYCStatus) Valid() bool {
+	switch s {
+	case KYCUnverified, KYCPending, KYCVerified, KYCRejected:
+		return true
+	default:
+		return false
+	}
+}
+
 // User представляет модель пользователя
 type User struct {
-	ID        int64     `db:"id" json:"id"`                 // Уникальный идентификатор пользователя
-	Email     string    `db:"email" json:"email"`           // Электронная почта пользователя
-	Password  string    `db:"password_hash" json:"-"`       // Хэш пароля (не выводится в JSON)
-	CreatedAt time.Time `db:"created_at" json:"created_at"` // Дата и время регистрации пользователя
+	ID               int64      `db:"id" json:"id"`                                 // Уникальный идентификатор пользователя
+	Email            string     `db:"email" json:"email"`                           // Электронная почта пользователя
+	Username         *string    `db:"username" json:"username"`                     // Уникальное имя пользователя
+	Password         string     `db:"password_hash" json:"-"`                       // Хэш пароля (не выводится в JSON)
+	FullName         string     `db:"full_name" json:"full_name"`                   // Имя и фамилия пользователя
+	DateOfBirth      *time.Time `db:"date_of_birth" json:"date_of_birth"`           // Дата рождения
+	Phone            string     `db:"phone" json:"phone"`                           // Номер телефона в формате E.164
+	Address          string     `db:"address" json:"address"`                       // Адрес регистрации
+	DefaultAccountID *int64     `db:"default_account_id" json:"default_account_id"` // Счет для зачисления переводов по email
+	Role             Role       `db:"role" json:"role"`                             // Роль пользователя
+	EmailVerifiedAt  *time.Time `db:"email_verified_at" json:"email_verified_at"`   // Дата и время подтверждения email
+	KYCStatus        KYCStatus  `db:"kyc_status" json:"kyc_status"`                 // Статус проверки личности
+	KYCUpdatedAt     *time.Time `db:"kyc_updated_at" json:"kyc_updated_at"`         // Дата и время последней смены статуса проверки
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`                 // Дата и время регистрации пользователя
+}
+
+// Profile содержит изменяемые пользователем данные профиля
+type Profile struct {
+	Username    *string    // Уникальное имя пользователя; nil — не задано
+	FullName    string     // Имя и фамилия
+	DateOfBirth *time.Time // Дата рождения
+	Phone       string     // Номер телефона
+	Address     string     // Адрес регистрации
+}
+
+// Profile возвращает текущие данные профиля пользователя
+func (u *User) Profile() Profile {
+	return Profile{
+		Username:    u.Username,
+		FullName:    u.FullName,
+		DateOfBirth: u.DateOfBirth,
+		Phone:       u.Phone,
+		Address:     u.Address,
+	}
 }
@@ -0,0 +1,22 @@
+package usertoken
+
+import "time"
+
+// Purpose представляет назначение одноразового токена, отправляемого пользователю по email
+type Purpose string
+
+const (
+	EMAIL_VERIFICATION Purpose = "EMAIL_VERIFICATION" // Подтверждение email после регистрации
+	PASSWORD_RESET     Purpose = "PASSWORD_RESET"     // Сброс забытого пароля
+)
+
+// Token представляет одноразовый токен пользователя. Пользователь получает токен в письме, в базе данных
+// хранится только его хеш
+type Token struct {
+	ID        int64      `db:"id"         json:"id"`         // Уникальный идентификатор
+	UserID    int64      `db:"user_id"    json:"user_id"`    // Идентификатор пользователя
+	Purpose   Purpose    `db:"purpose"    json:"purpose"`    // Назначение токена
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"` // Срок действия
+	UsedAt    *time.Time `db:"used_at"    json:"used_at"`    // Дата и время использования
+	CreatedAt time.Time  `db:"created_at" json:"created_at"` // Дата и время выпуска
+}
@@ -0,0 +1,83 @@
+// Package notify отправляет уведомления клиентам по электронной почте
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/config"
+)
+
+// Sender отправляет уведомление получателю
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error // Отправляет письмо с темой subject и текстом body
+}
+
+// New создает отправителя по параметрам SMTP; если SMTP-сервер не задан, письма только записываются в лог
+func New(cfg config.SMTPConfig, logger *logrus.Logger) Sender {
+	if cfg.Host == "" {
+		return &LogSender{logger: logger}
+	}
+	return NewSMTPSender(cfg)
+}
+
+// SMTPSender отправляет письма через SMTP-сервер
+type SMTPSender struct {
+	addr string    // Адрес сервера host:port
+	auth smtp.Auth // Аутентификация PLAIN или nil
+	from string    // Адрес отправителя
+}
+
+// NewSMTPSender создает отправителя писем через SMTP-сервер
+func NewSMTPSender(cfg config.SMTPConfig) *SMTPSender {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return &SMTPSender{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+// Send отправляет письмо в кодировке UTF-8
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{to}, buildMessage(s.from, to, subject, body)); err != nil {
+		return fmt.Errorf("отправка письма на %s: %w", to, err)
+	}
+	return nil
+}
+
+// buildMessage формирует письмо с заголовками; тема кодируется по RFC 2047
+func buildMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// LogSender записывает уведомления в лог вместо отправки; используется, когда SMTP-сервер не настроен
+type LogSender struct {
+	logger *logrus.Logger // Логгер для записи уведомлений
+}
+
+// Send записывает уведомление в лог
+func (s *LogSender) Send(_ context.Context, to, subject, body string) error {
+	s.logger.Infof("Уведомление для %s: %s\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,101 @@
+package pain
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// csvColumns перечисляет колонки CSV-файла пакета; колонка description необязательна
+var csvColumns = []string{"end_to_end_id", "to_account_id", "amount", "description"}
+
+// ParseCSV разбирает упрощенный CSV-файл пакета переводов со счета fromAccountID.
+// Первая строка — заголовок с колонками end_to_end_id, to_account_id, amount и необязательной description.
+// В CSV нет идентификатора сообщения, поэтому он вычисляется по содержимому файла и счету списания:
+// повторная загрузка того же файла распознается так же, как повтор MsgId в pain.001
+func ParseCSV(r io.Reader, fromAccountID int64) (*Instructions, error) {
+	digest := sha256.New()
+	fmt.Fprintf(digest, "%d\n", fromAccountID)
+	reader := csv.NewReader(io.TeeReader(r, digest))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyBatch
+		}
+		return nil, fmt.Errorf("%w: ошибка чтения заголовка CSV: %w", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range csvColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: в CSV отсутствует обязательная колонка %s", ErrInvalidFile, name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	in := &Instructions{FromAccountID: fromAccountID}
+	verr := &ValidationError{}
+
+	for index := 1; ; index++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: ошибка чтения CSV: %w", ErrInvalidFile, err)
+		}
+		if len(in.Items) >= MaxInstructions {
+			verr.Add(0, "", "превышено максимальное количество платежей в файле")
+			return nil, verr
+		}
+
+		endToEndID := field(record, "end_to_end_id")
+		amount, err := decimal.NewFromString(field(record, "amount"))
+		if err != nil {
+			verr.Add(index, endToEndID, "неверный формат суммы")
+		}
+		toID, err := strconv.ParseInt(field(record, "to_account_id"), 10, 64)
+		if err != nil {
+			verr.Add(index, endToEndID, "неверный счет получателя")
+		}
+
+		in.Items = append(in.Items, Instruction{
+			EndToEndID:  endToEndID,
+			ToAccountID: toID,
+			Amount:      amount,
+			Description: field(record, "description"),
+		})
+	}
+
+	// MsgId ограничен 35 символами: префикс и 31 шестнадцатеричный символ SHA-256
+	in.MessageID = "CSV-" + hex.EncodeToString(digest.Sum(nil))[:31]
+	in.PaymentInfoID = in.MessageID
+
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	if err := in.validate(0); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
@@ -0,0 +1,141 @@
+// Package pain реализует разбор пакетных платежных поручений ISO 20022 pain.001
+// (и упрощенной CSV-альтернативы) и формирование отчетов о статусе pain.002.
+package pain
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+)
+
+// Ограничения на содержимое пакета
+const (
+	MaxInstructions = 10000 // Максимальное количество платежей в одном файле
+	maxIDLength     = 35    // Максимальная длина идентификаторов ISO 20022 (Max35Text)
+	maxDescription  = 140   // Максимальная длина назначения платежа (Max140Text)
+)
+
+// Форматы исходных файлов пакета
+const (
+	FormatPain001 = "pain.001" // XML ISO 20022 pain.001.001.03
+	FormatCSV     = "csv"      // Упрощенный CSV
+)
+
+// Ошибки разбора и проверки пакета
+var (
+	ErrEmptyBatch    = apperr.New(apperr.Invalid, apperr.CodeEmptyBatch, "файл не содержит платежей")      // Файл не содержит ни одного платежа
+	ErrInvalidFile   = apperr.New(apperr.Invalid, apperr.CodeInvalidBatch, "неверный формат файла пакета") // Файл не разбирается как pain.001 или CSV
+	ErrBatchRejected = apperr.New(apperr.Unprocessable, apperr.CodeBatchRejected, "пакет отклонен")        // Базовая ошибка для ValidationError
+)
+
+// Instructions содержит разобранный пакет переводов с одного счета
+type Instructions struct {
+	MessageID     string           // Идентификатор сообщения (MsgId)
+	PaymentInfoID string           // Идентификатор блока платежей (PmtInfId)
+	FromAccountID int64            // Счет списания
+	Items         []Instruction    // Платежи пакета
+	ControlSum    *decimal.Decimal // Контрольная сумма из файла, если указана
+}
+
+// Instruction представляет отдельный перевод
+type Instruction struct {
+	EndToEndID  string          // Сквозной идентификатор платежа
+	ToAccountID int64           // Счет получателя
+	Amount      decimal.Decimal // Сумма перевода
+	Currency    string          // Валюта перевода
+	Description string          // Назначение платежа
+}
+
+// Total возвращает общую сумму платежей пакета
+func (in *Instructions) Total() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range in.Items {
+		total = total.Add(item.Amount)
+	}
+	return total
+}
+
+// ItemError описывает ошибку в отдельном платеже пакета
+type ItemError struct {
+	Index      int    `json:"index"`                   // Порядковый номер платежа в файле (с единицы)
+	EndToEndID string `json:"end_to_end_id,omitempty"` // Сквозной идентификатор платежа
+	Message    string `json:"message"`                 // Описание ошибки
+}
+
+// ValidationError содержит все ошибки, найденные при проверке пакета
+type ValidationError struct {
+	Errors []ItemError // Ошибки по платежам; Index = 0 для ошибок уровня файла
+}
+
+// Error реализует интерфейс error
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "ошибка проверки пакета: " + e.Errors[0].Message
+	}
+	return "ошибки проверки пакета платежей"
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrBatchRejected через errors.Is
+func (e *ValidationError) Unwrap() error {
+	return ErrBatchRejected
+}
+
+// Add добавляет ошибку платежа с порядковым номером index
+func (e *ValidationError) Add(index int, endToEndID, message string) {
+	e.Errors = append(e.Errors, ItemError{Index: index, EndToEndID: endToEndID, Message: message})
+}
+
+// Err возвращает ошибку, если были найдены нарушения, иначе nil
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// validate проверяет структурную корректность пакета: идентификаторы, суммы и контрольные значения
+func (in *Instructions) validate(declaredCount int) error {
+	verr := &ValidationError{}
+
+	if len(in.Items) == 0 {
+		return ErrEmptyBatch
+	}
+	if len(in.Items) > MaxInstructions {
+		verr.Add(0, "", "превышено максимальное количество платежей в файле")
+		return verr
+	}
+	if in.MessageID == "" || len(in.MessageID) > maxIDLength {
+		verr.Add(0, "", "идентификатор сообщения обязателен и не длиннее 35 символов")
+	}
+	if declaredCount > 0 && declaredCount != len(in.Items) {
+		verr.Add(0, "", "количество платежей не совпадает с NbOfTxs")
+	}
+	if in.ControlSum != nil && !in.ControlSum.Equal(in.Total()) {
+		verr.Add(0, "", "сумма платежей не совпадает с CtrlSum")
+	}
+
+	seen := make(map[string]bool, len(in.Items))
+	for i, item := range in.Items {
+		index := i + 1
+		switch {
+		case item.EndToEndID == "" || len(item.EndToEndID) > maxIDLength:
+			verr.Add(index, item.EndToEndID, "EndToEndId обязателен и не длиннее 35 символов")
+		case seen[item.EndToEndID]:
+			verr.Add(index, item.EndToEndID, "EndToEndId повторяется в файле")
+		}
+		seen[item.EndToEndID] = true
+
+		if item.ToAccountID <= 0 {
+			verr.Add(index, item.EndToEndID, "не указан счет получателя")
+		}
+		if !item.Amount.IsPositive() {
+			verr.Add(index, item.EndToEndID, "сумма должна быть положительной")
+		} else if !item.Amount.Equal(item.Amount.Round(2)) {
+			verr.Add(index, item.EndToEndID, "сумма должна содержать не более двух знаков после запятой")
+		}
+		if len([]rune(item.Description)) > maxDescription {
+			verr.Add(index, item.EndToEndID, "назначение платежа длиннее 140 символов")
+		}
+	}
+
+	return verr.Err()
+}
@@ -0,0 +1,128 @@
+package pain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Pain001MessageName — идентификатор поддерживаемой версии сообщения
+const Pain001MessageName = "pain.001.001.03"
+
+// pain001Document описывает используемое подмножество сообщения pain.001.001.03
+type pain001Document struct {
+	XMLName    xml.Name `xml:"Document"`
+	Initiation struct {
+		GrpHdr struct {
+			MsgID   string `xml:"MsgId"`
+			NbOfTxs string `xml:"NbOfTxs"`
+			CtrlSum string `xml:"CtrlSum"`
+		} `xml:"GrpHdr"`
+		PmtInf []struct {
+			PmtInfID string `xml:"PmtInfId"`
+			PmtMtd   string `xml:"PmtMtd"`
+			DbtrAcct struct {
+				ID string `xml:"Id>Othr>Id"`
+			} `xml:"DbtrAcct"`
+			CdtTrfTxInf []struct {
+				EndToEndID string `xml:"PmtId>EndToEndId"`
+				InstdAmt   struct {
+					Ccy   string `xml:"Ccy,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"Amt>InstdAmt"`
+				CdtrAcct struct {
+					ID string `xml:"Id>Othr>Id"`
+				} `xml:"CdtrAcct"`
+				Ustrd string `xml:"RmtInf>Ustrd"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+// ParsePain001 разбирает пакет кредитовых переводов ISO 20022 pain.001.001.03.
+// Все блоки PmtInf должны списывать средства с одного и того же счета
+func ParsePain001(r io.Reader) (*Instructions, error) {
+	var doc pain001Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: ошибка разбора pain.001: %w", ErrInvalidFile, err)
+	}
+
+	hdr := doc.Initiation.GrpHdr
+	in := &Instructions{MessageID: strings.TrimSpace(hdr.MsgID)}
+	verr := &ValidationError{}
+
+	if len(doc.Initiation.PmtInf) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	index := 0
+	for _, pmt := range doc.Initiation.PmtInf {
+		if in.PaymentInfoID == "" {
+			in.PaymentInfoID = strings.TrimSpace(pmt.PmtInfID)
+		}
+		if pmt.PmtMtd != "" && pmt.PmtMtd != "TRF" {
+			verr.Add(0, "", "поддерживается только метод платежа TRF")
+		}
+
+		fromID, err := strconv.ParseInt(strings.TrimSpace(pmt.DbtrAcct.ID), 10, 64)
+		if err != nil {
+			verr.Add(0, "", "неверный счет плательщика в PmtInf/DbtrAcct")
+		} else if in.FromAccountID != 0 && in.FromAccountID != fromID {
+			verr.Add(0, "", "все платежи пакета должны списываться с одного счета")
+		} else {
+			in.FromAccountID = fromID
+		}
+
+		for _, tx := range pmt.CdtTrfTxInf {
+			index++
+			endToEndID := strings.TrimSpace(tx.EndToEndID)
+
+			amount, err := decimal.NewFromString(strings.TrimSpace(tx.InstdAmt.Value))
+			if err != nil {
+				verr.Add(index, endToEndID, "неверный формат суммы")
+			}
+			toID, err := strconv.ParseInt(strings.TrimSpace(tx.CdtrAcct.ID), 10, 64)
+			if err != nil {
+				verr.Add(index, endToEndID, "неверный счет получателя в CdtrAcct")
+			}
+
+			in.Items = append(in.Items, Instruction{
+				EndToEndID:  endToEndID,
+				ToAccountID: toID,
+				Amount:      amount,
+				Currency:    strings.TrimSpace(tx.InstdAmt.Ccy),
+				Description: strings.TrimSpace(tx.Ustrd),
+			})
+		}
+	}
+
+	if ctrl := strings.TrimSpace(hdr.CtrlSum); ctrl != "" {
+		sum, err := decimal.NewFromString(ctrl)
+		if err != nil {
+			verr.Add(0, "", "неверный формат CtrlSum")
+		} else {
+			in.ControlSum = &sum
+		}
+	}
+
+	declared := 0
+	if nb := strings.TrimSpace(hdr.NbOfTxs); nb != "" {
+		n, err := strconv.Atoi(nb)
+		if err != nil || n <= 0 {
+			verr.Add(0, "", "неверный формат NbOfTxs")
+		}
+		declared = n
+	}
+
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	if err := in.validate(declared); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
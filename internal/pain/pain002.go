@@ -0,0 +1,132 @@
+package pain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/yujihn/bank_API/internal/models/batch"
+)
+
+// Коды статусов ISO 20022 (ExternalPaymentTransactionStatus1Code / ExternalPaymentGroupStatus1Code)
+const (
+	statusAccepted  = "ACSC" // Исполнен
+	statusPending   = "PDNG" // Ожидает исполнения
+	statusRejected  = "RJCT" // Отклонен
+	statusPartial   = "PART" // Исполнен частично
+	statusInProcess = "ACSP" // Принят, исполняется
+)
+
+// pain002Document описывает отчет о статусе платежей pain.002.001.03
+type pain002Document struct {
+	XMLName xml.Name         `xml:"Document"`
+	Xmlns   string           `xml:"xmlns,attr"`
+	Report  pain002StsReport `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002StsReport struct {
+	GrpHdr struct {
+		MsgID   string `xml:"MsgId"`
+		CreDtTm string `xml:"CreDtTm"`
+	} `xml:"GrpHdr"`
+	OrgnlGrpInfAndSts struct {
+		OrgnlMsgID   string `xml:"OrgnlMsgId"`
+		OrgnlMsgNmID string `xml:"OrgnlMsgNmId"`
+		OrgnlNbOfTxs string `xml:"OrgnlNbOfTxs"`
+		OrgnlCtrlSum string `xml:"OrgnlCtrlSum"`
+		GrpSts       string `xml:"GrpSts"`
+	} `xml:"OrgnlGrpInfAndSts"`
+	OrgnlPmtInfAndSts struct {
+		OrgnlPmtInfID string            `xml:"OrgnlPmtInfId"`
+		TxInfAndSts   []pain002TxStatus `xml:"TxInfAndSts"`
+	} `xml:"OrgnlPmtInfAndSts"`
+}
+
+type pain002TxStatus struct {
+	StsID           string         `xml:"StsId"`
+	OrgnlEndToEndID string         `xml:"OrgnlEndToEndId"`
+	TxSts           string         `xml:"TxSts"`
+	StsRsnInf       *pain002Reason `xml:"StsRsnInf,omitempty"`
+}
+
+type pain002Reason struct {
+	AddtlInf string `xml:"AddtlInf"`
+}
+
+// WritePain002 записывает отчет о статусе пакета и каждого платежа в формате pain.002.001.03
+func WritePain002(w io.Writer, b *batch.Batch, items []*batch.Item) error {
+	return writePain002(w, b, items, time.Now())
+}
+
+// writePain002 записывает отчет pain.002, сформированный в момент now
+func writePain002(w io.Writer, b *batch.Batch, items []*batch.Item, now time.Time) error {
+	doc := pain002Document{Xmlns: "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"}
+	report := &doc.Report
+
+	now = now.UTC()
+	report.GrpHdr.MsgID = fmt.Sprintf("STS-%d-%d", b.ID, now.Unix())
+	report.GrpHdr.CreDtTm = now.Format("2006-01-02T15:04:05Z")
+
+	orig := &report.OrgnlGrpInfAndSts
+	orig.OrgnlMsgID = b.MessageID
+	orig.OrgnlMsgNmID = Pain001MessageName
+	if b.SourceFormat == FormatCSV {
+		orig.OrgnlMsgNmID = "CSV"
+	}
+	orig.OrgnlNbOfTxs = strconv.Itoa(b.ItemCount)
+	orig.OrgnlCtrlSum = b.TotalAmount.StringFixed(2)
+	orig.GrpSts = groupStatus(b.Status)
+
+	report.OrgnlPmtInfAndSts.OrgnlPmtInfID = b.MessageID
+	for _, item := range items {
+		status := pain002TxStatus{
+			StsID:           strconv.FormatInt(item.ID, 10),
+			OrgnlEndToEndID: item.EndToEndID,
+			TxSts:           itemStatus(item.Status),
+		}
+		// Причина указывается только для отклоненных платежей; пустой StsRsnInf не формируется
+		if item.Error != "" {
+			status.StsRsnInf = &pain002Reason{AddtlInf: item.Error}
+		}
+		report.OrgnlPmtInfAndSts.TxInfAndSts = append(report.OrgnlPmtInfAndSts.TxInfAndSts, status)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(doc)
+}
+
+// groupStatus сопоставляет статус пакета с кодом ISO 20022
+func groupStatus(s batch.Status) string {
+	switch s {
+	case batch.COMPLETED:
+		return statusAccepted
+	case batch.PARTIALLY_COMPLETED:
+		return statusPartial
+	case batch.FAILED:
+		return statusRejected
+	case batch.PROCESSING:
+		return statusInProcess
+	default:
+		return statusPending
+	}
+}
+
+// itemStatus сопоставляет статус платежа с кодом ISO 20022
+func itemStatus(s batch.ItemStatus) string {
+	switch s {
+	case batch.ITEM_COMPLETED:
+		return statusAccepted
+	case batch.ITEM_FAILED:
+		return statusRejected
+	case batch.ITEM_PROCESSING:
+		return statusInProcess
+	default:
+		return statusPending
+	}
+}
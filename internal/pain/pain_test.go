@@ -0,0 +1,171 @@
+package pain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/batch"
+)
+
+// update перезаписывает эталонные файлы testdata/*.golden результатами текущей реализации
+var update = flag.Bool("update", false, "перезаписать эталонные файлы testdata/*.golden")
+
+// TestParseGolden разбирает файлы пакетов из testdata и сравнивает результат разбора или перечень
+// ошибок проверки с эталонными файлами
+func TestParseGolden(t *testing.T) {
+	tests := []struct {
+		file  string
+		parse func(f *os.File) (*Instructions, error)
+	}{
+		{"pain001_valid.xml", func(f *os.File) (*Instructions, error) { return ParsePain001(f) }},
+		{"pain001_invalid.xml", func(f *os.File) (*Instructions, error) { return ParsePain001(f) }},
+		{"batch_valid.csv", func(f *os.File) (*Instructions, error) { return ParseCSV(f, 42) }},
+		{"batch_invalid.csv", func(f *os.File) (*Instructions, error) { return ParseCSV(f, 42) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			in, err := tt.parse(f)
+			var result any = in
+			if err != nil {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				result = verr.Errors
+			}
+
+			got, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareGolden(t, strings.TrimSuffix(tt.file, filepath.Ext(tt.file))+".golden.json", append(got, '\n'))
+		})
+	}
+}
+
+// TestParseCSVMessageID проверяет, что идентификатор CSV-пакета зависит только от содержимого файла
+// и счета списания: повторная загрузка того же файла получает тот же MsgId
+func TestParseCSVMessageID(t *testing.T) {
+	const file = "end_to_end_id,to_account_id,amount\nA-1,101,10.00\n"
+	parse := func(content string, from int64) string {
+		t.Helper()
+		in, err := ParseCSV(strings.NewReader(content), from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.MessageID) > maxIDLength {
+			t.Errorf("длина MsgId %d, ожидается не более %d", len(in.MessageID), maxIDLength)
+		}
+		return in.MessageID
+	}
+
+	first := parse(file, 42)
+	if again := parse(file, 42); again != first {
+		t.Errorf("повторная загрузка: MsgId %s, ожидается %s", again, first)
+	}
+	if other := parse(file, 43); other == first {
+		t.Error("для другого счета списания ожидается другой MsgId")
+	}
+	if changed := parse(file+"A-2,102,1\n", 42); changed == first {
+		t.Error("для измененного файла ожидается другой MsgId")
+	}
+}
+
+// TestParseEmpty проверяет, что пустые файлы отклоняются с ErrEmptyBatch
+func TestParseEmpty(t *testing.T) {
+	if _, err := ParseCSV(strings.NewReader(""), 42); !errors.Is(err, ErrEmptyBatch) {
+		t.Errorf("ParseCSV: %v, ожидается %v", err, ErrEmptyBatch)
+	}
+	if _, err := ParseCSV(strings.NewReader("end_to_end_id,to_account_id,amount\n"), 42); !errors.Is(err, ErrEmptyBatch) {
+		t.Errorf("ParseCSV без строк: %v, ожидается %v", err, ErrEmptyBatch)
+	}
+	doc := `<Document><CstmrCdtTrfInitn><GrpHdr><MsgId>M</MsgId></GrpHdr></CstmrCdtTrfInitn></Document>`
+	if _, err := ParsePain001(strings.NewReader(doc)); !errors.Is(err, ErrEmptyBatch) {
+		t.Errorf("ParsePain001: %v, ожидается %v", err, ErrEmptyBatch)
+	}
+}
+
+// TestWritePain002Golden сравнивает отчет о статусе частично исполненного пакета с эталоном
+func TestWritePain002Golden(t *testing.T) {
+	executed := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	b := &batch.Batch{
+		ID:           7,
+		MessageID:    "PAYROLL-2024-03",
+		SourceFormat: FormatPain001,
+		ItemCount:    4,
+		TotalAmount:  decimal.RequireFromString("1751.5"),
+		Status:       batch.PARTIALLY_COMPLETED,
+	}
+	items := []*batch.Item{
+		{ID: 1, EndToEndID: "E2E-001", Status: batch.ITEM_COMPLETED, ExecutedAt: &executed},
+		{ID: 2, EndToEndID: "E2E-002", Status: batch.ITEM_FAILED, Error: "недостаточно средств"},
+		{ID: 3, EndToEndID: "E2E-003", Status: batch.ITEM_PROCESSING},
+		{ID: 4, EndToEndID: "E2E-004", Status: batch.ITEM_PENDING},
+	}
+
+	var buf bytes.Buffer
+	if err := writePain002(&buf, b, items, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "pain002_partial.golden.xml", buf.Bytes())
+}
+
+// TestStatusCodes проверяет сопоставление статусов пакета и платежа с кодами ISO 20022
+func TestStatusCodes(t *testing.T) {
+	groups := map[batch.Status]string{
+		batch.PENDING:             statusPending,
+		batch.PROCESSING:          statusInProcess,
+		batch.COMPLETED:           statusAccepted,
+		batch.PARTIALLY_COMPLETED: statusPartial,
+		batch.FAILED:              statusRejected,
+	}
+	for status, want := range groups {
+		if got := groupStatus(status); got != want {
+			t.Errorf("groupStatus(%s) = %s, ожидается %s", status, got, want)
+		}
+	}
+
+	items := map[batch.ItemStatus]string{
+		batch.ITEM_PENDING:    statusPending,
+		batch.ITEM_PROCESSING: statusInProcess,
+		batch.ITEM_COMPLETED:  statusAccepted,
+		batch.ITEM_FAILED:     statusRejected,
+	}
+	for status, want := range items {
+		if got := itemStatus(status); got != want {
+			t.Errorf("itemStatus(%s) = %s, ожидается %s", status, got, want)
+		}
+	}
+}
+
+// compareGolden сравнивает got с эталонным файлом testdata/name или перезаписывает его при -update
+func compareGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("эталон %s: %v (для создания запустите go test -update)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("результат не совпадает с эталоном %s\nполучено:\n%s\nожидается:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,344 @@
+// Package pdf реализует минимальный потоковый генератор PDF-документов без внешних зависимостей.
+//
+// Страницы записываются в выходной поток по мере добавления, поэтому в памяти
+// одновременно находится только содержимое текущей страницы. Текст выводится
+// встроенными TrueType-шрифтами Go в кодировке Identity-H, поэтому кириллица
+// отображается без установленных в системе шрифтов; шрифты записываются при
+// закрытии документа вместе с ширинами и таблицей ToUnicode использованных глифов.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Размеры страницы A4 в пунктах
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// Шрифты, доступные на странице
+const (
+	FontRegular = "F1" // Go Regular
+	FontBold    = "F2" // Go Bold
+)
+
+// Номера зарезервированных объектов документа
+const (
+	catalogObj = 1
+	pagesObj   = 2
+	fontObj    = 3
+	fontBold   = 4
+	firstFree  = 5
+)
+
+// Document представляет PDF-документ, записываемый в поток
+type Document struct {
+	w       *countingWriter // Поток вывода с подсчетом смещений
+	offsets map[int]int64   // Смещения объектов для таблицы xref
+	pages   []int           // Номера объектов страниц
+	used    glyphSet        // Глифы, выведенные на записанных страницах
+	nextObj int             // Номер следующего свободного объекта
+	closed  bool            // Признак завершенного документа
+}
+
+// glyphSet хранит использованные глифы каждого шрифта и соответствующие им символы
+type glyphSet map[*trueTypeFont]map[uint16]rune
+
+// add отмечает глиф шрифта как использованный
+func (g glyphSet) add(f *trueTypeFont, gid uint16, r rune) {
+	if g[f] == nil {
+		g[f] = make(map[uint16]rune)
+	}
+	if _, ok := g[f][gid]; !ok {
+		g[f][gid] = r
+	}
+}
+
+// NewDocument создает документ и записывает в поток его заголовок и каталог
+func NewDocument(w io.Writer) (*Document, error) {
+	d := &Document{
+		w:       &countingWriter{w: bufio.NewWriter(w)},
+		offsets: make(map[int]int64),
+		used:    make(glyphSet),
+		nextObj: firstFree,
+	}
+
+	if _, err := io.WriteString(d.w, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return nil, err
+	}
+
+	if err := d.writeObject(catalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// AddPage записывает страницу в поток; после вызова содержимое страницы можно переиспользовать
+func (d *Document) AddPage(p *Page) error {
+	if d.closed {
+		return fmt.Errorf("документ уже закрыт")
+	}
+
+	contentObj := d.allocate()
+	pageObj := d.allocate()
+
+	content := p.buf.Bytes()
+	stream := fmt.Sprintf("<< /Length %d >>\nstream\n", len(content))
+	if err := d.startObject(contentObj); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(d.w, stream); err != nil {
+		return err
+	}
+	if _, err := d.w.Write(content); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(d.w, "\nendstream\nendobj\n"); err != nil {
+		return err
+	}
+
+	page := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] "+
+		"/Resources << /Font << /%s %d 0 R /%s %d 0 R >> >> /Contents %d 0 R >>",
+		pagesObj, PageWidth, PageHeight, FontRegular, fontObj, FontBold, fontBold, contentObj)
+	if err := d.writeObject(pageObj, page); err != nil {
+		return err
+	}
+
+	d.pages = append(d.pages, pageObj)
+	for f, glyphs := range p.used {
+		for gid, r := range glyphs {
+			d.used.add(f, gid, r)
+		}
+	}
+	p.buf.Reset()
+	clear(p.used)
+	return nil
+}
+
+// PageCount возвращает количество записанных страниц
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Close записывает шрифты, дерево страниц, таблицу xref и трейлер документа
+func (d *Document) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	if err := d.writeFont(fontObj, regularFont); err != nil {
+		return err
+	}
+	if err := d.writeFont(fontBold, boldFont); err != nil {
+		return err
+	}
+
+	kids := make([]string, 0, len(d.pages))
+	for _, obj := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", obj))
+	}
+	pages := fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+	if err := d.writeObject(pagesObj, pages); err != nil {
+		return err
+	}
+
+	// Таблица перекрестных ссылок
+	xrefOffset := d.w.n
+	if _, err := fmt.Fprintf(d.w, "xref\n0 %d\n0000000000 65535 f \n", d.nextObj); err != nil {
+		return err
+	}
+	for obj := 1; obj < d.nextObj; obj++ {
+		if _, err := fmt.Fprintf(d.w, "%010d 00000 n \n", d.offsets[obj]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(d.w, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		d.nextObj, catalogObj, xrefOffset); err != nil {
+		return err
+	}
+
+	return d.w.w.Flush()
+}
+
+// writeFont записывает составной шрифт Type0 с потомком CIDFontType2, встроенным файлом шрифта
+// и таблицей ToUnicode; номера CID совпадают с номерами глифов
+func (d *Document) writeFont(obj int, f *trueTypeFont) error {
+	used := d.used[f]
+	gids := make([]int, 0, len(used))
+	for gid := range used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	fileObj := d.allocate()
+	if err := d.writeStream(fileObj, fmt.Sprintf("/Filter /FlateDecode /Length1 %d", f.length), f.data); err != nil {
+		return err
+	}
+
+	descriptorObj := d.allocate()
+	descriptor := fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.capHeight, fileObj)
+	if err := d.writeObject(descriptorObj, descriptor); err != nil {
+		return err
+	}
+
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.width(uint16(gid)))
+	}
+	cidObj := d.allocate()
+	cid := fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>",
+		f.name, descriptorObj, strings.TrimSpace(widths.String()))
+	if err := d.writeObject(cidObj, cid); err != nil {
+		return err
+	}
+
+	toUnicodeObj := d.allocate()
+	if err := d.writeStream(toUnicodeObj, "", toUnicode(gids, used)); err != nil {
+		return err
+	}
+
+	return d.writeObject(obj, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", f.name, cidObj, toUnicodeObj))
+}
+
+// toUnicode формирует CMap, по которой программы просмотра восстанавливают текст из номеров глифов
+func toUnicode(gids []int, used map[uint16]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// Блок bfchar ограничен 100 записями
+	for i := 0; i < len(gids); i += 100 {
+		chunk := gids[i:min(i+100, len(gids))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, gid := range chunk {
+			fmt.Fprintf(&b, "<%04X> <%04X>\n", gid, used[uint16(gid)])
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// allocate резервирует номер для нового объекта
+func (d *Document) allocate() int {
+	obj := d.nextObj
+	d.nextObj++
+	return obj
+}
+
+// startObject запоминает смещение объекта и записывает его заголовок
+func (d *Document) startObject(obj int) error {
+	d.offsets[obj] = d.w.n
+	_, err := fmt.Fprintf(d.w, "%d 0 obj\n", obj)
+	return err
+}
+
+// writeStream записывает объект-поток с дополнительными ключами словаря extra
+func (d *Document) writeStream(obj int, extra string, data []byte) error {
+	if err := d.startObject(obj); err != nil {
+		return err
+	}
+	dict := fmt.Sprintf("/Length %d", len(data))
+	if extra != "" {
+		dict += " " + extra
+	}
+	if _, err := fmt.Fprintf(d.w, "<< %s >>\nstream\n", dict); err != nil {
+		return err
+	}
+	if _, err := d.w.Write(data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(d.w, "\nendstream\nendobj\n")
+	return err
+}
+
+// writeObject записывает объект целиком
+func (d *Document) writeObject(obj int, body string) error {
+	if err := d.startObject(obj); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(d.w, "%s\nendobj\n", body)
+	return err
+}
+
+// Page накапливает команды отрисовки одной страницы
+type Page struct {
+	buf  bytes.Buffer // Поток команд содержимого страницы
+	used glyphSet     // Глифы, выведенные на странице
+}
+
+// Text выводит строку шрифтом font размером size, начиная с точки (x, y) от левого нижнего угла
+func (p *Page) Text(x, y float64, font string, size float64, s string) {
+	if p.used == nil {
+		p.used = make(glyphSet)
+	}
+	f := fontByName(font)
+	fmt.Fprintf(&p.buf, "BT /%s %.1f Tf %.2f %.2f Td <", font, size, x, y)
+	for _, r := range s {
+		gid := f.glyph(r)
+		p.used.add(f, gid, r)
+		fmt.Fprintf(&p.buf, "%04X", gid)
+	}
+	p.buf.WriteString("> Tj ET\n")
+}
+
+// TextRight выводит строку, выровненную по правому краю в точке x
+func (p *Page) TextRight(x, y float64, font string, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line рисует отрезок толщиной width
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.buf, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Empty сообщает, что на страницу еще ничего не выведено
+func (p *Page) Empty() bool {
+	return p.buf.Len() == 0
+}
+
+// TextWidth вычисляет ширину строки в пунктах по метрикам встроенного шрифта
+func TextWidth(font string, size float64, s string) float64 {
+	f := fontByName(font)
+	var units int
+	for _, r := range s {
+		units += f.width(f.glyph(r))
+	}
+	return float64(units) * size / 1000
+}
+
+// fontByName возвращает встроенный шрифт по имени ресурса страницы
+func fontByName(font string) *trueTypeFont {
+	if font == FontBold {
+		return boldFont
+	}
+	return regularFont
+}
+
+// countingWriter считает количество записанных байт для вычисления смещений объектов
+type countingWriter struct {
+	w *bufio.Writer // Буферизованный поток вывода
+	n int64         // Количество записанных байт
+}
+
+// Write записывает данные и увеличивает счетчик
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
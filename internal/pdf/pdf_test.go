@@ -0,0 +1,116 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestFontsCoverCyrillic проверяет, что встроенные шрифты содержат глифы латиницы, кириллицы и знака №
+func TestFontsCoverCyrillic(t *testing.T) {
+	var runes []rune
+	for r := 'А'; r <= 'я'; r++ {
+		runes = append(runes, r)
+	}
+	runes = append(runes, 'Ё', 'ё', '№', 'A', 'z', '0', '9')
+
+	for _, f := range []*trueTypeFont{regularFont, boldFont} {
+		for _, r := range runes {
+			gid, ok := f.glyphs[r]
+			if !ok {
+				t.Errorf("%s: нет глифа для %q", f.name, r)
+				continue
+			}
+			if f.width(gid) == 0 {
+				t.Errorf("%s: нулевая ширина глифа %q", f.name, r)
+			}
+		}
+	}
+}
+
+// TestTextWidth проверяет, что ширина строки складывается из ширин глифов шрифта
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		font string
+		s    string
+	}{
+		{FontRegular, "Выписка"},
+		{FontBold, "Остаток 1 250.00"},
+		{FontRegular, ""},
+	}
+	for _, tt := range tests {
+		f := fontByName(tt.font)
+		var units int
+		for _, r := range tt.s {
+			units += f.width(f.glyph(r))
+		}
+		want := float64(units) * 10 / 1000
+		if got := TextWidth(tt.font, 10, tt.s); got != want {
+			t.Errorf("TextWidth(%s, %q) = %v, ожидается %v", tt.font, tt.s, got, want)
+		}
+	}
+	if TextWidth(FontBold, 10, "Ж") <= TextWidth(FontRegular, 10, ".") {
+		t.Error("ширина «Ж» не больше ширины точки, ожидается использование метрик hmtx")
+	}
+}
+
+// TestDocumentEmbedsFontAndToUnicode формирует документ с кириллицей и проверяет, что текст записан
+// номерами глифов, шрифт встроен, а ToUnicode позволяет восстановить исходные символы
+func TestDocumentEmbedsFontAndToUnicode(t *testing.T) {
+	var buf bytes.Buffer
+	doc, err := NewDocument(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := &Page{}
+	page.Text(40, 800, FontRegular, 10, "Банк №1")
+	page.Text(40, 780, FontBold, 10, "Итого")
+	if err := doc.AddPage(page); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	var hex strings.Builder
+	for _, r := range "Банк №1" {
+		fmt.Fprintf(&hex, "%04X", regularFont.glyph(r))
+	}
+	if !strings.Contains(out, "<"+hex.String()+"> Tj") {
+		t.Errorf("текст не записан номерами глифов %s", hex.String())
+	}
+
+	for _, want := range []string{
+		"/Subtype /Type0 /BaseFont /Go-Regular /Encoding /Identity-H",
+		"/Subtype /Type0 /BaseFont /Go-Bold /Encoding /Identity-H",
+		"/FontFile2",
+		"/CIDToGIDMap /Identity",
+		fmt.Sprintf("<%04X> <%04X>", regularFont.glyph('Б'), 'Б'),
+		fmt.Sprintf("<%04X> <%04X>", regularFont.glyph('№'), '№'),
+		fmt.Sprintf("<%04X> <%04X>", boldFont.glyph('И'), 'И'),
+		"%%EOF",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("в документе нет %q", want)
+		}
+	}
+	if strings.Contains(out, "Helvetica") {
+		t.Error("документ ссылается на стандартный шрифт Helvetica, ожидаются только встроенные шрифты")
+	}
+}
+
+// TestParseTrueTypeRejectsGarbage проверяет, что поврежденный файл шрифта не принимается
+func TestParseTrueTypeRejectsGarbage(t *testing.T) {
+	tests := [][]byte{
+		nil,
+		[]byte("not a font file"),
+		{0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0, 0, 0, 0, 0, 0},
+	}
+	for _, raw := range tests {
+		if _, err := parseTrueType(raw, "Broken"); err == nil {
+			t.Errorf("parseTrueType(%q): ошибка не возвращена", raw)
+		}
+	}
+}
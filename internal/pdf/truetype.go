@@ -0,0 +1,218 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"embed"
+	"encoding/binary"
+	"fmt"
+)
+
+// Шрифты Go (Bigelow & Holmes, лицензия BSD, см. fonts/LICENSE) покрывают латиницу и кириллицу
+//
+//go:embed fonts/Go-Regular.ttf fonts/Go-Bold.ttf
+var fontFiles embed.FS
+
+// Разобранные встроенные шрифты; файлы вшиты в бинарник, поэтому ошибка разбора — ошибка сборки
+var (
+	regularFont = mustLoadFont("fonts/Go-Regular.ttf", "Go-Regular")
+	boldFont    = mustLoadFont("fonts/Go-Bold.ttf", "Go-Bold")
+)
+
+// trueTypeFont содержит метрики TrueType-шрифта, необходимые для вывода и встраивания в PDF
+type trueTypeFont struct {
+	name       string          // PostScript-имя шрифта в документе
+	data       []byte          // Файл шрифта, сжатый zlib для потока FontFile2
+	length     int             // Размер несжатого файла шрифта
+	unitsPerEm int             // Размер em-квадрата в единицах шрифта
+	bbox       [4]int          // Габаритный прямоугольник в тысячных долях кегля
+	ascent     int             // Верхний выносной элемент в тысячных долях кегля
+	descent    int             // Нижний выносной элемент в тысячных долях кегля
+	capHeight  int             // Высота прописных букв в тысячных долях кегля
+	glyphs     map[rune]uint16 // Таблица cmap: символ Unicode -> номер глифа
+	advances   []uint16        // Ширины глифов из таблицы hmtx в единицах шрифта
+}
+
+// mustLoadFont разбирает встроенный файл шрифта
+func mustLoadFont(path, name string) *trueTypeFont {
+	raw, err := fontFiles.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	f, err := parseTrueType(raw, name)
+	if err != nil {
+		panic(fmt.Sprintf("шрифт %s: %v", path, err))
+	}
+	return f
+}
+
+// parseTrueType читает таблицы head, hhea, maxp, hmtx, OS/2 и cmap (формат 4, Unicode BMP)
+func parseTrueType(raw []byte, name string) (*trueTypeFont, error) {
+	tables, err := readTableDirectory(raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("нет таблицы %s", tag)
+		}
+	}
+
+	f := &trueTypeFont{name: name, length: len(raw)}
+
+	head := tables["head"]
+	if len(head) < 54 {
+		return nil, fmt.Errorf("таблица head слишком короткая")
+	}
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	if f.unitsPerEm == 0 {
+		return nil, fmt.Errorf("нулевой unitsPerEm")
+	}
+	for i := range f.bbox {
+		f.bbox[i] = f.scale(int(int16(binary.BigEndian.Uint16(head[36+2*i:]))))
+	}
+
+	hhea := tables["hhea"]
+	if len(hhea) < 36 {
+		return nil, fmt.Errorf("таблица hhea слишком короткая")
+	}
+	f.ascent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[4:]))))
+	f.descent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[6:]))))
+	f.capHeight = f.ascent
+	if os2 := tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = f.scale(int(int16(binary.BigEndian.Uint16(os2[88:]))))
+	}
+
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	numGlyphs := int(binary.BigEndian.Uint16(tables["maxp"][4:]))
+	hmtx := tables["hmtx"]
+	if numMetrics == 0 || len(hmtx) < 4*numMetrics {
+		return nil, fmt.Errorf("таблица hmtx слишком короткая")
+	}
+	// Глифы после numberOfHMetrics наследуют ширину последней записи
+	f.advances = make([]uint16, max(numGlyphs, numMetrics))
+	for i := range f.advances {
+		f.advances[i] = binary.BigEndian.Uint16(hmtx[4*min(i, numMetrics-1):])
+	}
+
+	f.glyphs, err = parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	f.data = buf.Bytes()
+
+	return f, nil
+}
+
+// readTableDirectory возвращает содержимое таблиц шрифта по их тегам
+func readTableDirectory(raw []byte) (map[string][]byte, error) {
+	if len(raw) < 12 {
+		return nil, fmt.Errorf("файл шрифта слишком короткий")
+	}
+	if v := binary.BigEndian.Uint32(raw); v != 0x00010000 && v != 0x74727565 {
+		return nil, fmt.Errorf("неподдерживаемый формат шрифта %#08x", v)
+	}
+
+	numTables := int(binary.BigEndian.Uint16(raw[4:]))
+	if len(raw) < 12+16*numTables {
+		return nil, fmt.Errorf("оглавление таблиц выходит за пределы файла")
+	}
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		rec := raw[12+16*i:]
+		offset := int(binary.BigEndian.Uint32(rec[8:]))
+		length := int(binary.BigEndian.Uint32(rec[12:]))
+		if offset+length > len(raw) {
+			return nil, fmt.Errorf("таблица %s выходит за пределы файла", rec[:4])
+		}
+		tables[string(rec[:4])] = raw[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// parseCmap строит отображение символов в глифы по подтаблице Windows Unicode BMP (3, 1) формата 4
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("таблица cmap слишком короткая")
+	}
+	var sub []byte
+	n := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < n && 4+8*i+8 <= len(cmap); i++ {
+		rec := cmap[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		offset := int(binary.BigEndian.Uint32(rec[4:]))
+		if platform == 3 && encoding == 1 && offset+4 <= len(cmap) && binary.BigEndian.Uint16(cmap[offset:]) == 4 {
+			sub = cmap[offset:]
+			break
+		}
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("нет подтаблицы cmap (3, 1) формата 4")
+	}
+
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	if len(sub) < 16+8*segCount {
+		return nil, fmt.Errorf("подтаблица cmap слишком короткая")
+	}
+	endCodes := sub[14:]
+	startCodes := sub[16+2*segCount:]
+	deltas := sub[16+4*segCount:]
+	rangeOffsets := sub[16+6*segCount:]
+
+	glyphs := make(map[rune]uint16)
+	for s := 0; s < segCount; s++ {
+		start := int(binary.BigEndian.Uint16(startCodes[2*s:]))
+		end := int(binary.BigEndian.Uint16(endCodes[2*s:]))
+		delta := binary.BigEndian.Uint16(deltas[2*s:])
+		rangeOffset := int(binary.BigEndian.Uint16(rangeOffsets[2*s:]))
+		for c := start; c <= end && c != 0xffff; c++ {
+			var gid uint16
+			if rangeOffset == 0 {
+				gid = uint16(c) + delta
+			} else {
+				// idRangeOffset отсчитывается от положения самого элемента массива idRangeOffset
+				pos := 16 + 6*segCount + 2*s + rangeOffset + 2*(c-start)
+				if pos+2 > len(sub) {
+					return nil, fmt.Errorf("ссылка на глиф выходит за пределы cmap")
+				}
+				if gid = binary.BigEndian.Uint16(sub[pos:]); gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				glyphs[rune(c)] = gid
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+// scale переводит единицы шрифта в тысячные доли кегля
+func (f *trueTypeFont) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// glyph возвращает номер глифа для символа; отсутствующие в шрифте символы выводятся как «?»
+func (f *trueTypeFont) glyph(r rune) uint16 {
+	if gid, ok := f.glyphs[r]; ok {
+		return gid
+	}
+	return f.glyphs['?']
+}
+
+// width возвращает ширину глифа в тысячных долях кегля
+func (f *trueTypeFont) width(gid uint16) int {
+	if int(gid) >= len(f.advances) {
+		return 0
+	}
+	return f.scale(int(f.advances[gid]))
+}
@@ -0,0 +1,116 @@
+// Package problem формирует ответы с ошибками в формате RFC 7807 (application/problem+json). Статус
+// и код ответа определяются доменной ошибкой apperr в цепочке, язык сообщения — заголовком Accept-Language.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/pain"
+	"github.com/yujihn/bank_API/internal/validation"
+)
+
+// ContentType — тип содержимого ответа с ошибкой
+const ContentType = "application/problem+json"
+
+// Write отправляет ответ с ошибкой err. Доменные ошибки отвечают статусом своего вида, pgx.ErrNoRows — 404,
+// остальные ошибки логируются и отвечают 500 без подробностей. Ошибки валидации дополняются списком полей,
+// ошибки проверки пакета платежей — списком платежей
+func Write(w http.ResponseWriter, r *http.Request, logger *logrus.Logger, err error) {
+	lang := Language(r)
+
+	var fields validation.Errors
+	appErr := apperr.From(err)
+	domain := appErr != nil
+	switch {
+	case errors.As(err, &fields):
+		appErr, domain = apperr.ErrValidation, false
+	case domain:
+		// Статус и код определяет доменная ошибка
+	case errors.Is(err, pgx.ErrNoRows):
+		appErr = apperr.ErrNotFound
+	default:
+		appErr = apperr.ErrInternal
+	}
+
+	status := appErr.Kind.Status()
+	entry := logger.WithError(err).WithField("path", r.URL.Path)
+	if status >= http.StatusInternalServerError {
+		entry.Error("Ошибка обработки запроса")
+	} else {
+		entry.Warn("Запрос отклонен")
+	}
+
+	response := dto.ProblemResponse{
+		Type:     "/problems/" + strings.ReplaceAll(strings.ToLower(string(appErr.Code)), "_", "-"),
+		Title:    apperr.Message(appErr.Code, lang),
+		Status:   status,
+		Instance: r.URL.Path,
+		Code:     string(appErr.Code),
+	}
+	if len(fields) > 0 {
+		response.Errors = fields.In(lang)
+	}
+	var rejected *pain.ValidationError
+	if errors.As(err, &rejected) {
+		response.Items = rejected.Errors
+	}
+	// Уточнение из цепочки доменной ошибки написано по-русски; для внутренних ошибок оно может раскрыть
+	// подробности реализации
+	if domain && lang == apperr.RU && status < http.StatusInternalServerError && rejected == nil && err.Error() != appErr.Message {
+		response.Detail = err.Error()
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Ошибка кодирования ответа: %v", err)
+	}
+}
+
+// Language выбирает язык ответа по заголовку Accept-Language с учетом весов q. Если ни один
+// из поддерживаемых языков не указан, возвращается русский
+func Language(r *http.Request) apperr.Lang {
+	type candidate struct {
+		lang apperr.Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch apperr.Lang(base) {
+		case apperr.RU, apperr.EN:
+			candidates = append(candidates, candidate{lang: apperr.Lang(base), q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return apperr.RU
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
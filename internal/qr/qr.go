@@ -0,0 +1,533 @@
+// Package qr формирует QR-коды (ISO/IEC 18004) в байтовом режиме и отрисовывает их в PNG.
+//
+// Поддерживаются версии 1–10 (до 271 байта при уровне коррекции L), чего достаточно
+// для платежных ссылок. Маска выбирается по минимальному штрафу согласно стандарту.
+package qr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// ErrDataTooLong возвращается, если данные не помещаются в QR-код максимальной поддерживаемой версии
+var ErrDataTooLong = errors.New("данные не помещаются в QR-код")
+
+// Level представляет уровень коррекции ошибок
+type Level int
+
+const (
+	L Level = iota // Восстанавливается около 7% кода
+	M              // Восстанавливается около 15% кода
+	Q              // Восстанавливается около 25% кода
+	H              // Восстанавливается около 30% кода
+)
+
+// formatBits возвращает код уровня коррекции в информации о формате
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// blockSpec описывает разбиение кодовых слов версии на блоки для одного уровня коррекции
+type blockSpec struct {
+	ecLen   int // Кодовых слов коррекции в каждом блоке
+	blocks1 int // Блоков в первой группе
+	data1   int // Кодовых слов данных в блоке первой группы; во второй группе на одно больше
+	blocks2 int // Блоков во второй группе
+}
+
+// dataLen возвращает количество кодовых слов данных
+func (b blockSpec) dataLen() int {
+	return b.blocks1*b.data1 + b.blocks2*(b.data1+1)
+}
+
+// versions содержит параметры блоков по версиям (индекс — версия минус один) и уровням коррекции L, M, Q, H
+var versions = [...][4]blockSpec{
+	{{7, 1, 19, 0}, {10, 1, 16, 0}, {13, 1, 13, 0}, {17, 1, 9, 0}},
+	{{10, 1, 34, 0}, {16, 1, 28, 0}, {22, 1, 22, 0}, {28, 1, 16, 0}},
+	{{15, 1, 55, 0}, {26, 1, 44, 0}, {18, 2, 17, 0}, {22, 2, 13, 0}},
+	{{20, 1, 80, 0}, {18, 2, 32, 0}, {26, 2, 24, 0}, {16, 4, 9, 0}},
+	{{26, 1, 108, 0}, {24, 2, 43, 0}, {18, 2, 15, 2}, {22, 2, 11, 2}},
+	{{18, 2, 68, 0}, {16, 4, 27, 0}, {24, 4, 19, 0}, {28, 4, 15, 0}},
+	{{20, 2, 78, 0}, {18, 4, 31, 0}, {18, 2, 14, 4}, {26, 4, 13, 1}},
+	{{24, 2, 97, 0}, {22, 2, 38, 2}, {22, 4, 18, 2}, {26, 4, 14, 2}},
+	{{30, 2, 116, 0}, {22, 3, 36, 2}, {20, 4, 16, 4}, {24, 4, 12, 4}},
+	{{18, 2, 68, 2}, {26, 4, 43, 1}, {24, 6, 19, 2}, {28, 6, 15, 2}},
+}
+
+// alignments содержит координаты центров выравнивающих узоров по версиям
+var alignments = [...][]int{
+	nil, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34},
+	{6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+}
+
+// Code представляет сформированный QR-код
+type Code struct {
+	Version int      // Версия (размер) кода
+	Size    int      // Количество модулей по стороне
+	modules [][]bool // Темные модули
+	fixed   [][]bool // Служебные модули, не подлежащие маскированию
+}
+
+// Capacity возвращает наибольшую длину данных в байтах, помещающихся в код при заданном уровне коррекции
+func Capacity(level Level) int {
+	return (versions[len(versions)-1][level].dataLen()*8 - 4 - countBits(len(versions))) / 8
+}
+
+// Encode кодирует данные в QR-код минимальной версии, вмещающей данные при заданном уровне коррекции
+func Encode(data []byte, level Level) (*Code, error) {
+	version := 0
+	for v := 1; v <= len(versions); v++ {
+		if 4+countBits(v)+8*len(data) <= versions[v-1][level].dataLen()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrDataTooLong
+	}
+
+	c := newCode(version)
+	spec := versions[version-1][level]
+	c.drawFunctionPatterns(level)
+	c.drawCodewords(interleave(encodeData(data, version, spec.dataLen()), spec))
+
+	// Выбор маски с наименьшим штрафом
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(level, mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // Повторное применение снимает маску
+	}
+	c.applyMask(best)
+	c.drawFormat(level, best)
+	return c, nil
+}
+
+// Dark сообщает, является ли модуль в столбце x и строке y темным
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Image отрисовывает код с размером модуля scale пикселей и белой рамкой border модулей
+func (c *Code) Image(scale, border int) image.Image {
+	side := (c.Size + 2*border) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			x0, y0 := (x+border)*scale, (y+border)*scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(x0+dx, y0+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG записывает код в формате PNG со стандартной рамкой в четыре модуля
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale, 4))
+}
+
+// newCode создает пустую матрицу кода указанной версии
+func newCode(version int) *Code {
+	size := 17 + 4*version
+	c := &Code{Version: version, Size: size}
+	c.modules = make([][]bool, size)
+	c.fixed = make([][]bool, size)
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.fixed[i] = make([]bool, size)
+	}
+	return c
+}
+
+// setFixed устанавливает служебный модуль
+func (c *Code) setFixed(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.fixed[y][x] = true
+}
+
+// drawFunctionPatterns рисует поисковые, синхронизирующие и выравнивающие узоры и резервирует
+// области информации о формате и версии
+func (c *Code) drawFunctionPatterns(level Level) {
+	for i := 0; i < c.Size; i++ {
+		c.setFixed(6, i, i%2 == 0)
+		c.setFixed(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	pos := alignments[c.Version-1]
+	last := len(pos) - 1
+	for i := range pos {
+		for j := range pos {
+			// Углы, занятые поисковыми узорами, пропускаются
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFixed(pos[i]+dx, pos[j]+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.drawFormat(level, 0)
+	c.drawVersion()
+}
+
+// drawFinder рисует поисковый узор с разделителем вокруг центра (x, y)
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFixed(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// formatInfo возвращает 15 бит информации об уровне коррекции и маске: код BCH(15,5) с маской 0x5412
+func formatInfo(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionInfo возвращает 18 бит информации о версии: код BCH(18,6)
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// drawFormat записывает информацию об уровне коррекции и маске в обе копии
+func (c *Code) drawFormat(level Level, mask int) {
+	bits := formatInfo(level, mask)
+
+	// Первая копия — вокруг левого верхнего поискового узора
+	for i := 0; i <= 5; i++ {
+		c.setFixed(8, i, bit(bits, i))
+	}
+	c.setFixed(8, 7, bit(bits, 6))
+	c.setFixed(8, 8, bit(bits, 7))
+	c.setFixed(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFixed(14-i, 8, bit(bits, i))
+	}
+
+	// Вторая копия — у правого верхнего и левого нижнего поисковых узоров
+	for i := 0; i < 8; i++ {
+		c.setFixed(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFixed(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFixed(8, c.Size-8, true) // Постоянный темный модуль
+}
+
+// drawVersion записывает информацию о версии для версий 7 и выше
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	bits := versionInfo(c.Version)
+
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFixed(a, b, bit(bits, i))
+		c.setFixed(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords размещает кодовые слова зигзагом снизу вверх парами столбцов справа налево
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Вертикальный синхронизирующий узор пропускается
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.fixed[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = bit(int(codewords[i>>3]), 7-i&7)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask инвертирует модули данных по шаблону маски
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.fixed[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty вычисляет штраф маскированного кода по правилам N1–N4 стандарта
+func (c *Code) penalty() int {
+	penalty := 0
+	line := make([]bool, c.Size)
+
+	// N1 и N3 по строкам и столбцам
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < c.Size; i++ {
+			for j := 0; j < c.Size; j++ {
+				if vertical {
+					line[j] = c.modules[j][i]
+				} else {
+					line[j] = c.modules[i][j]
+				}
+			}
+			penalty += linePenalty(line)
+		}
+	}
+
+	// N2: блоки 2×2 одного цвета
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			v := c.modules[y][x]
+			if v == c.modules[y][x+1] && v == c.modules[y+1][x] && v == c.modules[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+
+	// N4: отклонение доли темных модулей от 50%
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+		}
+	}
+	percent := dark * 100 / (c.Size * c.Size)
+	penalty += abs(percent-50) / 5 * 10
+	return penalty
+}
+
+// finderLike — узор 1:1:3:1:1, похожий на поисковый, с четырьмя светлыми модулями с одной стороны
+var finderLike = [...][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// linePenalty вычисляет штрафы N1 (серии одного цвета) и N3 (узоры, похожие на поисковые) для строки модулей
+func linePenalty(line []bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLike[0]) <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+			for k, v := range pattern {
+				if line[i+k] != v {
+					match = false
+					break
+				}
+			}
+			if match {
+				penalty += 40
+			}
+		}
+	}
+	return penalty
+}
+
+// encodeData формирует кодовые слова данных: режим, длина, байты, терминатор и заполнение
+func encodeData(data []byte, version, capacity int) []byte {
+	var bb bitBuffer
+	bb.append(0b0100, 4) // Байтовый режим
+	bb.append(len(data), countBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	bb.append(0, min(4, capacity*8-bb.n))
+	if rem := bb.n % 8; rem != 0 {
+		bb.append(0, 8-rem)
+	}
+	for pad := 0xEC; len(bb.bytes) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	return bb.bytes
+}
+
+// countBits возвращает разрядность поля длины данных в байтовом режиме для версии
+func countBits(version int) int {
+	if version >= 10 {
+		return 16
+	}
+	return 8
+}
+
+// interleave делит данные на блоки, вычисляет коды коррекции и перемежает кодовые слова блоков
+func interleave(data []byte, spec blockSpec) []byte {
+	divisor := rsDivisor(spec.ecLen)
+	var blocks, ecc [][]byte
+	offset := 0
+	for i := 0; i < spec.blocks1+spec.blocks2; i++ {
+		n := spec.data1
+		if i >= spec.blocks1 {
+			n++
+		}
+		block := data[offset : offset+n]
+		offset += n
+		blocks = append(blocks, block)
+		ecc = append(ecc, rsRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, len(data)+len(blocks)*spec.ecLen)
+	for i := 0; i <= spec.data1; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < spec.ecLen; i++ {
+		for _, e := range ecc {
+			result = append(result, e[i])
+		}
+	}
+	return result
+}
+
+// rsDivisor вычисляет порождающий многочлен кода Рида — Соломона указанной степени
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder вычисляет кодовые слова коррекции для блока данных
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply умножает элементы поля GF(2^8) по модулю x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bitBuffer накапливает биты старшим битом вперед
+type bitBuffer struct {
+	bytes []byte // Заполненные байты
+	n     int    // Количество записанных битов
+}
+
+// append добавляет младшие length битов значения
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>i&1 == 1 {
+			b.bytes[b.n/8] |= 0x80 >> (b.n % 8)
+		}
+		b.n++
+	}
+}
+
+// bit возвращает i-й бит числа
+func bit(x, i int) bool {
+	return x>>i&1 != 0
+}
+
+// abs возвращает модуль числа
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,275 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestFormatInfo сверяет информацию о формате с таблицей стандарта ISO/IEC 18004 (приложение C)
+func TestFormatInfo(t *testing.T) {
+	tests := []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{L, 0, 0b111011111000100},
+		{L, 1, 0b111001011110011},
+		{M, 0, 0b101010000010010},
+		{M, 1, 0b101000100100101},
+		{M, 5, 0b100000011001110},
+		{Q, 0, 0b011010101011111},
+		{H, 0, 0b001011010001001},
+	}
+	for _, tt := range tests {
+		if got := formatInfo(tt.level, tt.mask); got != tt.want {
+			t.Errorf("formatInfo(%d, %d) = %015b, ожидается %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+}
+
+// TestVersionInfo сверяет информацию о версии с таблицей стандарта ISO/IEC 18004 (приложение D)
+func TestVersionInfo(t *testing.T) {
+	tests := map[int]int{
+		7:  0b000111110010010100,
+		8:  0b001000010110111100,
+		9:  0b001001101010011001,
+		10: 0b001010010011010011,
+	}
+	for version, want := range tests {
+		if got := versionInfo(version); got != want {
+			t.Errorf("versionInfo(%d) = %018b, ожидается %018b", version, got, want)
+		}
+	}
+}
+
+// TestRSRemainder проверяет коды коррекции на известном примере «HELLO WORLD» версии 1-M
+func TestRSRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, ожидается %v", got, want)
+	}
+}
+
+// TestEncodeRoundTrip кодирует данные, читает матрицу обратно (формат, маска, зигзаг, блоки, коды коррекции)
+// и сравнивает извлеченные байты с исходными
+func TestEncodeRoundTrip(t *testing.T) {
+	payment := "ST00012|PersonalAcc=42|Sum=150000|Cur=RUB|Name=Иван П.|Exp=1709290800|Nonce=0123456789abcdef|Sig=" +
+		strings.Repeat("ab", 32)
+	tests := []struct {
+		name        string
+		data        string
+		level       Level
+		wantVersion int
+	}{
+		{"один байт", "A", M, 1},
+		{"граница версии 1-M", strings.Repeat("x", 14), M, 1},
+		{"переход на версию 2-M", strings.Repeat("x", 15), M, 2},
+		{"платежная строка", payment, M, 9},
+		{"несколько групп блоков", strings.Repeat("0123456789", 10), Q, 8},
+		{"максимум M", strings.Repeat("z", Capacity(M)), M, 10},
+		{"максимум L", strings.Repeat("z", Capacity(L)), L, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode([]byte(tt.data), tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Version != tt.wantVersion {
+				t.Errorf("версия %d, ожидается %d", c.Version, tt.wantVersion)
+			}
+			if c.Size != 17+4*c.Version {
+				t.Errorf("размер %d, ожидается %d", c.Size, 17+4*c.Version)
+			}
+
+			got, level, err := decode(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if level != tt.level {
+				t.Errorf("уровень коррекции %d, ожидается %d", level, tt.level)
+			}
+			if string(got) != tt.data {
+				t.Errorf("прочитано %q, ожидается %q", got, tt.data)
+			}
+		})
+	}
+}
+
+// TestCapacity проверяет емкость версии 10 и отказ для данных, не помещающихся в нее
+func TestCapacity(t *testing.T) {
+	tests := map[Level]int{L: 271, M: 213, Q: 151, H: 119}
+	for level, want := range tests {
+		if got := Capacity(level); got != want {
+			t.Errorf("Capacity(%d) = %d, ожидается %d", level, got, want)
+		}
+		if _, err := Encode(make([]byte, want+1), level); !errors.Is(err, ErrDataTooLong) {
+			t.Errorf("Encode(%d байт, %d): %v, ожидается %v", want+1, level, err, ErrDataTooLong)
+		}
+	}
+}
+
+// TestWritePNG проверяет, что изображение содержит код с рамкой в четыре модуля
+func TestWritePNG(t *testing.T) {
+	c, err := Encode([]byte("test"), M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := c.Image(3, 4)
+	if side := img.Bounds().Dx(); side != (c.Size+8)*3 {
+		t.Errorf("сторона изображения %d, ожидается %d", side, (c.Size+8)*3)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("нет сигнатуры PNG")
+	}
+}
+
+// decode читает байтовые данные из матрицы кода независимо от кодировщика: определяет уровень и маску
+// по обеим копиям информации о формате, снимает маску, читает кодовые слова, собирает блоки
+// и проверяет их коды коррекции
+func decode(c *Code) ([]byte, Level, error) {
+	// Информация о формате: первая копия вокруг левого верхнего поискового узора
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= b2i(c.Dark(8, i)) << i
+	}
+	first |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		first |= b2i(c.Dark(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= b2i(c.Dark(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= b2i(c.Dark(8, c.Size-15+i)) << i
+	}
+	if first != second {
+		return nil, 0, fmt.Errorf("копии информации о формате различаются: %015b и %015b", first, second)
+	}
+	level, mask := Level(-1), -1
+	for l := L; l <= H; l++ {
+		for m := 0; m < 8; m++ {
+			if formatInfo(l, m) == first {
+				level, mask = l, m
+			}
+		}
+	}
+	if mask < 0 {
+		return nil, 0, fmt.Errorf("неизвестная информация о формате %015b", first)
+	}
+
+	// Служебные модули берутся из пустого кода той же версии
+	ref := newCode(c.Version)
+	ref.drawFunctionPatterns(level)
+
+	spec := versions[c.Version-1][level]
+	blockCount := spec.blocks1 + spec.blocks2
+	total := spec.dataLen() + blockCount*spec.ecLen
+	raw := make([]byte, 0, total)
+	var cur, n int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for x := right; x >= right-1; x-- {
+				if ref.fixed[y][x] || len(raw) == total {
+					continue
+				}
+				dark := c.Dark(x, y) != maskBit(mask, x, y)
+				cur = cur<<1 | b2i(dark)
+				if n++; n == 8 {
+					raw, cur, n = append(raw, byte(cur)), 0, 0
+				}
+			}
+		}
+	}
+	if len(raw) != total {
+		return nil, 0, fmt.Errorf("прочитано %d кодовых слов, ожидается %d", len(raw), total)
+	}
+
+	// Восстановление блоков из перемеженных кодовых слов
+	blocks := make([][]byte, blockCount)
+	pos := 0
+	for i := 0; i <= spec.data1; i++ {
+		for b := range blocks {
+			if i < spec.data1 || b >= spec.blocks1 {
+				blocks[b] = append(blocks[b], raw[pos])
+				pos++
+			}
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		ecc := make([]byte, spec.ecLen)
+		for i := range ecc {
+			ecc[i] = raw[pos+i*blockCount+b]
+		}
+		if want := rsRemainder(block, rsDivisor(spec.ecLen)); !bytes.Equal(ecc, want) {
+			return nil, 0, fmt.Errorf("блок %d: коды коррекции %v, ожидается %v", b, ecc, want)
+		}
+		data = append(data, block...)
+	}
+
+	// Байтовый режим: 4 бита режима, длина и данные
+	bitAt := func(i int) int { return int(data[i/8]>>(7-i%8)) & 1 }
+	read := func(from, length int) int {
+		v := 0
+		for i := from; i < from+length; i++ {
+			v = v<<1 | bitAt(i)
+		}
+		return v
+	}
+	if read(0, 4) != 0b0100 {
+		return nil, 0, fmt.Errorf("режим %04b, ожидается байтовый", read(0, 4))
+	}
+	length := read(4, countBits(c.Version))
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(read(4+countBits(c.Version)+8*i, 8))
+	}
+	return out, level, nil
+}
+
+// maskBit возвращает значение шаблона маски в модуле по формулам стандарта
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+// b2i переводит логическое значение в бит
+func b2i(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
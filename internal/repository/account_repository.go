@@ -2,12 +2,24 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/transaction"
 )
 
+// DebitGuard проверяет расходную операцию в транзакции списания. Перед проверкой строка пользователя
+// блокируется, поэтому параллельные списания одного пользователя проверяются по очереди и каждое
+// учитывает уже записанные расходы остальных
+type DebitGuard struct {
+	UserID int64                            // Пользователь, чьи расходные операции ограничены
+	Since  time.Time                        // Начало периода, за который считаются расходы
+	Check  func(used decimal.Decimal) error // Проверка по сумме расходных операций пользователя с момента Since
+}
+
 // AccountRepository реализует работу с таблицей счетов в базе данных
 type AccountRepository struct {
 	db *pgxpool.Pool // Пул соединений с базой данных
@@ -19,15 +31,15 @@ func NewAccountRepository(db *pgxpool.Pool) *AccountRepository {
 }
 
 // CreateAccount создает новый счет для пользователя
-func (r *AccountRepository) CreateAccount(ctx context.Context, userID int64, currency account.Currency) (*account.Account, error) {
+func (r *AccountRepository) CreateAccount(ctx context.Context, userID int64, currency account.Currency, accType account.Type) (*account.Account, error) {
 	query := `
-		INSERT INTO accounts (user_id, currency)
-		VALUES ($1, $2)
-		RETURNING id, user_id, balance, currency, created_at
+		INSERT INTO accounts (user_id, currency, type)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
 	`
 	var acc account.Account
-	err := r.db.QueryRow(ctx, query, userID, currency).Scan(
-		&acc.ID, &acc.UserID, &acc.Balance, &acc.Currency, &acc.CreatedAt,
+	err := r.db.QueryRow(ctx, query, userID, currency, accType).Scan(
+		&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -38,13 +50,13 @@ func (r *AccountRepository) CreateAccount(ctx context.Context, userID int64, cur
 // GetAccountByID получает счет по его ID
 func (r *AccountRepository) GetAccountByID(ctx context.Context, id int64) (*account.Account, error) {
 	query := `
-		SELECT id, user_id, balance, currency, created_at
+		SELECT id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
 		FROM accounts
 		WHERE id = $1
 	`
 	var acc account.Account
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&acc.ID, &acc.UserID, &acc.Balance, &acc.Currency, &acc.CreatedAt,
+		&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -55,7 +67,7 @@ func (r *AccountRepository) GetAccountByID(ctx context.Context, id int64) (*acco
 // GetAccountsByUserID получает все счета пользователя по его ID
 func (r *AccountRepository) GetAccountsByUserID(ctx context.Context, userID int64) ([]*account.Account, error) {
 	query := `
-		SELECT id, user_id, balance, currency, created_at
+		SELECT id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
 		FROM accounts
 		WHERE user_id = $1
 		ORDER BY id
@@ -69,7 +81,7 @@ func (r *AccountRepository) GetAccountsByUserID(ctx context.Context, userID int6
 	var accounts []*account.Account
 	for rows.Next() {
 		var acc account.Account
-		if err := rows.Scan(&acc.ID, &acc.UserID, &acc.Balance, &acc.Currency, &acc.CreatedAt); err != nil {
+		if err := rows.Scan(&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt); err != nil {
 			return nil, err
 		}
 		accounts = append(accounts, &acc)
@@ -81,30 +93,137 @@ func (r *AccountRepository) GetAccountsByUserID(ctx context.Context, userID int6
 	return accounts, nil
 }
 
-// UpdateBalance обновляет баланс счета, прибавляя указанную сумму
-func (r *AccountRepository) UpdateBalance(ctx context.Context, id int64, amount decimal.Decimal) error {
+// GetAccountsByType получает все счета указанного типа
+func (r *AccountRepository) GetAccountsByType(ctx context.Context, accType account.Type) ([]*account.Account, error) {
+	query := `
+		SELECT id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
+		FROM accounts
+		WHERE type = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, accType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*account.Account
+	for rows.Next() {
+		var acc account.Account
+		if err := rows.Scan(&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, &acc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetAccountsWithOverdraft получает все счета с одобренным лимитом овердрафта или отрицательным балансом
+func (r *AccountRepository) GetAccountsWithOverdraft(ctx context.Context) ([]*account.Account, error) {
+	query := `
+		SELECT id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
+		FROM accounts
+		WHERE overdraft_limit > 0 OR balance < 0
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*account.Account
+	for rows.Next() {
+		var acc account.Account
+		if err := rows.Scan(&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, &acc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// SetOverdraftLimit устанавливает лимит овердрафта. Лимит не может быть меньше уже использованного овердрафта;
+// в этом случае возвращается pgx.ErrNoRows
+func (r *AccountRepository) SetOverdraftLimit(ctx context.Context, id int64, limit decimal.Decimal) (*account.Account, error) {
+	query := `
+		UPDATE accounts
+		SET overdraft_limit = $1
+		WHERE id = $2 AND balance + $1 >= 0
+		RETURNING id, user_id, type, balance, overdraft_limit, restricted, currency, created_at
+	`
+	var acc account.Account
+	err := r.db.QueryRow(ctx, query, limit, id).Scan(
+		&acc.ID, &acc.UserID, &acc.Type, &acc.Balance, &acc.OverdraftLimit, &acc.Restricted, &acc.Currency, &acc.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// UpdateBalance изменяет баланс счета на сумму amount и записывает транзакцию вида kind в одной транзакции
+// базы данных. Списание предварительно проверяется guard, если он задан
+func (r *AccountRepository) UpdateBalance(ctx context.Context, id int64, amount decimal.Decimal, kind transaction.Kind,
+	guard *DebitGuard) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = checkDebit(ctx, tx, guard); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE accounts
 		SET balance = balance + $1
 		WHERE id = $2
 	`
-	_, err := r.db.Exec(ctx, query, amount, id)
-	return err
+	if _, err = tx.Exec(ctx, query, amount, id); err != nil {
+		return err
+	}
+
+	// Определяем тип транзакции: пополнение или списание
+	txType := transaction.WITHDRAWAL
+	if amount.IsPositive() {
+		txType = transaction.DEPOSIT
+	}
+	if err = insertTransaction(ctx, tx, id, amount.Abs(), txType, kind, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// TransferBetweenAccounts выполняет перевод средств между двумя счетами в рамках транзакции
-func (r *AccountRepository) TransferBetweenAccounts(ctx context.Context, fromID, toID int64, amount decimal.Decimal) error {
+// TransferBetweenAccounts выполняет перевод средств между двумя счетами и записывает транзакции перевода
+// для обоих счетов в рамках одной транзакции. Списание предварительно проверяется guard, если он задан
+func (r *AccountRepository) TransferBetweenAccounts(ctx context.Context, fromID, toID int64, amount decimal.Decimal,
+	guard *DebitGuard) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	// Списание со счета отправителя с проверкой достаточности средств
+	if err = checkDebit(ctx, tx, guard); err != nil {
+		return err
+	}
+
+	// Списание со счета отправителя с проверкой достаточности средств с учетом лимита овердрафта
 	updateFromQuery := `
 		UPDATE accounts
 		SET balance = balance - $1
-		WHERE id = $2 AND balance >= $1
+		WHERE id = $2 AND balance + overdraft_limit >= $1
 		RETURNING balance
 	`
 	var newBalance decimal.Decimal
@@ -124,5 +243,31 @@ func (r *AccountRepository) TransferBetweenAccounts(ctx context.Context, fromID,
 		return err
 	}
 
+	// Запись транзакций для обоих счетов: списание у отправителя и зачисление получателю
+	if err = insertTransaction(ctx, tx, fromID, amount, transaction.WITHDRAWAL, transaction.KIND_TRANSFER, &toID); err != nil {
+		return err
+	}
+	if err = insertTransaction(ctx, tx, toID, amount, transaction.DEPOSIT, transaction.KIND_TRANSFER, &fromID); err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
+
+// checkDebit блокирует строку пользователя и проверяет списание guard по сумме его расходных операций.
+// Блокировка FOR NO KEY UPDATE не мешает вставке строк, ссылающихся на пользователя
+func checkDebit(ctx context.Context, tx pgx.Tx, guard *DebitGuard) error {
+	if guard == nil {
+		return nil
+	}
+
+	var userID int64
+	if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR NO KEY UPDATE`, guard.UserID).Scan(&userID); err != nil {
+		return err
+	}
+	used, err := sumExternal(ctx, tx, guard.UserID, transaction.WITHDRAWAL, guard.Since)
+	if err != nil {
+		return err
+	}
+	return guard.Check(used)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/batch"
+)
+
+// ErrBatchExists возвращается, если пакет с таким идентификатором сообщения уже загружен пользователем
+var ErrBatchExists = errors.New("пакет с таким идентификатором сообщения уже загружен")
+
+// BatchRepository реализует работу с таблицами пакетов платежей в базе данных
+type BatchRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewBatchRepository создает новый экземпляр репозитория для работы с пакетами платежей
+func NewBatchRepository(db *pgxpool.Pool) *BatchRepository {
+	return &BatchRepository{db: db}
+}
+
+// CreateBatch сохраняет пакет и все его платежи в рамках одной транзакции
+func (r *BatchRepository) CreateBatch(ctx context.Context, b *batch.Batch, items []*batch.Item) (*batch.Batch, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Пакет с тем же MsgId от того же пользователя не вставляется: повторная загрузка файла
+	// не должна исполнить платежи второй раз
+	query := `
+		INSERT INTO payment_batches (user_id, from_account_id, message_id, source_format, item_count, total_amount, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, message_id) DO NOTHING
+		RETURNING id, status, created_at
+	`
+	created := *b
+	err = tx.QueryRow(ctx, query, b.UserID, b.FromAccountID, b.MessageID, b.SourceFormat,
+		b.ItemCount, b.TotalAmount, batch.PENDING).Scan(&created.ID, &created.Status, &created.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBatchExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Платежи загружаются через COPY: в пакете могут быть тысячи строк
+	rows := make([][]any, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []any{created.ID, item.EndToEndID, item.ToAccountID, item.Amount, item.Description, batch.ITEM_PENDING})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"payment_batch_items"},
+		[]string{"batch_id", "end_to_end_id", "to_account_id", "amount", "description", "status"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetBatchIDByMessageID получает ID пакета пользователя по идентификатору исходного сообщения
+func (r *BatchRepository) GetBatchIDByMessageID(ctx context.Context, userID int64, messageID string) (int64, error) {
+	query := `SELECT id FROM payment_batches WHERE user_id = $1 AND message_id = $2`
+	var id int64
+	err := r.db.QueryRow(ctx, query, userID, messageID).Scan(&id)
+	return id, err
+}
+
+// GetBatchByID получает пакет по ID
+func (r *BatchRepository) GetBatchByID(ctx context.Context, id int64) (*batch.Batch, error) {
+	query := `
+		SELECT id, user_id, from_account_id, message_id, source_format, item_count, total_amount, status, created_at, completed_at
+		FROM payment_batches
+		WHERE id = $1
+	`
+	var b batch.Batch
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&b.ID, &b.UserID, &b.FromAccountID, &b.MessageID, &b.SourceFormat, &b.ItemCount,
+		&b.TotalAmount, &b.Status, &b.CreatedAt, &b.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetBatchIDsByStatus получает ID пакетов в указанных статусах в порядке поступления
+func (r *BatchRepository) GetBatchIDsByStatus(ctx context.Context, statuses ...batch.Status) ([]int64, error) {
+	query := `
+		SELECT id
+		FROM payment_batches
+		WHERE status = ANY($1)
+		ORDER BY id
+	`
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+
+	rows, err := r.db.Query(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetItemsByBatchID получает все платежи пакета в порядке следования в файле
+func (r *BatchRepository) GetItemsByBatchID(ctx context.Context, batchID int64) ([]*batch.Item, error) {
+	query := `
+		SELECT id, batch_id, end_to_end_id, to_account_id, amount, description, status, error, executed_at
+		FROM payment_batch_items
+		WHERE batch_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*batch.Item
+	for rows.Next() {
+		var item batch.Item
+		if err := rows.Scan(&item.ID, &item.BatchID, &item.EndToEndID, &item.ToAccountID, &item.Amount,
+			&item.Description, &item.Status, &item.Error, &item.ExecutedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateBatchStatus обновляет статус пакета; для итоговых статусов фиксируется время завершения
+func (r *BatchRepository) UpdateBatchStatus(ctx context.Context, id int64, status batch.Status, completedAt *time.Time) error {
+	query := `
+		UPDATE payment_batches
+		SET status = $1, completed_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(ctx, query, status, completedAt, id)
+	return err
+}
+
+// UpdateItemStatus обновляет статус платежа и причину отклонения
+func (r *BatchRepository) UpdateItemStatus(ctx context.Context, id int64, status batch.ItemStatus, errMsg string) error {
+	query := `
+		UPDATE payment_batch_items
+		SET status = $1, error = $2, executed_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(ctx, query, status, errMsg, id)
+	return err
+}
+
+// ClaimItem переводит ожидающий платеж в статус PROCESSING перед переводом денег. Возвращает false,
+// если платеж уже захвачен другим обработчиком или исполнен
+func (r *BatchRepository) ClaimItem(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE payment_batch_items
+		SET status = $1, started_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = $3
+	`
+	tag, err := r.db.Exec(ctx, query, batch.ITEM_PROCESSING, id, batch.ITEM_PENDING)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FailInterruptedItems отклоняет с причиной errMsg платежи пакета, захваченные обработчиком раньше before
+// и не завершенные: исполнение прервано сбоем, и повторять перевод нельзя. Возвращает число таких платежей
+func (r *BatchRepository) FailInterruptedItems(ctx context.Context, batchID int64, before time.Time, errMsg string) (int64, error) {
+	query := `
+		UPDATE payment_batch_items
+		SET status = $1, error = $2, executed_at = CURRENT_TIMESTAMP
+		WHERE batch_id = $3 AND status = $4 AND started_at < $5
+	`
+	tag, err := r.db.Exec(ctx, query, batch.ITEM_FAILED, errMsg, batchID, batch.ITEM_PROCESSING, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -43,13 +43,14 @@ func (r *CardRepository) CreateCard(ctx context.Context, userID int64, encrypted
 // GetCardByID получает карту по ID
 func (r *CardRepository) GetCardByID(ctx context.Context, cardID int64) (*models.Card, error) {
 	query := `
-		SELECT id, user_id, card_number, expire, cvv_hash, created_at
+		SELECT id, user_id, card_number, expire, cvv_hash, cvv_failures, blocked_at, created_at
 		FROM cards 
 		WHERE id = $1
 	`
 	var card models.Card
 	err := r.db.QueryRow(ctx, query, cardID).Scan(
-		&card.ID, &card.UserID, &card.CardNumber, &card.Expire, &card.CVVHash, &card.CreatedAt,
+		&card.ID, &card.UserID, &card.CardNumber, &card.Expire, &card.CVVHash, &card.CVVFailures,
+		&card.BlockedAt, &card.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -61,7 +62,7 @@ func (r *CardRepository) GetCardByID(ctx context.Context, cardID int64) (*models
 // GetCardsByUserID получает все карты пользователя по его ID
 func (r *CardRepository) GetCardsByUserID(ctx context.Context, userID int64) ([]*models.Card, error) {
 	query := `
-		SELECT id, user_id, created_at
+		SELECT id, user_id, blocked_at, created_at
 		FROM cards 
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -75,7 +76,7 @@ func (r *CardRepository) GetCardsByUserID(ctx context.Context, userID int64) ([]
 	var cards []*models.Card
 	for rows.Next() {
 		var card models.Card
-		if err := rows.Scan(&card.ID, &card.UserID, &card.CreatedAt); err != nil {
+		if err := rows.Scan(&card.ID, &card.UserID, &card.BlockedAt, &card.CreatedAt); err != nil {
 			return nil, err
 		}
 		cards = append(cards, &card)
@@ -102,3 +103,29 @@ func (r *CardRepository) IsCardExistsForUser(ctx context.Context, cardID int64,
 
 	return true, nil
 }
+
+// RecordCVVFailure учитывает неверный ввод CVV и блокирует карту, если неверных вводов подряд стало
+// не меньше maxFailures. Возвращает true, если карта заблокирована
+func (r *CardRepository) RecordCVVFailure(ctx context.Context, cardID int64, maxFailures int) (bool, error) {
+	query := `
+		UPDATE cards
+		SET cvv_failures = cvv_failures + 1,
+		    blocked_at = CASE WHEN cvv_failures + 1 >= $2 THEN COALESCE(blocked_at, CURRENT_TIMESTAMP) ELSE blocked_at END
+		WHERE id = $1
+		RETURNING blocked_at IS NOT NULL
+	`
+	var blocked bool
+	err := r.db.QueryRow(ctx, query, cardID, maxFailures).Scan(&blocked)
+	return blocked, err
+}
+
+// ResetCVVFailures сбрасывает счетчик неверных вводов CVV после успешной проверки карты
+func (r *CardRepository) ResetCVVFailures(ctx context.Context, cardID int64) error {
+	query := `
+		UPDATE cards
+		SET cvv_failures = 0
+		WHERE id = $1 AND cvv_failures > 0
+	`
+	_, err := r.db.Exec(ctx, query, cardID)
+	return err
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/collection"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// CollectionRepository реализует работу с просроченной задолженностью: смену статуса кредита, неустойки,
+// списание средств с других счетов заемщика, ограничения по счетам и журнал действий
+type CollectionRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewCollectionRepository создает новый экземпляр репозитория для работы с просроченной задолженностью
+func NewCollectionRepository(db *pgxpool.Pool) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// insertActionQuery сохраняет запись журнала; неустойка за уже обработанный день не сохраняется повторно
+const insertActionQuery = `
+	INSERT INTO collection_actions (credit_id, action, stage_days, days_past_due, action_date, amount, account_id, details)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT DO NOTHING
+	RETURNING id, created_at
+`
+
+// GetDelinquent получает просроченные кредиты и активные кредиты с неоплаченными платежами, дата которых
+// раньше today
+func (r *CollectionRepository) GetDelinquent(ctx context.Context, today time.Time) ([]*credit.Credit, error) {
+	query := `
+		SELECT ` + creditColumns + `
+		FROM credits c
+		WHERE c.status = $1 OR (c.status = $2 AND EXISTS (
+			SELECT 1 FROM payment_schedules ps WHERE ps.credit_id = c.id AND NOT ps.paid AND ps.due_date < $3
+		))
+		ORDER BY c.id
+	`
+	rows, err := r.db.Query(ctx, query, credit.OVERDUE, credit.ACTIVE, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []*credit.Credit
+	for rows.Next() {
+		c, err := scanCredit(rows)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// GetBorrowerID получает идентификатор владельца счета кредита
+func (r *CollectionRepository) GetBorrowerID(ctx context.Context, creditID int64) (int64, error) {
+	var userID int64
+	err := r.db.QueryRow(ctx, `
+		SELECT a.user_id FROM credits c JOIN accounts a ON a.id = c.account_id WHERE c.id = $1
+	`, creditID).Scan(&userID)
+	return userID, err
+}
+
+// MarkOverdue в одной транзакции переводит активный кредит в статус OVERDUE с днем начала просрочки
+// l.ActionDate и сохраняет запись журнала l. Возвращает pgx.ErrNoRows, если кредит не активен
+func (r *CollectionRepository) MarkOverdue(ctx context.Context, l *collection.Log) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE credits SET status = $1, overdue_since = $2 WHERE id = $3 AND status = $4`,
+		credit.OVERDUE, l.ActionDate, l.CreditID, credit.ACTIVE)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if err := insertCollectionAction(ctx, tx, l); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Clear в одной транзакции возвращает просроченный кредит в статус ACTIVE, записывает действие CLEARED
+// и снимает ограничения по счетам заемщика, если у него не осталось других просроченных кредитов.
+// Возвращает pgx.ErrNoRows, если кредит не просрочен или по нему есть неоплаченные платежи с датой раньше today
+func (r *CollectionRepository) Clear(ctx context.Context, creditID int64, today time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE credits SET status = $1, overdue_since = NULL
+		WHERE id = $2 AND status = $3
+		  AND NOT EXISTS (SELECT 1 FROM payment_schedules WHERE credit_id = $2 AND NOT paid AND due_date < $4)
+	`, credit.ACTIVE, creditID, credit.OVERDUE, today)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if err := clearOverdue(ctx, tx, creditID, today, "просроченная задолженность погашена"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// HasAction сообщает, выполнялось ли действие этапа stageDays по кредиту начиная с дня since
+func (r *CollectionRepository) HasAction(ctx context.Context, creditID int64, action collection.Action, stageDays int,
+	since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM collection_actions
+			WHERE credit_id = $1 AND action = $2 AND stage_days = $3 AND action_date >= $4
+		)
+	`, creditID, action, stageDays, since).Scan(&exists)
+	return exists, err
+}
+
+// LogAction сохраняет запись журнала, заполняя ее ID и время создания
+func (r *CollectionRepository) LogAction(ctx context.Context, l *collection.Log) error {
+	return r.db.QueryRow(ctx, insertActionQuery, l.CreditID, l.Action, l.StageDays, l.DaysPastDue, l.ActionDate,
+		l.Amount, l.AccountID, l.Details).Scan(&l.ID, &l.CreatedAt)
+}
+
+// AccruePenalty в одной транзакции увеличивает сумму неоплаченного платежа scheduleID на неустойку l.Amount
+// и сохраняет запись журнала. Возвращает pgx.ErrNoRows, если платеж уже оплачен или неустойка за день
+// l.ActionDate уже начислена
+func (r *CollectionRepository) AccruePenalty(ctx context.Context, scheduleID int64, l *collection.Log) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE payment_schedules SET amount = amount + $1, penalty = penalty + $1
+		WHERE id = $2 AND credit_id = $3 AND NOT paid
+	`, l.Amount, scheduleID, l.CreditID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if err := insertCollectionAction(ctx, tx, l); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Sweep в одной транзакции переводит l.Amount со счета заемщика l.AccountID на счет кредита toAccountID
+// операциями WITHDRAWAL и DEPOSIT и сохраняет запись журнала. Списание выполняется без учета лимита
+// овердрафта и ограничений по счету. Возвращает pgx.ErrNoRows, если на счете недостаточно средств
+func (r *CollectionRepository) Sweep(ctx context.Context, toAccountID int64, l *collection.Log) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var fromID int64
+	err = tx.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $1
+		WHERE id = $2 AND balance >= $1
+		RETURNING id
+	`, l.Amount, *l.AccountID).Scan(&fromID)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, `UPDATE accounts SET balance = balance + $1 WHERE id = $2`, l.Amount, toAccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, counterparty_account_id, status)
+		VALUES ($1, $2, $3, $7, $4, $5), ($4, $2, $6, $7, $1, $5)
+	`, fromID, l.Amount, transaction.WITHDRAWAL, toAccountID, transaction.COMPLETED, transaction.DEPOSIT,
+		transaction.KIND_TRANSFER)
+	if err != nil {
+		return err
+	}
+	if err := insertCollectionAction(ctx, tx, l); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Restrict в одной транзакции ограничивает расходные операции по всем счетам пользователя userID
+// и сохраняет запись журнала. Возвращает число счетов, на которые установлено ограничение
+func (r *CollectionRepository) Restrict(ctx context.Context, userID int64, l *collection.Log) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE accounts SET restricted = TRUE WHERE user_id = $1 AND NOT restricted`, userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := insertCollectionAction(ctx, tx, l); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetActions получает журнал действий по кредиту в хронологическом порядке
+func (r *CollectionRepository) GetActions(ctx context.Context, creditID int64) ([]*collection.Log, error) {
+	query := `
+		SELECT id, credit_id, action, stage_days, days_past_due, action_date, amount, account_id, details, created_at
+		FROM collection_actions
+		WHERE credit_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []*collection.Log
+	for rows.Next() {
+		var l collection.Log
+		if err := rows.Scan(&l.ID, &l.CreditID, &l.Action, &l.StageDays, &l.DaysPastDue, &l.ActionDate, &l.Amount,
+			&l.AccountID, &l.Details, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		actions = append(actions, &l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// clearOverdue записывает погашение просрочки по кредиту и снимает ограничения по счетам заемщика,
+// если у него не осталось кредитов в статусе OVERDUE. Статус кредита должен быть уже изменен
+func clearOverdue(ctx context.Context, tx pgx.Tx, creditID int64, date time.Time, details string) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE accounts a SET restricted = FALSE
+		WHERE a.restricted
+		  AND a.user_id = (SELECT ca.user_id FROM credits c JOIN accounts ca ON ca.id = c.account_id WHERE c.id = $1)
+		  AND NOT EXISTS (
+			SELECT 1 FROM credits c JOIN accounts ca ON ca.id = c.account_id
+			WHERE ca.user_id = a.user_id AND c.status = $2
+		  )
+	`, creditID, credit.OVERDUE)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		details += ", ограничения по счетам сняты"
+	}
+	return insertCollectionAction(ctx, tx, &collection.Log{
+		CreditID:   creditID,
+		Action:     collection.CLEARED,
+		ActionDate: date,
+		Details:    details,
+	})
+}
+
+// insertCollectionAction сохраняет запись журнала в транзакции, заполняя ее ID и время создания.
+// Возвращает pgx.ErrNoRows, если неустойка за этот день уже записана
+func insertCollectionAction(ctx context.Context, tx pgx.Tx, l *collection.Log) error {
+	return tx.QueryRow(ctx, insertActionQuery, l.CreditID, l.Action, l.StageDays, l.DaysPastDue, l.ActionDate,
+		l.Amount, l.AccountID, l.Details).Scan(&l.ID, &l.CreatedAt)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/application"
+)
+
+// CreditApplicationRepository реализует работу с кредитными заявками
+type CreditApplicationRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewCreditApplicationRepository создает новый экземпляр репозитория кредитных заявок
+func NewCreditApplicationRepository(db *pgxpool.Pool) *CreditApplicationRepository {
+	return &CreditApplicationRepository{db: db}
+}
+
+// applicationColumns — список столбцов заявки в порядке сканирования scanApplication
+const applicationColumns = `id, user_id, account_id, amount, term_months, scheme, rate_type, declared_income, observed_income,
+	debt_payments, payment, debt_to_income, status, reasons, credit_id, accepted_at, reviewed_by, reviewed_at, decision_reason, created_at`
+
+// Create сохраняет заявку с результатом скоринга
+func (r *CreditApplicationRepository) Create(ctx context.Context, a *application.Application) (*application.Application, error) {
+	reasons := make([]string, 0, len(a.Reasons))
+	for _, reason := range a.Reasons {
+		reasons = append(reasons, string(reason))
+	}
+
+	query := `
+		INSERT INTO credit_applications (user_id, account_id, amount, term_months, scheme, rate_type, declared_income,
+			observed_income, debt_payments, payment, debt_to_income, status, reasons)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + applicationColumns
+	return scanApplication(r.db.QueryRow(ctx, query, a.UserID, a.AccountID, a.Amount, a.TermMonths, a.Scheme, a.RateType,
+		a.DeclaredIncome, a.ObservedIncome, a.DebtPayments, a.Payment, a.DebtToIncome, a.Status, reasons))
+}
+
+// GetByID получает заявку по ID
+func (r *CreditApplicationRepository) GetByID(ctx context.Context, id int64) (*application.Application, error) {
+	query := `SELECT ` + applicationColumns + ` FROM credit_applications WHERE id = $1`
+	return scanApplication(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByUserID получает все заявки пользователя, начиная с последних
+func (r *CreditApplicationRepository) GetByUserID(ctx context.Context, userID int64) ([]*application.Application, error) {
+	query := `SELECT ` + applicationColumns + ` FROM credit_applications WHERE user_id = $1 ORDER BY id DESC`
+	return r.queryApplications(ctx, query, userID)
+}
+
+// GetByStatus получает заявки в статусе, начиная с самых ранних
+func (r *CreditApplicationRepository) GetByStatus(ctx context.Context, status application.Status) ([]*application.Application, error) {
+	query := `SELECT ` + applicationColumns + ` FROM credit_applications WHERE status = $1 ORDER BY id`
+	return r.queryApplications(ctx, query, status)
+}
+
+// Decide сохраняет ручное решение по заявке на ручной проверке.
+// Возвращает pgx.ErrNoRows, если заявка не в статусе MANUAL_REVIEW
+func (r *CreditApplicationRepository) Decide(ctx context.Context, id int64, status application.Status, reason string,
+	reviewerID int64) (*application.Application, error) {
+	query := `
+		UPDATE credit_applications
+		SET status = $1, decision_reason = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = $5
+		RETURNING ` + applicationColumns
+	return scanApplication(r.db.QueryRow(ctx, query, status, reason, reviewerID, id, application.MANUAL_REVIEW))
+}
+
+// Claim переводит одобренную заявку в статус ACCEPTED перед оформлением кредита, исключая повторное оформление.
+// Возвращает pgx.ErrNoRows, если заявка не в статусе APPROVED
+func (r *CreditApplicationRepository) Claim(ctx context.Context, id int64) error {
+	var claimed int64
+	return r.db.QueryRow(ctx, `
+		UPDATE credit_applications SET status = $1, accepted_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = $3
+		RETURNING id
+	`, application.ACCEPTED, id, application.APPROVED).Scan(&claimed)
+}
+
+// Release возвращает заявку в статус APPROVED, если кредит по ней оформить не удалось
+func (r *CreditApplicationRepository) Release(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE credit_applications SET status = $1, accepted_at = NULL
+		WHERE id = $2 AND status = $3 AND credit_id IS NULL
+	`, application.APPROVED, id, application.ACCEPTED)
+	return err
+}
+
+// SetCredit связывает принятую заявку с оформленным кредитом
+func (r *CreditApplicationRepository) SetCredit(ctx context.Context, id, creditID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE credit_applications SET credit_id = $1 WHERE id = $2`, creditID, id)
+	return err
+}
+
+// Expire переводит одобренную заявку с истекшим сроком действия в статус EXPIRED
+func (r *CreditApplicationRepository) Expire(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE credit_applications SET status = $1 WHERE id = $2 AND status = $3`,
+		application.EXPIRED, id, application.APPROVED)
+	return err
+}
+
+// queryApplications выполняет запрос списка заявок со столбцами applicationColumns
+func (r *CreditApplicationRepository) queryApplications(ctx context.Context, query string, args ...any) ([]*application.Application, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applications []*application.Application
+	for rows.Next() {
+		a, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// scanApplication сканирует строку со столбцами applicationColumns
+func scanApplication(row pgx.Row) (*application.Application, error) {
+	var a application.Application
+	var reasons []string
+	err := row.Scan(&a.ID, &a.UserID, &a.AccountID, &a.Amount, &a.TermMonths, &a.Scheme, &a.RateType, &a.DeclaredIncome,
+		&a.ObservedIncome, &a.DebtPayments, &a.Payment, &a.DebtToIncome, &a.Status, &reasons, &a.CreditID,
+		&a.AcceptedAt, &a.ReviewedBy, &a.ReviewedAt, &a.DecisionReason, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.Reasons = make([]application.Reason, 0, len(reasons))
+	for _, reason := range reasons {
+		a.Reasons = append(a.Reasons, application.Reason(reason))
+	}
+	return &a, nil
+}
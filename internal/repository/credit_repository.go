@@ -0,0 +1,587 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// CreditRepository реализует работу с кредитами, графиками платежей и платежами по кредитам
+type CreditRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewCreditRepository создает новый экземпляр репозитория для работы с кредитами
+func NewCreditRepository(db *pgxpool.Pool) *CreditRepository {
+	return &CreditRepository{db: db}
+}
+
+// creditColumns — список столбцов кредита в порядке сканирования scanCredit
+const creditColumns = `id, account_id, principal, interest_rate, term_months, scheme, rate_type, rate_margin, start_date, status, overdue_since, closed_at, created_at`
+
+// Create в одной транзакции создает кредит с графиком платежей и зачисляет сумму кредита на счет операцией DEPOSIT.
+// Для кредита с плавающей ставкой rate содержит начальную запись истории ставки, для фиксированной — nil
+func (r *CreditRepository) Create(ctx context.Context, c *credit.Credit, schedule []*models.PaymentSchedule,
+	rate *credit.RateChange) (*credit.Credit, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO credits (account_id, principal, interest_rate, term_months, scheme, rate_type, rate_margin, start_date, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + creditColumns
+	created, err := scanCredit(tx.QueryRow(ctx, query, c.AccountID, c.Principal, c.InterestRate, c.TermMonths,
+		c.Scheme, c.RateType, c.RateMargin, c.StartDate, credit.ACTIVE))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertSchedule(ctx, tx, created.ID, schedule); err != nil {
+		return nil, err
+	}
+	if rate != nil {
+		rate.CreditID = created.ID
+		if err := insertRateChange(ctx, tx, rate); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE accounts SET balance = balance + $1 WHERE id = $2`, c.Principal, c.AccountID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`, c.AccountID, c.Principal, transaction.DEPOSIT, transaction.KIND_CREDIT_DISBURSEMENT, transaction.COMPLETED)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByID получает кредит по ID
+func (r *CreditRepository) GetByID(ctx context.Context, id int64) (*credit.Credit, error) {
+	query := `SELECT ` + creditColumns + ` FROM credits WHERE id = $1`
+	return scanCredit(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByUserID получает все кредиты по счетам пользователя, начиная с последних
+func (r *CreditRepository) GetByUserID(ctx context.Context, userID int64) ([]*credit.Credit, error) {
+	query := `
+		SELECT c.id, c.account_id, c.principal, c.interest_rate, c.term_months, c.scheme, c.rate_type, c.rate_margin, c.start_date, c.status, c.overdue_since, c.closed_at, c.created_at
+		FROM credits c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE a.user_id = $1
+		ORDER BY c.id DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []*credit.Credit
+	for rows.Next() {
+		c, err := scanCredit(rows)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// GetSchedule получает график платежей по кредиту в порядке дат
+func (r *CreditRepository) GetSchedule(ctx context.Context, creditID int64) ([]*models.PaymentSchedule, error) {
+	query := `
+		SELECT id, credit_id, due_date, amount, principal, interest, penalty, paid, paid_at, created_at
+		FROM payment_schedules
+		WHERE credit_id = $1
+		ORDER BY due_date, id
+	`
+	rows, err := r.db.Query(ctx, query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedule []*models.PaymentSchedule
+	for rows.Next() {
+		var p models.PaymentSchedule
+		if err := rows.Scan(&p.ID, &p.CreditID, &p.DueDate, &p.Amount, &p.Principal, &p.Interest, &p.Penalty,
+			&p.Paid, &p.PaidAt, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetPayments получает фактические платежи по кредиту в хронологическом порядке
+func (r *CreditRepository) GetPayments(ctx context.Context, creditID int64) ([]*credit.Payment, error) {
+	query := `
+		SELECT id, credit_id, amount, principal, interest, penalty, kind, transaction_id, created_at
+		FROM credit_payments
+		WHERE credit_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*credit.Payment
+	for rows.Next() {
+		var p credit.Payment
+		if err := rows.Scan(&p.ID, &p.CreditID, &p.Amount, &p.Principal, &p.Interest, &p.Penalty, &p.Kind,
+			&p.TransactionID, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		payments = append(payments, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// Prepay в одной транзакции списывает досрочный платеж со счета кредита операцией WITHDRAWAL, сохраняет платеж
+// (заполняя его ID и время) и заменяет неоплаченную часть графика на schedule; при closeCredit кредит закрывается.
+// Остаток основного долга по неоплаченной части графика должен совпадать с outstanding — так исключается
+// одновременное погашение по устаревшему графику. Возвращает pgx.ErrNoRows, если кредит не активен,
+// график изменился или на счете недостаточно средств
+func (r *CreditRepository) Prepay(ctx context.Context, c *credit.Credit, outstanding decimal.Decimal, payment *credit.Payment,
+	schedule []*models.PaymentSchedule, closeCredit bool) (*credit.Credit, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка кредита на время пересчета графика
+	var status credit.Status
+	if err = tx.QueryRow(ctx, `SELECT status FROM credits WHERE id = $1 FOR UPDATE`, c.ID).Scan(&status); err != nil {
+		return nil, err
+	}
+	var current decimal.Decimal
+	err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(principal), 0) FROM payment_schedules WHERE credit_id = $1 AND NOT paid`,
+		c.ID).Scan(&current)
+	if err != nil {
+		return nil, err
+	}
+	if status != credit.ACTIVE || !current.Equal(outstanding) {
+		return nil, pgx.ErrNoRows
+	}
+
+	var accountID int64
+	err = tx.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $1
+		WHERE id = $2 AND balance >= $1
+		RETURNING id
+	`, payment.Amount, c.AccountID).Scan(&accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, c.AccountID, payment.Amount, transaction.WITHDRAWAL, transaction.KIND_CREDIT_PAYMENT, transaction.COMPLETED).Scan(&transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO credit_payments (credit_id, amount, principal, interest, kind, transaction_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, c.ID, payment.Amount, payment.Principal, payment.Interest, payment.Kind, transactionID).Scan(&payment.ID, &payment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	payment.TransactionID = &transactionID
+
+	if _, err = tx.Exec(ctx, `DELETE FROM payment_schedules WHERE credit_id = $1 AND NOT paid`, c.ID); err != nil {
+		return nil, err
+	}
+	if err := insertSchedule(ctx, tx, c.ID, schedule); err != nil {
+		return nil, err
+	}
+
+	var updated *credit.Credit
+	if closeCredit {
+		updated, err = scanCredit(tx.QueryRow(ctx, `
+			UPDATE credits SET status = $1, closed_at = CURRENT_TIMESTAMP
+			WHERE id = $2
+			RETURNING `+creditColumns, credit.CLOSED, c.ID))
+	} else {
+		updated, err = scanCredit(tx.QueryRow(ctx, `SELECT `+creditColumns+` FROM credits WHERE id = $1`, c.ID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetActiveByRateType получает активные кредиты с указанным видом ставки
+func (r *CreditRepository) GetActiveByRateType(ctx context.Context, rateType credit.RateType) ([]*credit.Credit, error) {
+	query := `SELECT ` + creditColumns + ` FROM credits WHERE status = $1 AND rate_type = $2 ORDER BY id`
+	rows, err := r.db.Query(ctx, query, credit.ACTIVE, rateType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []*credit.Credit
+	for rows.Next() {
+		c, err := scanCredit(rows)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// GetBorrowerEmail получает email владельца счета кредита
+func (r *CreditRepository) GetBorrowerEmail(ctx context.Context, creditID int64) (string, error) {
+	var email string
+	err := r.db.QueryRow(ctx, `
+		SELECT u.email
+		FROM credits c
+		JOIN accounts a ON a.id = c.account_id
+		JOIN users u ON u.id = a.user_id
+		WHERE c.id = $1
+	`, creditID).Scan(&email)
+	return email, err
+}
+
+// GetBorrowerIDs получает идентификаторы пользователей, у которых есть хотя бы один кредит, в порядке возрастания
+func (r *CreditRepository) GetBorrowerIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT a.user_id
+		FROM credits c
+		JOIN accounts a ON a.id = c.account_id
+		ORDER BY a.user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetRateHistory получает историю ставки по кредиту в хронологическом порядке
+func (r *CreditRepository) GetRateHistory(ctx context.Context, creditID int64) ([]*credit.RateChange, error) {
+	query := `
+		SELECT id, credit_id, effective_date, key_rate, margin, rate, payment, created_at
+		FROM credit_rate_history
+		WHERE credit_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*credit.RateChange
+	for rows.Next() {
+		var h credit.RateChange
+		if err := rows.Scan(&h.ID, &h.CreditID, &h.EffectiveDate, &h.KeyRate, &h.Margin, &h.Rate,
+			&h.Payment, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, &h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Reprice в одной транзакции применяет новую ставку к кредиту: заменяет неоплаченные платежи replaced
+// на schedule, обновляет ставку кредита и сохраняет запись истории change. Остаток основного долга
+// по неоплаченной части графика должен совпадать с outstanding. Возвращает pgx.ErrNoRows, если кредит
+// не активен или график изменился
+func (r *CreditRepository) Reprice(ctx context.Context, c *credit.Credit, outstanding decimal.Decimal, replaced []int64,
+	schedule []*models.PaymentSchedule, change *credit.RateChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка кредита на время пересчета графика
+	var status credit.Status
+	if err = tx.QueryRow(ctx, `SELECT status FROM credits WHERE id = $1 FOR UPDATE`, c.ID).Scan(&status); err != nil {
+		return err
+	}
+	var current decimal.Decimal
+	err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(principal), 0) FROM payment_schedules WHERE credit_id = $1 AND NOT paid`,
+		c.ID).Scan(&current)
+	if err != nil {
+		return err
+	}
+	if status != credit.ACTIVE || !current.Equal(outstanding) {
+		return pgx.ErrNoRows
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM payment_schedules WHERE credit_id = $1 AND NOT paid AND id = ANY($2)`,
+		c.ID, replaced)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != int64(len(replaced)) {
+		return pgx.ErrNoRows
+	}
+	if err := insertSchedule(ctx, tx, c.ID, schedule); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE credits SET interest_rate = $1 WHERE id = $2`, change.Rate, c.ID); err != nil {
+		return err
+	}
+	change.CreditID = c.ID
+	if err := insertRateChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetDebtProfile получает для пользователя сумму ближайших платежей по действующим кредитам, число
+// непогашенных просроченных платежей и кредитов в статусе OVERDUE на дату today и число платежей,
+// внесенных позже даты по графику
+func (r *CreditRepository) GetDebtProfile(ctx context.Context, userID int64, today time.Time) (decimal.Decimal, int, int, error) {
+	query := `
+		SELECT
+			COALESCE(SUM((
+				SELECT ps.amount FROM payment_schedules ps
+				WHERE ps.credit_id = c.id AND NOT ps.paid
+				ORDER BY ps.due_date, ps.id
+				LIMIT 1
+			)) FILTER (WHERE c.status IN ($2, $3)), 0),
+			(COUNT(*) FILTER (WHERE c.status = $3) + COALESCE(SUM((
+				SELECT COUNT(*) FROM payment_schedules ps
+				WHERE ps.credit_id = c.id AND NOT ps.paid AND ps.due_date < $4
+			)), 0))::int,
+			COALESCE(SUM((
+				SELECT COUNT(*) FROM payment_schedules ps
+				WHERE ps.credit_id = c.id AND ps.paid AND ps.paid_at::date > ps.due_date
+			)), 0)::int
+		FROM credits c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE a.user_id = $1
+	`
+	var payments decimal.Decimal
+	var arrears, late int
+	err := r.db.QueryRow(ctx, query, userID, credit.ACTIVE, credit.OVERDUE, today).Scan(&payments, &arrears, &late)
+	if err != nil {
+		return decimal.Zero, 0, 0, err
+	}
+	return payments, arrears, late, nil
+}
+
+// GetDuePayments получает неоплаченные платежи по активным и просроченным кредитам с датой не позже date
+// в порядке кредитов и дат
+func (r *CreditRepository) GetDuePayments(ctx context.Context, date time.Time) ([]*models.PaymentSchedule, error) {
+	query := `
+		SELECT ps.id, ps.credit_id, ps.due_date, ps.amount, ps.principal, ps.interest, ps.penalty, ps.paid, ps.paid_at, ps.created_at
+		FROM payment_schedules ps
+		JOIN credits c ON c.id = ps.credit_id
+		WHERE c.status IN ($1, $2) AND NOT ps.paid AND ps.due_date <= $3
+		ORDER BY ps.credit_id, ps.due_date, ps.id
+	`
+	rows, err := r.db.Query(ctx, query, credit.ACTIVE, credit.OVERDUE, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedule []*models.PaymentSchedule
+	for rows.Next() {
+		var p models.PaymentSchedule
+		if err := rows.Scan(&p.ID, &p.CreditID, &p.DueDate, &p.Amount, &p.Principal, &p.Interest, &p.Penalty,
+			&p.Paid, &p.PaidAt, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// PayScheduled в одной транзакции списывает платеж по графику со счета кредита операцией WITHDRAWAL,
+// отмечает его оплаченным и сохраняет платеж SCHEDULED; после последнего платежа кредит закрывается.
+// Закрытие просроченного кредита записывается в журнал работы с просрочкой, а ограничения по счетам
+// заемщика снимаются, если у него не осталось других просроченных кредитов.
+// Возвращает pgx.ErrNoRows, если кредит закрыт, платеж уже оплачен или на счете недостаточно средств
+func (r *CreditRepository) PayScheduled(ctx context.Context, c *credit.Credit, p *models.PaymentSchedule) (*credit.Payment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка кредита, чтобы платеж не пересекся с досрочным погашением
+	var status credit.Status
+	if err = tx.QueryRow(ctx, `SELECT status FROM credits WHERE id = $1 FOR UPDATE`, c.ID).Scan(&status); err != nil {
+		return nil, err
+	}
+	if status != credit.ACTIVE && status != credit.OVERDUE {
+		return nil, pgx.ErrNoRows
+	}
+
+	// Сумма берется из графика: к ней могла быть начислена неустойка после чтения платежа
+	err = tx.QueryRow(ctx, `
+		UPDATE payment_schedules SET paid = TRUE, paid_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND NOT paid
+		RETURNING amount, principal, interest, penalty
+	`, p.ID).Scan(&p.Amount, &p.Principal, &p.Interest, &p.Penalty)
+	if err != nil {
+		return nil, err
+	}
+
+	var accountID int64
+	err = tx.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $1
+		WHERE id = $2 AND balance >= $1
+		RETURNING id
+	`, p.Amount, c.AccountID).Scan(&accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, c.AccountID, p.Amount, transaction.WITHDRAWAL, transaction.KIND_CREDIT_PAYMENT, transaction.COMPLETED).Scan(&transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &credit.Payment{
+		CreditID:      c.ID,
+		Amount:        p.Amount,
+		Principal:     p.Principal,
+		Interest:      p.Interest,
+		Penalty:       p.Penalty,
+		Kind:          credit.SCHEDULED,
+		TransactionID: &transactionID,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO credit_payments (credit_id, amount, principal, interest, penalty, kind, transaction_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, c.ID, payment.Amount, payment.Principal, payment.Interest, payment.Penalty, payment.Kind,
+		transactionID).Scan(&payment.ID, &payment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE credits SET status = $1, overdue_since = NULL, closed_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND NOT EXISTS (SELECT 1 FROM payment_schedules WHERE credit_id = $2 AND NOT paid)
+	`, credit.CLOSED, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() > 0 && status == credit.OVERDUE {
+		if err := clearOverdue(ctx, tx, c.ID, time.Now().UTC().Truncate(24*time.Hour), "кредит погашен полностью"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// insertRateChange сохраняет запись истории ставки, заполняя ее ID и время создания
+func insertRateChange(ctx context.Context, tx pgx.Tx, h *credit.RateChange) error {
+	return tx.QueryRow(ctx, `
+		INSERT INTO credit_rate_history (credit_id, effective_date, key_rate, margin, rate, payment)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, h.CreditID, h.EffectiveDate, h.KeyRate, h.Margin, h.Rate, h.Payment).Scan(&h.ID, &h.CreatedAt)
+}
+
+// insertSchedule сохраняет строки графика платежей кредита одним запросом COPY
+func insertSchedule(ctx context.Context, tx pgx.Tx, creditID int64, schedule []*models.PaymentSchedule) error {
+	if len(schedule) == 0 {
+		return nil
+	}
+	rows := make([][]any, 0, len(schedule))
+	for _, p := range schedule {
+		rows = append(rows, []any{creditID, p.DueDate, p.Amount, p.Principal, p.Interest, p.Paid})
+	}
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"payment_schedules"},
+		[]string{"credit_id", "due_date", "amount", "principal", "interest", "paid"},
+		pgx.CopyFromRows(rows))
+	return err
+}
+
+// scanCredit сканирует строку со столбцами creditColumns
+func scanCredit(row pgx.Row) (*credit.Credit, error) {
+	var c credit.Credit
+	err := row.Scan(&c.ID, &c.AccountID, &c.Principal, &c.InterestRate, &c.TermMonths, &c.Scheme, &c.RateType, &c.RateMargin, &c.StartDate,
+		&c.Status, &c.OverdueSince, &c.ClosedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
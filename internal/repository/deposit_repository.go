@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/deposit"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// DepositRepository реализует работу с таблицей срочных вкладов в базе данных
+type DepositRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewDepositRepository создает новый экземпляр репозитория для работы со вкладами
+func NewDepositRepository(db *pgxpool.Pool) *DepositRepository {
+	return &DepositRepository{db: db}
+}
+
+// depositColumns — список столбцов вклада в порядке сканирования scanDeposit
+const depositColumns = `id, user_id, account_id, principal, rate, rate_source, penalty_rate, term_months, maturity_action,
+	start_date, maturity_date, rollovers, interest_paid, status, closed_at, created_at`
+
+// Open в одной транзакции списывает сумму вклада со счета операцией WITHDRAWAL и создает вклад.
+// Возвращает pgx.ErrNoRows, если на счете недостаточно средств
+func (r *DepositRepository) Open(ctx context.Context, d *deposit.Deposit) (*deposit.Deposit, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var accountID int64
+	err = tx.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $1
+		WHERE id = $2 AND balance >= $1
+		RETURNING id
+	`, d.Principal, d.AccountID).Scan(&accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`, d.AccountID, d.Principal, transaction.WITHDRAWAL, transaction.KIND_DEPOSIT_OPENING, transaction.COMPLETED)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO deposits (user_id, account_id, principal, rate, rate_source, penalty_rate, term_months,
+			maturity_action, start_date, maturity_date, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + depositColumns
+	created, err := scanDeposit(tx.QueryRow(ctx, query, d.UserID, d.AccountID, d.Principal, d.Rate, d.RateSource,
+		d.PenaltyRate, d.TermMonths, d.MaturityAction, d.StartDate, d.MaturityDate, deposit.ACTIVE))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByID получает вклад по ID
+func (r *DepositRepository) GetByID(ctx context.Context, id int64) (*deposit.Deposit, error) {
+	query := `SELECT ` + depositColumns + ` FROM deposits WHERE id = $1`
+	return scanDeposit(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByUserID получает все вклады пользователя, начиная с последних
+func (r *DepositRepository) GetByUserID(ctx context.Context, userID int64) ([]*deposit.Deposit, error) {
+	query := `SELECT ` + depositColumns + ` FROM deposits WHERE user_id = $1 ORDER BY id DESC`
+	return r.queryDeposits(ctx, query, userID)
+}
+
+// GetMatured получает открытые вклады, срок которых закончился не позднее date
+func (r *DepositRepository) GetMatured(ctx context.Context, date time.Time) ([]*deposit.Deposit, error) {
+	query := `SELECT ` + depositColumns + ` FROM deposits WHERE status = $1 AND maturity_date <= $2 ORDER BY maturity_date, id`
+	return r.queryDeposits(ctx, query, deposit.ACTIVE, date)
+}
+
+// Close в одной транзакции закрывает открытый вклад со статусом status и зачисляет на счет вклада
+// сумму вклада операцией DEPOSIT и проценты операцией INTEREST. Возвращает pgx.ErrNoRows, если вклад уже закрыт
+func (r *DepositRepository) Close(ctx context.Context, d *deposit.Deposit, status deposit.Status, interest decimal.Decimal) (*deposit.Deposit, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE deposits
+		SET status = $1, interest_paid = interest_paid + $2, closed_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = $4
+		RETURNING ` + depositColumns
+	closed, err := scanDeposit(tx.QueryRow(ctx, query, status, interest, d.ID, deposit.ACTIVE))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE accounts SET balance = balance + $1 WHERE id = $2`,
+		closed.Principal.Add(interest), closed.AccountID); err != nil {
+		return nil, err
+	}
+
+	insert := `
+		INSERT INTO transactions (account_id, amount, type, kind, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err = tx.Exec(ctx, insert, closed.AccountID, closed.Principal, transaction.DEPOSIT,
+		transaction.KIND_DEPOSIT_CLOSING, transaction.COMPLETED); err != nil {
+		return nil, err
+	}
+	if interest.IsPositive() {
+		if _, err = tx.Exec(ctx, insert, closed.AccountID, interest, transaction.INTEREST,
+			transaction.KIND_INTEREST, transaction.COMPLETED); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+
+// Rollover продлевает открытый вклад на новый срок: причисляет проценты к сумме вклада и обновляет ставку и даты.
+// Возвращает pgx.ErrNoRows, если вклад закрыт или уже продлен
+func (r *DepositRepository) Rollover(ctx context.Context, d *deposit.Deposit, interest decimal.Decimal,
+	rate decimal.Decimal, source deposit.RateSource, start, maturity time.Time) (*deposit.Deposit, error) {
+	query := `
+		UPDATE deposits
+		SET principal = principal + $1, interest_paid = interest_paid + $1, rate = $2, rate_source = $3,
+			start_date = $4, maturity_date = $5, rollovers = rollovers + 1
+		WHERE id = $6 AND status = $7 AND maturity_date = $8
+		RETURNING ` + depositColumns
+	return scanDeposit(r.db.QueryRow(ctx, query, interest, rate, source, start, maturity,
+		d.ID, deposit.ACTIVE, d.MaturityDate))
+}
+
+// queryDeposits выполняет запрос и сканирует список вкладов
+func (r *DepositRepository) queryDeposits(ctx context.Context, query string, args ...any) ([]*deposit.Deposit, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*deposit.Deposit
+	for rows.Next() {
+		d, err := scanDeposit(rows)
+		if err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return deposits, nil
+}
+
+// scanDeposit сканирует строку со столбцами depositColumns
+func scanDeposit(row pgx.Row) (*deposit.Deposit, error) {
+	var d deposit.Deposit
+	err := row.Scan(&d.ID, &d.UserID, &d.AccountID, &d.Principal, &d.Rate, &d.RateSource, &d.PenaltyRate,
+		&d.TermMonths, &d.MaturityAction, &d.StartDate, &d.MaturityDate, &d.Rollovers, &d.InterestPaid,
+		&d.Status, &d.ClosedAt, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
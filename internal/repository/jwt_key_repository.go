@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/keyset"
+)
+
+// jwtKeyRotationLock — ключ advisory-блокировки, под которой экземпляры сервиса ротируют ключи JWT по очереди
+const jwtKeyRotationLock = 7302144
+
+// JWTKeyRepository реализует хранение ключей подписи JWT. Секретные данные ключей шифруются PGP
+// средствами pgcrypto
+type JWTKeyRepository struct {
+	db            *pgxpool.Pool // Пул соединений с базой данных
+	encryptionKey string        // Ключ PGP-шифрования секретных данных
+}
+
+// NewJWTKeyRepository создает новый экземпляр репозитория ключей подписи JWT
+func NewJWTKeyRepository(db *pgxpool.Pool, encryptionKey string) *JWTKeyRepository {
+	return &JWTKeyRepository{db: db, encryptionKey: encryptionKey}
+}
+
+// GetValid получает ключи, которые еще проверяют подписи в момент now: действующие и выведенные из оборота,
+// срок проверки которых не истек
+func (r *JWTKeyRepository) GetValid(ctx context.Context, now time.Time) ([]*keyset.Key, error) {
+	query := `
+		SELECT id, algorithm, pgp_sym_decrypt_bytea(key_data, $1), created_at, retired_at, expires_at
+		FROM jwt_keys
+		WHERE expires_at IS NULL OR expires_at > $2
+		ORDER BY created_at
+	`
+	rows, err := r.db.Query(ctx, query, r.encryptionKey, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*keyset.Key
+	for rows.Next() {
+		var (
+			id, alg   string
+			data      []byte
+			createdAt time.Time
+			retiredAt *time.Time
+			expiresAt *time.Time
+		)
+		if err := rows.Scan(&id, &alg, &data, &createdAt, &retiredAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		k, err := keyset.Parse(id, keyset.Algorithm(alg), data)
+		if err != nil {
+			return nil, err
+		}
+		k.CreatedAt, k.RetiredAt, k.ExpiresAt = createdAt, retiredAt, expiresAt
+		keys = append(keys, k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Rotate под advisory-блокировкой сохраняет ключ k и выводит из оборота остальные действующие ключи,
+// оставляя их для проверки подписей до verifyUntil. Ключ не сохраняется, если действующий ключ того же
+// алгоритма создан позже rotateBefore — например, его уже создал другой экземпляр сервиса.
+// Возвращает true, если ключ сохранен
+func (r *JWTKeyRepository) Rotate(ctx context.Context, k *keyset.Key, rotateBefore, verifyUntil time.Time) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, jwtKeyRotationLock); err != nil {
+		return false, err
+	}
+
+	var fresh bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM jwt_keys WHERE retired_at IS NULL AND algorithm = $1 AND created_at > $2
+		)
+	`, k.Algorithm, rotateBefore).Scan(&fresh)
+	if err != nil {
+		return false, err
+	}
+	if fresh {
+		return false, nil
+	}
+
+	data, err := k.Marshal()
+	if err != nil {
+		return false, err
+	}
+	if _, err = tx.Exec(ctx, `UPDATE jwt_keys SET retired_at = $1, expires_at = $2 WHERE retired_at IS NULL`,
+		k.CreatedAt, verifyUntil); err != nil {
+		return false, err
+	}
+	if _, err = tx.Exec(ctx, `
+		INSERT INTO jwt_keys (id, algorithm, key_data, created_at) VALUES ($1, $2, pgp_sym_encrypt_bytea($3, $4), $5)
+	`, k.ID, k.Algorithm, data, r.encryptionKey, k.CreatedAt); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteExpired удаляет выведенные из оборота ключи, срок проверки которых истек к моменту now
+func (r *JWTKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM jwt_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/credit"
+)
+
+// KeyRateRepository хранит полученные значения ключевой ставки ЦБ РФ
+type KeyRateRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewKeyRateRepository создает новый экземпляр репозитория ключевой ставки
+func NewKeyRateRepository(db *pgxpool.Pool) *KeyRateRepository {
+	return &KeyRateRepository{db: db}
+}
+
+// GetLatest получает последнее сохраненное значение ключевой ставки; возвращает pgx.ErrNoRows, если значений нет
+func (r *KeyRateRepository) GetLatest(ctx context.Context) (*credit.KeyRate, error) {
+	var k credit.KeyRate
+	err := r.db.QueryRow(ctx, `SELECT id, rate, fetched_at FROM key_rates ORDER BY id DESC LIMIT 1`).
+		Scan(&k.ID, &k.Rate, &k.FetchedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Save сохраняет новое значение ключевой ставки (в процентах)
+func (r *KeyRateRepository) Save(ctx context.Context, rate decimal.Decimal) (*credit.KeyRate, error) {
+	k := credit.KeyRate{Rate: rate}
+	err := r.db.QueryRow(ctx, `INSERT INTO key_rates (rate) VALUES ($1) RETURNING id, fetched_at`, rate).
+		Scan(&k.ID, &k.FetchedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
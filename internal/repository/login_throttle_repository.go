@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/lockout"
+)
+
+// LoginThrottleRepository реализует хранение счетчиков неудачных попыток входа. Счетчики хранятся в базе
+// данных, поэтому ограничения действуют сразу на всех экземплярах сервиса
+type LoginThrottleRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewLoginThrottleRepository создает новый экземпляр репозитория счетчиков неудачных попыток входа
+func NewLoginThrottleRepository(db *pgxpool.Pool) *LoginThrottleRepository {
+	return &LoginThrottleRepository{db: db}
+}
+
+// throttleColumns перечисляет столбцы счетчика в порядке сканирования scanThrottle
+const throttleColumns = `scope, key, failures, last_failure_at, blocked_until, locked_at`
+
+// GetBlocked получает счетчики по email и IP-адресу, попытки входа по которым отклоняются в момент now
+func (r *LoginThrottleRepository) GetBlocked(ctx context.Context, email, ip string, now time.Time) ([]*lockout.Entry, error) {
+	query := `
+		SELECT ` + throttleColumns + `
+		FROM login_throttles
+		WHERE ((scope = $1 AND key = $2) OR (scope = $3 AND key = $4)) AND blocked_until > $5
+		ORDER BY blocked_until DESC
+	`
+	return r.query(ctx, query, lockout.EMAIL, email, lockout.IP, ip, now)
+}
+
+// GetActive получает счетчики, попытки входа по которым отклоняются в момент now, начиная с последних
+func (r *LoginThrottleRepository) GetActive(ctx context.Context, now time.Time) ([]*lockout.Entry, error) {
+	query := `
+		SELECT ` + throttleColumns + `
+		FROM login_throttles
+		WHERE blocked_until > $1
+		ORDER BY last_failure_at DESC
+	`
+	return r.query(ctx, query, now)
+}
+
+// RecordFailure засчитывает неудачную попытку входа в момент now и возвращает обновленный счетчик.
+// Счетчик начинается заново, если предыдущая неудачная попытка была раньше windowStart
+func (r *LoginThrottleRepository) RecordFailure(ctx context.Context, scope lockout.Scope, key string,
+	now, windowStart time.Time) (*lockout.Entry, error) {
+	query := `
+		INSERT INTO login_throttles (scope, key, failures, last_failure_at) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < $4 THEN 1 ELSE login_throttles.failures + 1 END,
+			blocked_until = CASE WHEN login_throttles.last_failure_at < $4 THEN NULL ELSE login_throttles.blocked_until END,
+			locked_at = CASE WHEN login_throttles.last_failure_at < $4 THEN NULL ELSE login_throttles.locked_at END,
+			last_failure_at = $3
+		RETURNING ` + throttleColumns
+	return scanThrottle(r.db.QueryRow(ctx, query, scope, key, now, windowStart))
+}
+
+// Block отклоняет попытки входа по счетчику до until; срок уже действующего ограничения не сокращается.
+// При lock ограничение отмечается как блокировка после превышения лимита. Возвращает true, если
+// блокировка установлена этим вызовом, а не продлена уже действующая
+func (r *LoginThrottleRepository) Block(ctx context.Context, scope lockout.Scope, key string, until time.Time, lock bool,
+	now time.Time) (bool, error) {
+	var locked bool
+	err := r.db.QueryRow(ctx, `
+		UPDATE login_throttles SET
+			locked_at = CASE
+				WHEN NOT $1 OR (locked_at IS NOT NULL AND blocked_until > $2) THEN locked_at
+				ELSE $2
+			END,
+			blocked_until = GREATEST(blocked_until, $3)
+		WHERE scope = $4 AND key = $5
+		RETURNING $1 AND locked_at = $2
+	`, lock, now, until, scope, key).Scan(&locked)
+	return locked, err
+}
+
+// Reset удаляет счетчик неудачных попыток. Возвращает false, если счетчика не было
+func (r *LoginThrottleRepository) Reset(ctx context.Context, scope lockout.Scope, key string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM login_throttles WHERE scope = $1 AND key = $2`, scope, key)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteStale удаляет счетчики без действующих ограничений, последняя неудачная попытка по которым
+// была раньше before
+func (r *LoginThrottleRepository) DeleteStale(ctx context.Context, now, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < $1 AND (blocked_until IS NULL OR blocked_until <= $2)
+	`, before, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// query выполняет запрос, возвращающий столбцы throttleColumns
+func (r *LoginThrottleRepository) query(ctx context.Context, query string, args ...interface{}) ([]*lockout.Entry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*lockout.Entry
+	for rows.Next() {
+		e, err := scanThrottle(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// scanThrottle сканирует строку со столбцами throttleColumns
+func scanThrottle(row pgx.Row) (*lockout.Entry, error) {
+	var e lockout.Entry
+	if err := row.Scan(&e.Scope, &e.Key, &e.Failures, &e.LastFailureAt, &e.BlockedUntil, &e.LockedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
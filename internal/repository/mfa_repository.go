@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/mfa"
+)
+
+// MFARepository реализует хранение данных двухфакторной аутентификации: секретов TOTP, хешей кодов
+// восстановления и незавершенных входов. Секреты TOTP шифруются PGP средствами pgcrypto
+type MFARepository struct {
+	db            *pgxpool.Pool // Пул соединений с базой данных
+	encryptionKey string        // Ключ PGP-шифрования секретов
+}
+
+// NewMFARepository создает новый экземпляр репозитория двухфакторной аутентификации
+func NewMFARepository(db *pgxpool.Pool, encryptionKey string) *MFARepository {
+	return &MFARepository{db: db, encryptionKey: encryptionKey}
+}
+
+// challengeColumns перечисляет столбцы незавершенного входа в порядке сканирования scanChallenge
+const challengeColumns = `id, user_id, user_agent, ip_address, attempts, expires_at, completed_at, created_at`
+
+// GetTOTP получает секрет TOTP пользователя. Возвращает pgx.ErrNoRows, если секрет не выпускался
+func (r *MFARepository) GetTOTP(ctx context.Context, userID int64) (*mfa.TOTP, error) {
+	query := `
+		SELECT user_id, pgp_sym_decrypt_bytea(secret, $1), confirmed_at, last_step, failed_attempts, locked_until, created_at
+		FROM user_totp
+		WHERE user_id = $2
+	`
+	var t mfa.TOTP
+	err := r.db.QueryRow(ctx, query, r.encryptionKey, userID).
+		Scan(&t.UserID, &t.Secret, &t.ConfirmedAt, &t.LastStep, &t.Failures, &t.LockedUntil, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SaveTOTP сохраняет новый секрет пользователя, заменяя неподтвержденный. Возвращает pgx.ErrNoRows,
+// если двухфакторная аутентификация уже включена
+func (r *MFARepository) SaveTOTP(ctx context.Context, userID int64, secret []byte) error {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO user_totp (user_id, secret) VALUES ($1, pgp_sym_encrypt_bytea($2, $3))
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_step = 0, created_at = now()
+		WHERE user_totp.confirmed_at IS NULL
+	`, userID, secret, r.encryptionKey)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Confirm в одной транзакции включает двухфакторную аутентификацию, запоминая шаг step первого кода,
+// и заменяет коды восстановления пользователя хешами codeHashes. Возвращает pgx.ErrNoRows, если секрет
+// не выпускался, уже подтвержден или код с этим шагом уже принят
+func (r *MFARepository) Confirm(ctx context.Context, userID, step int64, codeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE user_totp SET confirmed_at = now(), last_step = $1
+		WHERE user_id = $2 AND confirmed_at IS NULL AND last_step < $1
+	`, step, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err = tx.Exec(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseStep принимает код с шагом step, если двухфакторная аутентификация включена и код с этим или более
+// поздним шагом еще не принимался. Возвращает false при повторном использовании кода
+func (r *MFARepository) UseStep(ctx context.Context, userID, step int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_totp SET last_step = $1
+		WHERE user_id = $2 AND confirmed_at IS NOT NULL AND last_step < $1
+	`, step, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// UseRecoveryCode погашает неиспользованный код восстановления с хешем codeHash. Возвращает false,
+// если код не найден или уже использован
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE recovery_codes SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecordFailure учитывает неверный код пользователя. Если неверных кодов подряд стало не меньше maxFailures,
+// ввод кодов блокируется до lockUntil, а счетчик сбрасывается. Возвращает время окончания блокировки
+// или nil, если блокировки нет
+func (r *MFARepository) RecordFailure(ctx context.Context, userID int64, maxFailures int, lockUntil time.Time) (*time.Time, error) {
+	var lockedUntil *time.Time
+	err := r.db.QueryRow(ctx, `
+		UPDATE user_totp
+		SET failed_attempts = CASE WHEN failed_attempts + 1 >= $2 THEN 0 ELSE failed_attempts + 1 END,
+		    locked_until = CASE WHEN failed_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE user_id = $1
+		RETURNING CASE WHEN locked_until > now() THEN locked_until END
+	`, userID, maxFailures, lockUntil).Scan(&lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+// ResetFailures сбрасывает счетчик неверных кодов пользователя после принятого кода
+func (r *MFARepository) ResetFailures(ctx context.Context, userID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE user_totp SET failed_attempts = 0 WHERE user_id = $1 AND failed_attempts > 0`, userID)
+	return err
+}
+
+// CountRecoveryCodes возвращает количество неиспользованных кодов восстановления пользователя
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// Delete в одной транзакции выключает двухфакторную аутентификацию: удаляет секрет и коды восстановления
+func (r *MFARepository) Delete(ctx context.Context, userID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateChallenge сохраняет незавершенный вход c с хешем токена tokenHash, заполняя ID и время создания
+func (r *MFARepository) CreateChallenge(ctx context.Context, c *mfa.Challenge, tokenHash string) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO mfa_challenges (user_id, token_hash, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, c.UserID, tokenHash, c.UserAgent, c.IPAddress, c.ExpiresAt).Scan(&c.ID, &c.CreatedAt)
+}
+
+// AttemptChallenge засчитывает попытку ввода кода по токену с хешем tokenHash и возвращает незавершенный вход.
+// Возвращает pgx.ErrNoRows, если токен не найден, истек, уже использован или попытки исчерпаны
+func (r *MFARepository) AttemptChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*mfa.Challenge, error) {
+	query := `
+		UPDATE mfa_challenges SET attempts = attempts + 1
+		WHERE token_hash = $1 AND completed_at IS NULL AND expires_at > now() AND attempts < $2
+		RETURNING ` + challengeColumns
+	return scanChallenge(r.db.QueryRow(ctx, query, tokenHash, maxAttempts))
+}
+
+// CompleteChallenge отмечает вход id завершенным. Возвращает pgx.ErrNoRows, если он уже завершен
+func (r *MFARepository) CompleteChallenge(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `UPDATE mfa_challenges SET completed_at = now() WHERE id = $1 AND completed_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteExpiredChallenges удаляет незавершенные входы, срок действия которых истек к моменту now
+func (r *MFARepository) DeleteExpiredChallenges(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM mfa_challenges WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanChallenge сканирует строку со столбцами challengeColumns
+func scanChallenge(row pgx.Row) (*mfa.Challenge, error) {
+	var c mfa.Challenge
+	if err := row.Scan(&c.ID, &c.UserID, &c.UserAgent, &c.IPAddress, &c.Attempts, &c.ExpiresAt, &c.CompletedAt,
+		&c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/overdraft"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// OverdraftRepository реализует работу с начислениями процентов за пользование овердрафтом
+type OverdraftRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewOverdraftRepository создает новый экземпляр репозитория для работы с овердрафтом
+func NewOverdraftRepository(db *pgxpool.Pool) *OverdraftRepository {
+	return &OverdraftRepository{db: db}
+}
+
+// GetLastChargeDate получает последний день, за который рассчитаны проценты по овердрафту счета, или nil
+func (r *OverdraftRepository) GetLastChargeDate(ctx context.Context, accountID int64) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(charge_date) FROM overdraft_charges WHERE account_id = $1`, accountID).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// Charge в одной транзакции сохраняет дневные начисления и списывает их сумму со счета операцией
+// OVERDRAFT_INTEREST. Списание выполняется независимо от лимита овердрафта. Нулевая сумма не списывается
+func (r *OverdraftRepository) Charge(ctx context.Context, accountID int64, charges []*overdraft.Charge, total decimal.Decimal) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var transactionID *int64
+	if total.IsPositive() {
+		var id int64
+		err = tx.QueryRow(ctx, `
+			INSERT INTO transactions (account_id, amount, type, kind, status)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, accountID, total, transaction.OVERDRAFT_INTEREST, transaction.KIND_OVERDRAFT_INTEREST, transaction.COMPLETED).Scan(&id)
+		if err != nil {
+			return err
+		}
+		transactionID = &id
+
+		if _, err = tx.Exec(ctx, `UPDATE accounts SET balance = balance - $1 WHERE id = $2`, total, accountID); err != nil {
+			return err
+		}
+	}
+
+	rows := make([][]any, 0, len(charges))
+	for _, c := range charges {
+		rows = append(rows, []any{accountID, c.ChargeDate, c.Balance, c.Rate, c.Amount, transactionID})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"overdraft_charges"},
+		[]string{"account_id", "charge_date", "balance", "rate", "amount", "transaction_id"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetChargedByUserSince получает суммы процентов по овердрафту, начисленных по счетам пользователя
+// начиная с дня from, в разрезе счетов
+func (r *OverdraftRepository) GetChargedByUserSince(ctx context.Context, userID int64, from time.Time) (map[int64]decimal.Decimal, error) {
+	query := `
+		SELECT c.account_id, SUM(c.amount)
+		FROM overdraft_charges c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE a.user_id = $1 AND c.charge_date >= $2
+		GROUP BY c.account_id
+	`
+	rows, err := r.db.Query(ctx, query, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	charged := make(map[int64]decimal.Decimal)
+	for rows.Next() {
+		var accountID int64
+		var total decimal.Decimal
+		if err := rows.Scan(&accountID, &total); err != nil {
+			return nil, err
+		}
+		charged[accountID] = total
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return charged, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/paymentrequest"
+)
+
+// paymentRequestSelect выбирает колонки запроса на оплату вместе с email сторон в порядке сканирования.
+// Таблица запросов должна иметь псевдоним pr
+const paymentRequestSelect = `
+	SELECT pr.id, pr.requester_id, ru.email, pr.payer_id, pu.email, pr.to_account_id, pr.amount, pr.paid_amount,
+		pr.memo, pr.due_date, pr.status, pr.created_at, pr.updated_at`
+
+// paymentRequestJoins присоединяет пользователей — получателя и плательщика
+const paymentRequestJoins = `
+	JOIN users ru ON ru.id = pr.requester_id
+	JOIN users pu ON pu.id = pr.payer_id`
+
+// PaymentRequestRepository реализует работу с таблицами запросов на оплату в базе данных
+type PaymentRequestRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewPaymentRequestRepository создает новый экземпляр репозитория для работы с запросами на оплату
+func NewPaymentRequestRepository(db *pgxpool.Pool) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db}
+}
+
+// Create создает новый запрос на оплату
+func (r *PaymentRequestRepository) Create(ctx context.Context, pr *paymentrequest.PaymentRequest) (*paymentrequest.PaymentRequest, error) {
+	query := `
+		WITH pr AS (
+			INSERT INTO payment_requests (requester_id, payer_id, to_account_id, amount, memo, due_date, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING *
+		)` + paymentRequestSelect + ` FROM pr` + paymentRequestJoins
+	row := r.db.QueryRow(ctx, query, pr.RequesterID, pr.PayerID, pr.ToAccountID, pr.Amount, pr.Memo, pr.DueDate, pr.Status)
+	return scanPaymentRequest(row)
+}
+
+// GetByID получает запрос на оплату по ID
+func (r *PaymentRequestRepository) GetByID(ctx context.Context, id int64) (*paymentrequest.PaymentRequest, error) {
+	query := paymentRequestSelect + ` FROM payment_requests pr` + paymentRequestJoins + ` WHERE pr.id = $1`
+	return scanPaymentRequest(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByPayerID получает входящие запросы пользователя, начиная с новых
+func (r *PaymentRequestRepository) GetByPayerID(ctx context.Context, payerID int64) ([]*paymentrequest.PaymentRequest, error) {
+	query := paymentRequestSelect + ` FROM payment_requests pr` + paymentRequestJoins + `
+		WHERE pr.payer_id = $1
+		ORDER BY pr.id DESC`
+	return r.queryPaymentRequests(ctx, query, payerID)
+}
+
+// GetByRequesterID получает исходящие запросы пользователя, начиная с новых
+func (r *PaymentRequestRepository) GetByRequesterID(ctx context.Context, requesterID int64) ([]*paymentrequest.PaymentRequest, error) {
+	query := paymentRequestSelect + ` FROM payment_requests pr` + paymentRequestJoins + `
+		WHERE pr.requester_id = $1
+		ORDER BY pr.id DESC`
+	return r.queryPaymentRequests(ctx, query, requesterID)
+}
+
+// Transition переводит запрос в статус to, если его текущий статус входит в from.
+// Возвращает pgx.ErrNoRows, если запрос не найден или находится в другом статусе
+func (r *PaymentRequestRepository) Transition(ctx context.Context, id int64, to paymentrequest.Status, from ...paymentrequest.Status) (*paymentrequest.PaymentRequest, error) {
+	values := make([]string, 0, len(from))
+	for _, status := range from {
+		values = append(values, string(status))
+	}
+
+	query := `
+		WITH pr AS (
+			UPDATE payment_requests
+			SET status = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND status = ANY($3)
+			RETURNING *
+		)` + paymentRequestSelect + ` FROM pr` + paymentRequestJoins
+	return scanPaymentRequest(r.db.QueryRow(ctx, query, id, to, values))
+}
+
+// ReservePayment атомарно учитывает оплату в запросе до выполнения перевода, чтобы параллельные оплаты
+// не превысили запрошенную сумму. Возвращает pgx.ErrNoRows, если запрос закрыт, просрочен на дату today
+// или сумма превышает неоплаченный остаток
+func (r *PaymentRequestRepository) ReservePayment(ctx context.Context, id int64, amount decimal.Decimal, today time.Time) (*paymentrequest.PaymentRequest, error) {
+	query := `
+		WITH pr AS (
+			UPDATE payment_requests
+			SET paid_amount = paid_amount + $2,
+				status = CASE WHEN paid_amount + $2 = amount THEN $4 ELSE $5 END,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND status IN ($6, $5) AND paid_amount + $2 <= amount AND due_date >= $3
+			RETURNING *
+		)` + paymentRequestSelect + ` FROM pr` + paymentRequestJoins
+	row := r.db.QueryRow(ctx, query, id, amount, today,
+		paymentrequest.PAID, paymentrequest.PARTIALLY_PAID, paymentrequest.PENDING)
+	return scanPaymentRequest(row)
+}
+
+// ReleasePayment отменяет учет оплаты, перевод по которой не выполнен
+func (r *PaymentRequestRepository) ReleasePayment(ctx context.Context, id int64, amount decimal.Decimal) error {
+	query := `
+		UPDATE payment_requests
+		SET paid_amount = paid_amount - $2,
+			status = CASE
+				WHEN status NOT IN ($3, $4) THEN status
+				WHEN paid_amount - $2 = 0 THEN $5
+				ELSE $4
+			END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(ctx, query, id, amount,
+		paymentrequest.PAID, paymentrequest.PARTIALLY_PAID, paymentrequest.PENDING)
+	return err
+}
+
+// CreatePayment записывает выполненную оплату по запросу
+func (r *PaymentRequestRepository) CreatePayment(ctx context.Context, p *paymentrequest.Payment) error {
+	query := `
+		INSERT INTO payment_request_payments (request_id, from_account_id, amount)
+		VALUES ($1, $2, $3)
+	`
+	_, err := r.db.Exec(ctx, query, p.RequestID, p.FromAccountID, p.Amount)
+	return err
+}
+
+// GetPaymentsByRequestID получает оплаты по запросу в хронологическом порядке
+func (r *PaymentRequestRepository) GetPaymentsByRequestID(ctx context.Context, requestID int64) ([]*paymentrequest.Payment, error) {
+	query := `
+		SELECT id, request_id, from_account_id, amount, created_at
+		FROM payment_request_payments
+		WHERE request_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*paymentrequest.Payment
+	for rows.Next() {
+		var p paymentrequest.Payment
+		if err := rows.Scan(&p.ID, &p.RequestID, &p.FromAccountID, &p.Amount, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		payments = append(payments, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// ExpireOverdue переводит в статус EXPIRED открытые запросы, последний день оплаты которых раньше today.
+// Возвращает количество просроченных запросов
+func (r *PaymentRequestRepository) ExpireOverdue(ctx context.Context, today time.Time) (int64, error) {
+	query := `
+		UPDATE payment_requests
+		SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE status IN ($2, $3) AND due_date < $4
+	`
+	tag, err := r.db.Exec(ctx, query, paymentrequest.EXPIRED, paymentrequest.PENDING, paymentrequest.PARTIALLY_PAID, today)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// queryPaymentRequests выполняет запрос и сканирует список запросов на оплату
+func (r *PaymentRequestRepository) queryPaymentRequests(ctx context.Context, query string, args ...any) ([]*paymentrequest.PaymentRequest, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*paymentrequest.PaymentRequest
+	for rows.Next() {
+		pr, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, pr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// scanPaymentRequest сканирует строку с колонками paymentRequestSelect
+func scanPaymentRequest(row pgx.Row) (*paymentrequest.PaymentRequest, error) {
+	var pr paymentrequest.PaymentRequest
+	err := row.Scan(&pr.ID, &pr.RequesterID, &pr.RequesterEmail, &pr.PayerID, &pr.PayerEmail, &pr.ToAccountID,
+		&pr.Amount, &pr.PaidAmount, &pr.Memo, &pr.DueDate, &pr.Status, &pr.CreatedAt, &pr.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models"
+)
+
+// QRPaymentRepository реализует работу с таблицей оплат по QR-кодам в базе данных
+type QRPaymentRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewQRPaymentRepository создает новый экземпляр репозитория для работы с оплатами по QR-кодам
+func NewQRPaymentRepository(db *pgxpool.Pool) *QRPaymentRepository {
+	return &QRPaymentRepository{db: db}
+}
+
+// Claim записывает оплату по QR-коду до выполнения перевода. Возвращает pgx.ErrNoRows,
+// если QR-код с таким одноразовым кодом уже оплачен
+func (r *QRPaymentRepository) Claim(ctx context.Context, p *models.QRPayment) (*models.QRPayment, error) {
+	query := `
+		INSERT INTO qr_payments (nonce, from_account_id, to_account_id, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (nonce) DO NOTHING
+		RETURNING id, nonce, from_account_id, to_account_id, amount, created_at
+	`
+	var claimed models.QRPayment
+	err := r.db.QueryRow(ctx, query, p.Nonce, p.FromAccountID, p.ToAccountID, p.Amount).Scan(
+		&claimed.ID, &claimed.Nonce, &claimed.FromAccountID, &claimed.ToAccountID, &claimed.Amount, &claimed.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &claimed, nil
+}
+
+// Release удаляет запись об оплате, перевод по которой не выполнен, чтобы QR-код можно было оплатить снова
+func (r *QRPaymentRepository) Release(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM qr_payments WHERE id = $1`, id)
+	return err
+}
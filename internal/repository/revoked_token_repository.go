@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/session"
+)
+
+// RevokedTokenRepository реализует список отозванных access-токенов по идентификатору jti.
+// Запись нужна только до истечения срока токена
+type RevokedTokenRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewRevokedTokenRepository создает новый экземпляр репозитория отозванных токенов
+func NewRevokedTokenRepository(db *pgxpool.Pool) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+// Revoke добавляет токен jti пользователя userID в список отозванных до expiresAt; повторный отзыв не меняет запись
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, jti string, userID int64, reason session.RevokeReason,
+	expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO revoked_tokens (jti, user_id, reason, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`, jti, userID, reason, expiresAt)
+	return err
+}
+
+// IsRevoked сообщает, отозван ли токен jti
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired удаляет записи о токенах, срок действия которых истек к моменту now
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
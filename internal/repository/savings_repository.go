@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/savings"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// SavingsRepository реализует работу со ставками и начислениями процентов по накопительным счетам
+type SavingsRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewSavingsRepository создает новый экземпляр репозитория для работы с накопительными счетами
+func NewSavingsRepository(db *pgxpool.Pool) *SavingsRepository {
+	return &SavingsRepository{db: db}
+}
+
+// GetRates получает все ставки в порядке даты начала действия
+func (r *SavingsRepository) GetRates(ctx context.Context) ([]*savings.Rate, error) {
+	query := `
+		SELECT id, effective_date, rate, day_count, created_at
+		FROM savings_rates
+		ORDER BY effective_date
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*savings.Rate
+	for rows.Next() {
+		var rate savings.Rate
+		if err := rows.Scan(&rate.ID, &rate.EffectiveDate, &rate.Rate, &rate.DayCount, &rate.CreatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, &rate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// SaveRate устанавливает ставку, действующую с указанной даты; ставка на ту же дату заменяется
+func (r *SavingsRepository) SaveRate(ctx context.Context, rate *savings.Rate) error {
+	query := `
+		INSERT INTO savings_rates (effective_date, rate, day_count)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (effective_date) DO UPDATE SET rate = EXCLUDED.rate, day_count = EXCLUDED.day_count
+	`
+	_, err := r.db.Exec(ctx, query, rate.EffectiveDate, rate.Rate, rate.DayCount)
+	return err
+}
+
+// GetLastAccrualDate получает последний день, за который начислены проценты по счету, или nil
+func (r *SavingsRepository) GetLastAccrualDate(ctx context.Context, accountID int64) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(accrual_date) FROM interest_accruals WHERE account_id = $1`, accountID).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// CreateAccruals сохраняет дневные начисления одним запросом COPY
+func (r *SavingsRepository) CreateAccruals(ctx context.Context, accruals []*savings.Accrual) error {
+	rows := make([][]any, 0, len(accruals))
+	for _, a := range accruals {
+		rows = append(rows, []any{a.AccountID, a.AccrualDate, a.Balance, a.Rate, a.DayCount, a.Amount})
+	}
+	_, err := r.db.CopyFrom(ctx, pgx.Identifier{"interest_accruals"},
+		[]string{"account_id", "accrual_date", "balance", "rate", "day_count", "amount"},
+		pgx.CopyFromRows(rows))
+	return err
+}
+
+// GetAccruals получает начисления по счету за период [from, to] в хронологическом порядке
+func (r *SavingsRepository) GetAccruals(ctx context.Context, accountID int64, from, to time.Time) ([]*savings.Accrual, error) {
+	query := `
+		SELECT id, account_id, accrual_date, balance, rate, day_count, amount, transaction_id, capitalized_at, created_at
+		FROM interest_accruals
+		WHERE account_id = $1 AND accrual_date BETWEEN $2 AND $3
+		ORDER BY accrual_date
+	`
+	return r.queryAccruals(ctx, query, accountID, from, to)
+}
+
+// GetUncapitalized получает начисления по счету, еще не капитализированные, за дни раньше before
+func (r *SavingsRepository) GetUncapitalized(ctx context.Context, accountID int64, before time.Time) ([]*savings.Accrual, error) {
+	query := `
+		SELECT id, account_id, accrual_date, balance, rate, day_count, amount, transaction_id, capitalized_at, created_at
+		FROM interest_accruals
+		WHERE account_id = $1 AND capitalized_at IS NULL AND accrual_date < $2
+		ORDER BY accrual_date
+	`
+	return r.queryAccruals(ctx, query, accountID, before)
+}
+
+// Capitalize в одной транзакции захватывает некапитализированные начисления по счету за период [from, to),
+// зачисляет их сумму, округленную до копеек, операцией INTEREST и связывает начисления с операцией.
+// Зачисляется только сумма захваченных начислений: параллельный вызов за тот же период ждет блокировки строк
+// и не захватывает ни одного начисления. Возвращает зачисленную сумму; нулевая сумма не зачисляется
+func (r *SavingsRepository) Capitalize(ctx context.Context, accountID int64, from, to time.Time) (decimal.Decimal, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		UPDATE interest_accruals
+		SET capitalized_at = CURRENT_TIMESTAMP
+		WHERE account_id = $1 AND capitalized_at IS NULL AND accrual_date >= $2 AND accrual_date < $3
+		RETURNING id, amount
+	`, accountID, from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var ids []int64
+	total := decimal.Zero
+	for rows.Next() {
+		var (
+			id     int64
+			amount decimal.Decimal
+		)
+		if err := rows.Scan(&id, &amount); err != nil {
+			rows.Close()
+			return decimal.Zero, err
+		}
+		ids = append(ids, id)
+		total = total.Add(amount)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return decimal.Zero, err
+	}
+	if len(ids) == 0 {
+		return decimal.Zero, nil
+	}
+
+	total = total.Round(2)
+	if total.IsPositive() {
+		var transactionID int64
+		err = tx.QueryRow(ctx, `
+			INSERT INTO transactions (account_id, amount, type, kind, status)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, accountID, total, transaction.INTEREST, transaction.KIND_INTEREST, transaction.COMPLETED).Scan(&transactionID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		if _, err = tx.Exec(ctx, `UPDATE accounts SET balance = balance + $1 WHERE id = $2`, total, accountID); err != nil {
+			return decimal.Zero, err
+		}
+		if _, err = tx.Exec(ctx, `UPDATE interest_accruals SET transaction_id = $1 WHERE id = ANY($2)`, transactionID, ids); err != nil {
+			return decimal.Zero, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return decimal.Zero, err
+	}
+	return total, nil
+}
+
+// queryAccruals выполняет запрос и сканирует список начислений
+func (r *SavingsRepository) queryAccruals(ctx context.Context, query string, args ...any) ([]*savings.Accrual, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accruals []*savings.Accrual
+	for rows.Next() {
+		var a savings.Accrual
+		if err := rows.Scan(&a.ID, &a.AccountID, &a.AccrualDate, &a.Balance, &a.Rate, &a.DayCount, &a.Amount,
+			&a.TransactionID, &a.CapitalizedAt, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, &a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return accruals, nil
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/session"
+)
+
+// ErrRefreshTokenReused возвращается, если предъявлен уже использованный refresh-токен; сессия при этом завершается
+var ErrRefreshTokenReused = errors.New("refresh-токен уже был использован")
+
+// SessionRepository реализует хранение сессий пользователей и хешей выпущенных в них refresh-токенов
+type SessionRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewSessionRepository создает новый экземпляр репозитория для работы с сессиями
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// sessionColumns перечисляет столбцы сессии в порядке сканирования scanSession
+const sessionColumns = `id, user_id, user_agent, ip_address, last_ip, created_at, last_used_at, expires_at, revoked_at, revoke_reason,
+	access_jti, access_expires_at, mfa_verified_at`
+
+// revokeAccessQuery добавляет последний access-токен сессии в список отозванных, если его срок не истек
+const revokeAccessQuery = `
+	INSERT INTO revoked_tokens (jti, user_id, reason, expires_at)
+	SELECT access_jti, user_id, $2, access_expires_at FROM sessions
+	WHERE id = $1 AND access_jti IS NOT NULL AND access_expires_at > now()
+	ON CONFLICT (jti) DO NOTHING
+`
+
+// Create в одной транзакции создает сессию s с первым access-токеном s.AccessJTI и сохраняет хеш первого
+// refresh-токена, заполняя ID и время создания
+func (r *SessionRepository) Create(ctx context.Context, s *session.Session, tokenHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO sessions (user_id, user_agent, ip_address, last_ip, expires_at, access_jti, access_expires_at, mfa_verified_at)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, last_used_at
+	`, s.UserID, s.UserAgent, s.IPAddress, s.ExpiresAt, s.AccessJTI, s.AccessExpiresAt, s.MFAVerifiedAt).Scan(&s.ID, &s.CreatedAt, &s.LastUsedAt)
+	if err != nil {
+		return err
+	}
+	s.LastIP = s.IPAddress
+
+	if _, err = tx.Exec(ctx, `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		s.ID, tokenHash, s.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Rotate в одной транзакции погашает refresh-токен с хешем oldHash и выпускает вместо него токен next.
+// Предыдущий access-токен сессии отзывается: в сессии действует только последний выданный access-токен,
+// поэтому завершение сессии отзывает все ее токены. Если refresh-токен уже был погашен, завершает всю сессию
+// с причиной REUSE, отзывает ее последний access-токен и возвращает ErrRefreshTokenReused. Возвращает pgx.ErrNoRows, если токен не найден, истек
+// или сессия уже завершена
+func (r *SessionRepository) Rotate(ctx context.Context, oldHash string, next *session.Rotation) (*session.Session, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		tokenID        int64
+		sessionID      int64
+		tokenExpiresAt time.Time
+		usedAt         *time.Time
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, session_id, expires_at, used_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE
+	`, oldHash).Scan(&tokenID, &sessionID, &tokenExpiresAt, &usedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := scanSession(tx.QueryRow(ctx, `SELECT `+sessionColumns+` FROM sessions WHERE id = $1 FOR UPDATE`, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if s.RevokedAt != nil {
+		return nil, pgx.ErrNoRows
+	}
+
+	// Повторное предъявление погашенного токена означает его утечку: завершаем всю цепочку
+	if usedAt != nil {
+		if _, err = tx.Exec(ctx, `UPDATE sessions SET revoked_at = $1, revoke_reason = $2 WHERE id = $3`,
+			now, session.REUSE, s.ID); err != nil {
+			return nil, err
+		}
+		if _, err = tx.Exec(ctx, revokeAccessQuery, s.ID, session.REUSE); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if !now.Before(tokenExpiresAt) {
+		return nil, pgx.ErrNoRows
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2`, now, tokenID); err != nil {
+		return nil, err
+	}
+	// Отзыв выполняется до замены access_jti, пока запрос еще видит предыдущий токен
+	if _, err = tx.Exec(ctx, revokeAccessQuery, s.ID, session.ROTATED); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		s.ID, next.TokenHash, next.ExpiresAt); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE sessions SET last_used_at = $1, last_ip = $2, expires_at = $3, access_jti = $4, access_expires_at = $5
+		WHERE id = $6
+	`, now, next.IP, next.ExpiresAt, next.AccessJTI, next.AccessExpiresAt, s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.LastUsedAt, s.LastIP, s.ExpiresAt = now, next.IP, next.ExpiresAt
+	s.AccessJTI, s.AccessExpiresAt = &next.AccessJTI, &next.AccessExpiresAt
+	return s, nil
+}
+
+// GetActiveByUserID получает незавершенные и не истекшие сессии пользователя, начиная с последних использованных
+func (r *SessionRepository) GetActiveByUserID(ctx context.Context, userID int64) ([]*session.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*session.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetByID получает действующую сессию id пользователя userID. Возвращает pgx.ErrNoRows, если сессия
+// не найдена, принадлежит другому пользователю или уже завершена
+func (r *SessionRepository) GetByID(ctx context.Context, id, userID int64) (*session.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	return scanSession(r.db.QueryRow(ctx, query, id, userID))
+}
+
+// MarkMFAVerified запоминает время at подтверждения кодом двухфакторной аутентификации в сессии id
+// пользователя userID. Возвращает pgx.ErrNoRows, если сессия не найдена или уже завершена
+func (r *SessionRepository) MarkMFAVerified(ctx context.Context, id, userID int64, at time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE sessions SET mfa_verified_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`, at, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Revoke в одной транзакции завершает сессию id пользователя userID с причиной reason и отзывает ее последний
+// access-токен. Возвращает pgx.ErrNoRows, если сессия не найдена, принадлежит другому пользователю
+// или уже завершена
+func (r *SessionRepository) Revoke(ctx context.Context, id, userID int64, reason session.RevokeReason) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE sessions SET revoked_at = now(), revoke_reason = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`, reason, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if _, err = tx.Exec(ctx, revokeAccessQuery, id, reason); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeAll в одной транзакции завершает все действующие сессии пользователя userID с причиной reason
+// и отзывает выданные в них access-токены. Возвращает количество завершенных сессий
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID int64, reason session.RevokeReason) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Токены отзываются до завершения сессий, пока сессии еще отбираются условием revoked_at IS NULL
+	_, err = tx.Exec(ctx, `
+		INSERT INTO revoked_tokens (jti, user_id, reason, expires_at)
+		SELECT access_jti, user_id, $2, access_expires_at FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND access_jti IS NOT NULL AND access_expires_at > now()
+		ON CONFLICT (jti) DO NOTHING
+	`, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE sessions SET revoked_at = now(), revoke_reason = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`, reason, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanSession сканирует строку со столбцами sessionColumns
+func scanSession(row pgx.Row) (*session.Session, error) {
+	var s session.Session
+	if err := row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IPAddress, &s.LastIP, &s.CreatedAt, &s.LastUsedAt,
+		&s.ExpiresAt, &s.RevokedAt, &s.RevokeReason, &s.AccessJTI, &s.AccessExpiresAt, &s.MFAVerifiedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/standingorder"
+)
+
+// standingOrderColumns перечисляет колонки поручения в порядке сканирования
+const standingOrderColumns = `id, user_id, from_account_id, to_account_id, amount, frequency, day_of_month, start_date,
+	end_date, max_occurrences, occurrences, next_run_date, consecutive_failures, status, description, created_at`
+
+// StandingOrderRepository реализует работу с таблицами платежных поручений в базе данных
+type StandingOrderRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewStandingOrderRepository создает новый экземпляр репозитория для работы с платежными поручениями
+func NewStandingOrderRepository(db *pgxpool.Pool) *StandingOrderRepository {
+	return &StandingOrderRepository{db: db}
+}
+
+// Create создает новое платежное поручение
+func (r *StandingOrderRepository) Create(ctx context.Context, o *standingorder.StandingOrder) (*standingorder.StandingOrder, error) {
+	query := `
+		INSERT INTO standing_orders (user_id, from_account_id, to_account_id, amount, frequency, day_of_month,
+			start_date, end_date, max_occurrences, next_run_date, status, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + standingOrderColumns
+	row := r.db.QueryRow(ctx, query, o.UserID, o.FromAccountID, o.ToAccountID, o.Amount, o.Frequency, o.DayOfMonth,
+		o.StartDate, o.EndDate, o.MaxOccurrences, o.NextRunDate, o.Status, o.Description)
+	return scanStandingOrder(row)
+}
+
+// GetByID получает платежное поручение по ID
+func (r *StandingOrderRepository) GetByID(ctx context.Context, id int64) (*standingorder.StandingOrder, error) {
+	query := `SELECT ` + standingOrderColumns + ` FROM standing_orders WHERE id = $1`
+	return scanStandingOrder(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByUserID получает все платежные поручения пользователя
+func (r *StandingOrderRepository) GetByUserID(ctx context.Context, userID int64) ([]*standingorder.StandingOrder, error) {
+	query := `SELECT ` + standingOrderColumns + ` FROM standing_orders WHERE user_id = $1 ORDER BY id`
+	return r.queryStandingOrders(ctx, query, userID)
+}
+
+// GetDue получает активные поручения, дата исполнения которых наступила не позже date
+func (r *StandingOrderRepository) GetDue(ctx context.Context, date time.Time) ([]*standingorder.StandingOrder, error) {
+	query := `SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE status = $1 AND next_run_date <= $2
+		ORDER BY next_run_date, id`
+	return r.queryStandingOrders(ctx, query, standingorder.ACTIVE, date)
+}
+
+// UpdateProgress сохраняет состояние исполнения поручения
+func (r *StandingOrderRepository) UpdateProgress(ctx context.Context, o *standingorder.StandingOrder) error {
+	query := `
+		UPDATE standing_orders
+		SET occurrences = $1, next_run_date = $2, consecutive_failures = $3, status = $4
+		WHERE id = $5
+	`
+	_, err := r.db.Exec(ctx, query, o.Occurrences, o.NextRunDate, o.ConsecutiveFailures, o.Status, o.ID)
+	return err
+}
+
+// UpdateStatus изменяет статус поручения
+func (r *StandingOrderRepository) UpdateStatus(ctx context.Context, id int64, status standingorder.Status) error {
+	query := `
+		UPDATE standing_orders
+		SET status = $1
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(ctx, query, status, id)
+	return err
+}
+
+// executionColumns перечисляет колонки исполнения поручения в порядке сканирования
+const executionColumns = `id, order_id, scheduled_date, amount, status, error, attempts, started_at, created_at`
+
+// ClaimExecution захватывает дату исполнения поручения перед переводом денег: создает запись в статусе
+// PROCESSING или, если предыдущая попытка за эту дату ожидает повтора, переводит ее в PROCESSING
+// с увеличением счетчика попыток. Возвращает nil, если дата уже захвачена другим обработчиком или завершена
+func (r *StandingOrderRepository) ClaimExecution(ctx context.Context, orderID int64, date time.Time, amount decimal.Decimal) (*standingorder.Execution, error) {
+	query := `
+		INSERT INTO standing_order_executions (order_id, scheduled_date, amount, status, started_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (order_id, scheduled_date) DO UPDATE
+		SET status = EXCLUDED.status, started_at = EXCLUDED.started_at,
+			attempts = standing_order_executions.attempts + 1
+		WHERE standing_order_executions.status = $5
+		RETURNING ` + executionColumns
+	e, err := scanExecution(r.db.QueryRow(ctx, query, orderID, date, amount,
+		standingorder.EXECUTION_PROCESSING, standingorder.EXECUTION_RETRY))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// GetExecution получает исполнение поручения за дату по расписанию
+func (r *StandingOrderRepository) GetExecution(ctx context.Context, orderID int64, date time.Time) (*standingorder.Execution, error) {
+	query := `SELECT ` + executionColumns + ` FROM standing_order_executions WHERE order_id = $1 AND scheduled_date = $2`
+	return scanExecution(r.db.QueryRow(ctx, query, orderID, date))
+}
+
+// CompleteExecution записывает результат захваченного исполнения и, если o не nil, в той же транзакции
+// сохраняет состояние поручения, перешедшего с даты prevRunDate. Результат записывается, только если
+// исполнение все еще захвачено этой попыткой (совпадает started_at). Возвращает false, если результат
+// не записан или поручение успело измениться: отменено пользователем или обработано другим обработчиком
+func (r *StandingOrderRepository) CompleteExecution(ctx context.Context, e *standingorder.Execution,
+	o *standingorder.StandingOrder, prevRunDate time.Time) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE standing_order_executions
+		SET status = $1, error = $2, attempts = $3
+		WHERE id = $4 AND status = $5 AND started_at = $6
+	`
+	tag, err := tx.Exec(ctx, query, e.Status, e.Error, e.Attempts, e.ID, standingorder.EXECUTION_PROCESSING, e.StartedAt)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() != 1 {
+		return false, nil
+	}
+
+	saved := true
+	if o != nil {
+		query = `
+			UPDATE standing_orders
+			SET occurrences = $1, next_run_date = $2, consecutive_failures = $3, status = $4
+			WHERE id = $5 AND next_run_date = $6 AND status = $7
+		`
+		tag, err = tx.Exec(ctx, query, o.Occurrences, o.NextRunDate, o.ConsecutiveFailures, o.Status,
+			o.ID, prevRunDate, standingorder.ACTIVE)
+		if err != nil {
+			return false, err
+		}
+		saved = tag.RowsAffected() == 1
+	}
+
+	// Результат исполнения фиксируется и тогда, когда поручение изменилось: перевод уже выполнен
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+// GetExecutionsByOrderID получает историю исполнения поручения, начиная с последних дат
+func (r *StandingOrderRepository) GetExecutionsByOrderID(ctx context.Context, orderID int64) ([]*standingorder.Execution, error) {
+	query := `
+		SELECT ` + executionColumns + `
+		FROM standing_order_executions
+		WHERE order_id = $1
+		ORDER BY scheduled_date DESC, id DESC
+	`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var executions []*standingorder.Execution
+	for rows.Next() {
+		e, err := scanExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+// queryStandingOrders выполняет запрос и сканирует список поручений
+func (r *StandingOrderRepository) queryStandingOrders(ctx context.Context, query string, args ...any) ([]*standingorder.StandingOrder, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*standingorder.StandingOrder
+	for rows.Next() {
+		o, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// scanStandingOrder сканирует строку с колонками standingOrderColumns
+func scanStandingOrder(row pgx.Row) (*standingorder.StandingOrder, error) {
+	var o standingorder.StandingOrder
+	err := row.Scan(&o.ID, &o.UserID, &o.FromAccountID, &o.ToAccountID, &o.Amount, &o.Frequency, &o.DayOfMonth,
+		&o.StartDate, &o.EndDate, &o.MaxOccurrences, &o.Occurrences, &o.NextRunDate, &o.ConsecutiveFailures,
+		&o.Status, &o.Description, &o.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// scanExecution сканирует строку с колонками executionColumns
+func scanExecution(row pgx.Row) (*standingorder.Execution, error) {
+	var e standingorder.Execution
+	err := row.Scan(&e.ID, &e.OrderID, &e.ScheduledDate, &e.Amount, &e.Status, &e.Error, &e.Attempts,
+		&e.StartedAt, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 	"github.com/yujihn/bank_API/internal/models/transaction"
@@ -18,28 +20,24 @@ func NewTransactionRepository(db *pgxpool.Pool) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-// CreateTransaction создает новую запись о транзакции
+// transactionColumns перечисляет колонки транзакции в порядке, который ожидает scanTransaction
+const transactionColumns = `id, account_id, amount, type, kind, counterparty_account_id, status, created_at`
+
+// CreateTransaction создает новую запись о транзакции вида kind; counterpartyID — счет-контрагент перевода
+// или nil, если контрагента в банке нет
 func (r *TransactionRepository) CreateTransaction(ctx context.Context, accountID int64, amount decimal.Decimal,
-	txType transaction.Type, status transaction.Status) (*transaction.Transaction, error) {
+	txType transaction.Type, kind transaction.Kind, counterpartyID *int64, status transaction.Status) (*transaction.Transaction, error) {
 	query := `
-		INSERT INTO transactions (account_id, amount, type, status)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, account_id, amount, type, status, created_at
-	`
-	var tx transaction.Transaction
-	err := r.db.QueryRow(ctx, query, accountID, amount, txType, status).Scan(
-		&tx.ID, &tx.AccountID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return &tx, nil
+		INSERT INTO transactions (account_id, amount, type, kind, counterparty_account_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + transactionColumns
+	return scanTransaction(r.db.QueryRow(ctx, query, accountID, amount, txType, kind, counterpartyID, status))
 }
 
 // GetTransactionsByAccountID получает все транзакции для указанного счета
 func (r *TransactionRepository) GetTransactionsByAccountID(ctx context.Context, accountID int64) ([]*transaction.Transaction, error) {
 	query := `
-		SELECT id, account_id, amount, type, status, created_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE account_id = $1
 		ORDER BY created_at DESC
@@ -52,11 +50,11 @@ func (r *TransactionRepository) GetTransactionsByAccountID(ctx context.Context,
 
 	var transactions []*transaction.Transaction
 	for rows.Next() {
-		var tx transaction.Transaction
-		if err := rows.Scan(&tx.ID, &tx.AccountID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt); err != nil {
+		tx, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		transactions = append(transactions, &tx)
+		transactions = append(transactions, tx)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -68,7 +66,7 @@ func (r *TransactionRepository) GetTransactionsByAccountID(ctx context.Context,
 // GetTransactionsByUserID получает все транзакции для всех счетов пользователя
 func (r *TransactionRepository) GetTransactionsByUserID(ctx context.Context, userID int64) ([]*transaction.Transaction, error) {
 	query := `
-		SELECT t.id, t.account_id, t.amount, t.type, t.status, t.created_at
+		SELECT t.id, t.account_id, t.amount, t.type, t.kind, t.counterparty_account_id, t.status, t.created_at
 		FROM transactions t
 		JOIN accounts a ON t.account_id = a.id
 		WHERE a.user_id = $1
@@ -82,11 +80,11 @@ func (r *TransactionRepository) GetTransactionsByUserID(ctx context.Context, use
 
 	var transactions []*transaction.Transaction
 	for rows.Next() {
-		var tx transaction.Transaction
-		if err := rows.Scan(&tx.ID, &tx.AccountID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt); err != nil {
+		tx, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		transactions = append(transactions, &tx)
+		transactions = append(transactions, tx)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -94,3 +92,124 @@ func (r *TransactionRepository) GetTransactionsByUserID(ctx context.Context, use
 	}
 	return transactions, nil
 }
+
+// GetIncome получает сумму поступлений на счета пользователя начиная с момента since. Поступлениями считаются
+// внешние зачисления и переводы с чужих счетов; выдача кредитов, возврат вкладов и переводы между собственными
+// счетами не учитываются
+func (r *TransactionRepository) GetIncome(ctx context.Context, userID int64, since time.Time) (decimal.Decimal, error) {
+	return sumExternal(ctx, r.db, userID, transaction.DEPOSIT, since)
+}
+
+// GetOutgoing получает сумму расходных операций по счетам пользователя начиная с момента since. Расходными
+// считаются внешние списания и переводы на чужие счета; платежи по кредитам, открытие вкладов и переводы
+// между собственными счетами не учитываются
+func (r *TransactionRepository) GetOutgoing(ctx context.Context, userID int64, since time.Time) (decimal.Decimal, error) {
+	return sumExternal(ctx, r.db, userID, transaction.WITHDRAWAL, since)
+}
+
+// rowQuerier выполняет запрос, возвращающий одну строку: пул соединений или открытая транзакция
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// sumExternal суммирует завершенные транзакции типа txType по счетам пользователя начиная с момента since,
+// если это внешние операции или переводы, счет-контрагент которых принадлежит другому пользователю.
+// Транзакции вида UNKNOWN не учитываются. Запрос выполняется через q, чтобы сумму можно было получить
+// и внутри транзакции списания
+func sumExternal(ctx context.Context, q rowQuerier, userID int64, txType transaction.Type,
+	since time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(t.amount), 0)
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		LEFT JOIN accounts c ON c.id = t.counterparty_account_id
+		WHERE a.user_id = $1 AND t.type = $2 AND t.status = $3 AND t.created_at >= $4
+		  AND (t.kind = $5 OR (t.kind = $6 AND c.user_id IS DISTINCT FROM $1))
+	`
+	var sum decimal.Decimal
+	err := q.QueryRow(ctx, query, userID, txType, transaction.COMPLETED, since,
+		transaction.KIND_EXTERNAL, transaction.KIND_TRANSFER).Scan(&sum)
+	return sum, err
+}
+
+// insertTransaction записывает завершенную транзакцию в рамках транзакции базы данных tx
+func insertTransaction(ctx context.Context, tx pgx.Tx, accountID int64, amount decimal.Decimal, txType transaction.Type,
+	kind transaction.Kind, counterpartyID *int64) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO transactions (account_id, amount, type, kind, counterparty_account_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, accountID, amount, txType, kind, counterpartyID, transaction.COMPLETED)
+	return err
+}
+
+// StreamTransactionsSince последовательно передает в fn завершенные транзакции счета,
+// созданные начиная с момента since, в хронологическом порядке
+func (r *TransactionRepository) StreamTransactionsSince(ctx context.Context, accountID int64, since time.Time,
+	fn func(tx *transaction.Transaction) error) error {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE account_id = $1 AND status = $2 AND created_at >= $3
+		ORDER BY created_at, id
+	`
+	return r.streamTransactions(ctx, query, fn, accountID, transaction.COMPLETED, since)
+}
+
+// HasLegacySince проверяет, есть ли у счета операции, записанные до исправления типов переводов
+// и созданные начиная с момента since
+func (r *TransactionRepository) HasLegacySince(ctx context.Context, accountID int64, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM transactions
+			WHERE account_id = $1 AND legacy AND created_at >= $2
+		)
+	`
+	var exists bool
+	err := r.db.QueryRow(ctx, query, accountID, since).Scan(&exists)
+	return exists, err
+}
+
+// StreamTransactionsByPeriod последовательно передает в fn завершенные транзакции счета за период [from, to)
+// в хронологическом порядке, не загружая весь результат в память
+func (r *TransactionRepository) StreamTransactionsByPeriod(ctx context.Context, accountID int64, from, to time.Time,
+	fn func(tx *transaction.Transaction) error) error {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE account_id = $1 AND status = $2 AND created_at >= $3 AND created_at < $4
+		ORDER BY created_at, id
+	`
+	return r.streamTransactions(ctx, query, fn, accountID, transaction.COMPLETED, from, to)
+}
+
+// streamTransactions выполняет запрос и построчно передает транзакции в fn
+func (r *TransactionRepository) streamTransactions(ctx context.Context, query string,
+	fn func(tx *transaction.Transaction) error, args ...any) error {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanTransaction считывает транзакцию из строки результата с колонками transactionColumns
+func scanTransaction(row pgx.Row) (*transaction.Transaction, error) {
+	var tx transaction.Transaction
+	err := row.Scan(&tx.ID, &tx.AccountID, &tx.Amount, &tx.Type, &tx.Kind, &tx.CounterpartyAccountID, &tx.Status, &tx.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
@@ -5,18 +5,40 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/apperr"
 	"github.com/yujihn/bank_API/internal/models"
 )
 
-// ErrUserNotFound возвращается, когда пользователь не найден в базе данных
-var ErrUserNotFound = errors.New("пользователь не найден")
+var (
+	ErrUserNotFound  = apperr.New(apperr.NotFound, apperr.CodeUserNotFound, "пользователь не найден")       // Пользователь не найден в базе данных
+	ErrEmailTaken    = apperr.New(apperr.Conflict, apperr.CodeUserExists, "email уже занят")                // Email уже зарегистрирован другим пользователем
+	ErrUsernameTaken = apperr.New(apperr.Conflict, apperr.CodeUsernameTaken, "имя пользователя уже занято") // Username уже занят другим пользователем
+)
+
+// Ограничения уникальности таблицы users
+const (
+	usersEmailKey    = "users_email_key"
+	usersUsernameKey = "users_username_key"
+)
+
+// userColumns — столбцы таблицы users в порядке сканирования scanUser
+const userColumns = `id, email, username, password_hash, full_name, date_of_birth, phone, address, default_account_id, role,
+	email_verified_at, kyc_status, kyc_updated_at, created_at`
 
 // UserRepository интерфейс для работы с данными пользователей
 type UserRepository interface {
-	Create(ctx context.Context, user *models.User) (int64, error)       // Создает нового пользователя
-	GetByEmail(ctx context.Context, email string) (*models.User, error) // Находит пользователя по email
-	GetByID(ctx context.Context, id int64) (*models.User, error)        // Находит пользователя по ID
+	Create(ctx context.Context, user *models.User) (int64, error)                  // Создает нового пользователя
+	GetByEmail(ctx context.Context, email string) (*models.User, error)            // Находит пользователя по email
+	GetByID(ctx context.Context, id int64) (*models.User, error)                   // Находит пользователя по ID
+	SetDefaultAccount(ctx context.Context, userID, accountID int64) error          // Назначает счет по умолчанию
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string) error   // Заменяет хеш пароля
+	MarkEmailVerified(ctx context.Context, userID int64) error                     // Отмечает email подтвержденным
+	UpdateProfile(ctx context.Context, userID int64, profile models.Profile) error // Заменяет данные профиля
+	// UpdateKYCStatus меняет статус проверки личности, если текущий статус входит в from
+	UpdateKYCStatus(ctx context.Context, userID int64, from []models.KYCStatus, to models.KYCStatus) (bool, error)
+	GetByKYCStatus(ctx context.Context, status models.KYCStatus) ([]*models.User, error) // Пользователи с данным статусом проверки
 }
 
 // UserRepositoryPgx реализует интерфейс UserRepository с помощью pgx
@@ -34,27 +56,25 @@ func (r *UserRepositoryPgx) Create(ctx context.Context, user *models.User) (int6
 	var id int64
 
 	err := r.pool.QueryRow(ctx,
-		`INSERT INTO users (email, password_hash) 
-         VALUES ($1, $2) 
+		`INSERT INTO users (email, username, password_hash, full_name) 
+         VALUES ($1, $2, $3, $4) 
          RETURNING id`,
-		user.Email, user.Password).Scan(&id)
+		user.Email, user.Username, user.Password, user.FullName).Scan(&id)
 
 	if err != nil {
-		return 0, err
+		return 0, uniqueViolation(err)
 	}
 
 	return id, nil
 }
 
-// GetByEmail ищет пользователя по email
+// GetByEmail ищет пользователя по email без учета регистра
 func (r *UserRepositoryPgx) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	user := &models.User{}
-
-	err := r.pool.QueryRow(ctx,
-		`SELECT id, email, password_hash, created_at 
+	user, err := scanUser(r.pool.QueryRow(ctx,
+		`SELECT `+userColumns+` 
          FROM users 
-         WHERE email = $1`,
-		email).Scan(&user.ID, &user.Email, &user.Password, &user.CreatedAt)
+         WHERE lower(email) = lower($1)`,
+		email))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -68,13 +88,11 @@ func (r *UserRepositoryPgx) GetByEmail(ctx context.Context, email string) (*mode
 
 // GetByID ищет пользователя по ID
 func (r *UserRepositoryPgx) GetByID(ctx context.Context, id int64) (*models.User, error) {
-	user := &models.User{}
-
-	err := r.pool.QueryRow(ctx,
-		`SELECT id, email, password_hash, created_at 
+	user, err := scanUser(r.pool.QueryRow(ctx,
+		`SELECT `+userColumns+` 
          FROM users 
          WHERE id = $1`,
-		id).Scan(&user.ID, &user.Email, &user.Password, &user.CreatedAt)
+		id))
 
 	if err != nil {
 		return nil, err
@@ -82,3 +100,150 @@ func (r *UserRepositoryPgx) GetByID(ctx context.Context, id int64) (*models.User
 
 	return user, nil
 }
+
+// SetDefaultAccount назначает пользователю счет для зачисления переводов по email
+func (r *UserRepositoryPgx) SetDefaultAccount(ctx context.Context, userID, accountID int64) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users 
+         SET default_account_id = $2 
+         WHERE id = $1`,
+		userID, accountID)
+
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword заменяет хеш пароля пользователя
+func (r *UserRepositoryPgx) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users 
+         SET password_hash = $2 
+         WHERE id = $1`,
+		userID, passwordHash)
+
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified отмечает email пользователя подтвержденным; время первого подтверждения не меняется
+func (r *UserRepositoryPgx) MarkEmailVerified(ctx context.Context, userID int64) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users 
+         SET email_verified_at = COALESCE(email_verified_at, now()) 
+         WHERE id = $1`,
+		userID)
+
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateProfile заменяет данные профиля пользователя
+func (r *UserRepositoryPgx) UpdateProfile(ctx context.Context, userID int64, profile models.Profile) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users 
+         SET username = $2, full_name = $3, date_of_birth = $4, phone = $5, address = $6 
+         WHERE id = $1`,
+		userID, profile.Username, profile.FullName, profile.DateOfBirth, profile.Phone, profile.Address)
+
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateKYCStatus меняет статус проверки личности на to, если текущий статус входит в from.
+// Возвращает false, если пользователь не найден или его статус не входит в from
+func (r *UserRepositoryPgx) UpdateKYCStatus(ctx context.Context, userID int64, from []models.KYCStatus,
+	to models.KYCStatus) (bool, error) {
+	statuses := make([]string, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE users 
+         SET kyc_status = $3, kyc_updated_at = now() 
+         WHERE id = $1 AND kyc_status = ANY($2)`,
+		userID, statuses, to)
+
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetByKYCStatus возвращает пользователей с данным статусом проверки личности, начиная с самых давних заявок
+func (r *UserRepositoryPgx) GetByKYCStatus(ctx context.Context, status models.KYCStatus) ([]*models.User, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+userColumns+` 
+         FROM users 
+         WHERE kyc_status = $1 
+         ORDER BY kyc_updated_at, id`,
+		status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// scanUser сканирует строку со столбцами userColumns
+func scanUser(row pgx.Row) (*models.User, error) {
+	var u models.User
+	err := row.Scan(&u.ID, &u.Email, &u.Username, &u.Password, &u.FullName, &u.DateOfBirth, &u.Phone, &u.Address,
+		&u.DefaultAccountID, &u.Role, &u.EmailVerifiedAt, &u.KYCStatus, &u.KYCUpdatedAt, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// uniqueViolation заменяет нарушение уникальности email или username на ErrEmailTaken или ErrUsernameTaken
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case usersEmailKey:
+		return ErrEmailTaken
+	case usersUsernameKey:
+		return ErrUsernameTaken
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yujihn/bank_API/internal/models/usertoken"
+)
+
+// UserTokenRepository реализует хранение одноразовых токенов подтверждения email и сброса пароля.
+// Хранятся только хеши токенов
+type UserTokenRepository struct {
+	db *pgxpool.Pool // Пул соединений с базой данных
+}
+
+// NewUserTokenRepository создает новый экземпляр репозитория одноразовых токенов
+func NewUserTokenRepository(db *pgxpool.Pool) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Create в одной транзакции сохраняет токен t с хешем tokenHash, заполняя ID и время выпуска, и удаляет
+// неиспользованные токены пользователя с тем же назначением: действует только последний выпущенный
+func (r *UserTokenRepository) Create(ctx context.Context, t *usertoken.Token, tokenHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		t.UserID, t.Purpose); err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, t.UserID, t.Purpose, tokenHash, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Consume погашает токен с хешем tokenHash и назначением purpose и возвращает его. Возвращает pgx.ErrNoRows,
+// если токен не найден, истек или уже использован
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose usertoken.Purpose, tokenHash string) (*usertoken.Token, error) {
+	var t usertoken.Token
+	err := r.db.QueryRow(ctx, `
+		UPDATE user_tokens SET used_at = now()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING id, user_id, purpose, expires_at, used_at, created_at
+	`, tokenHash, purpose).Scan(&t.ID, &t.UserID, &t.Purpose, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteExpired удаляет токены, срок действия которых истек к моменту now, и использованные токены
+func (r *UserTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_tokens WHERE expires_at <= $1 OR used_at IS NOT NULL`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,69 @@
+// Package scheduler запускает периодические фоновые задачи на основе time.Ticker.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job описывает периодическую задачу
+type Job struct {
+	Name     string                          // Название задачи для логов
+	Interval time.Duration                   // Период запуска
+	Run      func(ctx context.Context) error // Выполнение одного прохода задачи
+}
+
+// Scheduler управляет набором периодических задач
+type Scheduler struct {
+	jobs   []Job          // Зарегистрированные задачи
+	logger *logrus.Logger // Логгер для результатов выполнения
+}
+
+// New создает новый планировщик
+func New(logger *logrus.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Add регистрирует задачу; задачи нужно добавлять до вызова Start
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start запускает каждую задачу в отдельной горутине: сразу при старте и далее с заданным периодом.
+// Проходы одной задачи не пересекаются; задачи останавливаются при отмене ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// loop выполняет задачу по таймеру до отмены контекста
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.run(ctx, job)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// run выполняет один проход задачи и логирует результат
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	started := time.Now()
+	if err := job.Run(ctx); err != nil {
+		s.logger.WithError(err).WithField("job", job.Name).Error("Ошибка выполнения фоновой задачи")
+		return
+	}
+	s.logger.WithFields(logrus.Fields{
+		"job":      job.Name,
+		"duration": time.Since(started).String(),
+	}).Debug("Фоновая задача выполнена")
+}
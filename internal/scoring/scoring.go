@@ -0,0 +1,85 @@
+// Package scoring реализует автоматическую оценку кредитных заявок по доходу, долговой нагрузке
+// и платежной дисциплине заявителя
+package scoring
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/application"
+)
+
+// Policy задает пороги принятия решения
+type Policy struct {
+	MaxDebtToIncome    decimal.Decimal // Долговая нагрузка, выше которой заявка отклоняется
+	ReviewDebtToIncome decimal.Decimal // Долговая нагрузка, выше которой заявка направляется на ручную проверку
+	IncomeTolerance    decimal.Decimal // Минимальная доля заявленного дохода, подтвержденная поступлениями
+	MaxLatePayments    int             // Допустимое число платежей, внесенных с опозданием
+}
+
+// Input содержит данные заявки и кредитной истории заявителя
+type Input struct {
+	DeclaredIncome decimal.Decimal // Заявленный ежемесячный доход
+	ObservedIncome decimal.Decimal // Средние ежемесячные поступления на счета за период оценки
+	DebtPayments   decimal.Decimal // Ежемесячные платежи по действующим кредитам
+	Payment        decimal.Decimal // Ежемесячный платеж по запрошенному кредиту
+	ArrearsCount   int             // Непогашенные просроченные платежи и кредиты в статусе OVERDUE
+	LatePayments   int             // Платежи, внесенные позже даты по графику
+}
+
+// Result содержит решение по заявке
+type Result struct {
+	Status       application.Status   // APPROVED, REJECTED или MANUAL_REVIEW
+	Reasons      []application.Reason // Коды причин; пусто для одобренной заявки
+	Income       decimal.Decimal      // Доход, принятый для расчета нагрузки
+	DebtToIncome decimal.Decimal      // Долговая нагрузка с учетом нового кредита
+}
+
+// Evaluate оценивает заявку. Для расчета долговой нагрузки (все ежемесячные платежи к доходу) принимается
+// меньшее из заявленного и подтвержденного поступлениями значений дохода, а при отсутствии поступлений — заявленный.
+// Причины отказа имеют приоритет над причинами ручной проверки
+func Evaluate(in Input, policy Policy) Result {
+	var reject, review []application.Reason
+
+	if in.ArrearsCount > 0 {
+		reject = append(reject, application.CURRENT_ARREARS)
+	}
+
+	income := in.DeclaredIncome
+	switch {
+	case !in.ObservedIncome.IsPositive():
+		review = append(review, application.NO_INCOME_HISTORY)
+	case in.ObservedIncome.LessThan(in.DeclaredIncome):
+		income = in.ObservedIncome
+		if in.ObservedIncome.LessThan(in.DeclaredIncome.Mul(policy.IncomeTolerance)) {
+			review = append(review, application.INCOME_NOT_CONFIRMED)
+		}
+	}
+
+	dti := decimal.NewFromInt(1)
+	if income.IsPositive() {
+		dti = in.DebtPayments.Add(in.Payment).Div(income).Round(4)
+	}
+	switch {
+	case dti.GreaterThan(policy.MaxDebtToIncome):
+		reject = append(reject, application.DEBT_LOAD_TOO_HIGH)
+	case dti.GreaterThan(policy.ReviewDebtToIncome):
+		review = append(review, application.DEBT_LOAD_ELEVATED)
+	}
+
+	if in.LatePayments > policy.MaxLatePayments {
+		review = append(review, application.LATE_PAYMENT_HISTORY)
+	}
+
+	result := Result{Income: income, DebtToIncome: dti}
+	switch {
+	case len(reject) > 0:
+		result.Status = application.REJECTED
+		result.Reasons = append(reject, review...)
+	case len(review) > 0:
+		result.Status = application.MANUAL_REVIEW
+		result.Reasons = review
+	default:
+		result.Status = application.APPROVED
+		result.Reasons = []application.Reason{}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package scoring
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/application"
+)
+
+// testPolicy повторяет пороги по умолчанию из конфигурации
+var testPolicy = Policy{
+	MaxDebtToIncome:    decimal.RequireFromString("0.5"),
+	ReviewDebtToIncome: decimal.RequireFromString("0.4"),
+	IncomeTolerance:    decimal.RequireFromString("0.8"),
+	MaxLatePayments:    2,
+}
+
+// TestEvaluate проверяет решения по заявкам, причины и принятый для расчета доход
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		observed string
+		debt     string
+		payment  string
+		arrears  int
+		late     int
+		status   application.Status
+		reasons  []application.Reason
+		income   string
+		dti      string
+	}{
+		{"одобрение", "100000", "100000", "10000", "20000", 0, 0,
+			application.APPROVED, nil, "100000", "0.3"},
+		{"поступления больше заявленного", "100000", "150000", "0", "30000", 0, 0,
+			application.APPROVED, nil, "100000", "0.3"},
+		{"поступления в пределах допуска", "100000", "90000", "0", "27000", 0, 0,
+			application.APPROVED, nil, "90000", "0.3"},
+		{"доход не подтвержден", "100000", "50000", "0", "10000", 0, 0,
+			application.MANUAL_REVIEW, []application.Reason{application.INCOME_NOT_CONFIRMED}, "50000", "0.2"},
+		{"нет поступлений", "100000", "0", "0", "20000", 0, 0,
+			application.MANUAL_REVIEW, []application.Reason{application.NO_INCOME_HISTORY}, "100000", "0.2"},
+		{"нагрузка на границе ручной проверки", "100000", "100000", "15000", "25000", 0, 0,
+			application.APPROVED, nil, "100000", "0.4"},
+		{"повышенная нагрузка", "100000", "100000", "20000", "25000", 0, 0,
+			application.MANUAL_REVIEW, []application.Reason{application.DEBT_LOAD_ELEVATED}, "100000", "0.45"},
+		{"нагрузка на границе отказа", "100000", "100000", "20000", "30000", 0, 0,
+			application.MANUAL_REVIEW, []application.Reason{application.DEBT_LOAD_ELEVATED}, "100000", "0.5"},
+		{"слишком высокая нагрузка", "100000", "100000", "30000", "30000", 0, 0,
+			application.REJECTED, []application.Reason{application.DEBT_LOAD_TOO_HIGH}, "100000", "0.6"},
+		{"просрочки и повышенная нагрузка", "100000", "100000", "20000", "25000", 1, 0,
+			application.REJECTED, []application.Reason{application.CURRENT_ARREARS, application.DEBT_LOAD_ELEVATED}, "100000", "0.45"},
+		{"допустимые опоздания", "100000", "100000", "0", "10000", 0, 2,
+			application.APPROVED, nil, "100000", "0.1"},
+		{"частые опоздания", "100000", "100000", "0", "10000", 0, 3,
+			application.MANUAL_REVIEW, []application.Reason{application.LATE_PAYMENT_HISTORY}, "100000", "0.1"},
+		{"нет дохода", "0", "0", "0", "10000", 0, 0,
+			application.REJECTED, []application.Reason{application.DEBT_LOAD_TOO_HIGH, application.NO_INCOME_HISTORY}, "0", "1"},
+		{"округление нагрузки", "90000", "90000", "0", "30000", 0, 0,
+			application.APPROVED, nil, "90000", "0.3333"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(Input{
+				DeclaredIncome: decimal.RequireFromString(tt.declared),
+				ObservedIncome: decimal.RequireFromString(tt.observed),
+				DebtPayments:   decimal.RequireFromString(tt.debt),
+				Payment:        decimal.RequireFromString(tt.payment),
+				ArrearsCount:   tt.arrears,
+				LatePayments:   tt.late,
+			}, testPolicy)
+
+			if got.Status != tt.status {
+				t.Errorf("статус %s, ожидается %s", got.Status, tt.status)
+			}
+			want := tt.reasons
+			if want == nil {
+				want = []application.Reason{}
+			}
+			if !slices.Equal(got.Reasons, want) {
+				t.Errorf("причины %v, ожидается %v", got.Reasons, want)
+			}
+			if got.Reasons == nil {
+				t.Error("причины должны быть пустым списком, а не nil")
+			}
+			if !got.Income.Equal(decimal.RequireFromString(tt.income)) {
+				t.Errorf("доход %s, ожидается %s", got.Income, tt.income)
+			}
+			if !got.DebtToIncome.Equal(decimal.RequireFromString(tt.dti)) {
+				t.Errorf("нагрузка %s, ожидается %s", got.DebtToIncome, tt.dti)
+			}
+		})
+	}
+}
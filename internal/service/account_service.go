@@ -3,35 +3,50 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
 	"github.com/yujihn/bank_API/internal/models/account"
 	"github.com/yujihn/bank_API/internal/models/transaction"
 	"github.com/yujihn/bank_API/internal/repository"
 )
 
 var (
-	ErrInsufficientFunds = errors.New("недостаточно средств")                    // Ошибка при недостатке средств на счете
-	ErrSameAccount       = errors.New("нельзя переводить деньги на тот же счет") // Ошибка при попытке перевода на тот же счет
-	ErrNegativeAmount    = errors.New("сумма не может быть отрицательной")       // Ошибка при отрицательной сумме
+	ErrInsufficientFunds   = apperr.New(apperr.Invalid, apperr.CodeInsufficientFunds, "недостаточно средств")                     // Ошибка при недостатке средств на счете
+	ErrSameAccount         = apperr.New(apperr.Invalid, apperr.CodeSameAccount, "нельзя переводить деньги на тот же счет")        // Ошибка при попытке перевода на тот же счет
+	ErrNegativeAmount      = apperr.New(apperr.Invalid, apperr.CodeNonPositiveAmount, "сумма не может быть отрицательной")        // Ошибка при отрицательной сумме
+	ErrAccountNotOwned     = apperr.New(apperr.NotFound, apperr.CodeAccountNotFound, "счет не принадлежит пользователю")          // Ошибка при обращении к чужому счету
+	ErrAccountRestricted   = apperr.New(apperr.Forbidden, apperr.CodeAccountRestricted, "расходные операции по счету ограничены") // Списание со счета, ограниченного из-за просрочки по кредиту
+	ErrUnsupportedCurrency = apperr.New(apperr.Invalid, apperr.CodeUnsupportedCurrency, "поддерживается только валюта RUB")       // Открытие счета в другой валюте
+	ErrInvalidAccountType  = apperr.New(apperr.Invalid, apperr.CodeInvalidAccountType, "неизвестный тип счета")                   // Открытие счета неизвестного типа
 )
 
 type AccountService struct {
 	accountRepo     *repository.AccountRepository     // Репозиторий для работы со счетами
 	transactionRepo *repository.TransactionRepository // Репозиторий для работы с транзакциями
+	kyc             *KYCPolicy                        // Лимиты расходных операций по статусу проверки личности
 }
 
 // NewAccountService создает новый сервис для работы со счетами
-func NewAccountService(accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository) *AccountService {
+func NewAccountService(accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository,
+	kyc *KYCPolicy) *AccountService {
 	return &AccountService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		kyc:             kyc,
 	}
 }
 
-// CreateAccount создает новый счет для пользователя
-func (s *AccountService) CreateAccount(ctx context.Context, userID int64, currency account.Currency) (*account.Account, error) {
-	return s.accountRepo.CreateAccount(ctx, userID, currency)
+// CreateAccount создает новый счет для пользователя. Пока поддерживаются только счета в рублях
+func (s *AccountService) CreateAccount(ctx context.Context, userID int64, currency account.Currency, accType account.Type) (*account.Account, error) {
+	if currency != account.RUB {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	if !accType.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccountType, accType)
+	}
+	return s.accountRepo.CreateAccount(ctx, userID, currency, accType)
 }
 
 // GetAccountByID получает счет по ID с проверкой владения
@@ -43,7 +58,7 @@ func (s *AccountService) GetAccountByID(ctx context.Context, id int64, userID in
 
 	// Проверка, принадлежит ли счет пользователю
 	if acc.UserID != userID {
-		return nil, errors.New("счет не принадлежит пользователю")
+		return nil, ErrAccountNotOwned
 	}
 
 	return acc, nil
@@ -67,28 +82,26 @@ func (s *AccountService) UpdateBalance(ctx context.Context, id int64, userID int
 		return err
 	}
 
-	// Если это списание, проверяем достаточность средств
-	if amount.LessThan(decimal.Zero) && acc.Balance.Add(amount).LessThan(decimal.Zero) {
-		return ErrInsufficientFunds
+	// Списание с ограниченного счета запрещено
+	if amount.LessThan(decimal.Zero) && acc.Restricted {
+		return ErrAccountRestricted
 	}
 
-	// Определяем тип транзакции: пополнение или списание
-	txType := transaction.WITHDRAWAL // Списание
-	if amount.GreaterThan(decimal.Zero) {
-		txType = transaction.DEPOSIT // Пополнение
+	// Если это списание, проверяем достаточность средств с учетом лимита овердрафта
+	if amount.LessThan(decimal.Zero) && acc.Available().Add(amount).LessThan(decimal.Zero) {
+		return ErrInsufficientFunds
 	}
 
-	// Обновляем баланс в базе данных
-	err = s.accountRepo.UpdateBalance(ctx, id, amount)
-	if err != nil {
-		return err
+	// Списание без подтвержденной личности ограничено лимитами
+	var guard *repository.DebitGuard
+	if amount.LessThan(decimal.Zero) {
+		if guard, err = s.kyc.DebitGuard(ctx, userID, amount.Abs()); err != nil {
+			return err
+		}
 	}
 
-	// Записываем транзакцию
-	absAmount := amount.Abs()
-	_, err = s.transactionRepo.CreateTransaction(ctx, id, absAmount, txType, transaction.COMPLETED)
-
-	return err
+	// Обновляем баланс и записываем транзакцию
+	return s.accountRepo.UpdateBalance(ctx, id, amount, transaction.KIND_EXTERNAL, guard)
 }
 
 // Transfer переводит деньги между счетами
@@ -108,31 +121,33 @@ func (s *AccountService) Transfer(ctx context.Context, fromID, toID int64, userI
 		return err
 	}
 
-	// Проверка достаточности средств
-	if fromAcc.Balance.LessThan(amount) {
-		return ErrInsufficientFunds
+	// Списание с ограниченного счета запрещено
+	if fromAcc.Restricted {
+		return ErrAccountRestricted
 	}
 
-	// Проверка существования счета получателя
-	_, err = s.accountRepo.GetAccountByID(ctx, toID)
-	if err != nil {
-		return err
+	// Проверка достаточности средств с учетом лимита овердрафта
+	if fromAcc.Available().LessThan(amount) {
+		return ErrInsufficientFunds
 	}
 
-	// Выполнение перевода в базе данных
-	err = s.accountRepo.TransferBetweenAccounts(ctx, fromID, toID, amount)
+	// Проверка существования счета получателя
+	toAcc, err := s.accountRepo.GetAccountByID(ctx, toID)
 	if err != nil {
 		return err
 	}
 
-	// Запись транзакций для обоих счетов
-	_, err = s.transactionRepo.CreateTransaction(ctx, fromID, amount, transaction.DEPOSIT, transaction.COMPLETED)
-	if err != nil {
-		return err
+	// Перевод другому клиенту без подтвержденной личности ограничен лимитами; переводы между
+	// собственными счетами не ограничиваются
+	var guard *repository.DebitGuard
+	if toAcc.UserID != userID {
+		if guard, err = s.kyc.DebitGuard(ctx, userID, amount); err != nil {
+			return err
+		}
 	}
 
-	_, err = s.transactionRepo.CreateTransaction(ctx, toID, amount, transaction.WITHDRAWAL, transaction.COMPLETED)
-	return err
+	// Выполнение перевода и запись транзакций для обоих счетов в базе данных
+	return s.accountRepo.TransferBetweenAccounts(ctx, fromID, toID, amount, guard)
 }
 
 // GetTransactionsByAccountID получает историю транзакций для конкретного счета
@@ -2,46 +2,123 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/yujihn/bank_API/internal/apperr"
 	"github.com/yujihn/bank_API/internal/config"
 	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/keyset"
 	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/session"
 	"github.com/yujihn/bank_API/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Различные ошибки, которые могут возникнуть в процессе аутентификации
 var (
-	ErrInvalidCredentials = errors.New("неверные учетные данные")     // Ошибка при неправильных данных входа
-	ErrUserExists         = errors.New("пользователь уже существует") // Ошибка при попытке зарегистрировать существующего пользователя
+	ErrInvalidCredentials  = apperr.New(apperr.Unauthenticated, apperr.CodeInvalidCredentials, "неверные учетные данные")                             // Ошибка при неправильных данных входа
+	ErrUserExists          = apperr.New(apperr.Conflict, apperr.CodeUserExists, "пользователь уже существует")                                        // Ошибка при попытке зарегистрировать существующего пользователя
+	ErrInvalidRefreshToken = apperr.New(apperr.Unauthenticated, apperr.CodeInvalidRefreshToken, "неверный или просроченный refresh-токен")            // Токен не найден, истек или сессия завершена
+	ErrRefreshTokenReused  = apperr.New(apperr.Unauthenticated, apperr.CodeRefreshTokenReused, "refresh-токен уже был использован, сессия завершена") // Повторное предъявление погашенного токена
+	ErrSessionNotFound     = apperr.New(apperr.NotFound, apperr.CodeSessionNotFound, "сессия не найдена")                                             // Сессия не найдена или уже завершена
 )
 
+// Длины случайных токенов в байтах до кодирования
+const (
+	refreshTokenBytes = 32 // Refresh-токен
+	tokenIDBytes      = 16 // Идентификатор access-токена (jti)
+)
+
+// ClientInfo описывает устройство, с которого выполняется вход или обновление токенов
+type ClientInfo struct {
+	UserAgent string // Заголовок User-Agent
+	IP        string // IP-адрес клиента
+}
+
+// TokenPair содержит access- и refresh-токены, выданные при входе или обновлении
+type TokenPair struct {
+	AccessToken      string        // JWT для доступа к API
+	RefreshToken     string        // Одноразовый токен для получения новой пары
+	ExpiresIn        time.Duration // Время жизни access-токена
+	RefreshExpiresAt time.Time     // Срок действия refresh-токена
+	SessionID        int64         // ID сессии
+}
+
+// LoginResult содержит результат проверки пароля: пару токенов или, если у пользователя включена
+// двухфакторная аутентификация, MFA-токен для второго шага входа
+type LoginResult struct {
+	Tokens    *TokenPair    // Пара токенов, если второй шаг не требуется
+	Challenge *MFAChallenge // MFA-токен, который вместе с кодом обменивается на пару токенов
+}
+
+// AccessClaims содержит данные, извлеченные из access-токена
+type AccessClaims struct {
+	UserID    int64     // ID пользователя
+	SessionID int64     // ID сессии, в которой выпущен токен
+	TokenID   string    // Идентификатор токена (jti) для отзыва
+	ExpiresAt time.Time // Срок действия токена
+}
+
 // AuthService интерфейс для сервиса аутентификации
 type AuthService interface {
-	Register(ctx context.Context, req dto.RegisterRequest) (int64, error) // Регистрация нового пользователя
-	Login(ctx context.Context, req dto.LoginRequest) (string, error)      // Вход и получение JWT
-	ParseToken(tokenString string) (int64, error)                         // Разбор JWT и получение ID пользователя
+	Register(ctx context.Context, req dto.RegisterRequest) (int64, error)                          // Регистрация нового пользователя
+	Login(ctx context.Context, req dto.LoginRequest, client ClientInfo) (*LoginResult, error)      // Вход: сессия или второй шаг
+	CompleteMFA(ctx context.Context, mfaToken, code string, client ClientInfo) (*TokenPair, error) // Второй шаг входа по коду
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error)       // Обновление пары токенов
+	Logout(ctx context.Context, claims *AccessClaims) error                                        // Завершение текущей сессии
+	GetSessions(ctx context.Context, userID int64) ([]*session.Session, error)                     // Список активных сессий
+	RevokeSession(ctx context.Context, userID, sessionID int64) error                              // Завершение сессии по ID
+	ParseToken(ctx context.Context, tokenString string) (*AccessClaims, error)                     // Разбор access-токена
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)                                   // Проверка отзыва токена по jti
+	PurgeRevoked(ctx context.Context) error                                                        // Очистка истекших записей об отзыве
 }
 
 // authService реализует интерфейс AuthService
 type authService struct {
-	userRepo repository.UserRepository // Репозиторий пользователей
-	jwtCfg   config.JWTConfig          // Конфигурация JWT
+	userRepo    repository.UserRepository          // Репозиторий пользователей
+	sessionRepo *repository.SessionRepository      // Репозиторий сессий и refresh-токенов
+	revokedRepo *repository.RevokedTokenRepository // Список отозванных access-токенов
+	keyService  *JWTKeyService                     // Ключи подписи JWT
+	mfaService  *MFAService                        // Двухфакторная аутентификация
+	loginGuard  *LoginGuardService                 // Защита входа от подбора пароля
+	credentials *CredentialService                 // Подтверждение email
+	jwtCfg      config.JWTConfig                   // Конфигурация JWT
 }
 
 // NewAuthService создает новый сервис аутентификации
-func NewAuthService(userRepo repository.UserRepository, jwtCfg config.JWTConfig) AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo *repository.SessionRepository,
+	revokedRepo *repository.RevokedTokenRepository, keyService *JWTKeyService, mfaService *MFAService,
+	loginGuard *LoginGuardService, credentials *CredentialService, jwtCfg config.JWTConfig) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		jwtCfg:   jwtCfg,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		revokedRepo: revokedRepo,
+		keyService:  keyService,
+		mfaService:  mfaService,
+		loginGuard:  loginGuard,
+		credentials: credentials,
+		jwtCfg:      jwtCfg,
 	}
 }
 
-// Register регистрирует нового пользователя
+// Register регистрирует нового пользователя и отправляет ему письмо со ссылкой подтверждения email.
+// Email сохраняется в нижнем регистре; адрес, отличающийся от занятого только регистром, считается занятым
 func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (int64, error) {
+	email := normalizeEmail(req.Email)
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return 0, ErrUserExists
+	} else if !errors.Is(err, repository.ErrUserNotFound) {
+		return 0, err
+	}
+
 	// Хеширование пароля с использованием bcrypt
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -49,93 +126,322 @@ func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (in
 	}
 
 	user := &models.User{
-		Email:    req.Email,
+		Email:    email,
 		Password: string(hashedPassword),
+		FullName: strings.TrimSpace(req.FullName),
+	}
+	if username := strings.ToLower(strings.TrimSpace(req.Username)); username != "" {
+		user.Username = &username
 	}
 
 	id, err := s.userRepo.Create(ctx, user)
 	if err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			return 0, ErrUserExists
+		}
 		return 0, err
 	}
 
+	s.credentials.StartVerification(ctx, id, user.Email)
 	return id, nil
 }
 
-// Login аутентифицирует пользователя и возвращает JWT-токен
-func (s *authService) Login(ctx context.Context, req dto.LoginRequest) (string, error) {
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+// Login проверяет пароль пользователя. Если двухфакторная аутентификация не включена, создает сессию для
+// устройства client и возвращает пару токенов; иначе возвращает MFA-токен для второго шага входа.
+// Пока по email или IP-адресу действует ограничение после неудачных попыток, пароль не проверяется
+// и возвращается *LoginBlockedError
+func (s *authService) Login(ctx context.Context, req dto.LoginRequest, client ClientInfo) (*LoginResult, error) {
+	email := normalizeEmail(req.Email)
+	if err := s.loginGuard.Check(ctx, email, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
-			return "", ErrInvalidCredentials
+			return nil, s.failLogin(ctx, email, client)
 		}
-		return "", err
+		return nil, err
 	}
 
 	// Проверка пароля с помощью bcrypt
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return "", ErrInvalidCredentials
+		return nil, s.failLogin(ctx, email, client)
+	}
+	if err := s.loginGuard.Succeed(ctx, email); err != nil {
+		return nil, err
 	}
 
-	// Генерация JWT-токена
-	token, err := s.generateToken(user.ID)
+	enabled, err := s.mfaService.Enabled(ctx, user.ID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if enabled {
+		challenge, err := s.mfaService.CreateChallenge(ctx, user.ID, client)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{Challenge: challenge}, nil
 	}
 
-	return token, nil
+	tokens, err := s.startSession(ctx, user.ID, client, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Tokens: tokens}, nil
 }
 
-// generateToken создает JWT с данными пользователя
-func (s *authService) generateToken(userID int64) (string, error) {
-	// Создаем claims для JWT
-	claims := jwt.MapClaims{
-		"sub": userID,                                    // subject (ID пользователя)
-		"exp": time.Now().Add(s.jwtCfg.ExpiresIn).Unix(), // время истечения
-		"iat": time.Now().Unix(),                         // время выпуска
+// failLogin засчитывает неудачную попытку входа и возвращает ErrInvalidCredentials
+func (s *authService) failLogin(ctx context.Context, email string, client ClientInfo) error {
+	if err := s.loginGuard.Fail(ctx, email, client.IP); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// CompleteMFA завершает вход: проверяет код из приложения или код восстановления по MFA-токену, выданному
+// Login, и создает сессию, сразу подтвержденную кодом
+func (s *authService) CompleteMFA(ctx context.Context, mfaToken, code string, client ClientInfo) (*TokenPair, error) {
+	challenge, err := s.mfaService.VerifyChallenge(ctx, mfaToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+	return s.startSession(ctx, challenge.UserID, client, &verifiedAt)
+}
+
+// startSession создает сессию пользователя для устройства client и выпускает в ней первую пару токенов;
+// mfaVerifiedAt — время подтверждения входа кодом, если оно было
+func (s *authService) startSession(ctx context.Context, userID int64, client ClientInfo,
+	mfaVerifiedAt *time.Time) (*TokenPair, error) {
+	refreshToken, err := randomToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	tokenID, err := randomToken(tokenIDBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(s.jwtCfg.ExpiresIn)
+	sess := &session.Session{
+		UserID:          userID,
+		UserAgent:       client.UserAgent,
+		IPAddress:       client.IP,
+		ExpiresAt:       now.Add(s.jwtCfg.RefreshExpiresIn),
+		AccessJTI:       &tokenID,
+		AccessExpiresAt: &accessExpiresAt,
+		MFAVerifiedAt:   mfaVerifiedAt,
+	}
+	if err := s.sessionRepo.Create(ctx, sess, hashRefreshToken(refreshToken)); err != nil {
+		return nil, err
+	}
+
+	return s.issue(sess, refreshToken)
+}
+
+// Refresh погашает refresh-токен и выдает новую пару токенов в той же сессии. Повторное предъявление
+// погашенного токена завершает сессию целиком: и злоумышленник, и владелец должны войти заново
+func (s *authService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next, err := randomToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	tokenID, err := randomToken(tokenIDBytes)
+	if err != nil {
+		return nil, err
 	}
 
-	// Создаем новый токен
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	now := time.Now()
+	sess, err := s.sessionRepo.Rotate(ctx, hashRefreshToken(refreshToken), &session.Rotation{
+		TokenHash:       hashRefreshToken(next),
+		ExpiresAt:       now.Add(s.jwtCfg.RefreshExpiresIn),
+		IP:              client.IP,
+		AccessJTI:       tokenID,
+		AccessExpiresAt: now.Add(s.jwtCfg.ExpiresIn),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			return nil, ErrRefreshTokenReused
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrInvalidRefreshToken
+		default:
+			return nil, err
+		}
+	}
+
+	return s.issue(sess, next)
+}
+
+// Logout завершает сессию, в которой выпущен access-токен, и отзывает сам токен
+func (s *authService) Logout(ctx context.Context, claims *AccessClaims) error {
+	if err := s.revoke(ctx, claims.UserID, claims.SessionID, session.LOGOUT); err != nil {
+		return err
+	}
+	return s.revokedRepo.Revoke(ctx, claims.TokenID, claims.UserID, session.LOGOUT, claims.ExpiresAt)
+}
+
+// GetSessions возвращает активные сессии пользователя
+func (s *authService) GetSessions(ctx context.Context, userID int64) ([]*session.Session, error) {
+	return s.sessionRepo.GetActiveByUserID(ctx, userID)
+}
+
+// RevokeSession завершает сессию пользователя по ID и отзывает последний выданный в ней access-токен
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID int64) error {
+	return s.revoke(ctx, userID, sessionID, session.REVOKED)
+}
 
-	// Подписываем токен секретным ключом
-	tokenString, err := token.SignedString([]byte(s.jwtCfg.Secret))
+// revoke завершает сессию с указанной причиной
+func (s *authService) revoke(ctx context.Context, userID, sessionID int64, reason session.RevokeReason) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, userID, reason); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// IsRevoked сообщает, отозван ли access-токен с идентификатором tokenID
+func (s *authService) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return s.revokedRepo.IsRevoked(ctx, tokenID)
+}
+
+// PurgeRevoked удаляет из списка отозванных токены с истекшим сроком действия
+func (s *authService) PurgeRevoked(ctx context.Context) error {
+	_, err := s.revokedRepo.DeleteExpired(ctx, time.Now())
+	return err
+}
+
+// issue выпускает access-токен, сведения о котором сохранены в сессии, и объединяет его с refresh-токеном
+func (s *authService) issue(sess *session.Session, refreshToken string) (*TokenPair, error) {
+	accessToken, err := s.generateToken(&AccessClaims{
+		UserID:    sess.UserID,
+		SessionID: sess.ID,
+		TokenID:   *sess.AccessJTI,
+		ExpiresAt: *sess.AccessExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        s.jwtCfg.ExpiresIn,
+		RefreshExpiresAt: sess.ExpiresAt,
+		SessionID:        sess.ID,
+	}, nil
+}
+
+// generateToken создает JWT с данными пользователя и сессии, подписанный текущим ключом; kid ключа
+// записывается в заголовок токена
+func (s *authService) generateToken(c *AccessClaims) (string, error) {
+	key, err := s.keyService.Signing()
 	if err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	// Создаем claims для JWT
+	claims := jwt.MapClaims{
+		"sub": c.UserID,           // subject (ID пользователя)
+		"sid": c.SessionID,        // ID сессии
+		"jti": c.TokenID,          // ID токена
+		"exp": c.ExpiresAt.Unix(), // время истечения
+		"iat": time.Now().Unix(),  // время выпуска
+	}
+
+	// Создаем новый токен с идентификатором ключа
+	token := jwt.NewWithClaims(key.Algorithm.Method(), claims)
+	token.Header["kid"] = key.ID
+
+	// Подписываем токен текущим ключом
+	return token.SignedString(key.SigningKey())
 }
 
-// ParseToken разбирает JWT и возвращает ID пользователя
-func (s *authService) ParseToken(tokenString string) (int64, error) {
+// ParseToken разбирает access-токен: находит ключ по kid из заголовка, проверяет алгоритм, подпись и срок
+// действия и возвращает данные токена. Отзыв токена проверяется отдельно методом IsRevoked
+func (s *authService) ParseToken(ctx context.Context, tokenString string) (*AccessClaims, error) {
+	methods := make([]string, 0, len(keyset.Algorithms))
+	for _, alg := range keyset.Algorithms {
+		methods = append(methods, string(alg))
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Проверка метода подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, errors.New("в заголовке токена нет идентификатора ключа")
+		}
+		key, err := s.keyService.Lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		// Алгоритм задает ключ, а не заголовок токена
+		if token.Method.Alg() != string(key.Algorithm) {
 			return nil, errors.New("неожиданный метод подписи токена")
 		}
-		return []byte(s.jwtCfg.Secret), nil
-	})
+		return key.VerificationKey(), nil
+	}, jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Проверка валидности токена
 	if !token.Valid {
-		return 0, errors.New("невалидный токен")
+		return nil, errors.New("невалидный токен")
 	}
 
 	// Получение claims
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("невалидные claims")
+		return nil, errors.New("невалидные claims")
 	}
 
-	// Извлечение ID пользователя из claims
+	// Извлечение ID пользователя, сессии и токена из claims
 	userID, ok := claims["sub"].(float64)
 	if !ok {
-		return 0, errors.New("невалидный ID пользователя")
+		return nil, errors.New("невалидный ID пользователя")
+	}
+	sessionID, ok := claims["sid"].(float64)
+	if !ok {
+		return nil, errors.New("невалидный ID сессии")
+	}
+	tokenID, ok := claims["jti"].(string)
+	if !ok || tokenID == "" {
+		return nil, errors.New("невалидный ID токена")
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, err
 	}
 
-	return int64(userID), nil
+	return &AccessClaims{
+		UserID:    int64(userID),
+		SessionID: int64(sessionID),
+		TokenID:   tokenID,
+		ExpiresAt: exp.Time,
+	}, nil
+}
+
+// randomToken создает случайную строку из n байт в кодировке base64url
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken возвращает SHA-256 refresh-токена; в базе данных хранится только хеш
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
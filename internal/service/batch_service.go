@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/models/batch"
+	"github.com/yujihn/bank_API/internal/pain"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrBatchNotFound  = apperr.New(apperr.NotFound, apperr.CodeBatchNotFound, "пакет платежей не найден")                            // Пакет не найден или не принадлежит пользователю
+	ErrDuplicateBatch = apperr.New(apperr.Conflict, apperr.CodeDuplicateBatch, "пакет с таким идентификатором сообщения уже принят") // Повторная загрузка файла
+)
+
+// DuplicateBatchError возвращается при повторной загрузке файла с уже принятым идентификатором сообщения
+type DuplicateBatchError struct {
+	BatchID int64 // ID ранее принятого пакета
+}
+
+// Error возвращает описание ошибки с ID ранее принятого пакета
+func (e *DuplicateBatchError) Error() string {
+	return fmt.Sprintf("%s (ID %d)", ErrDuplicateBatch, e.BatchID)
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrDuplicateBatch через errors.Is
+func (e *DuplicateBatchError) Unwrap() error {
+	return ErrDuplicateBatch
+}
+
+const (
+	// batchPollInterval задает период проверки пакетов, не попавших в очередь (например, после перезапуска)
+	batchPollInterval = 30 * time.Second
+	// batchItemTimeout задает время, после которого захваченный и не завершенный платеж считается прерванным сбоем
+	batchItemTimeout = 10 * time.Minute
+)
+
+// BatchService принимает пакеты переводов и асинхронно исполняет их через AccountService.Transfer
+type BatchService struct {
+	batchRepo      *repository.BatchRepository   // Репозиторий пакетов платежей
+	accountRepo    *repository.AccountRepository // Репозиторий для работы со счетами
+	accountService *AccountService               // Сервис счетов, выполняющий переводы
+	logger         *logrus.Logger                // Логгер для фоновой обработки
+	queue          chan int64                    // Очередь ID пакетов на исполнение
+}
+
+// NewBatchService создает новый сервис пакетных платежей
+func NewBatchService(batchRepo *repository.BatchRepository, accountRepo *repository.AccountRepository,
+	accountService *AccountService, logger *logrus.Logger) *BatchService {
+	return &BatchService{
+		batchRepo:      batchRepo,
+		accountRepo:    accountRepo,
+		accountService: accountService,
+		logger:         logger,
+		queue:          make(chan int64, 100),
+	}
+}
+
+// Submit проверяет все платежи пакета до исполнения, сохраняет пакет и ставит его в очередь.
+// При любой ошибке проверки пакет отклоняется целиком с ошибкой *pain.ValidationError,
+// а повторная загрузка того же MsgId — с ошибкой *DuplicateBatchError
+func (s *BatchService) Submit(ctx context.Context, userID int64, sourceFormat string, in *pain.Instructions) (*batch.Batch, []*batch.Item, error) {
+	if err := s.validate(ctx, userID, in); err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]*batch.Item, 0, len(in.Items))
+	for _, instr := range in.Items {
+		items = append(items, &batch.Item{
+			EndToEndID:  instr.EndToEndID,
+			ToAccountID: instr.ToAccountID,
+			Amount:      instr.Amount,
+			Description: instr.Description,
+			Status:      batch.ITEM_PENDING,
+		})
+	}
+
+	created, err := s.batchRepo.CreateBatch(ctx, &batch.Batch{
+		UserID:        userID,
+		FromAccountID: in.FromAccountID,
+		MessageID:     in.MessageID,
+		SourceFormat:  sourceFormat,
+		ItemCount:     len(items),
+		TotalAmount:   in.Total(),
+	}, items)
+	if errors.Is(err, repository.ErrBatchExists) {
+		existingID, err := s.batchRepo.GetBatchIDByMessageID(ctx, userID, in.MessageID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &DuplicateBatchError{BatchID: existingID}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Если очередь заполнена, пакет будет подхвачен при следующем опросе
+	select {
+	case s.queue <- created.ID:
+	default:
+	}
+
+	storedItems, err := s.batchRepo.GetItemsByBatchID(ctx, created.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, storedItems, nil
+}
+
+// GetBatch получает пакет и статусы его платежей с проверкой владения
+func (s *BatchService) GetBatch(ctx context.Context, id, userID int64) (*batch.Batch, []*batch.Item, error) {
+	b, err := s.batchRepo.GetBatchByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrBatchNotFound
+		}
+		return nil, nil, err
+	}
+	if b.UserID != userID {
+		return nil, nil, ErrBatchNotFound
+	}
+
+	items, err := s.batchRepo.GetItemsByBatchID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, items, nil
+}
+
+// Start запускает фоновый обработчик пакетов, который работает до отмены ctx
+func (s *BatchService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(batchPollInterval)
+		defer ticker.Stop()
+
+		// При старте дорабатываем пакеты, прерванные остановкой сервера
+		s.processWaiting(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-s.queue:
+				s.process(ctx, id)
+			case <-ticker.C:
+				s.processWaiting(ctx)
+			}
+		}
+	}()
+}
+
+// validate выполняет бизнес-проверки пакета: владение счетом списания, существование счетов получателей,
+// валюту и достаточность средств на всю сумму пакета с учетом лимита овердрафта
+func (s *BatchService) validate(ctx context.Context, userID int64, in *pain.Instructions) error {
+	verr := &pain.ValidationError{}
+
+	from, err := s.accountService.GetAccountByID(ctx, in.FromAccountID, userID)
+	if err != nil {
+		verr.Add(0, "", "счет списания не найден или не принадлежит пользователю")
+		return verr
+	}
+
+	if from.Available().LessThan(in.Total()) {
+		verr.Add(0, "", "недостаточно средств для исполнения пакета")
+	}
+
+	known := make(map[int64]bool)
+	for i, instr := range in.Items {
+		index := i + 1
+		if instr.Currency != "" && instr.Currency != string(from.Currency) {
+			verr.Add(index, instr.EndToEndID, "валюта платежа не совпадает с валютой счета списания")
+		}
+		if instr.ToAccountID == in.FromAccountID {
+			verr.Add(index, instr.EndToEndID, ErrSameAccount.Error())
+			continue
+		}
+
+		exists, checked := known[instr.ToAccountID]
+		if !checked {
+			_, err := s.accountRepo.GetAccountByID(ctx, instr.ToAccountID)
+			switch {
+			case err == nil:
+				exists = true
+			case errors.Is(err, pgx.ErrNoRows):
+				exists = false
+			default:
+				return err
+			}
+			known[instr.ToAccountID] = exists
+		}
+		if !exists {
+			verr.Add(index, instr.EndToEndID, "счет получателя не найден")
+		}
+	}
+
+	return verr.Err()
+}
+
+// processWaiting исполняет все пакеты, ожидающие обработки
+func (s *BatchService) processWaiting(ctx context.Context) {
+	ids, err := s.batchRepo.GetBatchIDsByStatus(ctx, batch.PENDING, batch.PROCESSING)
+	if err != nil {
+		s.logger.Errorf("Ошибка получения ожидающих пакетов платежей: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		s.process(ctx, id)
+	}
+}
+
+// process последовательно исполняет неисполненные платежи пакета и выставляет итоговый статус.
+// Каждый платеж захватывается (PENDING → PROCESSING) до перевода денег, поэтому его не исполнят повторно
+// ни после сбоя, ни параллельно на другом экземпляре. Платеж, прерванный между захватом и записью
+// результата, отклоняется для ручной сверки: был ли выполнен перевод, неизвестно
+func (s *BatchService) process(ctx context.Context, id int64) {
+	b, err := s.batchRepo.GetBatchByID(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Ошибка получения пакета платежей %d: %v", id, err)
+		return
+	}
+	if b.Status != batch.PENDING && b.Status != batch.PROCESSING {
+		return
+	}
+
+	if err := s.batchRepo.UpdateBatchStatus(ctx, id, batch.PROCESSING, nil); err != nil {
+		s.logger.Errorf("Ошибка обновления статуса пакета %d: %v", id, err)
+		return
+	}
+
+	interrupted, err := s.batchRepo.FailInterruptedItems(ctx, id, time.Now().Add(-batchItemTimeout),
+		"исполнение платежа прервано, требуется сверка")
+	if err != nil {
+		s.logger.Errorf("Ошибка отклонения прерванных платежей пакета %d: %v", id, err)
+		return
+	}
+	if interrupted > 0 {
+		s.logger.WithFields(logrus.Fields{"batch_id": id, "items": interrupted}).
+			Warn("Прерванные платежи пакета отклонены, требуется сверка")
+	}
+
+	items, err := s.batchRepo.GetItemsByBatchID(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Ошибка получения платежей пакета %d: %v", id, err)
+		return
+	}
+
+	completed, failed, inProgress := 0, 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case batch.ITEM_COMPLETED:
+			completed++
+			continue
+		case batch.ITEM_FAILED:
+			failed++
+			continue
+		case batch.ITEM_PROCESSING:
+			inProgress++
+			continue
+		}
+
+		// Пакет остается в статусе PROCESSING и будет дообработан после перезапуска
+		if ctx.Err() != nil {
+			return
+		}
+
+		claimed, err := s.batchRepo.ClaimItem(ctx, item.ID)
+		if err != nil {
+			s.logger.Errorf("Ошибка захвата платежа %d пакета %d: %v", item.ID, id, err)
+			return
+		}
+		if !claimed {
+			// Платеж исполняет другой обработчик; пакет завершит тот, кто увидит все платежи исполненными
+			inProgress++
+			continue
+		}
+
+		status, reason := batch.ITEM_COMPLETED, ""
+		if err := s.accountService.Transfer(ctx, b.FromAccountID, item.ToAccountID, b.UserID, item.Amount); err != nil {
+			status, reason = batch.ITEM_FAILED, err.Error()
+			failed++
+		} else {
+			completed++
+		}
+
+		if err := s.batchRepo.UpdateItemStatus(ctx, item.ID, status, reason); err != nil {
+			s.logger.Errorf("Ошибка обновления статуса платежа %d пакета %d: %v", item.ID, id, err)
+			return
+		}
+	}
+	if inProgress > 0 {
+		return
+	}
+
+	final := batch.COMPLETED
+	switch {
+	case completed == 0:
+		final = batch.FAILED
+	case failed > 0:
+		final = batch.PARTIALLY_COMPLETED
+	}
+
+	now := time.Now()
+	if err := s.batchRepo.UpdateBatchStatus(ctx, id, final, &now); err != nil {
+		s.logger.Errorf("Ошибка обновления итогового статуса пакета %d: %v", id, err)
+		return
+	}
+	s.logger.WithFields(logrus.Fields{
+		"batch_id":  id,
+		"status":    final,
+		"completed": completed,
+		"failed":    failed,
+	}).Info("Пакет платежей исполнен")
+}
@@ -5,32 +5,52 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
-	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
 	"github.com/yujihn/bank_API/internal/models"
 	"github.com/yujihn/bank_API/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Ошибки операций с картами
+var (
+	ErrCardNotFound = apperr.New(apperr.NotFound, apperr.CodeCardNotFound, "карта не найдена")    // Карта не найдена или принадлежит другому пользователю
+	ErrInvalidCard  = apperr.New(apperr.Invalid, apperr.CodeInvalidCard, "неверные данные карты") // Неверный CVV или истек срок действия карты
+	ErrCardBlocked  = apperr.New(apperr.Forbidden, apperr.CodeCardBlocked, "карта заблокирована") // Карта заблокирована после неверных вводов CVV подряд
+)
+
 // CardService обеспечивает бизнес-логику для работы с картами
 type CardService struct {
-	cardRepo      *repository.CardRepository // Репозиторий карт
-	db            *pgxpool.Pool              // Пул соединений с базой данных
-	encryptionKey []byte                     // Ключ для HMAC подписи
+	cardRepo       *repository.CardRepository // Репозиторий карт
+	userRepo       repository.UserRepository  // Репозиторий пользователей для поиска счета списания
+	accountService *AccountService            // Сервис счетов, выполняющий списание
+	kyc            *KYCPolicy                 // Выпуск карт доступен после подтверждения личности
+	db             *pgxpool.Pool              // Пул соединений с базой данных
+	encryptionKey  []byte                     // Ключ для HMAC подписи
+	maxCVVFailures int                        // Неверных вводов CVV подряд до блокировки карты
 }
 
 // NewCardService создает новый сервис карт
-func NewCardService(cardRepo *repository.CardRepository, db *pgxpool.Pool, encryptionKey string) *CardService {
+func NewCardService(cardRepo *repository.CardRepository, userRepo repository.UserRepository, accountService *AccountService,
+	kyc *KYCPolicy, db *pgxpool.Pool, encryptionKey string, cfg config.CardConfig) *CardService {
 	return &CardService{
-		cardRepo:      cardRepo,
-		db:            db,
-		encryptionKey: []byte(encryptionKey),
+		cardRepo:       cardRepo,
+		userRepo:       userRepo,
+		accountService: accountService,
+		kyc:            kyc,
+		db:             db,
+		encryptionKey:  []byte(encryptionKey),
+		maxCVVFailures: cfg.MaxCVVFailures,
 	}
 }
 
@@ -112,11 +132,17 @@ func (s *CardService) encryptWithPGP(ctx context.Context, data string, key strin
 	return encrypted, err
 }
 
-// decryptWithPGP расшифровывает данные с помощью PGP через SQL-функцию
+// decryptWithPGP расшифровывает данные с помощью PGP через SQL-функцию. Неверный ключ возвращает ErrInvalidCard
 func (s *CardService) decryptWithPGP(ctx context.Context, data []byte, key string) (string, error) {
 	query := `SELECT pgp_sym_decrypt($1, $2)`
 	var decrypted string
 	err := s.db.QueryRow(ctx, query, data, key).Scan(&decrypted)
+
+	// pgcrypto сообщает о неверном ключе ошибкой external_routine_exception (39000)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "39000" {
+		return "", fmt.Errorf("%w: неверный PGP-ключ", ErrInvalidCard)
+	}
 	return decrypted, err
 }
 
@@ -137,6 +163,11 @@ func (s *CardService) validateCVV(cvv string, hash string) bool {
 
 // CreateCard создает новую виртуальную карту
 func (s *CardService) CreateCard(ctx context.Context, userID int64, pgpKey string) (*models.Card, map[string]string, error) {
+	// Выпуск карты доступен только после подтверждения личности
+	if err := s.kyc.RequireVerified(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
 	// Генерируем данные карты
 	cardNumber, err := s.generateCardNumber()
 	if err != nil {
@@ -195,15 +226,15 @@ func (s *CardService) GetCardDetails(ctx context.Context, cardID int64, userID i
 	// Получаем карту
 	card, err := s.cardRepo.GetCardByID(ctx, cardID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("карта не найдена")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCardNotFound
 		}
 		return nil, fmt.Errorf("ошибка получения карты: %w", err)
 	}
 
 	// Проверяем, что карта принадлежит пользователю
 	if card.UserID != userID {
-		return nil, errors.New("доступ запрещен: карта не принадлежит пользователю")
+		return nil, ErrCardNotFound
 	}
 
 	// Расшифровываем данные
@@ -239,16 +270,38 @@ func (s *CardService) VerifyCardPayment(ctx context.Context, cardID int64, cvv s
 	// Получаем карту
 	card, err := s.cardRepo.GetCardByID(ctx, cardID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, errors.New("карта не найдена")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrCardNotFound
 		}
 		return false, fmt.Errorf("ошибка получения карты: %w", err)
 	}
 
+	return s.verifyCard(ctx, card, cvv, pgpKey)
+}
+
+// verifyCard проверяет CVV и срок действия карты. Неверные вводы CVV подряд считаются, и после
+// CARD_CVV_MAX_FAILURES попыток карта блокируется; успешная проверка сбрасывает счетчик
+func (s *CardService) verifyCard(ctx context.Context, card *models.Card, cvv string, pgpKey string) (bool, error) {
+	if card.BlockedAt != nil {
+		return false, ErrCardBlocked
+	}
+
 	// Проверяем CVV
 	isValidCVV := s.validateCVV(cvv, card.CVVHash)
 	if !isValidCVV {
-		return false, errors.New("неверный CVV код")
+		blocked, err := s.cardRepo.RecordCVVFailure(ctx, card.ID, s.maxCVVFailures)
+		if err != nil {
+			return false, fmt.Errorf("ошибка учета неверного CVV: %w", err)
+		}
+		if blocked {
+			return false, fmt.Errorf("%w: превышено число неверных вводов CVV", ErrCardBlocked)
+		}
+		return false, fmt.Errorf("%w: неверный CVV код", ErrInvalidCard)
+	}
+	if card.CVVFailures > 0 {
+		if err := s.cardRepo.ResetCVVFailures(ctx, card.ID); err != nil {
+			return false, fmt.Errorf("ошибка сброса счетчика неверных CVV: %w", err)
+		}
 	}
 
 	// Проверяем срок действия
@@ -280,11 +333,11 @@ func (s *CardService) VerifyCardPayment(ctx context.Context, cardID int64, cvv s
 	expiryDate = expiryDate.AddDate(0, 1, -1)
 
 	if now.After(expiryDate) {
-		return false, errors.New("карта просрочена")
+		return false, fmt.Errorf("%w: карта просрочена", ErrInvalidCard)
 	}
 
 	// Генерируем цифровую подпись для проверки целостности
-	message := fmt.Sprintf("%d:%s:%s:%s", cardID, cardNumber, expire, cvv)
+	message := fmt.Sprintf("%d:%s:%s:%s", card.ID, cardNumber, expire, cvv)
 	hmacSignature := s.generateHMAC(message)
 
 	// В реальном приложении мы бы сравнивали сгенерированную подпись
@@ -316,3 +369,42 @@ func (s *CardService) verifyHMAC(message, signature string) bool {
 	mac.Write([]byte(message))
 	return hmac.Equal(mac.Sum(nil), expectedMAC)
 }
+
+// ProcessPayment проверяет данные карты и списывает сумму платежа со счета по умолчанию владельца карты.
+// Оплатить можно только своей картой; чужая или несуществующая карта — ErrCardNotFound. Списание возможно
+// в пределах лимита овердрафта счета и лимитов операций без подтвержденной личности
+func (s *CardService) ProcessPayment(ctx context.Context, userID, cardID int64, cvv string, pgpKey string, amount decimal.Decimal) error {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return ErrNegativeAmount
+	}
+
+	card, err := s.cardRepo.GetCardByID(ctx, cardID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCardNotFound
+		}
+		return fmt.Errorf("ошибка получения карты: %w", err)
+	}
+	// Владение проверяется до CVV, чтобы по ответам нельзя было подбирать CVV чужой карты
+	if card.UserID != userID {
+		return ErrCardNotFound
+	}
+
+	isValid, err := s.verifyCard(ctx, card, cvv, pgpKey)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return ErrInvalidCard
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, card.UserID)
+	if err != nil {
+		return err
+	}
+	if owner.DefaultAccountID == nil {
+		return ErrNoDefaultAccount
+	}
+
+	return s.accountService.UpdateBalance(ctx, *owner.DefaultAccountID, card.UserID, amount.Neg())
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/collection"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/notify"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// CollectionService ведет работу с просроченной задолженностью по кредитам: переводит кредиты с неоплаченными
+// в срок платежами в статус OVERDUE, считает дни просрочки, выполняет этапы эскалации (напоминание, неустойка,
+// списание с других счетов заемщика, ограничение счетов), записывает каждое действие в журнал и возвращает
+// кредит в статус ACTIVE после погашения просрочки
+type CollectionService struct {
+	collectionRepo *repository.CollectionRepository // Репозиторий работы с просрочкой
+	creditRepo     *repository.CreditRepository     // Репозиторий кредитов
+	accountRepo    *repository.AccountRepository    // Репозиторий счетов для списания с других счетов
+	creditService  *CreditService                   // Сервис кредитов для проверки владения
+	notifier       notify.Sender                    // Отправка уведомлений заемщикам
+	cfg            config.CollectionsConfig         // Этапы эскалации и ставка неустойки
+	logger         *logrus.Logger                   // Логгер для фоновых задач
+}
+
+// NewCollectionService создает новый сервис работы с просроченной задолженностью
+func NewCollectionService(collectionRepo *repository.CollectionRepository, creditRepo *repository.CreditRepository,
+	accountRepo *repository.AccountRepository, creditService *CreditService, notifier notify.Sender,
+	cfg config.CollectionsConfig, logger *logrus.Logger) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		creditRepo:     creditRepo,
+		accountRepo:    accountRepo,
+		creditService:  creditService,
+		notifier:       notifier,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// GetCollections получает кредит пользователя, его просроченные платежи на сегодня, число дней просрочки
+// и журнал действий по просрочке
+func (s *CollectionService) GetCollections(ctx context.Context, id, userID int64) (
+	*credit.Credit, []*models.PaymentSchedule, int, []*collection.Log, error) {
+	c, err := s.creditService.getOwnedCredit(ctx, id, userID)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+	schedule, err := s.creditRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+	actions, err := s.collectionRepo.GetActions(ctx, id)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+
+	today := truncateDay(time.Now())
+	overdue := overduePayments(schedule, today)
+	return c, overdue, daysPastDue(overdue, today), actions, nil
+}
+
+// Run обрабатывает кредиты с просроченными платежами: переводит их в статус OVERDUE, выполняет этапы эскалации,
+// наступившие по числу дней просрочки, и возвращает в статус ACTIVE кредиты без просрочки. Разовые этапы
+// выполняются один раз за период просрочки, неустойка начисляется один раз за день, а списание с других счетов
+// повторяется при каждом запуске, пока есть просрочка. Предназначен для запуска планировщиком
+func (s *CollectionService) Run(ctx context.Context) error {
+	today := truncateDay(time.Now())
+	credits, err := s.collectionRepo.GetDelinquent(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range credits {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.process(ctx, c, today); err != nil {
+			s.logger.Errorf("Ошибка обработки просрочки по кредиту %d: %v", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// process обрабатывает один кредит: смена статуса и этапы эскалации в порядке дней просрочки
+func (s *CollectionService) process(ctx context.Context, c *credit.Credit, today time.Time) error {
+	schedule, err := s.creditRepo.GetSchedule(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	overdue := overduePayments(schedule, today)
+	if len(overdue) == 0 {
+		return s.clear(ctx, c, today)
+	}
+	dpd := daysPastDue(overdue, today)
+
+	if c.Status == credit.ACTIVE {
+		err := s.collectionRepo.MarkOverdue(ctx, &collection.Log{
+			CreditID:    c.ID,
+			Action:      collection.OVERDUE,
+			DaysPastDue: dpd,
+			ActionDate:  today,
+			Details:     fmt.Sprintf("не оплачен платеж от %s", overdue[0].DueDate.Format("2006-01-02")),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Кредит закрыт или уже переведен в OVERDUE параллельно
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c.Status = credit.OVERDUE
+		c.OverdueSince = &today
+		s.logger.Warnf("Кредит %d переведен в статус OVERDUE: просрочка %d дн.", c.ID, dpd)
+	}
+
+	since := today
+	if c.OverdueSince != nil {
+		since = *c.OverdueSince
+	}
+	recurring := make(map[collection.Action]bool)
+	for _, stage := range s.cfg.Stages {
+		if stage.Days > dpd {
+			break
+		}
+		if stage.Action.Recurring() {
+			// Повторяющееся действие выполняется по самому раннему наступившему этапу
+			if recurring[stage.Action] {
+				continue
+			}
+			recurring[stage.Action] = true
+		} else {
+			done, err := s.collectionRepo.HasAction(ctx, c.ID, stage.Action, stage.Days, since)
+			if err != nil {
+				return err
+			}
+			if done {
+				continue
+			}
+		}
+
+		cleared, err := s.runStage(ctx, c, stage, overdue, dpd, today)
+		if err != nil {
+			s.logger.Errorf("Ошибка этапа %s (день %d) по кредиту %d: %v", stage.Action, stage.Days, c.ID, err)
+			continue
+		}
+		if cleared {
+			return s.clear(ctx, c, today)
+		}
+	}
+	return nil
+}
+
+// runStage выполняет действие этапа эскалации. Возвращает true, если просрочка погашена
+func (s *CollectionService) runStage(ctx context.Context, c *credit.Credit, stage collection.Stage,
+	overdue []*models.PaymentSchedule, dpd int, today time.Time) (bool, error) {
+	l := &collection.Log{
+		CreditID:    c.ID,
+		Action:      stage.Action,
+		StageDays:   stage.Days,
+		DaysPastDue: dpd,
+		ActionDate:  today,
+	}
+	switch stage.Action {
+	case collection.REMINDER:
+		return false, s.remind(ctx, c, overdue, l)
+	case collection.PENALTY:
+		return false, s.accruePenalty(ctx, overdue, l)
+	case collection.SWEEP:
+		return s.sweep(ctx, c, overdue, l)
+	case collection.RESTRICT:
+		return false, s.restrict(ctx, c, l)
+	default:
+		return false, fmt.Errorf("неизвестное действие %q", stage.Action)
+	}
+}
+
+// remind отправляет заемщику напоминание о просрочке. Если отправка не удалась, действие не записывается
+// и повторяется при следующем запуске
+func (s *CollectionService) remind(ctx context.Context, c *credit.Credit, overdue []*models.PaymentSchedule, l *collection.Log) error {
+	email, err := s.creditRepo.GetBorrowerEmail(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	amount := overdueAmount(overdue)
+	subject := fmt.Sprintf("Просроченная задолженность по кредиту №%d", c.ID)
+	body := fmt.Sprintf("Платеж по кредиту №%d от %s не поступил, просрочка составляет %d дн.\n"+
+		"Сумма просроченной задолженности: %s. Пополните счет %d, чтобы платеж был списан.\n",
+		c.ID, overdue[0].DueDate.Format("02.01.2006"), l.DaysPastDue, amount.StringFixed(2), c.AccountID)
+	for _, stage := range s.cfg.Stages {
+		if stage.Action == collection.PENALTY {
+			body += fmt.Sprintf("С %d-го дня просрочки начисляется неустойка %s%% годовых.\n",
+				stage.Days, s.cfg.PenaltyRate.Mul(decimal.NewFromInt(100)).StringFixed(2))
+			break
+		}
+	}
+	if err := s.notifier.Send(ctx, email, subject, body); err != nil {
+		return err
+	}
+
+	l.Amount = &amount
+	l.Details = "напоминание отправлено на " + email
+	return s.collectionRepo.LogAction(ctx, l)
+}
+
+// accruePenalty начисляет неустойку за день: просроченная сумма без ранее начисленной неустойки × ставка / 365
+// с округлением до копеек. Неустойка добавляется к самому раннему просроченному платежу
+func (s *CollectionService) accruePenalty(ctx context.Context, overdue []*models.PaymentSchedule, l *collection.Log) error {
+	base := decimal.Zero
+	for _, p := range overdue {
+		base = base.Add(p.Amount.Sub(p.Penalty))
+	}
+	penalty := base.Mul(s.cfg.PenaltyRate).Div(decimal.NewFromInt(365)).Round(2)
+	if !penalty.IsPositive() {
+		return nil
+	}
+
+	l.Amount = &penalty
+	l.Details = fmt.Sprintf("неустойка %s%% годовых на просроченную сумму %s",
+		s.cfg.PenaltyRate.Mul(decimal.NewFromInt(100)).StringFixed(2), base.StringFixed(2))
+	err := s.collectionRepo.AccruePenalty(ctx, overdue[0].ID, l)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Неустойка за сегодня уже начислена
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	overdue[0].Amount = overdue[0].Amount.Add(penalty)
+	overdue[0].Penalty = overdue[0].Penalty.Add(penalty)
+	return nil
+}
+
+// sweep переводит на счет кредита недостающую для погашения просрочки сумму с других счетов заемщика в той же
+// валюте (только положительный остаток, без овердрафта) и списывает просроченные платежи в порядке дат.
+// Возвращает true, если все просроченные платежи оплачены
+func (s *CollectionService) sweep(ctx context.Context, c *credit.Credit, overdue []*models.PaymentSchedule, l *collection.Log) (bool, error) {
+	acc, err := s.accountRepo.GetAccountByID(ctx, c.AccountID)
+	if err != nil {
+		return false, err
+	}
+
+	need := overdueAmount(overdue).Sub(acc.Balance)
+	if need.IsPositive() {
+		accounts, err := s.accountRepo.GetAccountsByUserID(ctx, acc.UserID)
+		if err != nil {
+			return false, err
+		}
+		for _, from := range accounts {
+			if !need.IsPositive() {
+				break
+			}
+			if from.ID == acc.ID || from.Currency != acc.Currency || !from.Balance.IsPositive() {
+				continue
+			}
+
+			amount := decimal.Min(need, from.Balance)
+			entry := *l
+			entry.Amount = &amount
+			entry.AccountID = &from.ID
+			entry.Details = fmt.Sprintf("списано со счета %d на счет кредита %d", from.ID, acc.ID)
+			if err := s.collectionRepo.Sweep(ctx, acc.ID, &entry); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					// Остаток счета уменьшился после чтения
+					continue
+				}
+				return false, err
+			}
+			s.logger.Infof("По кредиту %d списано %s со счета %d", c.ID, amount, from.ID)
+			need = need.Sub(amount)
+		}
+	}
+
+	for _, p := range overdue {
+		if _, err := s.creditRepo.PayScheduled(ctx, c, p); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// restrict ограничивает расходные операции по всем счетам заемщика и уведомляет его
+func (s *CollectionService) restrict(ctx context.Context, c *credit.Credit, l *collection.Log) error {
+	userID, err := s.collectionRepo.GetBorrowerID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	l.Details = "расходные операции по счетам заемщика ограничены"
+	restricted, err := s.collectionRepo.Restrict(ctx, userID, l)
+	if err != nil {
+		return err
+	}
+	s.logger.Warnf("По кредиту %d ограничены расходные операции по счетам пользователя %d (счетов: %d)", c.ID, userID, restricted)
+
+	email, err := s.creditRepo.GetBorrowerEmail(ctx, c.ID)
+	if err != nil {
+		s.logger.Errorf("Ошибка получения email заемщика по кредиту %d: %v", c.ID, err)
+		return nil
+	}
+	subject := fmt.Sprintf("Ограничение операций по счетам: кредит №%d", c.ID)
+	body := fmt.Sprintf("Просрочка по кредиту №%d составляет %d дн. Расходные операции по вашим счетам ограничены.\n"+
+		"Ограничение будет снято автоматически после погашения просроченной задолженности.\n", c.ID, l.DaysPastDue)
+	if err := s.notifier.Send(ctx, email, subject, body); err != nil {
+		s.logger.Errorf("Ошибка отправки уведомления по кредиту %d: %v", c.ID, err)
+	}
+	return nil
+}
+
+// clear возвращает просроченный кредит без неоплаченных просроченных платежей в статус ACTIVE
+func (s *CollectionService) clear(ctx context.Context, c *credit.Credit, today time.Time) error {
+	if c.Status != credit.OVERDUE {
+		return nil
+	}
+	err := s.collectionRepo.Clear(ctx, c.ID, today)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Кредит закрыт последним платежом или появилась новая просрочка
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.logger.Infof("Просрочка по кредиту %d погашена, кредит переведен в статус ACTIVE", c.ID)
+	return nil
+}
+
+// overduePayments возвращает неоплаченные платежи с датой раньше today в порядке графика
+func overduePayments(schedule []*models.PaymentSchedule, today time.Time) []*models.PaymentSchedule {
+	var overdue []*models.PaymentSchedule
+	for _, p := range schedule {
+		if !p.Paid && p.DueDate.Before(today) {
+			overdue = append(overdue, p)
+		}
+	}
+	return overdue
+}
+
+// daysPastDue возвращает число дней с даты самого раннего просроченного платежа до today
+func daysPastDue(overdue []*models.PaymentSchedule, today time.Time) int {
+	if len(overdue) == 0 {
+		return 0
+	}
+	return int(today.Sub(truncateDay(overdue[0].DueDate)).Hours() / 24)
+}
+
+// overdueAmount возвращает сумму просроченных платежей с неустойкой
+func overdueAmount(overdue []*models.PaymentSchedule) decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range overdue {
+		total = total.Add(p.Amount)
+	}
+	return total
+}
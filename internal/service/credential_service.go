@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/session"
+	"github.com/yujihn/bank_API/internal/models/usertoken"
+	"github.com/yujihn/bank_API/internal/notify"
+	"github.com/yujihn/bank_API/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Ошибки подтверждения email и смены пароля
+var (
+	ErrInvalidUserToken     = apperr.New(apperr.Invalid, apperr.CodeInvalidUserToken, "неверная, просроченная или уже использованная ссылка") // Токен не найден, истек или погашен
+	ErrEmailAlreadyVerified = apperr.New(apperr.Conflict, apperr.CodeEmailAlreadyVerified, "email уже подтвержден")                           // Повторная отправка письма не требуется
+	ErrWrongPassword        = apperr.New(apperr.Forbidden, apperr.CodeWrongPassword, "неверный текущий пароль")                               // Текущий пароль при смене не совпал
+)
+
+// userTokenBytes — длина токена подтверждения email и сброса пароля в байтах до кодирования
+const userTokenBytes = 32
+
+// CredentialService подтверждает email пользователей и управляет паролями: отправляет письма со ссылками
+// подтверждения и сброса пароля, погашает одноразовые токены из ссылок и меняет пароль. После смены
+// или сброса пароля все сессии пользователя завершаются
+type CredentialService struct {
+	tokenRepo   *repository.UserTokenRepository // Репозиторий одноразовых токенов
+	userRepo    repository.UserRepository       // Репозиторий пользователей
+	sessionRepo *repository.SessionRepository   // Репозиторий сессий
+	loginGuard  *LoginGuardService              // Защита входа от подбора пароля
+	notifier    notify.Sender                   // Отправка писем
+	cfg         config.CredentialsConfig        // Параметры ссылок
+	logger      *logrus.Logger                  // Логгер
+}
+
+// NewCredentialService создает новый сервис подтверждения email и управления паролями
+func NewCredentialService(tokenRepo *repository.UserTokenRepository, userRepo repository.UserRepository,
+	sessionRepo *repository.SessionRepository, loginGuard *LoginGuardService, notifier notify.Sender,
+	cfg config.CredentialsConfig, logger *logrus.Logger) *CredentialService {
+	return &CredentialService{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		loginGuard:  loginGuard,
+		notifier:    notifier,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// StartVerification отправляет только что зарегистрированному пользователю письмо со ссылкой подтверждения
+// email. Ошибки только записываются в лог: письмо можно запросить повторно
+func (s *CredentialService) StartVerification(ctx context.Context, userID int64, email string) {
+	if err := s.sendVerification(ctx, userID, email); err != nil {
+		s.logger.Errorf("Ошибка отправки письма подтверждения email пользователю %d: %v", userID, err)
+	}
+}
+
+// ResendVerification повторно отправляет письмо подтверждения email; ссылка из предыдущего письма перестает
+// действовать. Возвращает ErrEmailAlreadyVerified, если email уже подтвержден
+func (s *CredentialService) ResendVerification(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	return s.sendVerification(ctx, user.ID, user.Email)
+}
+
+// VerifyEmail погашает токен из ссылки подтверждения и отмечает email пользователя подтвержденным
+func (s *CredentialService) VerifyEmail(ctx context.Context, token string) error {
+	t, err := s.consume(ctx, usertoken.EMAIL_VERIFICATION, token)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.MarkEmailVerified(ctx, t.UserID)
+}
+
+// ForgotPassword отправляет письмо со ссылкой сброса пароля, если email принадлежит пользователю. Для
+// неизвестного email ошибка не возвращается, чтобы по ответу нельзя было узнать, зарегистрирован ли он
+func (s *CredentialService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, expiresAt, err := s.issue(ctx, user.ID, usertoken.PASSWORD_RESET, s.cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	subject := "Сброс пароля"
+	body := fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке:\n%s\n"+
+		"Ссылка действует до %s UTC и может быть использована один раз.\n"+
+		"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+		s.link("/reset-password", token), expiresAt.UTC().Format("02.01.2006 15:04"))
+	return s.notifier.Send(ctx, user.Email, subject, body)
+}
+
+// ResetPassword погашает токен из ссылки сброса, задает новый пароль и завершает все сессии пользователя.
+// Переход по ссылке из письма подтверждает и email, а ограничения входа по нему снимаются
+func (s *CredentialService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	t, err := s.consume(ctx, usertoken.PASSWORD_RESET, token)
+	if err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetByID(ctx, t.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setPassword(ctx, user.ID, newPassword, session.PASSWORD_RESET); err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.loginGuard.Succeed(ctx, user.Email); err != nil {
+		s.logger.Errorf("Ошибка сброса счетчика неудачных попыток входа пользователя %d: %v", user.ID, err)
+	}
+
+	s.notifyPasswordChanged(ctx, user.ID, user.Email)
+	return nil
+}
+
+// ChangePassword меняет пароль пользователя после проверки текущего и завершает все его сессии, включая
+// текущую. Возвращает ErrWrongPassword, если текущий пароль не совпал
+func (s *CredentialService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if err := s.setPassword(ctx, user.ID, newPassword, session.PASSWORD_CHANGE); err != nil {
+		return err
+	}
+
+	s.notifyPasswordChanged(ctx, user.ID, user.Email)
+	return nil
+}
+
+// Purge удаляет истекшие и использованные токены
+func (s *CredentialService) Purge(ctx context.Context) error {
+	_, err := s.tokenRepo.DeleteExpired(ctx, time.Now())
+	return err
+}
+
+// sendVerification выпускает токен подтверждения email и отправляет письмо со ссылкой
+func (s *CredentialService) sendVerification(ctx context.Context, userID int64, email string) error {
+	token, expiresAt, err := s.issue(ctx, userID, usertoken.EMAIL_VERIFICATION, s.cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	subject := "Подтверждение email"
+	body := fmt.Sprintf("Чтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n"+
+		"Ссылка действует до %s UTC. До подтверждения email операции со счетами недоступны.\n",
+		s.link("/verify-email", token), expiresAt.UTC().Format("02.01.2006 15:04"))
+	return s.notifier.Send(ctx, email, subject, body)
+}
+
+// issue выпускает одноразовый токен с назначением purpose и сроком действия ttl; в базе данных сохраняется
+// только его хеш
+func (s *CredentialService) issue(ctx context.Context, userID int64, purpose usertoken.Purpose,
+	ttl time.Duration) (string, time.Time, error) {
+	token, err := randomToken(userTokenBytes)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	t := &usertoken.Token{
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.Create(ctx, t, hashRefreshToken(token)); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, t.ExpiresAt, nil
+}
+
+// consume погашает одноразовый токен с назначением purpose. Возвращает ErrInvalidUserToken, если токен
+// не найден, истек или уже использован
+func (s *CredentialService) consume(ctx context.Context, purpose usertoken.Purpose, token string) (*usertoken.Token, error) {
+	if token == "" {
+		return nil, ErrInvalidUserToken
+	}
+	t, err := s.tokenRepo.Consume(ctx, purpose, hashRefreshToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidUserToken
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// setPassword сохраняет хеш нового пароля и завершает все сессии пользователя с причиной reason
+func (s *CredentialService) setPassword(ctx context.Context, userID int64, password string, reason session.RevokeReason) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	revoked, err := s.sessionRepo.RevokeAll(ctx, userID, reason)
+	if err != nil {
+		return err
+	}
+	s.logger.WithField("user_id", userID).Infof("Пароль изменен, завершено сессий: %d", revoked)
+	return nil
+}
+
+// notifyPasswordChanged уведомляет пользователя о смене пароля; ошибки отправки только записываются в лог
+func (s *CredentialService) notifyPasswordChanged(ctx context.Context, userID int64, email string) {
+	subject := "Пароль изменен"
+	body := fmt.Sprintf("Пароль вашей учетной записи изменен %s UTC, все сеансы на устройствах завершены.\n"+
+		"Если это были не вы, немедленно восстановите доступ через форму «Забыли пароль?».\n",
+		time.Now().UTC().Format("02.01.2006 15:04"))
+	if err := s.notifier.Send(ctx, email, subject, body); err != nil {
+		s.logger.Errorf("Ошибка отправки уведомления о смене пароля пользователю %d: %v", userID, err)
+	}
+}
+
+// link возвращает ссылку клиентского приложения с токеном в параметре запроса
+func (s *CredentialService) link(path, token string) string {
+	return s.cfg.AppURL + path + "?token=" + url.QueryEscape(token)
+}
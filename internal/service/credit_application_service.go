@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/application"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/scoring"
+)
+
+var (
+	ErrApplicationNotFound = apperr.New(apperr.NotFound, apperr.CodeApplicationNotFound, "кредитная заявка не найдена")             // Заявка не найдена или подана другим пользователем
+	ErrInvalidApplication  = apperr.New(apperr.Invalid, apperr.CodeInvalidApplication, "некорректные параметры кредитной заявки")   // Ошибка в заявленном доходе
+	ErrApplicationState    = apperr.New(apperr.Conflict, apperr.CodeApplicationState, "заявка не одобрена или кредит уже оформлен") // Принятие заявки не в статусе APPROVED
+	ErrApplicationExpired  = apperr.New(apperr.Conflict, apperr.CodeApplicationExpired, "срок действия одобрения истек")            // Одобрение не принято вовремя
+	ErrInvalidDecision     = apperr.New(apperr.Invalid, apperr.CodeInvalidDecision, "некорректное решение по кредитной заявке")     // Решение не APPROVED/REJECTED или без обоснования
+	ErrApplicationReviewed = apperr.New(apperr.Conflict, apperr.CodeApplicationReviewed, "заявка не ожидает ручной проверки")       // Решение по заявке не в статусе MANUAL_REVIEW
+)
+
+// CreditApplicationService принимает кредитные заявки, оценивает их по кредитной истории заявителя
+// и оформляет кредит по одобренной заявке после ее принятия заемщиком
+type CreditApplicationService struct {
+	applicationRepo *repository.CreditApplicationRepository // Репозиторий кредитных заявок
+	creditRepo      *repository.CreditRepository            // Репозиторий кредитов для оценки долговой нагрузки
+	transactionRepo *repository.TransactionRepository       // Репозиторий транзакций для оценки дохода
+	accountService  *AccountService                         // Сервис счетов для проверки владения
+	creditService   *CreditService                          // Сервис кредитов для расчета платежа и оформления
+	kyc             *KYCPolicy                              // Заявки принимаются после подтверждения личности
+	cfg             config.ScoringConfig                    // Параметры скоринга
+	logger          *logrus.Logger                          // Логгер для записи решений
+}
+
+// NewCreditApplicationService создает новый сервис кредитных заявок
+func NewCreditApplicationService(applicationRepo *repository.CreditApplicationRepository, creditRepo *repository.CreditRepository,
+	transactionRepo *repository.TransactionRepository, accountService *AccountService, creditService *CreditService,
+	kyc *KYCPolicy, cfg config.ScoringConfig, logger *logrus.Logger) *CreditApplicationService {
+	return &CreditApplicationService{
+		applicationRepo: applicationRepo,
+		creditRepo:      creditRepo,
+		transactionRepo: transactionRepo,
+		accountService:  accountService,
+		creditService:   creditService,
+		kyc:             kyc,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// Submit принимает заявку и сразу выносит решение: доход подтверждается средними поступлениями на счета
+// за последние SCORING_INCOME_MONTHS месяцев, долговая нагрузка учитывает ближайшие платежи по действующим
+// кредитам, платежная дисциплина — текущую просрочку и платежи, внесенные с опозданием
+func (s *CreditApplicationService) Submit(ctx context.Context, userID int64, req dto.CreateCreditApplicationRequest) (
+	*application.Application, error) {
+	// Кредитные заявки принимаются только после подтверждения личности
+	if err := s.kyc.RequireVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Scheme == "" {
+		req.Scheme = credit.ANNUITY
+	}
+	if req.RateType == "" {
+		req.RateType = credit.FIXED
+	}
+	if !req.DeclaredIncome.IsPositive() {
+		return nil, fmt.Errorf("%w: заявленный доход должен быть положительным", ErrInvalidApplication)
+	}
+
+	payment, err := s.creditService.EstimatePayment(ctx, req.Amount, req.TermMonths, req.Scheme, req.RateType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверка владения счетом
+	if _, err := s.accountService.GetAccountByID(ctx, req.AccountID, userID); err != nil {
+		return nil, err
+	}
+
+	today := truncateDay(time.Now())
+	income, err := s.transactionRepo.GetIncome(ctx, userID, today.AddDate(0, -s.cfg.IncomeMonths, 0))
+	if err != nil {
+		return nil, err
+	}
+	debtPayments, arrears, late, err := s.creditRepo.GetDebtProfile(ctx, userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	in := scoring.Input{
+		DeclaredIncome: req.DeclaredIncome,
+		ObservedIncome: income.Div(decimal.NewFromInt(int64(s.cfg.IncomeMonths))).Round(2),
+		DebtPayments:   debtPayments,
+		Payment:        payment,
+		ArrearsCount:   arrears,
+		LatePayments:   late,
+	}
+	result := scoring.Evaluate(in, scoring.Policy{
+		MaxDebtToIncome:    s.cfg.MaxDebtToIncome,
+		ReviewDebtToIncome: s.cfg.ReviewDebtToIncome,
+		IncomeTolerance:    s.cfg.IncomeTolerance,
+		MaxLatePayments:    s.cfg.MaxLatePayments,
+	})
+
+	created, err := s.applicationRepo.Create(ctx, &application.Application{
+		UserID:         userID,
+		AccountID:      req.AccountID,
+		Amount:         req.Amount,
+		TermMonths:     req.TermMonths,
+		Scheme:         req.Scheme,
+		RateType:       req.RateType,
+		DeclaredIncome: req.DeclaredIncome,
+		ObservedIncome: in.ObservedIncome,
+		DebtPayments:   debtPayments,
+		Payment:        payment,
+		DebtToIncome:   result.DebtToIncome,
+		Status:         result.Status,
+		Reasons:        result.Reasons,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Кредитная заявка %d пользователя %d: %s %v (нагрузка %s)",
+		created.ID, userID, created.Status, created.Reasons, created.DebtToIncome)
+	return created, nil
+}
+
+// GetUserApplications получает все заявки пользователя
+func (s *CreditApplicationService) GetUserApplications(ctx context.Context, userID int64) ([]*application.Application, error) {
+	return s.applicationRepo.GetByUserID(ctx, userID)
+}
+
+// GetApplication получает заявку пользователя
+func (s *CreditApplicationService) GetApplication(ctx context.Context, id, userID int64) (*application.Application, error) {
+	return s.getOwnedApplication(ctx, id, userID)
+}
+
+// GetApplicationsByStatus получает заявки в статусе для администратора (по умолчанию — ожидающие ручной проверки)
+func (s *CreditApplicationService) GetApplicationsByStatus(ctx context.Context, status application.Status) (
+	[]*application.Application, error) {
+	if status == "" {
+		status = application.MANUAL_REVIEW
+	}
+	switch status {
+	case application.APPROVED, application.REJECTED, application.MANUAL_REVIEW, application.ACCEPTED, application.EXPIRED:
+	default:
+		return nil, fmt.Errorf("%w: неизвестный статус %s", ErrInvalidApplication, status)
+	}
+	return s.applicationRepo.GetByStatus(ctx, status)
+}
+
+// Decide выносит ручное решение администратора по заявке в статусе MANUAL_REVIEW: одобрение (APPROVED)
+// или отказ (REJECTED) с обязательным обоснованием
+func (s *CreditApplicationService) Decide(ctx context.Context, id, adminID int64, status application.Status, reason string) (
+	*application.Application, error) {
+	reason = strings.TrimSpace(reason)
+	if status != application.APPROVED && status != application.REJECTED {
+		return nil, fmt.Errorf("%w: допустимы решения APPROVED и REJECTED", ErrInvalidDecision)
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("%w: укажите обоснование решения", ErrInvalidDecision)
+	}
+
+	if _, err := s.applicationRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	decided, err := s.applicationRepo.Decide(ctx, id, status, reason, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrApplicationReviewed
+		}
+		return nil, err
+	}
+	s.logger.Infof("Кредитная заявка %d: ручное решение %s администратора %d (%s)", id, status, adminID, reason)
+	return decided, nil
+}
+
+// Accept оформляет кредит по одобренной заявке на одобренных условиях. Одобрение действует
+// CREDIT_APPLICATION_TTL с момента решения по заявке
+func (s *CreditApplicationService) Accept(ctx context.Context, id, userID int64) (
+	*application.Application, *credit.Credit, []*models.PaymentSchedule, error) {
+	a, err := s.getOwnedApplication(ctx, id, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if a.Status != application.APPROVED {
+		return nil, nil, nil, ErrApplicationState
+	}
+	if err := s.kyc.RequireVerified(ctx, userID); err != nil {
+		return nil, nil, nil, err
+	}
+	if time.Since(a.DecidedAt()) > s.cfg.ApprovalTTL {
+		if err := s.applicationRepo.Expire(ctx, a.ID); err != nil {
+			return nil, nil, nil, err
+		}
+		return nil, nil, nil, ErrApplicationExpired
+	}
+
+	if err := s.applicationRepo.Claim(ctx, a.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil, ErrApplicationState
+		}
+		return nil, nil, nil, err
+	}
+
+	c, schedule, err := s.creditService.Create(ctx, userID, dto.CreateCreditRequest{
+		AccountID:  a.AccountID,
+		Amount:     a.Amount,
+		TermMonths: a.TermMonths,
+		Scheme:     a.Scheme,
+		RateType:   a.RateType,
+	})
+	if err != nil {
+		if releaseErr := s.applicationRepo.Release(ctx, a.ID); releaseErr != nil {
+			s.logger.Errorf("Ошибка возврата заявки %d в статус APPROVED: %v", a.ID, releaseErr)
+		}
+		return nil, nil, nil, err
+	}
+	if err := s.applicationRepo.SetCredit(ctx, a.ID, c.ID); err != nil {
+		s.logger.Errorf("Ошибка привязки кредита %d к заявке %d: %v", c.ID, a.ID, err)
+	}
+
+	accepted, err := s.applicationRepo.GetByID(ctx, a.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return accepted, c, schedule, nil
+}
+
+// getOwnedApplication получает заявку и проверяет, что она подана пользователем
+func (s *CreditApplicationService) getOwnedApplication(ctx context.Context, id, userID int64) (*application.Application, error) {
+	a, err := s.applicationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, ErrApplicationNotFound
+	}
+	return a, nil
+}
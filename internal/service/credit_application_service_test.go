@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yujihn/bank_API/internal/models/application"
+)
+
+// TestDecideValidation проверяет, что ручное решение принимается только как APPROVED/REJECTED с обоснованием
+func TestDecideValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		status application.Status
+		reason string
+	}{
+		{"без статуса", "", "доход подтвержден справкой"},
+		{"недопустимый статус", application.ACCEPTED, "доход подтвержден справкой"},
+		{"без обоснования", application.APPROVED, ""},
+		{"обоснование из пробелов", application.REJECTED, "   "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CreditApplicationService{}
+			if _, err := s.Decide(context.Background(), 1, 1, tt.status, tt.reason); !errors.Is(err, ErrInvalidDecision) {
+				t.Errorf("ошибка %v, ожидается %v", err, ErrInvalidDecision)
+			}
+		})
+	}
+}
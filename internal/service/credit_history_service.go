@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yujihn/bank_API/internal/bureau"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// CreditHistoryService формирует кредитную историю заемщиков для выдачи пользователю и передачи в бюро
+type CreditHistoryService struct {
+	creditRepo     *repository.CreditRepository     // Репозиторий для работы с кредитами
+	collectionRepo *repository.CollectionRepository // Репозиторий журнала работы с просрочкой
+	userRepo       repository.UserRepository        // Репозиторий пользователей
+	bankCfg        config.BankConfig                // Реквизиты банка
+}
+
+// NewCreditHistoryService создает новый сервис кредитных историй
+func NewCreditHistoryService(creditRepo *repository.CreditRepository, collectionRepo *repository.CollectionRepository,
+	userRepo repository.UserRepository, bankCfg config.BankConfig) *CreditHistoryService {
+	return &CreditHistoryService{
+		creditRepo:     creditRepo,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		bankCfg:        bankCfg,
+	}
+}
+
+// Header формирует заголовок отчета с реквизитами банка на текущую дату
+func (s *CreditHistoryService) Header() *bureau.Header {
+	now := time.Now().UTC()
+	return &bureau.Header{
+		BankName:    s.bankCfg.Name,
+		BankBIC:     s.bankCfg.BIC,
+		ReportDate:  truncateDay(now),
+		GeneratedAt: now,
+	}
+}
+
+// Report собирает кредитную историю пользователя на дату reportDate: все его кредиты в порядке оформления
+// с графиками, платежами, корзинами просрочки и сменами статуса
+func (s *CreditHistoryService) Report(ctx context.Context, userID int64, reportDate time.Time) (*bureau.Subject, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credits, err := s.creditRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := &bureau.Subject{
+		UserID:   user.ID,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Credits:  make([]bureau.Credit, 0, len(credits)),
+	}
+	// Кредиты пользователя возвращаются начиная с последних
+	for i := len(credits) - 1; i >= 0; i-- {
+		c := credits[i]
+		schedule, err := s.creditRepo.GetSchedule(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		payments, err := s.creditRepo.GetPayments(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		actions, err := s.collectionRepo.GetActions(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		subject.Credits = append(subject.Credits, bureau.NewCredit(c, schedule, payments, actions, reportDate))
+	}
+	return subject, nil
+}
+
+// Write записывает отчет из одного заемщика subject с заголовком h
+func (s *CreditHistoryService) Write(h *bureau.Header, subject *bureau.Subject, w bureau.Writer) error {
+	if err := w.Begin(h); err != nil {
+		return err
+	}
+	if err := w.Subject(subject); err != nil {
+		return err
+	}
+	var totals bureau.Totals
+	totals.Add(subject)
+	return w.End(totals)
+}
+
+// Export потоково записывает кредитные истории всех заемщиков, у которых есть кредиты, и возвращает итоги
+func (s *CreditHistoryService) Export(ctx context.Context, w bureau.Writer) (bureau.Totals, error) {
+	var totals bureau.Totals
+	ids, err := s.creditRepo.GetBorrowerIDs(ctx)
+	if err != nil {
+		return totals, err
+	}
+
+	h := s.Header()
+	if err := w.Begin(h); err != nil {
+		return totals, err
+	}
+	for _, id := range ids {
+		subject, err := s.Report(ctx, id, h.ReportDate)
+		if err != nil {
+			return totals, err
+		}
+		if err := w.Subject(subject); err != nil {
+			return totals, err
+		}
+		totals.Add(subject)
+	}
+	return totals, w.End(totals)
+}
@@ -0,0 +1,640 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/calendar"
+	"github.com/yujihn/bank_API/internal/cbr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/loan"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/credit"
+	"github.com/yujihn/bank_API/internal/notify"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrCreditNotFound      = apperr.New(apperr.NotFound, apperr.CodeCreditNotFound, "кредит не найден")                                   // Кредит не найден или оформлен на счет другого пользователя
+	ErrInvalidCredit       = apperr.New(apperr.Invalid, apperr.CodeInvalidCredit, "некорректные параметры кредита")                       // Ошибка в сумме или сроке кредита
+	ErrCreditState         = apperr.New(apperr.Conflict, apperr.CodeCreditState, "операция недоступна в текущем статусе кредита")         // Кредит закрыт или просрочен
+	ErrCreditArrears       = apperr.New(apperr.Conflict, apperr.CodeCreditArrears, "по кредиту есть просроченные платежи")                // Досрочное погашение при непогашенной просрочке
+	ErrInvalidPrepayment   = apperr.New(apperr.Invalid, apperr.CodeInvalidPrepayment, "некорректные параметры досрочного погашения")      // Ошибка в сумме или способе досрочного погашения
+	ErrCreditScheduleStale = apperr.New(apperr.Conflict, apperr.CodeCreditScheduleStale, "график платежей изменился, повторите операцию") // Одновременное изменение графика
+	ErrKeyRateUnavailable  = apperr.New(apperr.Unavailable, apperr.CodeKeyRateUnavailable, "ключевая ставка ЦБ РФ недоступна")            // Нет сохраненной ставки и ЦБ РФ не отвечает
+)
+
+// CreditService оформляет кредиты с аннуитетным или дифференцированным графиком платежей,
+// рассчитывает графики без оформления кредита, выполняет досрочное погашение, списывает платежи по графику
+// и пересматривает плавающие ставки при изменении ключевой ставки ЦБ РФ.
+// Даты платежей, выпадающие на нерабочие дни, переносятся на следующий рабочий день
+type CreditService struct {
+	creditRepo     *repository.CreditRepository  // Репозиторий кредитов
+	keyRateRepo    *repository.KeyRateRepository // Сохраненные значения ключевой ставки
+	accountService *AccountService               // Сервис счетов для проверки владения
+	cbrClient      *cbr.Client                   // Клиент ЦБ РФ для получения ключевой ставки
+	notifier       notify.Sender                 // Отправка уведомлений заемщикам
+	calendar       *calendar.Calendar            // Производственный календарь
+	cfg            config.CreditConfig           // Параметры кредитов
+	logger         *logrus.Logger                // Логгер для фоновых задач
+}
+
+// NewCreditService создает новый сервис кредитов
+func NewCreditService(creditRepo *repository.CreditRepository, keyRateRepo *repository.KeyRateRepository,
+	accountService *AccountService, cbrClient *cbr.Client, notifier notify.Sender, cal *calendar.Calendar,
+	cfg config.CreditConfig, logger *logrus.Logger) *CreditService {
+	return &CreditService{
+		creditRepo:     creditRepo,
+		keyRateRepo:    keyRateRepo,
+		accountService: accountService,
+		cbrClient:      cbrClient,
+		notifier:       notifier,
+		calendar:       cal,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// Create оформляет кредит на счет пользователя по принятой кредитной заявке: сумма зачисляется на счет,
+// график строится по выбранной схеме (по умолчанию аннуитетной) с ежемесячными платежами в день выдачи. Плавающая ставка равна ключевой ставке
+// ЦБ РФ плюс надбавка CREDIT_KEY_RATE_MARGIN; надбавка фиксируется в договоре
+func (s *CreditService) Create(ctx context.Context, userID int64, req dto.CreateCreditRequest) (*credit.Credit, []*models.PaymentSchedule, error) {
+	if req.Scheme == "" {
+		req.Scheme = credit.ANNUITY
+	}
+	if req.RateType == "" {
+		req.RateType = credit.FIXED
+	}
+	if err := s.validateTerms(req.Amount, req.TermMonths, req.Scheme); err != nil {
+		return nil, nil, err
+	}
+	if !req.RateType.Valid() {
+		return nil, nil, fmt.Errorf("%w: неизвестный вид ставки %q", ErrInvalidCredit, req.RateType)
+	}
+
+	// Проверка владения счетом
+	if _, err := s.accountService.GetAccountByID(ctx, req.AccountID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	start := truncateDay(time.Now())
+	rate, change, err := s.currentRate(ctx, req.RateType, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &credit.Credit{
+		AccountID:    req.AccountID,
+		Principal:    req.Amount,
+		InterestRate: rate.InexactFloat64(),
+		TermMonths:   req.TermMonths,
+		Scheme:       req.Scheme,
+		RateType:     req.RateType,
+		StartDate:    start,
+	}
+	if change != nil {
+		c.RateMargin = &change.Margin
+	}
+	installments := buildSchedule(req.Scheme, req.Amount, rate, req.TermMonths, start, start.Day())
+	s.rollDueDates(installments)
+	schedule := toPaymentSchedule(installments)
+	if change != nil {
+		change.Payment = schedule[0].Amount
+	}
+
+	created, err := s.creditRepo.Create(ctx, c, schedule, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := s.creditRepo.GetSchedule(ctx, created.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, stored, nil
+}
+
+// EstimatePayment проверяет параметры кредита и рассчитывает первый ежемесячный платеж по текущей ставке.
+// Для дифференцированной схемы первый платеж — наибольший
+func (s *CreditService) EstimatePayment(ctx context.Context, amount decimal.Decimal, termMonths int,
+	scheme credit.Scheme, rateType credit.RateType) (decimal.Decimal, error) {
+	if err := s.validateTerms(amount, termMonths, scheme); err != nil {
+		return decimal.Zero, err
+	}
+	if !rateType.Valid() {
+		return decimal.Zero, fmt.Errorf("%w: неизвестный вид ставки %q", ErrInvalidCredit, rateType)
+	}
+
+	start := truncateDay(time.Now())
+	rate, _, err := s.currentRate(ctx, rateType, start)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return buildSchedule(scheme, amount, rate, termMonths, start, start.Day())[0].Payment, nil
+}
+
+// Calculate рассчитывает график платежей, переплату и полную стоимость кредита без его оформления.
+// Если ставка или дата выдачи не указаны, используются текущая ставка банка и текущий день
+func (s *CreditService) Calculate(req dto.CreditCalculationRequest) (*dto.CreditCalculationResponse, error) {
+	if req.Scheme == "" {
+		req.Scheme = credit.ANNUITY
+	}
+	if err := s.validateTerms(req.Amount, req.TermMonths, req.Scheme); err != nil {
+		return nil, err
+	}
+
+	rate := s.cfg.Rate
+	if req.Rate != nil {
+		rate = *req.Rate
+	}
+	if rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("%w: ставка должна быть от 0 до 1", ErrInvalidCredit)
+	}
+
+	start := truncateDay(time.Now())
+	if req.StartDate != "" {
+		date, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: неверный формат даты выдачи", ErrInvalidCredit)
+		}
+		start = date
+	}
+
+	installments := buildSchedule(req.Scheme, req.Amount, rate, req.TermMonths, start, start.Day())
+	s.rollDueDates(installments)
+	psk, effective := loan.PSK(req.Amount, installments)
+
+	resp := &dto.CreditCalculationResponse{
+		Amount:        req.Amount,
+		Rate:          rate,
+		TermMonths:    req.TermMonths,
+		Scheme:        req.Scheme,
+		StartDate:     start.Format("2006-01-02"),
+		TotalPayment:  decimal.Zero,
+		TotalInterest: decimal.Zero,
+		PSK:           psk,
+		EffectiveRate: effective,
+		Schedule:      make([]dto.CalculatedPaymentResponse, 0, len(installments)),
+	}
+	for i, in := range installments {
+		resp.TotalPayment = resp.TotalPayment.Add(in.Payment)
+		resp.TotalInterest = resp.TotalInterest.Add(in.Interest)
+		resp.Schedule = append(resp.Schedule, dto.CalculatedPaymentResponse{
+			Number:    i + 1,
+			DueDate:   in.DueDate.Format("2006-01-02"),
+			Payment:   in.Payment,
+			Principal: in.Principal,
+			Interest:  in.Interest,
+			Balance:   in.Balance,
+		})
+	}
+	return resp, nil
+}
+
+// GetUserCredits получает все кредиты пользователя с графиками платежей
+func (s *CreditService) GetUserCredits(ctx context.Context, userID int64) ([]*credit.Credit, map[int64][]*models.PaymentSchedule, error) {
+	credits, err := s.creditRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedules := make(map[int64][]*models.PaymentSchedule, len(credits))
+	for _, c := range credits {
+		schedule, err := s.creditRepo.GetSchedule(ctx, c.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		schedules[c.ID] = schedule
+	}
+	return credits, schedules, nil
+}
+
+// GetCredit получает кредит, его график и внесенные платежи с проверкой владения
+func (s *CreditService) GetCredit(ctx context.Context, id, userID int64) (*credit.Credit, []*models.PaymentSchedule, []*credit.Payment, error) {
+	c, err := s.getOwnedCredit(ctx, id, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	schedule, err := s.creditRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	payments, err := s.creditRepo.GetPayments(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return c, schedule, payments, nil
+}
+
+// Prepay выполняет досрочное погашение кредита со счета кредита.
+//
+// При частичном погашении вся сумма направляется в погашение основного долга, а неоплаченная часть графика
+// пересчитывается по схеме кредита от нового остатка: REDUCE_PAYMENT сохраняет количество оставшихся
+// платежей и уменьшает платеж, REDUCE_TERM сохраняет платеж (для дифференцированной схемы — часть основного
+// долга) и сокращает срок. Проценты за текущий период начисляются в ближайшем платеже на уменьшенный остаток.
+//
+// При полном погашении (FULL) списывается остаток основного долга и проценты, начисленные с даты последнего
+// платежа по графику (ACT/365), неоплаченные платежи удаляются из графика, кредит закрывается
+func (s *CreditService) Prepay(ctx context.Context, id, userID int64, req dto.PrepayCreditRequest) (
+	*credit.Credit, *credit.Payment, []*models.PaymentSchedule, error) {
+	if !req.Mode.Valid() {
+		return nil, nil, nil, fmt.Errorf("%w: неизвестный способ погашения %q", ErrInvalidPrepayment, req.Mode)
+	}
+
+	c, err := s.getOwnedCredit(ctx, id, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if c.Status != credit.ACTIVE {
+		return nil, nil, nil, ErrCreditState
+	}
+	schedule, err := s.creditRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	today := truncateDay(time.Now())
+	paid := 0
+	outstanding := decimal.Zero
+	var unpaid []*models.PaymentSchedule
+	for _, p := range schedule {
+		if p.Paid {
+			paid++
+			continue
+		}
+		if p.DueDate.Before(today) {
+			return nil, nil, nil, ErrCreditArrears
+		}
+		outstanding = outstanding.Add(p.Principal)
+		unpaid = append(unpaid, p)
+	}
+	if len(unpaid) == 0 {
+		return nil, nil, nil, ErrCreditState
+	}
+
+	// Даты графика перенесены с нерабочих дней, поэтому пересчет ведется от исходной даты последнего платежа
+	lastDue := c.StartDate
+	if paid > 0 {
+		lastDue = loan.DueDate(c.StartDate, paid, c.StartDate.Day())
+	}
+
+	rate := decimal.NewFromFloat(c.InterestRate)
+	payment := &credit.Payment{CreditID: c.ID, Interest: decimal.Zero}
+	var replacement []*models.PaymentSchedule
+	closeCredit := false
+
+	switch req.Mode {
+	case credit.FULL:
+		payment.Kind = credit.EARLY_CLOSURE
+		payment.Principal = outstanding
+		payment.Interest = loan.AccruedInterest(outstanding, rate, lastDue, today)
+		payment.Amount = outstanding.Add(payment.Interest)
+		closeCredit = true
+
+	default:
+		if !req.Amount.IsPositive() {
+			return nil, nil, nil, ErrNegativeAmount
+		}
+		if req.Amount.GreaterThanOrEqual(outstanding) {
+			return nil, nil, nil, fmt.Errorf("%w: сумма не меньше остатка долга %s, используйте полное погашение",
+				ErrInvalidPrepayment, outstanding)
+		}
+		payment.Kind = credit.PREPAYMENT
+		payment.Principal = req.Amount
+		payment.Amount = req.Amount
+
+		remaining := outstanding.Sub(req.Amount)
+		var installments []loan.Installment
+		switch {
+		case req.Mode == credit.REDUCE_PAYMENT:
+			installments = buildSchedule(c.Scheme, remaining, rate, len(unpaid), lastDue, c.StartDate.Day())
+		case c.Scheme == credit.DIFFERENTIATED:
+			// Количество платежей, за которое остаток гасится прежними частями основного долга
+			months := int(remaining.Div(unpaid[0].Principal).Ceil().IntPart())
+			installments = loan.Differentiated(remaining, rate, months, lastDue, c.StartDate.Day())
+		default:
+			installments, err = loan.FixedPayment(remaining, rate, unpaid[0].Amount, lastDue, c.StartDate.Day())
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		s.rollDueDates(installments)
+		replacement = toPaymentSchedule(installments)
+	}
+
+	updated, err := s.creditRepo.Prepay(ctx, c, outstanding, payment, replacement, closeCredit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Недостаток средств проверяется после блокировки кредита; отличаем его по текущему балансу
+		acc, accErr := s.accountService.GetAccountByID(ctx, c.AccountID, userID)
+		if accErr == nil && acc.Balance.LessThan(payment.Amount) {
+			return nil, nil, nil, ErrInsufficientFunds
+		}
+		return nil, nil, nil, ErrCreditScheduleStale
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	stored, err := s.creditRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return updated, payment, stored, nil
+}
+
+// CollectDue списывает со счетов кредитов платежи по графику, дата которых наступила. Списание выполняется
+// только в рабочие дни; платежи по одному кредиту списываются по порядку, и при недостатке средств
+// оставшиеся платежи этого кредита ждут следующего запуска. Предназначен для запуска планировщиком
+func (s *CreditService) CollectDue(ctx context.Context) error {
+	today := truncateDay(time.Now())
+	if !s.calendar.IsBusinessDay(today) {
+		return nil
+	}
+	due, err := s.creditRepo.GetDuePayments(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	var c *credit.Credit
+	skip := int64(0)
+	for _, p := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if p.CreditID == skip {
+			continue
+		}
+		if c == nil || c.ID != p.CreditID {
+			if c, err = s.creditRepo.GetByID(ctx, p.CreditID); err != nil {
+				s.logger.Errorf("Ошибка получения кредита %d: %v", p.CreditID, err)
+				skip = p.CreditID
+				continue
+			}
+		}
+
+		if _, err := s.creditRepo.PayScheduled(ctx, c, p); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				s.logger.Warnf("Платеж %d по кредиту %d от %s не списан: недостаточно средств на счете %d",
+					p.ID, c.ID, p.DueDate.Format("2006-01-02"), c.AccountID)
+			} else {
+				s.logger.Errorf("Ошибка списания платежа %d по кредиту %d: %v", p.ID, c.ID, err)
+			}
+			skip = c.ID
+		}
+	}
+	return nil
+}
+
+// GetRateHistory получает кредит пользователя и историю его ставки
+func (s *CreditService) GetRateHistory(ctx context.Context, id, userID int64) (*credit.Credit, []*credit.RateChange, error) {
+	c, err := s.getOwnedCredit(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := s.creditRepo.GetRateHistory(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, history, nil
+}
+
+// ResetFloatingRates запрашивает ключевую ставку ЦБ РФ, сохраняет ее при изменении и пересматривает ставку
+// по активным кредитам с плавающей ставкой, если она отличается от ключевой ставки плюс надбавка.
+// Кредит, который не удалось пересчитать, пересчитывается при следующем запуске. Предназначен для запуска планировщиком
+func (s *CreditService) ResetFloatingRates(ctx context.Context) error {
+	keyRate, err := s.cbrClient.KeyRate(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("получение ключевой ставки: %w", err)
+	}
+	latest, err := s.keyRateRepo.GetLatest(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if latest == nil || !latest.Rate.Equal(keyRate) {
+		s.logger.Infof("Ключевая ставка ЦБ РФ: %s%%", keyRate)
+		if _, err := s.keyRateRepo.Save(ctx, keyRate); err != nil {
+			return err
+		}
+	}
+
+	credits, err := s.creditRepo.GetActiveByRateType(ctx, credit.FLOATING)
+	if err != nil {
+		return err
+	}
+	for _, c := range credits {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.reprice(ctx, c, keyRate); err != nil {
+			s.logger.Errorf("Ошибка пересмотра ставки по кредиту %d: %v", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// reprice пересчитывает неоплаченную часть графика кредита по новой ставке со следующего периода.
+// Проценты текущего периода и просроченных платежей уже начислены по прежней ставке, поэтому эти платежи
+// сохраняются, а остальные заменяются графиком по схеме кредита на оставшийся долг и прежнее число платежей
+func (s *CreditService) reprice(ctx context.Context, c *credit.Credit, keyRate decimal.Decimal) error {
+	if c.RateMargin == nil {
+		return fmt.Errorf("%w: не задана надбавка к ключевой ставке", ErrCreditState)
+	}
+	rate := floatingRate(keyRate, *c.RateMargin)
+	if rate.Equal(decimal.NewFromFloat(c.InterestRate)) {
+		return nil
+	}
+	if !rate.IsPositive() {
+		return fmt.Errorf("%w: ключевая ставка %s%%, надбавка %s", ErrInvalidCredit, keyRate, *c.RateMargin)
+	}
+
+	schedule, err := s.creditRepo.GetSchedule(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	today := truncateDay(time.Now())
+	position := 0 // Номер последнего оплаченного или сохраняемого платежа
+	outstanding, kept := decimal.Zero, decimal.Zero
+	var current *models.PaymentSchedule
+	var replaced []int64
+	for _, p := range schedule {
+		if p.Paid {
+			position++
+			continue
+		}
+		outstanding = outstanding.Add(p.Principal)
+		if current != nil {
+			replaced = append(replaced, p.ID)
+			continue
+		}
+		kept = kept.Add(p.Principal)
+		position++
+		if !p.DueDate.Before(today) {
+			current = p
+		}
+	}
+	if current == nil {
+		// Все неоплаченные платежи просрочены: пересчитывать нечего
+		return nil
+	}
+
+	change := &credit.RateChange{
+		EffectiveDate: current.DueDate,
+		KeyRate:       keyRate,
+		Margin:        *c.RateMargin,
+		Rate:          rate,
+		Payment:       current.Amount,
+	}
+	var replacement []*models.PaymentSchedule
+	if len(replaced) > 0 {
+		// Пересчет ведется от исходной даты последнего сохраняемого платежа
+		start := loan.DueDate(c.StartDate, position, c.StartDate.Day())
+		installments := buildSchedule(c.Scheme, outstanding.Sub(kept), rate, len(replaced), start, c.StartDate.Day())
+		s.rollDueDates(installments)
+		replacement = toPaymentSchedule(installments)
+		change.Payment = replacement[0].Amount
+	}
+
+	if err := s.creditRepo.Reprice(ctx, c, outstanding, replaced, replacement, change); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCreditScheduleStale
+		}
+		return err
+	}
+	s.logger.Infof("Ставка по кредиту %d изменена на %s с %s", c.ID, rate, change.EffectiveDate.Format("2006-01-02"))
+	s.notifyRateChange(ctx, c, change)
+	return nil
+}
+
+// notifyRateChange сообщает заемщику новую ставку и ежемесячный платеж; ошибка отправки только логируется
+func (s *CreditService) notifyRateChange(ctx context.Context, c *credit.Credit, change *credit.RateChange) {
+	email, err := s.creditRepo.GetBorrowerEmail(ctx, c.ID)
+	if err != nil {
+		s.logger.Errorf("Ошибка получения email заемщика по кредиту %d: %v", c.ID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("Изменение ставки по кредиту №%d", c.ID)
+	body := fmt.Sprintf("Ключевая ставка Банка России изменилась и составляет %s%% годовых.\n"+
+		"Ставка по кредиту №%d после платежа %s составит %s%% годовых (ключевая ставка плюс %s%%).\n"+
+		"Новый ежемесячный платеж: %s.\n",
+		change.KeyRate.StringFixed(2), c.ID, change.EffectiveDate.Format("02.01.2006"),
+		change.Rate.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		change.Margin.Mul(decimal.NewFromInt(100)).StringFixed(2), change.Payment.StringFixed(2))
+	if err := s.notifier.Send(ctx, email, subject, body); err != nil {
+		s.logger.Errorf("Ошибка отправки уведомления по кредиту %d: %v", c.ID, err)
+	}
+}
+
+// currentRate возвращает ставку для нового кредита с видом ставки rateType, выдаваемого в день start.
+// Для плавающей ставки также возвращается начальная запись истории ставки
+func (s *CreditService) currentRate(ctx context.Context, rateType credit.RateType, start time.Time) (
+	decimal.Decimal, *credit.RateChange, error) {
+	if rateType != credit.FLOATING {
+		return s.cfg.Rate, nil, nil
+	}
+
+	keyRate, err := s.keyRate(ctx)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+	rate := floatingRate(keyRate, s.cfg.KeyRateMargin)
+	if !rate.IsPositive() {
+		return decimal.Zero, nil, fmt.Errorf("%w: ключевая ставка %s%%, надбавка %s", ErrInvalidCredit, keyRate, s.cfg.KeyRateMargin)
+	}
+	return rate, &credit.RateChange{EffectiveDate: start, KeyRate: keyRate, Margin: s.cfg.KeyRateMargin, Rate: rate}, nil
+}
+
+// keyRate возвращает последнюю сохраненную ключевую ставку в процентах; если сохраненной нет,
+// запрашивает ее у ЦБ РФ и сохраняет
+func (s *CreditService) keyRate(ctx context.Context) (decimal.Decimal, error) {
+	latest, err := s.keyRateRepo.GetLatest(ctx)
+	if err == nil {
+		return latest.Rate, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return decimal.Zero, err
+	}
+
+	rate, err := s.cbrClient.KeyRate(ctx, time.Now())
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %v", ErrKeyRateUnavailable, err)
+	}
+	if _, err := s.keyRateRepo.Save(ctx, rate); err != nil {
+		return decimal.Zero, err
+	}
+	return rate, nil
+}
+
+// floatingRate рассчитывает плавающую ставку (доля) по ключевой ставке в процентах и надбавке,
+// с точностью хранения ставки кредита
+func floatingRate(keyRate, margin decimal.Decimal) decimal.Decimal {
+	return keyRate.Div(decimal.NewFromInt(100)).Add(margin).Round(4)
+}
+
+// getOwnedCredit получает кредит и проверяет, что счет кредита принадлежит пользователю
+func (s *CreditService) getOwnedCredit(ctx context.Context, id, userID int64) (*credit.Credit, error) {
+	c, err := s.creditRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCreditNotFound
+		}
+		return nil, err
+	}
+	if _, err := s.accountService.GetAccountByID(ctx, c.AccountID, userID); err != nil {
+		if errors.Is(err, ErrAccountNotOwned) || errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCreditNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// validateTerms проверяет сумму, срок и схему погашения кредита
+func (s *CreditService) validateTerms(amount decimal.Decimal, termMonths int, scheme credit.Scheme) error {
+	if !amount.IsPositive() || amount.GreaterThan(s.cfg.MaxAmount) {
+		return fmt.Errorf("%w: сумма должна быть от 0 до %s", ErrInvalidCredit, s.cfg.MaxAmount)
+	}
+	if termMonths < 1 || termMonths > s.cfg.MaxTermMonths {
+		return fmt.Errorf("%w: срок должен быть от 1 до %d месяцев", ErrInvalidCredit, s.cfg.MaxTermMonths)
+	}
+	if !scheme.Valid() {
+		return fmt.Errorf("%w: неизвестная схема погашения %q", ErrInvalidCredit, scheme)
+	}
+	return nil
+}
+
+// buildSchedule строит график платежей по схеме погашения
+func buildSchedule(scheme credit.Scheme, principal, rate decimal.Decimal, months int, start time.Time, dayOfMonth int) []loan.Installment {
+	if scheme == credit.DIFFERENTIATED {
+		return loan.Differentiated(principal, rate, months, start, dayOfMonth)
+	}
+	return loan.Annuity(principal, rate, months, start, dayOfMonth)
+}
+
+// rollDueDates переносит даты платежей, выпадающие на нерабочие дни, на следующий рабочий день
+func (s *CreditService) rollDueDates(installments []loan.Installment) {
+	for i := range installments {
+		installments[i].DueDate = s.calendar.NextBusinessDay(installments[i].DueDate)
+	}
+}
+
+// toPaymentSchedule преобразует рассчитанный график в строки payment_schedules
+func toPaymentSchedule(installments []loan.Installment) []*models.PaymentSchedule {
+	schedule := make([]*models.PaymentSchedule, 0, len(installments))
+	for _, in := range installments {
+		schedule = append(schedule, &models.PaymentSchedule{
+			DueDate:   in.DueDate,
+			Amount:    in.Payment,
+			Principal: in.Principal,
+			Interest:  in.Interest,
+		})
+	}
+	return schedule
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/cbr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/models/deposit"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrDepositNotFound = apperr.New(apperr.NotFound, apperr.CodeDepositNotFound, "вклад не найден")                                  // Вклад не найден или принадлежит другому пользователю
+	ErrInvalidDeposit  = apperr.New(apperr.Invalid, apperr.CodeInvalidDeposit, "некорректные параметры вклада")                      // Ошибка в сумме, сроке или действии по окончании
+	ErrDepositClosed   = apperr.New(apperr.Conflict, apperr.CodeDepositClosed, "вклад уже закрыт")                                   // Операция над закрытым вкладом
+	ErrDepositRate     = apperr.New(apperr.Unavailable, apperr.CodeDepositRateUnavailable, "не удалось определить ставку по вкладу") // Рассчитанная ставка неположительна
+)
+
+// DepositService открывает срочные вклады, закрывает их досрочно и обрабатывает окончание срока
+type DepositService struct {
+	depositRepo    *repository.DepositRepository // Репозиторий вкладов
+	accountService *AccountService               // Сервис счетов для проверки владения
+	cbrClient      *cbr.Client                   // Клиент ЦБ РФ для получения ключевой ставки
+	cfg            config.DepositConfig          // Параметры вкладов
+	logger         *logrus.Logger                // Логгер для фоновой обработки
+}
+
+// NewDepositService создает новый сервис срочных вкладов
+func NewDepositService(depositRepo *repository.DepositRepository, accountService *AccountService, cbrClient *cbr.Client,
+	cfg config.DepositConfig, logger *logrus.Logger) *DepositService {
+	return &DepositService{
+		depositRepo:    depositRepo,
+		accountService: accountService,
+		cbrClient:      cbrClient,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// CurrentRate возвращает ставку, по которой сейчас открываются вклады, и ее источник.
+// Если ставка рассчитывается от ключевой, а ЦБ РФ недоступен, используется фиксированная ставка
+func (s *DepositService) CurrentRate(ctx context.Context) (decimal.Decimal, deposit.RateSource, error) {
+	if !s.cfg.UseKeyRate {
+		return s.cfg.Rate, deposit.FIXED, nil
+	}
+
+	keyRate, err := s.cbrClient.KeyRate(ctx, time.Now())
+	if err != nil {
+		s.logger.Warnf("Ключевая ставка недоступна, применяется фиксированная ставка по вкладам: %v", err)
+		return s.cfg.Rate, deposit.FIXED, nil
+	}
+
+	rate := keyRate.Div(decimal.NewFromInt(100)).Add(s.cfg.KeyRateMargin)
+	if !rate.IsPositive() {
+		return decimal.Zero, "", fmt.Errorf("%w: ключевая ставка %s%%, надбавка %s", ErrDepositRate, keyRate, s.cfg.KeyRateMargin)
+	}
+	return rate, deposit.KEY_RATE, nil
+}
+
+// PenaltyRate возвращает ставку досрочного закрытия для новых вкладов
+func (s *DepositService) PenaltyRate() decimal.Decimal {
+	return s.cfg.PenaltyRate
+}
+
+// Open открывает вклад, списывая сумму со счета пользователя
+func (s *DepositService) Open(ctx context.Context, userID int64, req dto.OpenDepositRequest) (*deposit.Deposit, error) {
+	if req.Amount.LessThan(s.cfg.MinAmount) {
+		return nil, fmt.Errorf("%w: минимальная сумма вклада %s", ErrInvalidDeposit, s.cfg.MinAmount)
+	}
+	if req.TermMonths < 1 || req.TermMonths > s.cfg.MaxTermMonths {
+		return nil, fmt.Errorf("%w: срок должен быть от 1 до %d месяцев", ErrInvalidDeposit, s.cfg.MaxTermMonths)
+	}
+	if req.MaturityAction == "" {
+		req.MaturityAction = deposit.PAYOUT
+	}
+	if !req.MaturityAction.Valid() {
+		return nil, fmt.Errorf("%w: неизвестное действие по окончании срока %q", ErrInvalidDeposit, req.MaturityAction)
+	}
+
+	// Проверка владения счетом и достаточности средств
+	acc, err := s.accountService.GetAccountByID(ctx, req.AccountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Restricted {
+		return nil, ErrAccountRestricted
+	}
+	if acc.Balance.LessThan(req.Amount) {
+		return nil, ErrInsufficientFunds
+	}
+
+	rate, source, err := s.CurrentRate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := truncateDay(time.Now())
+	created, err := s.depositRepo.Open(ctx, &deposit.Deposit{
+		UserID:         userID,
+		AccountID:      req.AccountID,
+		Principal:      req.Amount,
+		Rate:           rate,
+		RateSource:     source,
+		PenaltyRate:    s.cfg.PenaltyRate,
+		TermMonths:     req.TermMonths,
+		MaturityAction: req.MaturityAction,
+		StartDate:      start,
+		MaturityDate:   start.AddDate(0, req.TermMonths, 0),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Баланс изменился между проверкой и списанием
+		return nil, ErrInsufficientFunds
+	}
+	return created, err
+}
+
+// GetUserDeposits получает все вклады пользователя
+func (s *DepositService) GetUserDeposits(ctx context.Context, userID int64) ([]*deposit.Deposit, error) {
+	return s.depositRepo.GetByUserID(ctx, userID)
+}
+
+// GetDeposit получает вклад с проверкой владения
+func (s *DepositService) GetDeposit(ctx context.Context, id, userID int64) (*deposit.Deposit, error) {
+	d, err := s.depositRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDepositNotFound
+		}
+		return nil, err
+	}
+	if d.UserID != userID {
+		return nil, ErrDepositNotFound
+	}
+	return d, nil
+}
+
+// CloseEarly закрывает вклад до окончания срока: проценты за текущий срок пересчитываются по ставке
+// досрочного закрытия, сумма вклада и проценты возвращаются на счет. Если срок уже закончился,
+// вклад закрывается с процентами по ставке вклада
+func (s *DepositService) CloseEarly(ctx context.Context, id, userID int64) (*deposit.Deposit, error) {
+	d, err := s.GetDeposit(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != deposit.ACTIVE {
+		return nil, ErrDepositClosed
+	}
+
+	today := truncateDay(time.Now())
+	status, interest := deposit.CLOSED_EARLY, d.Interest(d.PenaltyRate, today)
+	if !d.MaturityDate.After(today) {
+		status, interest = deposit.CLOSED, d.Interest(d.Rate, d.MaturityDate)
+	}
+
+	closed, err := s.depositRepo.Close(ctx, d, status, interest)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDepositClosed
+	}
+	return closed, err
+}
+
+// MatureDue обрабатывает вклады, срок которых закончился: выплачивает их на счет или продлевает.
+// Предназначен для запуска планировщиком
+func (s *DepositService) MatureDue(ctx context.Context) error {
+	today := truncateDay(time.Now())
+	deposits, err := s.depositRepo.GetMatured(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deposits {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.mature(ctx, d, today); err != nil {
+			s.logger.Errorf("Ошибка обработки окончания срока вклада %d: %v", d.ID, err)
+		}
+	}
+	return nil
+}
+
+// mature выплачивает вклад или продлевает его на новые сроки, пока дата окончания не окажется в будущем
+func (s *DepositService) mature(ctx context.Context, d *deposit.Deposit, today time.Time) error {
+	for !d.MaturityDate.After(today) {
+		interest := d.Interest(d.Rate, d.MaturityDate)
+
+		if d.MaturityAction == deposit.PAYOUT {
+			_, err := s.depositRepo.Close(ctx, d, deposit.CLOSED, interest)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Вклад закрыт параллельно
+				return nil
+			}
+			return err
+		}
+
+		rate, source, err := s.CurrentRate(ctx)
+		if err != nil {
+			return err
+		}
+		next, err := s.depositRepo.Rollover(ctx, d, interest, rate, source,
+			d.MaturityDate, d.MaturityDate.AddDate(0, d.TermMonths, 0))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d = next
+	}
+	return nil
+}
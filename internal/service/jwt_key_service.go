@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/keyset"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// Ошибки работы с ключами подписи JWT
+var (
+	ErrNoSigningKey = errors.New("нет действующего ключа подписи JWT") // Ключи еще не загружены
+	ErrUnknownKey   = errors.New("неизвестный ключ подписи JWT")       // Токен подписан ключом не из набора
+)
+
+// keyReloadCooldown ограничивает внеплановую загрузку ключей при проверке токена с неизвестным kid
+const keyReloadCooldown = 10 * time.Second
+
+// JWTKeyService управляет ключами подписи JWT: создает и ротирует их по расписанию, удаляет истекшие
+// и синхронизирует набор ключей с базой данных, чтобы все экземпляры сервиса проверяли токены друг друга
+type JWTKeyService struct {
+	keyRepo  *repository.JWTKeyRepository // Репозиторий ключей подписи
+	keys     *keyset.Keyset               // Набор ключей в памяти
+	jwtCfg   config.JWTConfig             // Конфигурация JWT
+	interval time.Duration                // Период запуска Rotate; другие экземпляры подхватывают новый ключ не позже
+	logger   *logrus.Logger               // Логгер
+
+	reloadMu   sync.Mutex // Сериализует внеплановую загрузку ключей
+	lastReload time.Time  // Время последней загрузки ключей
+}
+
+// NewJWTKeyService создает новый сервис ключей подписи JWT; interval — период запуска Rotate
+func NewJWTKeyService(keyRepo *repository.JWTKeyRepository, jwtCfg config.JWTConfig, interval time.Duration,
+	logger *logrus.Logger) *JWTKeyService {
+	return &JWTKeyService{
+		keyRepo:  keyRepo,
+		keys:     keyset.New(),
+		jwtCfg:   jwtCfg,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Rotate загружает ключи из базы данных и создает новый ключ подписи, если действующего нет, он старше
+// KeyRotationPeriod или использует другой алгоритм. Прежний ключ проверяет подписи еще ExpiresIn плюс
+// период запуска: столько могут действовать токены, подписанные им на других экземплярах. Истекшие
+// ключи удаляются. Выполняется при старте сервиса и по расписанию
+func (s *JWTKeyService) Rotate(ctx context.Context) error {
+	now := time.Now().UTC()
+	if err := s.reload(ctx, now); err != nil {
+		return err
+	}
+
+	current := s.keys.Signing()
+	if current == nil || current.Algorithm != s.jwtCfg.Algorithm || now.Sub(current.CreatedAt) >= s.jwtCfg.KeyRotationPeriod {
+		k, err := keyset.Generate(s.jwtCfg.Algorithm)
+		if err != nil {
+			return err
+		}
+		rotated, err := s.keyRepo.Rotate(ctx, k, now.Add(-s.jwtCfg.KeyRotationPeriod), now.Add(s.jwtCfg.ExpiresIn+s.interval))
+		if err != nil {
+			return err
+		}
+		if rotated {
+			s.logger.WithFields(logrus.Fields{"kid": k.ID, "algorithm": k.Algorithm}).Info("Создан новый ключ подписи JWT")
+		}
+		if err := s.reload(ctx, now); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.keyRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.logger.WithField("count", deleted).Info("Удалены истекшие ключи подписи JWT")
+	}
+	return nil
+}
+
+// Signing возвращает текущий ключ подписи
+func (s *JWTKeyService) Signing() (*keyset.Key, error) {
+	k := s.keys.Signing()
+	if k == nil {
+		return nil, ErrNoSigningKey
+	}
+	return k, nil
+}
+
+// Lookup находит ключ проверки подписи по kid. Если ключа нет в памяти — например, его только что создал
+// другой экземпляр сервиса, — ключи загружаются из базы данных, но не чаще раза в keyReloadCooldown
+func (s *JWTKeyService) Lookup(ctx context.Context, kid string) (*keyset.Key, error) {
+	if k, ok := s.keys.Lookup(kid); ok {
+		return s.verifiable(k)
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	if k, ok := s.keys.Lookup(kid); ok {
+		return s.verifiable(k)
+	}
+	now := time.Now().UTC()
+	if now.Sub(s.lastReload) < keyReloadCooldown {
+		return nil, ErrUnknownKey
+	}
+	if err := s.load(ctx, now); err != nil {
+		return nil, err
+	}
+	if k, ok := s.keys.Lookup(kid); ok {
+		return s.verifiable(k)
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWKS возвращает открытые ключи для проверки токенов внешними сервисами
+func (s *JWTKeyService) JWKS() keyset.JWKS {
+	return s.keys.JWKS()
+}
+
+// verifiable проверяет, что срок проверки подписей ключом не истек
+func (s *JWTKeyService) verifiable(k *keyset.Key) (*keyset.Key, error) {
+	if k.ExpiresAt != nil && !time.Now().Before(*k.ExpiresAt) {
+		return nil, ErrUnknownKey
+	}
+	return k, nil
+}
+
+// reload загружает ключи из базы данных под блокировкой внеплановой загрузки
+func (s *JWTKeyService) reload(ctx context.Context, now time.Time) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	return s.load(ctx, now)
+}
+
+// load заменяет набор ключей в памяти ключами из базы данных; вызывается под reloadMu
+func (s *JWTKeyService) load(ctx context.Context, now time.Time) error {
+	keys, err := s.keyRepo.GetValid(ctx, now)
+	if err != nil {
+		return err
+	}
+	s.keys.Replace(keys)
+	s.lastReload = now
+	return nil
+}
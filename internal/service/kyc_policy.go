@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrKYCRequired      = apperr.New(apperr.Forbidden, apperr.CodeKYCRequired, "операция доступна после подтверждения личности")          // Выпуск карты или кредитная заявка без подтвержденной личности
+	ErrKYCLimitExceeded = apperr.New(apperr.Forbidden, apperr.CodeKYCLimitExceeded, "превышен лимит операций без подтверждения личности") // Расходная операция сверх лимита для неподтвержденного профиля
+)
+
+// KYCLimits описывает ограничения расходных операций пользователя без подтвержденной личности
+type KYCLimits struct {
+	OperationLimit decimal.Decimal // Максимальная сумма одной операции
+	MonthlyLimit   decimal.Decimal // Максимальная сумма операций за календарный месяц
+	MonthlyUsed    decimal.Decimal // Сумма операций с начала месяца
+}
+
+// KYCPolicy решает, какие операции доступны пользователю в зависимости от статуса проверки личности.
+// Пока личность не подтверждена, выпуск карт и кредитные заявки недоступны, а расходные операции
+// ограничены суммой одной операции и оборотом за календарный месяц
+type KYCPolicy struct {
+	userRepo        repository.UserRepository         // Репозиторий пользователей
+	transactionRepo *repository.TransactionRepository // Репозиторий транзакций для подсчета оборота за месяц
+	cfg             config.KYCConfig                  // Лимиты для неподтвержденных пользователей
+}
+
+// NewKYCPolicy создает новую политику ограничений по статусу проверки личности
+func NewKYCPolicy(userRepo repository.UserRepository, transactionRepo *repository.TransactionRepository,
+	cfg config.KYCConfig) *KYCPolicy {
+	return &KYCPolicy{
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		cfg:             cfg,
+	}
+}
+
+// RequireVerified возвращает ErrKYCRequired, если личность пользователя не подтверждена
+func (p *KYCPolicy) RequireVerified(ctx context.Context, userID int64) error {
+	user, err := p.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.KYCStatus != models.KYCVerified {
+		return fmt.Errorf("%w: статус проверки %s", ErrKYCRequired, user.KYCStatus)
+	}
+	return nil
+}
+
+// DebitGuard возвращает проверку расходной операции на сумму amount для пользователя без подтвержденной
+// личности или nil, если личность подтверждена. Сумма одной операции проверяется сразу, месячный оборот —
+// репозиторием в транзакции списания под блокировкой пользователя, чтобы параллельные операции
+// не превысили лимит в сумме. Превышение любого лимита возвращает ErrKYCLimitExceeded
+func (p *KYCPolicy) DebitGuard(ctx context.Context, userID int64, amount decimal.Decimal) (*repository.DebitGuard, error) {
+	user, err := p.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.KYCStatus == models.KYCVerified {
+		return nil, nil
+	}
+
+	if amount.GreaterThan(p.cfg.OperationLimit) {
+		return nil, fmt.Errorf("%w: сумма одной операции не больше %s", ErrKYCLimitExceeded, p.cfg.OperationLimit.StringFixed(2))
+	}
+	return &repository.DebitGuard{
+		UserID: userID,
+		Since:  monthStart(time.Now()),
+		Check: func(used decimal.Decimal) error {
+			if used.Add(amount).GreaterThan(p.cfg.MonthlyLimit) {
+				left := decimal.Max(p.cfg.MonthlyLimit.Sub(used), decimal.Zero)
+				return fmt.Errorf("%w: до конца месяца доступно %s", ErrKYCLimitExceeded, left.StringFixed(2))
+			}
+			return nil
+		},
+	}, nil
+}
+
+// Limits возвращает действующие ограничения расходных операций пользователя или nil, если личность подтверждена
+func (p *KYCPolicy) Limits(ctx context.Context, user *models.User) (*KYCLimits, error) {
+	if user.KYCStatus == models.KYCVerified {
+		return nil, nil
+	}
+
+	used, err := p.transactionRepo.GetOutgoing(ctx, user.ID, monthStart(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &KYCLimits{
+		OperationLimit: p.cfg.OperationLimit,
+		MonthlyLimit:   p.cfg.MonthlyLimit,
+		MonthlyUsed:    used,
+	}, nil
+}
+
+// monthStart возвращает начало календарного месяца момента t по UTC, с которого считается оборот
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
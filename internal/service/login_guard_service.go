@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/lockout"
+	"github.com/yujihn/bank_API/internal/notify"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// ErrLoginBlocked — базовая ошибка для LoginBlockedError
+var ErrLoginBlocked = apperr.New(apperr.TooManyRequests, apperr.CodeLoginBlocked, "слишком много неудачных попыток входа")
+
+// ErrLoginLocked — уточнение LoginBlockedError при блокировке после превышения лимита попыток
+var ErrLoginLocked = apperr.New(apperr.TooManyRequests, apperr.CodeLoginLocked, "вход временно заблокирован")
+
+// ErrUnlockTarget возвращается, если для снятия блокировки не указаны ни email, ни IP-адрес
+var ErrUnlockTarget = apperr.New(apperr.Invalid, apperr.CodeUnlockTargetRequired, "укажите email или IP-адрес")
+
+// LoginBlockedError возвращается при попытке входа, пока по email или IP-адресу действует ограничение
+type LoginBlockedError struct {
+	Until  time.Time // Когда можно повторить попытку
+	Locked bool      // Учетная запись или IP-адрес заблокированы после превышения лимита, а не просто задержка
+}
+
+// Error реализует интерфейс error
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("%s, повторите после %s", ErrLoginBlocked, e.Until.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrLoginBlocked через errors.Is, а при блокировке — и с ErrLoginLocked
+func (e *LoginBlockedError) Unwrap() []error {
+	if e.Locked {
+		return []error{ErrLoginLocked, ErrLoginBlocked}
+	}
+	return []error{ErrLoginBlocked}
+}
+
+// LoginGuardService защищает вход от подбора пароля: считает неудачные попытки по email и по IP-адресу,
+// после нескольких неудач вводит экспоненциально растущую задержку, а после превышения лимита временно
+// блокирует вход и уведомляет владельца учетной записи
+type LoginGuardService struct {
+	throttleRepo *repository.LoginThrottleRepository // Репозиторий счетчиков неудачных попыток
+	userRepo     repository.UserRepository           // Репозиторий пользователей
+	notifier     notify.Sender                       // Отправка уведомлений о блокировке
+	cfg          config.LockoutConfig                // Параметры защиты входа
+	logger       *logrus.Logger                      // Логгер
+}
+
+// NewLoginGuardService создает новый сервис защиты входа от подбора пароля
+func NewLoginGuardService(throttleRepo *repository.LoginThrottleRepository, userRepo repository.UserRepository,
+	notifier notify.Sender, cfg config.LockoutConfig, logger *logrus.Logger) *LoginGuardService {
+	return &LoginGuardService{
+		throttleRepo: throttleRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// Check возвращает *LoginBlockedError, если попытки входа по email или с IP-адреса ip сейчас отклоняются.
+// Вызывается до проверки пароля
+func (s *LoginGuardService) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	entries, err := s.throttleRepo.GetBlocked(ctx, normalizeEmail(email), ip, now)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Записи отсортированы по сроку ограничения: первая — самая долгая
+	blocked := &LoginBlockedError{Until: *entries[0].BlockedUntil}
+	for _, e := range entries {
+		blocked.Locked = blocked.Locked || e.Locked(now)
+	}
+	return blocked
+}
+
+// Fail засчитывает неудачную попытку входа по email с IP-адреса ip и при необходимости ограничивает
+// следующие попытки. При блокировке учетной записи владельцу отправляется уведомление
+func (s *LoginGuardService) Fail(ctx context.Context, email, ip string) error {
+	email = normalizeEmail(email)
+	if email != "" {
+		locked, err := s.fail(ctx, lockout.EMAIL, email, s.cfg.MaxFailures)
+		if err != nil {
+			return err
+		}
+		if locked {
+			s.notifyLocked(ctx, email)
+		}
+	}
+	if ip != "" {
+		locked, err := s.fail(ctx, lockout.IP, ip, s.cfg.IPMaxFailures)
+		if err != nil {
+			return err
+		}
+		if locked {
+			s.logger.WithField("ip", ip).Warn("Вход с IP-адреса временно заблокирован после неудачных попыток")
+		}
+	}
+	return nil
+}
+
+// Succeed сбрасывает счетчик неудачных попыток по email после успешного входа. Счетчик по IP-адресу
+// не сбрасывается: иначе успешный вход в свою учетную запись позволял бы продолжать подбор чужих паролей
+func (s *LoginGuardService) Succeed(ctx context.Context, email string) error {
+	_, err := s.throttleRepo.Reset(ctx, lockout.EMAIL, normalizeEmail(email))
+	return err
+}
+
+// Unlock снимает ограничения по email и (или) IP-адресу. Возвращает false, если ограничений не было
+func (s *LoginGuardService) Unlock(ctx context.Context, email, ip string) (bool, error) {
+	email = normalizeEmail(email)
+	if email == "" && ip == "" {
+		return false, ErrUnlockTarget
+	}
+
+	var unlocked bool
+	if email != "" {
+		ok, err := s.throttleRepo.Reset(ctx, lockout.EMAIL, email)
+		if err != nil {
+			return false, err
+		}
+		unlocked = ok
+	}
+	if ip != "" {
+		ok, err := s.throttleRepo.Reset(ctx, lockout.IP, ip)
+		if err != nil {
+			return false, err
+		}
+		unlocked = unlocked || ok
+	}
+	return unlocked, nil
+}
+
+// GetBlocked возвращает действующие ограничения входа
+func (s *LoginGuardService) GetBlocked(ctx context.Context) ([]*lockout.Entry, error) {
+	return s.throttleRepo.GetActive(ctx, time.Now())
+}
+
+// Purge удаляет счетчики без действующих ограничений, по которым не было неудачных попыток дольше Window
+func (s *LoginGuardService) Purge(ctx context.Context) error {
+	now := time.Now()
+	_, err := s.throttleRepo.DeleteStale(ctx, now, now.Add(-s.cfg.Window))
+	return err
+}
+
+// fail засчитывает неудачную попытку по счетчику и ограничивает следующие попытки: после BackoffAfter
+// неудач — задержкой, удваивающейся с каждой неудачей, после maxFailures — блокировкой на LockoutDuration.
+// Возвращает true, если блокировка установлена этой попыткой
+func (s *LoginGuardService) fail(ctx context.Context, scope lockout.Scope, key string, maxFailures int) (bool, error) {
+	now := time.Now()
+	e, err := s.throttleRepo.RecordFailure(ctx, scope, key, now, now.Add(-s.cfg.Window))
+	if err != nil {
+		return false, err
+	}
+
+	if e.Failures >= maxFailures {
+		return s.throttleRepo.Block(ctx, scope, key, now.Add(s.cfg.LockoutDuration), true, now)
+	}
+	if delay := s.backoff(e.Failures); delay > 0 {
+		_, err := s.throttleRepo.Block(ctx, scope, key, now.Add(delay), false, now)
+		return false, err
+	}
+	return false, nil
+}
+
+// backoff возвращает задержку после failures неудачных попыток подряд
+func (s *LoginGuardService) backoff(failures int) time.Duration {
+	if failures < s.cfg.BackoffAfter {
+		return 0
+	}
+	delay := s.cfg.BackoffBase
+	for i := s.cfg.BackoffAfter; i < failures && delay < s.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.BackoffMax {
+		delay = s.cfg.BackoffMax
+	}
+	return delay
+}
+
+// notifyLocked уведомляет владельца учетной записи о временной блокировке входа. Для email без учетной
+// записи уведомление не отправляется; ошибки отправки только записываются в лог
+func (s *LoginGuardService) notifyLocked(ctx context.Context, email string) {
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			s.logger.Errorf("Ошибка получения пользователя для уведомления о блокировке: %v", err)
+		}
+		return
+	}
+	s.logger.WithField("user_id", user.ID).Warn("Вход в учетную запись временно заблокирован после неудачных попыток")
+
+	subject := "Вход в учетную запись временно заблокирован"
+	body := fmt.Sprintf("Зафиксировано %d неудачных попыток входа в вашу учетную запись подряд. Вход заблокирован до %s UTC.\n"+
+		"Если это были не вы, рекомендуем сменить пароль и включить двухфакторную аутентификацию.\n",
+		s.cfg.MaxFailures, time.Now().Add(s.cfg.LockoutDuration).UTC().Format("02.01.2006 15:04"))
+	if err := s.notifier.Send(ctx, user.Email, subject, body); err != nil {
+		s.logger.Errorf("Ошибка отправки уведомления о блокировке пользователю %d: %v", user.ID, err)
+	}
+}
+
+// normalizeEmail приводит email к виду, в котором он хранится в users и используется как ключ счетчика
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/mfa"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/totp"
+)
+
+// Ошибки двухфакторной аутентификации
+var (
+	ErrMFAAlreadyEnabled = apperr.New(apperr.Conflict, apperr.CodeMFAAlreadyEnabled, "двухфакторная аутентификация уже включена")                    // Повторное подключение приложения
+	ErrMFANotEnrolled    = apperr.New(apperr.NotFound, apperr.CodeMFANotEnrolled, "приложение-аутентификатор не подключено")                         // Подтверждение без выпуска секрета
+	ErrMFANotEnabled     = apperr.New(apperr.NotFound, apperr.CodeMFANotEnabled, "двухфакторная аутентификация не включена")                         // Проверка кода у пользователя без 2FA
+	ErrInvalidMFACode    = apperr.New(apperr.Invalid, apperr.CodeInvalidMFACode, "неверный или уже использованный код")                              // Код не подошел
+	ErrInvalidMFAToken   = apperr.New(apperr.Unauthenticated, apperr.CodeInvalidMFAToken, "неверный или просроченный MFA-токен")                     // Токен входа не найден, истек или исчерпаны попытки
+	ErrMFARequired       = apperr.New(apperr.Forbidden, apperr.CodeMFARequired, "операция требует подтверждения кодом двухфакторной аутентификации") // Нет свежего подтверждения в сессии
+	ErrMFALocked         = apperr.New(apperr.TooManyRequests, apperr.CodeMFALocked, "ввод кодов временно заблокирован")                              // Превышен лимит неверных кодов подряд
+)
+
+// MFALockedError возвращается, пока ввод кодов пользователем заблокирован после MaxFailures неверных кодов подряд
+type MFALockedError struct {
+	Until time.Time // Когда можно повторить попытку
+}
+
+// Error реализует интерфейс error
+func (e *MFALockedError) Error() string {
+	return fmt.Sprintf("%s, повторите после %s", ErrMFALocked, e.Until.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrMFALocked через errors.Is
+func (e *MFALockedError) Unwrap() error {
+	return ErrMFALocked
+}
+
+// Параметры кодов восстановления
+const (
+	recoveryCodeCount = 10 // Количество кодов, выдаваемых при включении
+	recoveryCodeBytes = 5  // Случайных байт в коде: 10 символов base32
+	mfaTokenBytes     = 32 // Длина MFA-токена незавершенного входа
+)
+
+// recoveryEncoding кодирует коды восстановления строчными буквами base32 без дополнения
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Enrollment содержит данные для подключения приложения-аутентификатора
+type Enrollment struct {
+	Secret string // Секрет в base32 для ручного ввода
+	URI    string // otpauth URI для QR-кода
+}
+
+// MFAChallenge содержит токен незавершенного входа, который обменивается на пару токенов вместе с кодом
+type MFAChallenge struct {
+	Token     string    // MFA-токен
+	ExpiresAt time.Time // Срок действия токена
+}
+
+// MFAService управляет двухфакторной аутентификацией по одноразовым кодам (TOTP): подключением приложения,
+// кодами восстановления, вторым шагом входа и подтверждением кодом операций с крупными суммами
+type MFAService struct {
+	mfaRepo     *repository.MFARepository     // Репозиторий двухфакторной аутентификации
+	sessionRepo *repository.SessionRepository // Репозиторий сессий
+	userRepo    repository.UserRepository     // Репозиторий пользователей
+	cfg         config.MFAConfig              // Параметры двухфакторной аутентификации
+}
+
+// NewMFAService создает новый сервис двухфакторной аутентификации
+func NewMFAService(mfaRepo *repository.MFARepository, sessionRepo *repository.SessionRepository,
+	userRepo repository.UserRepository, cfg config.MFAConfig) *MFAService {
+	return &MFAService{
+		mfaRepo:     mfaRepo,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		cfg:         cfg,
+	}
+}
+
+// Enroll выпускает новый секрет TOTP и возвращает данные для приложения-аутентификатора. Двухфакторная
+// аутентификация включается только после подтверждения первым кодом; до этого секрет можно перевыпустить
+func (s *MFAService) Enroll(ctx context.Context, userID int64) (*Enrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.SaveTOTP(ctx, userID, secret); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm включает двухфакторную аутентификацию, если code — верный код из приложения, и возвращает коды
+// восстановления. Коды показываются один раз: в базе данных хранятся только их хеши
+func (s *MFAService) Confirm(ctx context.Context, userID int64, code string) ([]string, error) {
+	t, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if t.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := totp.Verify(t.Secret, normalizeCode(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := recoveryEncoding.EncodeToString(b)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+
+	if err := s.mfaRepo.Confirm(ctx, userID, step, hashes); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidMFACode
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable выключает двухфакторную аутентификацию после проверки кода из приложения или кода восстановления
+func (s *MFAService) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.mfaRepo.Delete(ctx, userID)
+}
+
+// Enabled сообщает, включена ли у пользователя двухфакторная аутентификация
+func (s *MFAService) Enabled(ctx context.Context, userID int64) (bool, error) {
+	t, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return t.Enabled(), nil
+}
+
+// RecoveryCodesLeft возвращает количество неиспользованных кодов восстановления
+func (s *MFAService) RecoveryCodesLeft(ctx context.Context, userID int64) (int, error) {
+	return s.mfaRepo.CountRecoveryCodes(ctx, userID)
+}
+
+// Assert проверяет код и отмечает сессию подтвержденной; подтверждение действует AssertionTTL.
+// Возвращает срок действия подтверждения
+func (s *MFAService) Assert(ctx context.Context, userID, sessionID int64, code string) (time.Time, error) {
+	if err := s.verify(ctx, userID, code); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	if err := s.sessionRepo.MarkMFAVerified(ctx, sessionID, userID, now); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrSessionNotFound
+		}
+		return time.Time{}, err
+	}
+	return now.Add(s.cfg.AssertionTTL), nil
+}
+
+// RequireFresh проверяет, можно ли выполнить операцию на сумму amount в сессии sessionID. Если сумма больше
+// AmountThreshold и у пользователя включена двухфакторная аутентификация, сессия должна быть подтверждена
+// кодом не раньше чем за AssertionTTL; иначе возвращается ErrMFARequired
+func (s *MFAService) RequireFresh(ctx context.Context, userID, sessionID int64, amount decimal.Decimal) error {
+	if amount.Abs().LessThanOrEqual(s.cfg.AmountThreshold) {
+		return nil
+	}
+	enabled, err := s.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		return err
+	}
+
+	sess, err := s.sessionRepo.GetByID(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if !sess.MFAFresh(time.Now(), s.cfg.AssertionTTL) {
+		return ErrMFARequired
+	}
+	return nil
+}
+
+// CreateChallenge создает незавершенный вход пользователя с устройства client после проверки пароля
+func (s *MFAService) CreateChallenge(ctx context.Context, userID int64, client ClientInfo) (*MFAChallenge, error) {
+	token, err := randomToken(mfaTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &mfa.Challenge{
+		UserID:    userID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IP,
+		ExpiresAt: time.Now().Add(s.cfg.ChallengeTTL),
+	}
+	if err := s.mfaRepo.CreateChallenge(ctx, c, hashRefreshToken(token)); err != nil {
+		return nil, err
+	}
+	return &MFAChallenge{Token: token, ExpiresAt: c.ExpiresAt}, nil
+}
+
+// VerifyChallenge проверяет код по MFA-токену и завершает вход. Каждая проверка расходует попытку;
+// после MaxAttempts неверных кодов токен перестает действовать и вход нужно начинать заново
+func (s *MFAService) VerifyChallenge(ctx context.Context, token, code string) (*mfa.Challenge, error) {
+	if token == "" {
+		return nil, ErrInvalidMFAToken
+	}
+
+	c, err := s.mfaRepo.AttemptChallenge(ctx, hashRefreshToken(token), s.cfg.MaxAttempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if err := s.verify(ctx, c.UserID, code); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.CompleteChallenge(ctx, c.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// PurgeChallenges удаляет незавершенные входы с истекшим сроком действия
+func (s *MFAService) PurgeChallenges(ctx context.Context) error {
+	_, err := s.mfaRepo.DeleteExpiredChallenges(ctx, time.Now())
+	return err
+}
+
+// verify проверяет код из приложения или код восстановления. Код из приложения принимается один раз:
+// шаг времени должен быть позже последнего принятого. Код восстановления погашается. Неверные коды
+// считаются по пользователю независимо от MFA-токена и сессии: после MaxFailures неверных кодов подряд
+// ввод кодов блокируется на LockoutDuration и возвращается *MFALockedError
+func (s *MFAService) verify(ctx context.Context, userID int64, code string) error {
+	t, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	if !t.Enabled() {
+		return ErrMFANotEnabled
+	}
+	now := time.Now()
+	if t.LockedUntil != nil && t.LockedUntil.After(now) {
+		return &MFALockedError{Until: *t.LockedUntil}
+	}
+
+	code = normalizeCode(code)
+	var ok bool
+	if len(code) == totp.Digits {
+		step, valid := totp.Verify(t.Secret, code, now)
+		if valid && step > t.LastStep {
+			ok, err = s.mfaRepo.UseStep(ctx, userID, step)
+		}
+	} else {
+		ok, err = s.mfaRepo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		lockedUntil, err := s.mfaRepo.RecordFailure(ctx, userID, s.cfg.MaxFailures, now.Add(s.cfg.LockoutDuration))
+		if err != nil {
+			return err
+		}
+		if lockedUntil != nil {
+			return &MFALockedError{Until: *lockedUntil}
+		}
+		return ErrInvalidMFACode
+	}
+	if t.Failures > 0 {
+		return s.mfaRepo.ResetFailures(ctx, userID)
+	}
+	return nil
+}
+
+// normalizeCode убирает из кода пробелы и дефисы и приводит его к нижнему регистру
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// hashRecoveryCode возвращает SHA-256 нормализованного кода восстановления
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
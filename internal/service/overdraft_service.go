@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/overdraft"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrInvalidOverdraftLimit = apperr.New(apperr.Invalid, apperr.CodeInvalidOverdraftLimit, "некорректный лимит овердрафта")                         // Отрицательный лимит или лимит выше допустимого
+	ErrOverdraftNotAllowed   = apperr.New(apperr.Invalid, apperr.CodeOverdraftNotAllowed, "овердрафт доступен только для текущих счетов")            // Попытка установить лимит на накопительный счет
+	ErrOverdraftInUse        = apperr.New(apperr.Conflict, apperr.CodeOverdraftInUse, "лимит овердрафта меньше уже использованной суммы овердрафта") // Снижение лимита ниже задолженности
+)
+
+// OverdraftService управляет лимитами овердрафта и ежедневно начисляет проценты на отрицательный остаток
+type OverdraftService struct {
+	accountRepo     *repository.AccountRepository     // Репозиторий для работы со счетами
+	overdraftRepo   *repository.OverdraftRepository   // Репозиторий начислений по овердрафту
+	transactionRepo *repository.TransactionRepository // Репозиторий для восстановления остатков на конец дня
+	accountService  *AccountService                   // Сервис счетов для проверки владения
+	cfg             config.OverdraftConfig            // Ставка и максимальный лимит
+	logger          *logrus.Logger                    // Логгер для фоновых задач
+}
+
+// NewOverdraftService создает новый сервис овердрафта
+func NewOverdraftService(accountRepo *repository.AccountRepository, overdraftRepo *repository.OverdraftRepository,
+	transactionRepo *repository.TransactionRepository, accountService *AccountService,
+	cfg config.OverdraftConfig, logger *logrus.Logger) *OverdraftService {
+	return &OverdraftService{
+		accountRepo:     accountRepo,
+		overdraftRepo:   overdraftRepo,
+		transactionRepo: transactionRepo,
+		accountService:  accountService,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// SetLimit устанавливает лимит овердрафта по текущему счету в пределах максимального лимита.
+// Вызывается администратором, поэтому владение счетом не проверяется.
+// Нулевой лимит отключает овердрафт, если он не используется
+func (s *OverdraftService) SetLimit(ctx context.Context, accountID int64, limit decimal.Decimal) (*account.Account, error) {
+	if limit.IsNegative() || limit.GreaterThan(s.cfg.MaxLimit) {
+		return nil, fmt.Errorf("%w: лимит должен быть от 0 до %s", ErrInvalidOverdraftLimit, s.cfg.MaxLimit)
+	}
+
+	acc, err := s.accountRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Type != account.CURRENT {
+		return nil, ErrOverdraftNotAllowed
+	}
+
+	updated, err := s.accountRepo.SetOverdraftLimit(ctx, accountID, limit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOverdraftInUse
+	}
+	return updated, err
+}
+
+// Rate возвращает годовую ставку за пользование овердрафтом
+func (s *OverdraftService) Rate() decimal.Decimal {
+	return s.cfg.Rate
+}
+
+// GetMonthCharges получает проценты по овердрафту, начисленные по счетам пользователя с начала текущего месяца
+func (s *OverdraftService) GetMonthCharges(ctx context.Context, userID int64) (map[int64]decimal.Decimal, error) {
+	now := time.Now().UTC()
+	return s.overdraftRepo.GetChargedByUserSince(ctx, userID, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
+
+// ChargeInterest начисляет и списывает проценты на отрицательный остаток на конец каждого завершенного дня.
+// Повторный запуск не начисляет проценты дважды. Предназначен для запуска планировщиком
+func (s *OverdraftService) ChargeInterest(ctx context.Context) error {
+	accounts, err := s.accountRepo.GetAccountsWithOverdraft(ctx)
+	if err != nil {
+		return err
+	}
+
+	today := truncateDay(time.Now())
+	for _, acc := range accounts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.chargeAccount(ctx, acc, today); err != nil {
+			s.logger.Errorf("Ошибка начисления процентов по овердрафту счета %d: %v", acc.ID, err)
+		}
+	}
+	return nil
+}
+
+// chargeAccount начисляет проценты по овердрафту счета за дни от последнего начисления
+// (или открытия счета) до вчерашнего: |остаток| × ставка / 365 с округлением до копеек за каждый день
+func (s *OverdraftService) chargeAccount(ctx context.Context, acc *account.Account, today time.Time) error {
+	start := truncateDay(acc.CreatedAt)
+	last, err := s.overdraftRepo.GetLastChargeDate(ctx, acc.ID)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		start = last.AddDate(0, 0, 1)
+	}
+	days := int(today.Sub(start).Hours() / 24)
+	if days <= 0 {
+		return nil
+	}
+
+	balances, err := closingBalances(ctx, s.transactionRepo, acc, start, days)
+	if err != nil {
+		return err
+	}
+
+	// Дни с неотрицательным остатком сохраняются с нулевой суммой, чтобы повторный запуск не возвращался к ним
+	charges := make([]*overdraft.Charge, 0, days)
+	total := decimal.Zero
+	for i, balance := range balances {
+		charge := &overdraft.Charge{
+			AccountID:  acc.ID,
+			ChargeDate: start.AddDate(0, 0, i),
+			Balance:    balance,
+			Rate:       s.cfg.Rate,
+			Amount:     decimal.Zero,
+		}
+		if balance.IsNegative() {
+			charge.Amount = balance.Neg().Mul(s.cfg.Rate).Div(decimal.NewFromInt(365)).Round(2)
+			total = total.Add(charge.Amount)
+		}
+		charges = append(charges, charge)
+	}
+	return s.overdraftRepo.Charge(ctx, acc.ID, charges, total)
+}
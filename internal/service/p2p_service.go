@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrRecipientNotFound = apperr.New(apperr.NotFound, apperr.CodeRecipientNotFound, "получатель не найден")                                           // Пользователь с указанным email не найден
+	ErrNoDefaultAccount  = apperr.New(apperr.Invalid, apperr.CodeNoDefaultAccount, "у получателя не назначен счет для переводов")                      // Получатель не выбрал счет по умолчанию
+	ErrCurrencyMismatch  = apperr.New(apperr.Invalid, apperr.CodeCurrencyMismatch, "валюта счета получателя не совпадает с валютой счета отправителя") // Перевод между счетами в разных валютах
+)
+
+// Recipient содержит данные получателя перевода по email
+type Recipient struct {
+	UserID     int64            // ID получателя
+	AccountID  int64            // Счет зачисления (не раскрывается отправителю)
+	MaskedName string           // Замаскированное имя для показа отправителю
+	Currency   account.Currency // Валюта счета зачисления
+}
+
+// P2PService выполняет переводы между пользователями по email получателя.
+// Перевод зачисляется на счет по умолчанию, который получатель выбирает сам,
+// поэтому отправителю не нужно знать номер счета получателя
+type P2PService struct {
+	userRepo       repository.UserRepository     // Репозиторий пользователей
+	accountRepo    *repository.AccountRepository // Репозиторий для работы со счетами
+	accountService *AccountService               // Сервис счетов, выполняющий переводы
+}
+
+// NewP2PService создает новый сервис переводов по email
+func NewP2PService(userRepo repository.UserRepository, accountRepo *repository.AccountRepository, accountService *AccountService) *P2PService {
+	return &P2PService{
+		userRepo:       userRepo,
+		accountRepo:    accountRepo,
+		accountService: accountService,
+	}
+}
+
+// SetDefaultAccount назначает счет пользователя счетом для зачисления переводов по email
+func (s *P2PService) SetDefaultAccount(ctx context.Context, userID, accountID int64) (*account.Account, error) {
+	// Проверка владения счетом
+	acc, err := s.accountService.GetAccountByID(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetDefaultAccount(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// PreviewRecipient находит получателя по email и возвращает данные для подтверждения перевода
+func (s *P2PService) PreviewRecipient(ctx context.Context, email string) (*Recipient, error) {
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+	if user.DefaultAccountID == nil {
+		return nil, ErrNoDefaultAccount
+	}
+
+	acc, err := s.accountRepo.GetAccountByID(ctx, *user.DefaultAccountID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoDefaultAccount
+		}
+		return nil, err
+	}
+
+	return &Recipient{
+		UserID:     user.ID,
+		AccountID:  acc.ID,
+		MaskedName: maskName(user),
+		Currency:   acc.Currency,
+	}, nil
+}
+
+// TransferByEmail переводит деньги со счета пользователя на счет по умолчанию получателя с указанным email
+func (s *P2PService) TransferByEmail(ctx context.Context, userID, fromID int64, email string, amount decimal.Decimal) (*Recipient, error) {
+	recipient, err := s.PreviewRecipient(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверка владения счетом отправителя и совпадения валют
+	fromAcc, err := s.accountService.GetAccountByID(ctx, fromID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAcc.Currency != recipient.Currency {
+		return nil, ErrCurrencyMismatch
+	}
+
+	if err := s.accountService.Transfer(ctx, fromID, recipient.AccountID, userID, amount); err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// maskName возвращает имя получателя в виде «Имя Ф.»; если имя не указано, маскируется email
+func maskName(user *models.User) string {
+	words := strings.Fields(user.FullName)
+	switch len(words) {
+	case 0:
+		local, domain, _ := strings.Cut(user.Email, "@")
+		first, _ := utf8.DecodeRuneInString(local)
+		return string(first) + "***@" + domain
+	case 1:
+		return words[0]
+	default:
+		initial, _ := utf8.DecodeRuneInString(words[len(words)-1])
+		return words[0] + " " + string(initial) + "."
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/models/paymentrequest"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrPaymentRequestNotFound = apperr.New(apperr.NotFound, apperr.CodePaymentRequestNotFound, "запрос на оплату не найден")                        // Запрос не найден или не относится к пользователю
+	ErrPaymentRequestState    = apperr.New(apperr.Conflict, apperr.CodePaymentRequestState, "операция недоступна в текущем статусе запроса")        // Запрос уже оплачен, отклонен, отозван или просрочен
+	ErrPaymentRequestExpired  = apperr.New(apperr.Conflict, apperr.CodePaymentRequestExpired, "срок оплаты запроса истек")                          // Оплата после последнего дня оплаты
+	ErrPaymentExceedsBalance  = apperr.New(apperr.Invalid, apperr.CodePaymentExceedsBalance, "сумма оплаты превышает неоплаченный остаток запроса") // Переплата по запросу
+	ErrInvalidPaymentRequest  = apperr.New(apperr.Invalid, apperr.CodeInvalidPaymentRequest, "некорректные параметры запроса на оплату")            // Ошибка в параметрах нового запроса
+	ErrPayerNotFound          = apperr.New(apperr.NotFound, apperr.CodePayerNotFound, "плательщик не найден")                                       // Пользователь с указанным email не найден
+)
+
+// maxMemoLength ограничивает длину назначения платежа
+const maxMemoLength = 140
+
+// PaymentRequestService управляет запросами на оплату между пользователями: выставлением, оплатой
+// (в том числе частичной), отклонением, отзывом и истечением срока
+type PaymentRequestService struct {
+	requestRepo    *repository.PaymentRequestRepository // Репозиторий запросов на оплату
+	userRepo       repository.UserRepository            // Репозиторий пользователей
+	accountService *AccountService                      // Сервис счетов, выполняющий переводы
+	logger         *logrus.Logger                       // Логгер для фоновых задач
+}
+
+// NewPaymentRequestService создает новый сервис запросов на оплату
+func NewPaymentRequestService(requestRepo *repository.PaymentRequestRepository, userRepo repository.UserRepository,
+	accountService *AccountService, logger *logrus.Logger) *PaymentRequestService {
+	return &PaymentRequestService{
+		requestRepo:    requestRepo,
+		userRepo:       userRepo,
+		accountService: accountService,
+		logger:         logger,
+	}
+}
+
+// Create выставляет запрос на оплату пользователю с указанным email. Если счет зачисления не указан,
+// используется счет по умолчанию получателя денег
+func (s *PaymentRequestService) Create(ctx context.Context, requesterID int64, req dto.CreatePaymentRequestRequest) (*paymentrequest.PaymentRequest, error) {
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		return nil, ErrNegativeAmount
+	}
+	if utf8.RuneCountInString(req.Memo) > maxMemoLength {
+		return nil, fmt.Errorf("%w: назначение длиннее %d символов", ErrInvalidPaymentRequest, maxMemoLength)
+	}
+
+	dueDate, err := time.Parse("2006-01-02", req.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: неверный формат срока оплаты", ErrInvalidPaymentRequest)
+	}
+	if dueDate.Before(truncateDay(time.Now())) {
+		return nil, fmt.Errorf("%w: срок оплаты в прошлом", ErrInvalidPaymentRequest)
+	}
+
+	payer, err := s.userRepo.GetByEmail(ctx, normalizeEmail(req.PayerEmail))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrPayerNotFound
+		}
+		return nil, err
+	}
+	if payer.ID == requesterID {
+		return nil, fmt.Errorf("%w: нельзя выставить запрос самому себе", ErrInvalidPaymentRequest)
+	}
+
+	toAccountID := req.ToAccountID
+	if toAccountID == 0 {
+		requester, err := s.userRepo.GetByID(ctx, requesterID)
+		if err != nil {
+			return nil, err
+		}
+		if requester.DefaultAccountID == nil {
+			return nil, fmt.Errorf("%w: не указан счет зачисления и не назначен счет по умолчанию", ErrInvalidPaymentRequest)
+		}
+		toAccountID = *requester.DefaultAccountID
+	}
+
+	// Проверка владения счетом зачисления
+	if _, err := s.accountService.GetAccountByID(ctx, toAccountID, requesterID); err != nil {
+		return nil, err
+	}
+
+	return s.requestRepo.Create(ctx, &paymentrequest.PaymentRequest{
+		RequesterID: requesterID,
+		PayerID:     payer.ID,
+		ToAccountID: toAccountID,
+		Amount:      req.Amount,
+		Memo:        req.Memo,
+		DueDate:     dueDate,
+		Status:      paymentrequest.PENDING,
+	})
+}
+
+// GetIncoming получает запросы, выставленные пользователю
+func (s *PaymentRequestService) GetIncoming(ctx context.Context, userID int64) ([]*paymentrequest.PaymentRequest, error) {
+	return s.requestRepo.GetByPayerID(ctx, userID)
+}
+
+// GetOutgoing получает запросы, выставленные пользователем
+func (s *PaymentRequestService) GetOutgoing(ctx context.Context, userID int64) ([]*paymentrequest.PaymentRequest, error) {
+	return s.requestRepo.GetByRequesterID(ctx, userID)
+}
+
+// GetRequest получает запрос и историю его оплат; запрос доступен обеим сторонам
+func (s *PaymentRequestService) GetRequest(ctx context.Context, id, userID int64) (*paymentrequest.PaymentRequest, []*paymentrequest.Payment, error) {
+	pr, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr.RequesterID != userID && pr.PayerID != userID {
+		return nil, nil, ErrPaymentRequestNotFound
+	}
+
+	payments, err := s.requestRepo.GetPaymentsByRequestID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, payments, nil
+}
+
+// Approve оплачивает запрос со счета плательщика. Нулевая сумма означает оплату всего остатка,
+// меньшая сумма — частичную оплату
+func (s *PaymentRequestService) Approve(ctx context.Context, id, payerID, fromAccountID int64, amount decimal.Decimal) (*paymentrequest.PaymentRequest, error) {
+	pr, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.PayerID != payerID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if !pr.Status.Open() {
+		return nil, ErrPaymentRequestState
+	}
+
+	today := truncateDay(time.Now())
+	if pr.DueDate.Before(today) {
+		if _, err := s.requestRepo.ExpireOverdue(ctx, today); err != nil {
+			s.logger.Errorf("Ошибка обновления просроченных запросов на оплату: %v", err)
+		}
+		return nil, ErrPaymentRequestExpired
+	}
+
+	if amount.IsZero() {
+		amount = pr.Remaining()
+	}
+	if amount.IsNegative() {
+		return nil, ErrNegativeAmount
+	}
+	if amount.GreaterThan(pr.Remaining()) {
+		return nil, ErrPaymentExceedsBalance
+	}
+
+	// Сумма учитывается в запросе до перевода, поэтому параллельные оплаты не превысят остаток
+	reserved, err := s.requestRepo.ReservePayment(ctx, id, amount, today)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestState
+		}
+		return nil, err
+	}
+
+	if err := s.accountService.Transfer(ctx, fromAccountID, pr.ToAccountID, payerID, amount); err != nil {
+		if releaseErr := s.requestRepo.ReleasePayment(ctx, id, amount); releaseErr != nil {
+			s.logger.Errorf("Ошибка отмены учета оплаты по запросу %d: %v", id, releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := s.requestRepo.CreatePayment(ctx, &paymentrequest.Payment{
+		RequestID:     id,
+		FromAccountID: fromAccountID,
+		Amount:        amount,
+	}); err != nil {
+		return nil, err
+	}
+	return reserved, nil
+}
+
+// Decline отклоняет запрос плательщиком; уже выполненные частичные оплаты сохраняются
+func (s *PaymentRequestService) Decline(ctx context.Context, id, payerID int64) (*paymentrequest.PaymentRequest, error) {
+	pr, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.PayerID != payerID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	return s.transition(ctx, id, paymentrequest.DECLINED)
+}
+
+// Cancel отзывает запрос получателем денег; уже выполненные частичные оплаты сохраняются
+func (s *PaymentRequestService) Cancel(ctx context.Context, id, requesterID int64) (*paymentrequest.PaymentRequest, error) {
+	pr, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.RequesterID != requesterID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	return s.transition(ctx, id, paymentrequest.CANCELLED)
+}
+
+// ExpireOverdue закрывает открытые запросы с истекшим сроком оплаты. Предназначен для запуска планировщиком
+func (s *PaymentRequestService) ExpireOverdue(ctx context.Context) error {
+	expired, err := s.requestRepo.ExpireOverdue(ctx, truncateDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		s.logger.Infof("Истек срок оплаты запросов: %d", expired)
+	}
+	return nil
+}
+
+// transition закрывает открытый запрос с указанным статусом
+func (s *PaymentRequestService) transition(ctx context.Context, id int64, to paymentrequest.Status) (*paymentrequest.PaymentRequest, error) {
+	pr, err := s.requestRepo.Transition(ctx, id, to, paymentrequest.PENDING, paymentrequest.PARTIALLY_PAID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestState
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+// getRequest получает запрос по ID
+func (s *PaymentRequestService) getRequest(ctx context.Context, id int64) (*paymentrequest.PaymentRequest, error) {
+	pr, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return pr, nil
+}
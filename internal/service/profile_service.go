@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/notify"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/validation"
+)
+
+var (
+	ErrProfileLocked     = apperr.New(apperr.Conflict, apperr.CodeProfileLocked, "имя и дату рождения нельзя изменить")    // Изменение данных личности во время проверки или после подтверждения
+	ErrProfileIncomplete = apperr.New(apperr.Unprocessable, apperr.CodeProfileIncomplete, "профиль заполнен не полностью") // Отправка на проверку без обязательных данных
+	ErrKYCState          = apperr.New(apperr.Conflict, apperr.CodeKYCState, "недопустимый переход статуса проверки")       // Отправка или решение по проверке не в том статусе
+)
+
+// ProfileService управляет профилем пользователя и проверкой его личности (KYC): пользователь заполняет
+// профиль и отправляет его на проверку, администратор подтверждает или отклоняет заявку
+type ProfileService struct {
+	userRepo repository.UserRepository // Репозиторий пользователей
+	kyc      *KYCPolicy                // Ограничения по статусу проверки личности
+	notifier notify.Sender             // Уведомления о решении по проверке
+	cfg      config.KYCConfig          // Параметры проверки личности
+	logger   *logrus.Logger            // Логгер
+}
+
+// NewProfileService создает новый сервис профиля пользователя
+func NewProfileService(userRepo repository.UserRepository, kyc *KYCPolicy, notifier notify.Sender,
+	cfg config.KYCConfig, logger *logrus.Logger) *ProfileService {
+	return &ProfileService{
+		userRepo: userRepo,
+		kyc:      kyc,
+		notifier: notifier,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// GetProfile возвращает пользователя и, если его личность не подтверждена, действующие лимиты операций
+func (s *ProfileService) GetProfile(ctx context.Context, userID int64) (*models.User, *KYCLimits, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits, err := s.kyc.Limits(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, limits, nil
+}
+
+// UpdateProfile изменяет переданные поля профиля; пустая строка очищает поле. Имя и дату рождения нельзя
+// изменить, пока профиль на проверке или после подтверждения личности
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID int64, req dto.UpdateProfileRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	profile := user.Profile()
+	if req.Username != nil {
+		profile.Username = nil
+		if username := strings.ToLower(strings.TrimSpace(*req.Username)); username != "" {
+			profile.Username = &username
+		}
+	}
+	if req.FullName != nil {
+		profile.FullName = strings.TrimSpace(*req.FullName)
+	}
+	if req.DateOfBirth != nil {
+		profile.DateOfBirth = nil
+		if *req.DateOfBirth != "" {
+			// Формат проверен правилом date при разборе запроса
+			dob, _ := time.Parse("2006-01-02", *req.DateOfBirth)
+			if err := s.checkAge(dob); err != nil {
+				return err
+			}
+			profile.DateOfBirth = &dob
+		}
+	}
+	if req.Phone != nil {
+		profile.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.Address != nil {
+		profile.Address = strings.TrimSpace(*req.Address)
+	}
+
+	identityChanged := profile.FullName != user.FullName || !sameDate(profile.DateOfBirth, user.DateOfBirth)
+	if identityChanged && (user.KYCStatus == models.KYCPending || user.KYCStatus == models.KYCVerified) {
+		return fmt.Errorf("%w: статус проверки %s", ErrProfileLocked, user.KYCStatus)
+	}
+
+	return s.userRepo.UpdateProfile(ctx, userID, profile)
+}
+
+// SubmitKYC отправляет заполненный профиль на проверку личности. Отправить можно непроверенный
+// или отклоненный профиль
+func (s *ProfileService) SubmitKYC(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	if user.FullName == "" {
+		missing = append(missing, "full_name")
+	}
+	if user.DateOfBirth == nil {
+		missing = append(missing, "date_of_birth")
+	}
+	if user.Phone == "" {
+		missing = append(missing, "phone")
+	}
+	if user.Address == "" {
+		missing = append(missing, "address")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: не заполнены %s", ErrProfileIncomplete, strings.Join(missing, ", "))
+	}
+
+	ok, err := s.userRepo.UpdateKYCStatus(ctx, userID, []models.KYCStatus{models.KYCUnverified, models.KYCRejected},
+		models.KYCPending)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: статус проверки %s", ErrKYCState, user.KYCStatus)
+	}
+
+	s.logger.WithField("user_id", userID).Info("Профиль отправлен на проверку личности")
+	return nil
+}
+
+// GetPendingKYC возвращает пользователей, ожидающих проверки личности, начиная с самых давних заявок
+func (s *ProfileService) GetPendingKYC(ctx context.Context) ([]*models.User, error) {
+	return s.userRepo.GetByKYCStatus(ctx, models.KYCPending)
+}
+
+// ReviewKYC выносит решение по проверке личности пользователя: VERIFIED или REJECTED. Решение принимается
+// только по профилю на проверке; пользователь получает уведомление на email
+func (s *ProfileService) ReviewKYC(ctx context.Context, userID int64, decision models.KYCStatus) error {
+	if decision != models.KYCVerified && decision != models.KYCRejected {
+		return validation.Errors{validation.NewFieldError("status", "decision",
+			"допустимые значения: VERIFIED, REJECTED", "allowed values: VERIFIED, REJECTED")}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.userRepo.UpdateKYCStatus(ctx, userID, []models.KYCStatus{models.KYCPending}, decision)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: статус проверки %s", ErrKYCState, user.KYCStatus)
+	}
+
+	s.notifyDecision(ctx, user, decision)
+	return nil
+}
+
+// checkAge проверяет, что дата рождения dob в прошлом и клиенту исполнилось KYC_MIN_AGE лет
+func (s *ProfileService) checkAge(dob time.Time) error {
+	today := truncateDay(time.Now())
+	if dob.AddDate(s.cfg.MinAge, 0, 0).After(today) {
+		ru := fmt.Sprintf("клиенту должно быть не меньше %d лет", s.cfg.MinAge)
+		en := fmt.Sprintf("the customer must be at least %d years old", s.cfg.MinAge)
+		return validation.Errors{validation.NewFieldError("date_of_birth", "age", ru, en)}
+	}
+	return nil
+}
+
+// notifyDecision отправляет пользователю письмо с решением по проверке личности
+func (s *ProfileService) notifyDecision(ctx context.Context, user *models.User, decision models.KYCStatus) {
+	subject := "Личность подтверждена"
+	body := "Проверка личности пройдена: ограничения на операции сняты, доступны выпуск карт и кредиты.\n"
+	if decision == models.KYCRejected {
+		subject = "Проверка личности не пройдена"
+		body = "Проверка личности не пройдена. Проверьте данные профиля и отправьте их повторно: POST /me/kyc.\n"
+	}
+	if err := s.notifier.Send(ctx, user.Email, subject, body); err != nil {
+		s.logger.Errorf("Ошибка отправки уведомления о проверке личности пользователю %d: %v", user.ID, err)
+	}
+}
+
+// sameDate сообщает, совпадают ли две необязательные даты
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
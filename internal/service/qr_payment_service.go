@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/qr"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrInvalidQR        = apperr.New(apperr.Invalid, apperr.CodeInvalidQR, "недействительный QR-код")                       // Неверный формат или подпись QR-кода
+	ErrQRExpired        = apperr.New(apperr.Gone, apperr.CodeQRExpired, "срок действия QR-кода истек")                      // QR-код просрочен
+	ErrQRAlreadyPaid    = apperr.New(apperr.Conflict, apperr.CodeQRAlreadyPaid, "QR-код уже оплачен")                       // Повторная оплата одноразового QR-кода
+	ErrQRAmountMismatch = apperr.New(apperr.Invalid, apperr.CodeQRAmountMismatch, "сумма не совпадает с суммой в QR-коде")  // Сумма оплаты отличается от зафиксированной в коде
+	ErrQRAmountRequired = apperr.New(apperr.Invalid, apperr.CodeQRAmountRequired, "QR-код без суммы: укажите сумму оплаты") // В коде нет суммы, и плательщик ее не указал
+)
+
+// QRLevel — уровень коррекции ошибок платежных QR-кодов
+const QRLevel = qr.M
+
+// qrHeader — заголовок платежной строки по ГОСТ Р 56042 (формат ST00012, кодировка UTF-8)
+const qrHeader = "ST00012"
+
+// QRPayload представляет содержимое платежного QR-кода
+type QRPayload struct {
+	Payload   string           // Подписанная строка, кодируемая в QR-код
+	AccountID int64            // Счет получателя
+	Amount    *decimal.Decimal // Сумма (nil — сумму вводит плательщик)
+	Currency  account.Currency // Валюта счета получателя
+	Name      string           // Замаскированное имя получателя
+	Nonce     string           // Одноразовый код; QR-код можно оплатить только один раз
+	ExpiresAt time.Time        // Срок действия QR-кода
+}
+
+// QRPaymentService формирует подписанные платежные QR-коды и выполняет оплату по ним.
+// Подпись HMAC-SHA256 защищает реквизиты и сумму от подмены, срок действия и одноразовый код — от повторного использования
+type QRPaymentService struct {
+	qrRepo         *repository.QRPaymentRepository // Репозиторий оплат по QR-кодам
+	userRepo       repository.UserRepository       // Репозиторий пользователей
+	accountService *AccountService                 // Сервис счетов, выполняющий переводы
+	signingKey     []byte                          // Ключ для HMAC-подписи
+	ttl            time.Duration                   // Срок действия QR-кода
+}
+
+// NewQRPaymentService создает новый сервис оплаты по QR-кодам
+func NewQRPaymentService(qrRepo *repository.QRPaymentRepository, userRepo repository.UserRepository,
+	accountService *AccountService, hmacKey string, qrCfg config.QRConfig) *QRPaymentService {
+	return &QRPaymentService{
+		qrRepo:         qrRepo,
+		userRepo:       userRepo,
+		accountService: accountService,
+		signingKey:     []byte(hmacKey),
+		ttl:            qrCfg.TTL,
+	}
+}
+
+// Generate формирует подписанную платежную строку для зачисления на счет пользователя.
+// Если сумма не указана, ее вводит плательщик
+func (s *QRPaymentService) Generate(ctx context.Context, userID, accountID int64, amount *decimal.Decimal) (*QRPayload, error) {
+	if amount != nil && !amount.IsPositive() {
+		return nil, ErrNegativeAmount
+	}
+
+	// Проверка владения счетом получателя
+	acc, err := s.accountService.GetAccountByID(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	p := &QRPayload{
+		AccountID: acc.ID,
+		Amount:    amount,
+		Currency:  acc.Currency,
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: time.Now().Add(s.ttl).Truncate(time.Second),
+	}
+
+	// Имя получателя сокращается так, чтобы подписанная строка поместилась в QR-код
+	p.Name = truncateUTF8(strings.ReplaceAll(maskName(user), "|", " "), qr.Capacity(QRLevel)-len(s.payload(p)))
+	p.Payload = s.payload(p)
+	return p, nil
+}
+
+// payload формирует подписанную платежную строку из содержимого QR-кода
+func (s *QRPaymentService) payload(p *QRPayload) string {
+	fields := []string{qrHeader, "PersonalAcc=" + strconv.FormatInt(p.AccountID, 10)}
+	if p.Amount != nil {
+		// Сумма в копейках, как принято в формате ST00012
+		fields = append(fields, "Sum="+p.Amount.Shift(2).Round(0).String())
+	}
+	fields = append(fields,
+		"Cur="+string(p.Currency),
+		"Name="+p.Name,
+		"Exp="+strconv.FormatInt(p.ExpiresAt.Unix(), 10),
+		"Nonce="+p.Nonce,
+	)
+	unsigned := strings.Join(fields, "|")
+	return unsigned + "|Sig=" + s.sign(unsigned)
+}
+
+// Parse проверяет подпись и срок действия платежной строки и возвращает ее содержимое
+func (s *QRPaymentService) Parse(payload string) (*QRPayload, error) {
+	unsigned, sig, ok := strings.Cut(payload, "|Sig=")
+	if !ok || !s.verify(unsigned, sig) {
+		return nil, ErrInvalidQR
+	}
+
+	fields := strings.Split(unsigned, "|")
+	if fields[0] != qrHeader {
+		return nil, ErrInvalidQR
+	}
+	values := make(map[string]string, len(fields)-1)
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, ErrInvalidQR
+		}
+		values[key] = value
+	}
+
+	p := &QRPayload{
+		Payload:  payload,
+		Currency: account.Currency(values["Cur"]),
+		Name:     values["Name"],
+		Nonce:    values["Nonce"],
+	}
+	var err error
+	if p.AccountID, err = strconv.ParseInt(values["PersonalAcc"], 10, 64); err != nil {
+		return nil, ErrInvalidQR
+	}
+	exp, err := strconv.ParseInt(values["Exp"], 10, 64)
+	if err != nil || p.Nonce == "" {
+		return nil, ErrInvalidQR
+	}
+	p.ExpiresAt = time.Unix(exp, 0)
+	if sum, ok := values["Sum"]; ok {
+		kopecks, err := decimal.NewFromString(sum)
+		if err != nil {
+			return nil, ErrInvalidQR
+		}
+		amount := kopecks.Shift(-2)
+		p.Amount = &amount
+	}
+
+	if time.Now().After(p.ExpiresAt) {
+		return nil, ErrQRExpired
+	}
+	return p, nil
+}
+
+// Pay оплачивает QR-код со счета пользователя. Сумма обязательна для кодов без суммы;
+// для кодов с суммой она может быть опущена или должна совпадать
+func (s *QRPaymentService) Pay(ctx context.Context, userID, fromAccountID int64, payload string, amount decimal.Decimal) (*models.QRPayment, *QRPayload, error) {
+	p, err := s.Parse(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case p.Amount == nil && amount.IsZero():
+		return nil, nil, ErrQRAmountRequired
+	case p.Amount == nil:
+		if !amount.IsPositive() {
+			return nil, nil, ErrNegativeAmount
+		}
+	case !amount.IsZero() && !amount.Equal(*p.Amount):
+		return nil, nil, ErrQRAmountMismatch
+	default:
+		amount = *p.Amount
+	}
+
+	// Проверка владения счетом плательщика и совпадения валют
+	fromAcc, err := s.accountService.GetAccountByID(ctx, fromAccountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromAcc.Currency != p.Currency {
+		return nil, nil, ErrCurrencyMismatch
+	}
+
+	// Одноразовый код фиксируется до перевода, поэтому параллельные оплаты одного QR-кода невозможны
+	claimed, err := s.qrRepo.Claim(ctx, &models.QRPayment{
+		Nonce:         p.Nonce,
+		FromAccountID: fromAccountID,
+		ToAccountID:   p.AccountID,
+		Amount:        amount,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrQRAlreadyPaid
+		}
+		return nil, nil, err
+	}
+
+	if err := s.accountService.Transfer(ctx, fromAccountID, p.AccountID, userID, amount); err != nil {
+		if releaseErr := s.qrRepo.Release(ctx, claimed.ID); releaseErr != nil {
+			return nil, nil, errors.Join(err, releaseErr)
+		}
+		return nil, nil, err
+	}
+	return claimed, p, nil
+}
+
+// sign формирует HMAC-SHA256 подпись платежной строки
+func (s *QRPaymentService) sign(message string) string {
+	h := hmac.New(sha256.New, s.signingKey)
+	h.Write([]byte(message))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// verify проверяет HMAC-SHA256 подпись платежной строки за постоянное время
+func (s *QRPaymentService) verify(message, signature string) bool {
+	mac, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	h := hmac.New(sha256.New, s.signingKey)
+	h.Write([]byte(message))
+	return hmac.Equal(mac, h.Sum(nil))
+}
+
+// truncateUTF8 сокращает строку до n байт, не разрывая многобайтовые символы
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimSpace(s[:n])
+}
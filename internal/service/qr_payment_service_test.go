@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// TestTruncateUTF8 проверяет сокращение имени получателя без разрыва многобайтовых символов
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"Иван П.", 100, "Иван П."},
+		{"Иван П.", 12, "Иван П."},
+		{"Иван П.", 11, "Иван П"},
+		{"Иван П.", 10, "Иван"},
+		{"Иван П.", 3, "И"},
+		{"Иван П.", 1, ""},
+		{"Иван П.", -5, ""},
+	}
+	for _, tt := range tests {
+		got := truncateUTF8(tt.s, tt.n)
+		if got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, ожидается %q", tt.s, tt.n, got, tt.want)
+		}
+		if !utf8.ValidString(got) || len(got) > max(tt.n, 0) {
+			t.Errorf("truncateUTF8(%q, %d) = %q: некорректная строка или превышена длина", tt.s, tt.n, got)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/savings"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+// ErrNotSavingsAccount возвращается при запросе процентов по счету, не являющемуся накопительным
+var ErrNotSavingsAccount = apperr.New(apperr.Invalid, apperr.CodeNotSavingsAccount, "счет не является накопительным")
+
+// accrualPrecision задает точность хранения дневных начислений; до копеек округляется только сумма капитализации
+const accrualPrecision = 8
+
+// SavingsService начисляет проценты по накопительным счетам: ежедневно на остаток на конец дня
+// по ставке, действовавшей в этот день, с капитализацией за каждый завершенный месяц
+type SavingsService struct {
+	savingsRepo     *repository.SavingsRepository     // Репозиторий ставок и начислений
+	accountRepo     *repository.AccountRepository     // Репозиторий для работы со счетами
+	transactionRepo *repository.TransactionRepository // Репозиторий для работы с транзакциями
+	accountService  *AccountService                   // Сервис счетов для проверки владения
+	cfg             config.SavingsConfig              // Настроенная ставка
+	logger          *logrus.Logger                    // Логгер для фоновых задач
+}
+
+// NewSavingsService создает новый сервис накопительных счетов
+func NewSavingsService(savingsRepo *repository.SavingsRepository, accountRepo *repository.AccountRepository,
+	transactionRepo *repository.TransactionRepository, accountService *AccountService,
+	cfg config.SavingsConfig, logger *logrus.Logger) *SavingsService {
+	return &SavingsService{
+		savingsRepo:     savingsRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		accountService:  accountService,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// SyncRate сохраняет настроенную ставку, если она отличается от действующей на дату начала ее действия.
+// Ставка применяется только с даты начала действия: уже начисленные проценты не пересчитываются,
+// поэтому дата в прошлом заменяется текущей
+func (s *SavingsService) SyncRate(ctx context.Context) error {
+	dayCount := savings.DayCount(s.cfg.DayCount)
+	if !dayCount.Valid() {
+		return fmt.Errorf("неизвестная конвенция подсчета дней %q", s.cfg.DayCount)
+	}
+
+	today := truncateDay(time.Now())
+	effective := s.cfg.EffectiveDate
+	if effective.Before(today) {
+		effective = today
+	}
+
+	rates, err := s.savingsRepo.GetRates(ctx)
+	if err != nil {
+		return err
+	}
+	if current := savings.RateOn(rates, effective); current != nil &&
+		current.Rate.Equal(s.cfg.Rate) && current.DayCount == dayCount {
+		return nil
+	}
+
+	s.logger.Infof("Ставка по накопительным счетам %s (%s) действует с %s",
+		s.cfg.Rate, dayCount, effective.Format("2006-01-02"))
+	return s.savingsRepo.SaveRate(ctx, &savings.Rate{EffectiveDate: effective, Rate: s.cfg.Rate, DayCount: dayCount})
+}
+
+// Accrue начисляет проценты по всем накопительным счетам за завершенные дни и капитализирует
+// начисления за завершенные месяцы. Повторный запуск не начисляет проценты дважды.
+// Предназначен для запуска планировщиком
+func (s *SavingsService) Accrue(ctx context.Context) error {
+	rates, err := s.savingsRepo.GetRates(ctx)
+	if err != nil {
+		return err
+	}
+	accounts, err := s.accountRepo.GetAccountsByType(ctx, account.SAVINGS)
+	if err != nil {
+		return err
+	}
+
+	today := truncateDay(time.Now())
+	for _, acc := range accounts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.accrueAccount(ctx, acc, rates, today); err != nil {
+			s.logger.Errorf("Ошибка начисления процентов по счету %d: %v", acc.ID, err)
+			continue
+		}
+		if err := s.capitalize(ctx, acc.ID, today); err != nil {
+			s.logger.Errorf("Ошибка капитализации процентов по счету %d: %v", acc.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetInterest получает действующую ставку и начисления по накопительному счету за период [from, to]
+func (s *SavingsService) GetInterest(ctx context.Context, accountID, userID int64, from, to time.Time) (
+	*savings.Rate, []*savings.Accrual, error) {
+	acc, err := s.accountService.GetAccountByID(ctx, accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if acc.Type != account.SAVINGS {
+		return nil, nil, ErrNotSavingsAccount
+	}
+	if to.Before(from) {
+		return nil, nil, ErrInvalidPeriod
+	}
+
+	rates, err := s.savingsRepo.GetRates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	accruals, err := s.savingsRepo.GetAccruals(ctx, accountID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return savings.RateOn(rates, truncateDay(time.Now())), accruals, nil
+}
+
+// accrueAccount начисляет проценты по счету за дни от последнего начисления (или открытия счета) до вчерашнего
+func (s *SavingsService) accrueAccount(ctx context.Context, acc *account.Account, rates []*savings.Rate, today time.Time) error {
+	start := truncateDay(acc.CreatedAt)
+	last, err := s.savingsRepo.GetLastAccrualDate(ctx, acc.ID)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		start = last.AddDate(0, 0, 1)
+	}
+	days := int(today.Sub(start).Hours() / 24)
+	if days <= 0 {
+		return nil
+	}
+
+	balances, err := closingBalances(ctx, s.transactionRepo, acc, start, days)
+	if err != nil {
+		return err
+	}
+
+	accruals := make([]*savings.Accrual, 0, days)
+	for i, balance := range balances {
+		date := start.AddDate(0, 0, i)
+		accrual := &savings.Accrual{
+			AccountID:   acc.ID,
+			AccrualDate: date,
+			Balance:     balance,
+			Rate:        decimal.Zero,
+			DayCount:    savings.ACT365,
+			Amount:      decimal.Zero,
+		}
+		// Дни без ставки и с неположительным остатком учитываются с нулевым начислением,
+		// чтобы повторный запуск не возвращался к ним
+		if rate := savings.RateOn(rates, date); rate != nil {
+			accrual.Rate, accrual.DayCount = rate.Rate, rate.DayCount
+			if balance.IsPositive() {
+				accrual.Amount = balance.Mul(rate.Rate).
+					Div(decimal.NewFromInt(rate.DayCount.DaysInYear(date))).
+					Round(accrualPrecision)
+			}
+		}
+		accruals = append(accruals, accrual)
+	}
+	return s.savingsRepo.CreateAccruals(ctx, accruals)
+}
+
+// closingBalances восстанавливает остатки на конец каждого из days дней начиная с start
+// по текущему балансу счета и операциям, совершенным с начала start
+func closingBalances(ctx context.Context, transactionRepo *repository.TransactionRepository, acc *account.Account,
+	start time.Time, days int) ([]decimal.Decimal, error) {
+	daily := make([]decimal.Decimal, days)
+	for i := range daily {
+		daily[i] = decimal.Zero
+	}
+	since := decimal.Zero
+	err := transactionRepo.StreamTransactionsSince(ctx, acc.ID, start, func(tx *transaction.Transaction) error {
+		amount := tx.SignedAmount()
+		since = since.Add(amount)
+		if day := int(truncateDay(tx.CreatedAt).Sub(start).Hours() / 24); day < days {
+			daily[day] = daily[day].Add(amount)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Остаток на начало start, затем нарастающим итогом по дням
+	balance := acc.Balance.Sub(since)
+	for i := range daily {
+		balance = balance.Add(daily[i])
+		daily[i] = balance
+	}
+	return daily, nil
+}
+
+// capitalize зачисляет на счет проценты, начисленные за каждый завершенный месяц, отдельной операцией INTEREST.
+// Сумма за месяц округляется до копеек; начисления, уже захваченные параллельным запуском, повторно не зачисляются
+func (s *SavingsService) capitalize(ctx context.Context, accountID int64, today time.Time) error {
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	accruals, err := s.savingsRepo.GetUncapitalized(ctx, accountID, monthStart)
+	if err != nil {
+		return err
+	}
+
+	for len(accruals) > 0 {
+		first := accruals[0].AccrualDate
+		from := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+
+		n := 0
+		for n < len(accruals) && accruals[n].AccrualDate.Before(to) {
+			n++
+		}
+		accruals = accruals[n:]
+
+		credited, err := s.savingsRepo.Capitalize(ctx, accountID, from, to)
+		if err != nil {
+			return err
+		}
+		if credited.IsPositive() {
+			s.logger.Infof("Капитализированы проценты по счету %d за %s: %s", accountID, from.Format("2006-01"), credited)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/calendar"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/dto"
+	"github.com/yujihn/bank_API/internal/models/standingorder"
+	"github.com/yujihn/bank_API/internal/repository"
+)
+
+var (
+	ErrStandingOrderNotFound = apperr.New(apperr.NotFound, apperr.CodeStandingOrderNotFound, "платежное поручение не найдено")               // Поручение не найдено или принадлежит другому пользователю
+	ErrInvalidSchedule       = apperr.New(apperr.Invalid, apperr.CodeInvalidSchedule, "некорректное расписание поручения")                   // Ошибка в параметрах расписания
+	ErrStandingOrderState    = apperr.New(apperr.Conflict, apperr.CodeStandingOrderState, "операция недоступна в текущем статусе поручения") // Недопустимый переход статуса
+)
+
+// Ошибки исполнения записываются в историю поручения и клиенту в ответах не возвращаются
+var (
+	ErrExecutionMissed      = errors.New("дата исполнения пропущена: планировщик не работал") // Дата прошла без попытки перевода
+	ErrExecutionInterrupted = errors.New("исполнение прервано, требуется сверка")             // Обработчик остановился во время перевода
+)
+
+// standingOrderExecutionTimeout задает время, после которого захваченное и не завершенное исполнение
+// считается прерванным сбоем
+const standingOrderExecutionTimeout = 10 * time.Minute
+
+// StandingOrderService управляет регулярными и отложенными переводами и исполняет их по расписанию.
+// Дата исполнения, выпадающая на нерабочий день, переносится на следующий рабочий день; расписание
+// поручения при этом не сдвигается
+type StandingOrderService struct {
+	orderRepo      *repository.StandingOrderRepository // Репозиторий платежных поручений
+	accountRepo    *repository.AccountRepository       // Репозиторий для работы со счетами
+	accountService *AccountService                     // Сервис счетов, выполняющий переводы
+	calendar       *calendar.Calendar                  // Производственный календарь
+	maxFailures    int                                 // Неудачных исполнений подряд до приостановки
+	logger         *logrus.Logger                      // Логгер для фонового исполнения
+}
+
+// NewStandingOrderService создает новый сервис платежных поручений
+func NewStandingOrderService(orderRepo *repository.StandingOrderRepository, accountRepo *repository.AccountRepository,
+	accountService *AccountService, cal *calendar.Calendar, schedCfg config.SchedulerConfig, logger *logrus.Logger) *StandingOrderService {
+	return &StandingOrderService{
+		orderRepo:      orderRepo,
+		accountRepo:    accountRepo,
+		accountService: accountService,
+		calendar:       cal,
+		maxFailures:    schedCfg.StandingOrderMaxFailures,
+		logger:         logger,
+	}
+}
+
+// Create проверяет параметры и создает платежное поручение
+func (s *StandingOrderService) Create(ctx context.Context, userID int64, req dto.CreateStandingOrderRequest) (*standingorder.StandingOrder, error) {
+	if !req.Frequency.Valid() {
+		return nil, fmt.Errorf("%w: неизвестная периодичность %q", ErrInvalidSchedule, req.Frequency)
+	}
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		return nil, ErrNegativeAmount
+	}
+	if req.FromAccountID == req.ToAccountID {
+		return nil, ErrSameAccount
+	}
+
+	today := truncateDay(time.Now())
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: неверный формат даты начала", ErrInvalidSchedule)
+	}
+	if start.Before(today) {
+		return nil, fmt.Errorf("%w: дата начала в прошлом", ErrInvalidSchedule)
+	}
+
+	dayOfMonth := 0
+	if req.Frequency == standingorder.MONTHLY {
+		dayOfMonth = req.DayOfMonth
+		if dayOfMonth == 0 {
+			dayOfMonth = start.Day()
+		}
+		if dayOfMonth < 1 || dayOfMonth > 31 {
+			return nil, fmt.Errorf("%w: день месяца должен быть от 1 до 31", ErrInvalidSchedule)
+		}
+	}
+
+	order := &standingorder.StandingOrder{
+		UserID:        userID,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Frequency:     req.Frequency,
+		DayOfMonth:    dayOfMonth,
+		StartDate:     start,
+		NextRunDate:   req.Frequency.FirstDate(start, dayOfMonth),
+		Status:        standingorder.ACTIVE,
+		Description:   req.Description,
+	}
+
+	if req.EndDate != "" {
+		end, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: неверный формат даты окончания", ErrInvalidSchedule)
+		}
+		if end.Before(order.NextRunDate) {
+			return nil, fmt.Errorf("%w: дата окончания раньше первого исполнения", ErrInvalidSchedule)
+		}
+		order.EndDate = &end
+	}
+	if req.MaxOccurrences != nil {
+		if *req.MaxOccurrences < 1 {
+			return nil, fmt.Errorf("%w: количество исполнений должно быть положительным", ErrInvalidSchedule)
+		}
+		order.MaxOccurrences = req.MaxOccurrences
+	}
+
+	// Проверка владения счетом отправителя и существования счета получателя
+	if _, err := s.accountService.GetAccountByID(ctx, req.FromAccountID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.accountRepo.GetAccountByID(ctx, req.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.Create(ctx, order)
+}
+
+// ToOtherUser сообщает, зачисляются ли переводы по поручению на счет другого пользователя
+func (s *StandingOrderService) ToOtherUser(ctx context.Context, userID int64, req dto.CreateStandingOrderRequest) (bool, error) {
+	to, err := s.accountRepo.GetAccountByID(ctx, req.ToAccountID)
+	if err != nil {
+		return false, err
+	}
+	return to.UserID != userID, nil
+}
+
+// GetUserOrders получает все платежные поручения пользователя
+func (s *StandingOrderService) GetUserOrders(ctx context.Context, userID int64) ([]*standingorder.StandingOrder, error) {
+	return s.orderRepo.GetByUserID(ctx, userID)
+}
+
+// GetOrder получает поручение с историей исполнения с проверкой владения
+func (s *StandingOrderService) GetOrder(ctx context.Context, id, userID int64) (*standingorder.StandingOrder, []*standingorder.Execution, error) {
+	order, err := s.getOwnedOrder(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	executions, err := s.orderRepo.GetExecutionsByOrderID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, executions, nil
+}
+
+// Cancel отменяет активное или приостановленное поручение
+func (s *StandingOrderService) Cancel(ctx context.Context, id, userID int64) error {
+	order, err := s.getOwnedOrder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if order.Status != standingorder.ACTIVE && order.Status != standingorder.PAUSED {
+		return ErrStandingOrderState
+	}
+	return s.orderRepo.UpdateStatus(ctx, id, standingorder.CANCELLED)
+}
+
+// Resume возобновляет приостановленное поручение; даты, пропущенные во время паузы, не исполняются
+func (s *StandingOrderService) Resume(ctx context.Context, id, userID int64) (*standingorder.StandingOrder, error) {
+	order, err := s.getOwnedOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != standingorder.PAUSED {
+		return nil, ErrStandingOrderState
+	}
+
+	today := truncateDay(time.Now())
+	order.Status = standingorder.ACTIVE
+	order.ConsecutiveFailures = 0
+	for order.Status == standingorder.ACTIVE && s.calendar.NextBusinessDay(order.NextRunDate).Before(today) {
+		s.moveNext(order)
+	}
+
+	if err := s.orderRepo.UpdateProgress(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// RunDue исполняет все поручения, дата исполнения которых наступила. Предназначен для запуска планировщиком
+func (s *StandingOrderService) RunDue(ctx context.Context) error {
+	today := truncateDay(time.Now())
+	orders, err := s.orderRepo.GetDue(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.execute(ctx, order, today); err != nil {
+			s.logger.Errorf("Ошибка исполнения платежного поручения %d: %v", order.ID, err)
+		}
+	}
+	return nil
+}
+
+// execute исполняет все наступившие даты поручения с учетом переноса с нерабочих дней. Перед переводом дата
+// захватывается записью исполнения, поэтому параллельные запуски планировщика не исполняют ее дважды.
+// При недостатке средств попытка за текущий день повторяется при следующем запуске в той же записи;
+// дата, так и не исполненная до конца дня, считается неудачной
+func (s *StandingOrderService) execute(ctx context.Context, order *standingorder.StandingOrder, today time.Time) error {
+	for order.Status == standingorder.ACTIVE && !s.calendar.NextBusinessDay(order.NextRunDate).After(today) {
+		date := order.NextRunDate
+		execution, err := s.orderRepo.ClaimExecution(ctx, order.ID, date, order.Amount)
+		if err != nil {
+			return err
+		}
+
+		if execution == nil {
+			// Дата уже захвачена: исполняется другим обработчиком, завершена или прервана сбоем
+			execution, err = s.orderRepo.GetExecution(ctx, order.ID, date)
+			if err != nil {
+				return err
+			}
+			if execution.Status != standingorder.EXECUTION_PROCESSING || execution.StartedAt == nil ||
+				time.Since(*execution.StartedAt) < standingOrderExecutionTimeout {
+				return nil
+			}
+			// Перевод мог пройти до сбоя, поэтому повторять его нельзя; неудача не связана с клиентом
+			// и не учитывается в счетчике неудач подряд
+			execution.Status, execution.Error = standingorder.EXECUTION_FAILED, ErrExecutionInterrupted.Error()
+			s.logger.Warnf("Исполнение платежного поручения %d за %s прервано, требуется сверка",
+				order.ID, date.Format("2006-01-02"))
+		} else {
+			s.run(ctx, order, execution, today)
+		}
+
+		if execution.Status == standingorder.EXECUTION_RETRY {
+			_, err := s.orderRepo.CompleteExecution(ctx, execution, nil, date)
+			return err
+		}
+
+		if order.ConsecutiveFailures >= s.maxFailures {
+			order.Status = standingorder.PAUSED
+			s.logger.Warnf("Платежное поручение %d приостановлено после %d неудачных исполнений подряд",
+				order.ID, order.ConsecutiveFailures)
+		}
+		s.advance(order)
+
+		saved, err := s.orderRepo.CompleteExecution(ctx, execution, order, date)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			// Поручение отменено пользователем или обработано другим обработчиком
+			return nil
+		}
+	}
+	return nil
+}
+
+// run определяет результат захваченного исполнения: за текущий день (с учетом переноса с нерабочего дня)
+// выполняет перевод, а прошедшую дату закрывает без перевода. Дата, пропущенная из-за простоя планировщика,
+// не учитывается в счетчике неудач подряд: сбой банка не должен приостанавливать исправные поручения
+func (s *StandingOrderService) run(ctx context.Context, order *standingorder.StandingOrder, execution *standingorder.Execution, today time.Time) {
+	if s.calendar.NextBusinessDay(execution.ScheduledDate).Before(today) {
+		// Захват без перевода не считается попыткой
+		execution.Attempts--
+		execution.Status = standingorder.EXECUTION_FAILED
+		if execution.Attempts == 0 {
+			execution.Error = ErrExecutionMissed.Error()
+		} else {
+			// Повторы при недостатке средств не помогли до конца дня
+			order.ConsecutiveFailures++
+		}
+		return
+	}
+
+	err := s.accountService.Transfer(ctx, order.FromAccountID, order.ToAccountID, order.UserID, order.Amount)
+	switch {
+	case err == nil:
+		execution.Status, execution.Error = standingorder.EXECUTION_SUCCESS, ""
+		order.ConsecutiveFailures = 0
+	case errors.Is(err, ErrInsufficientFunds):
+		execution.Status, execution.Error = standingorder.EXECUTION_RETRY, err.Error()
+	default:
+		execution.Status, execution.Error = standingorder.EXECUTION_FAILED, err.Error()
+		order.ConsecutiveFailures++
+	}
+}
+
+// advance учитывает обработанную дату исполнения и переводит поручение на следующую дату
+func (s *StandingOrderService) advance(order *standingorder.StandingOrder) {
+	order.Occurrences++
+	if order.MaxOccurrences != nil && order.Occurrences >= *order.MaxOccurrences {
+		order.Status = standingorder.COMPLETED
+		return
+	}
+	s.moveNext(order)
+}
+
+// moveNext переносит поручение на следующую дату по расписанию или завершает его, если дат больше нет
+func (s *StandingOrderService) moveNext(order *standingorder.StandingOrder) {
+	next, ok := order.Frequency.NextDate(order.NextRunDate, order.DayOfMonth)
+	if !ok || (order.EndDate != nil && next.After(*order.EndDate)) {
+		order.Status = standingorder.COMPLETED
+		return
+	}
+	order.NextRunDate = next
+}
+
+// getOwnedOrder получает поручение и проверяет, что оно принадлежит пользователю
+func (s *StandingOrderService) getOwnedOrder(ctx context.Context, id, userID int64) (*standingorder.StandingOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStandingOrderNotFound
+		}
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrStandingOrderNotFound
+	}
+	return order, nil
+}
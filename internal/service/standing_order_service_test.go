@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yujihn/bank_API/internal/calendar"
+	"github.com/yujihn/bank_API/internal/models/standingorder"
+)
+
+// TestRunPastDate проверяет закрытие прошедшей даты без перевода: дата, пропущенная во время простоя
+// планировщика, не входит в счетчик неудач, а дата с неудачными повторами входит
+func TestRunPastDate(t *testing.T) {
+	today := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name         string
+		attempts     int    // Счетчик попыток после захвата
+		prevError    string // Ошибка предыдущей попытки
+		wantAttempts int
+		wantError    string
+		wantFailures int
+	}{
+		{"пропущена при простое", 1, "", 0, ErrExecutionMissed.Error(), 1},
+		{"повторы не помогли", 3, ErrInsufficientFunds.Error(), 2, ErrInsufficientFunds.Error(), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &StandingOrderService{calendar: calendar.New()}
+			order := &standingorder.StandingOrder{ConsecutiveFailures: 1, NextRunDate: yesterday}
+			execution := &standingorder.Execution{
+				ScheduledDate: yesterday,
+				Status:        standingorder.EXECUTION_PROCESSING,
+				Attempts:      tt.attempts,
+				Error:         tt.prevError,
+			}
+
+			s.run(context.Background(), order, execution, today)
+
+			if execution.Status != standingorder.EXECUTION_FAILED {
+				t.Errorf("статус %s, ожидается %s", execution.Status, standingorder.EXECUTION_FAILED)
+			}
+			if execution.Attempts != tt.wantAttempts {
+				t.Errorf("попыток %d, ожидается %d", execution.Attempts, tt.wantAttempts)
+			}
+			if execution.Error != tt.wantError {
+				t.Errorf("ошибка %q, ожидается %q", execution.Error, tt.wantError)
+			}
+			if order.ConsecutiveFailures != tt.wantFailures {
+				t.Errorf("неудач подряд %d, ожидается %d", order.ConsecutiveFailures, tt.wantFailures)
+			}
+		})
+	}
+}
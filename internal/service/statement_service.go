@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/config"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+	"github.com/yujihn/bank_API/internal/repository"
+	"github.com/yujihn/bank_API/internal/statement"
+)
+
+var (
+	ErrInvalidPeriod          = apperr.New(apperr.Invalid, apperr.CodeInvalidPeriod, "некорректный период: дата начала позже даты окончания")                    // Начало периода позже его окончания
+	ErrStatementBeforeCutover = apperr.New(apperr.Unprocessable, apperr.CodeStatementUnavailable, "выписка недоступна за период до исправления типов переводов") // Период затрагивает операции с ненадежным типом
+)
+
+// StatementService формирует выписки по счетам
+type StatementService struct {
+	accountRepo     *repository.AccountRepository     // Репозиторий для работы со счетами
+	transactionRepo *repository.TransactionRepository // Репозиторий для работы с транзакциями
+	userRepo        repository.UserRepository         // Репозиторий пользователей
+	bankCfg         config.BankConfig                 // Реквизиты банка
+}
+
+// NewStatementService создает новый сервис выписок
+func NewStatementService(accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository,
+	userRepo repository.UserRepository, bankCfg config.BankConfig) *StatementService {
+	return &StatementService{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		userRepo:        userRepo,
+		bankCfg:         bankCfg,
+	}
+}
+
+// Prepare проверяет владение счетом и рассчитывает входящий и исходящий остатки за период. Для чужого
+// или несуществующего счета возвращается ErrAccountNotOwned, для периода, затрагивающего операции
+// до исправления типов переводов, — ErrStatementBeforeCutover. Даты from и to задаются включительно
+// и усекаются до начала суток
+func (s *StatementService) Prepare(ctx context.Context, accountID, userID int64, from, to time.Time) (*statement.Statement, error) {
+	from = truncateDay(from)
+	to = truncateDay(to)
+	if from.After(to) {
+		return nil, ErrInvalidPeriod
+	}
+
+	acc, err := s.accountRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotOwned
+		}
+		return nil, err
+	}
+	if acc.UserID != userID {
+		return nil, ErrAccountNotOwned
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Остатки восстанавливаются по операциям после from; до исправления Transfer записывал переводы
+	// с перепутанными типами, поэтому такие операции не должны попадать в расчет
+	legacy, err := s.transactionRepo.HasLegacySince(ctx, acc.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		return nil, ErrStatementBeforeCutover
+	}
+
+	opening, closing, turnover, err := s.periodBalances(ctx, acc, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.Statement{
+		BankName:       s.bankCfg.Name,
+		BankBIC:        s.bankCfg.BIC,
+		BankAddress:    s.bankCfg.Address,
+		AccountID:      acc.ID,
+		UserID:         acc.UserID,
+		OwnerEmail:     user.Email,
+		Currency:       acc.Currency,
+		AccountOpened:  acc.CreatedAt,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: closing,
+		Turnover:       turnover,
+		GeneratedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Write потоково записывает операции за период выписки с нарастающим остатком
+func (s *StatementService) Write(ctx context.Context, st *statement.Statement, w statement.Writer) error {
+	if err := w.Begin(st); err != nil {
+		return err
+	}
+
+	var totals statement.Totals
+	balance := st.OpeningBalance
+	err := s.transactionRepo.StreamTransactionsByPeriod(ctx, st.AccountID, st.From, st.To.AddDate(0, 0, 1),
+		func(tx *transaction.Transaction) error {
+			balance = balance.Add(tx.SignedAmount())
+			entry := statement.Entry{Transaction: tx, Balance: balance}
+			totals.Add(entry)
+			return w.Entry(entry)
+		})
+	if err != nil {
+		return err
+	}
+
+	return w.End(totals)
+}
+
+// periodBalances восстанавливает остатки на начало from и на начало end по текущему балансу счета,
+// вычитая обороты, совершенные после этих моментов, и попутно считает обороты за период [from, end)
+func (s *StatementService) periodBalances(ctx context.Context, acc *account.Account, from, end time.Time) (
+	opening, closing decimal.Decimal, turnover statement.Totals, err error) {
+	sinceFrom, sinceEnd := decimal.Zero, decimal.Zero
+	err = s.transactionRepo.StreamTransactionsSince(ctx, acc.ID, from, func(tx *transaction.Transaction) error {
+		amount := tx.SignedAmount()
+		sinceFrom = sinceFrom.Add(amount)
+		if tx.CreatedAt.Before(end) {
+			turnover.Add(statement.Entry{Transaction: tx})
+		} else {
+			sinceEnd = sinceEnd.Add(amount)
+		}
+		return nil
+	})
+	if err != nil {
+		return opening, closing, turnover, err
+	}
+	return acc.Balance.Sub(sinceFrom), acc.Balance.Sub(sinceEnd), turnover, nil
+}
+
+// truncateDay отбрасывает время, оставляя начало суток в UTC
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
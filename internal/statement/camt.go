@@ -0,0 +1,350 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// camtKind описывает вариант сообщения ISO 20022 семейства camt
+type camtKind struct {
+	namespace string // Пространство имен XSD
+	root      string // Корневой элемент сообщения
+	report    string // Элемент отчета внутри сообщения
+	closing   string // Код исходящего остатка
+	prefix    string // Префикс идентификаторов сообщения
+	intraday  bool   // Отчет может охватывать незавершенный день
+}
+
+var (
+	// camt053 — выписка по счету на конец дня (Bank To Customer Statement)
+	camt053 = camtKind{
+		namespace: "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08",
+		root:      "BkToCstmrStmt",
+		report:    "Stmt",
+		closing:   "CLBD",
+		prefix:    "STMT",
+	}
+	// camt052 — внутридневной отчет по счету (Bank To Customer Account Report)
+	camt052 = camtKind{
+		namespace: "urn:iso:std:iso:20022:tech:xsd:camt.052.001.08",
+		root:      "BkToCstmrAcctRpt",
+		report:    "Rpt",
+		closing:   "ITBD",
+		prefix:    "RPT",
+		intraday:  true,
+	}
+)
+
+// Коды ISO 20022
+const (
+	camtCredit     = "CRDT"  // Зачисление
+	camtDebit      = "DBIT"  // Списание
+	camtBooked     = "BOOK"  // Проведенная запись
+	camtOpening    = "OPBD"  // Входящий остаток
+	camtClearingRU = "RUCBC" // Клиринговая система Банка России (БИК)
+)
+
+// camtWriter потоково записывает выписку в формате ISO 20022 camt.053 или camt.052
+type camtWriter struct {
+	enc  *xml.Encoder // XML-кодировщик
+	kind camtKind     // Вариант сообщения
+	st   *Statement   // Заголовок выписки
+}
+
+// newCAMTWriter создает запись выписки в формате camt
+func newCAMTWriter(w io.Writer, kind camtKind) *camtWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &camtWriter{enc: enc, kind: kind}
+}
+
+// Begin записывает заголовок группы, реквизиты счета, остатки и сводку оборотов
+func (c *camtWriter) Begin(st *Statement) error {
+	c.st = st
+	if !c.kind.intraday {
+		if err := CAMT053.CheckPeriod(st.To, st.GeneratedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := c.enc.EncodeToken(xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)}); err != nil {
+		return err
+	}
+	if err := c.enc.EncodeToken(xml.CharData("\n")); err != nil {
+		return err
+	}
+
+	document := xml.StartElement{
+		Name: xml.Name{Local: "Document"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: c.kind.namespace}},
+	}
+	for _, start := range []xml.StartElement{document, {Name: xml.Name{Local: c.kind.root}}} {
+		if err := c.enc.EncodeToken(start); err != nil {
+			return err
+		}
+	}
+
+	msgID := c.messageID()
+	header := camtGroupHeader{MsgID: msgID, CreDtTm: camtDateTime(st.GeneratedAt)}
+	if err := c.enc.EncodeElement(header, xml.StartElement{Name: xml.Name{Local: "GrpHdr"}}); err != nil {
+		return err
+	}
+
+	if err := c.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: c.kind.report}}); err != nil {
+		return err
+	}
+
+	account := camtAccount{
+		ID:   camtOtherID{ID: strconv.FormatInt(st.AccountID, 10)},
+		Ccy:  string(st.Currency),
+		Ownr: &camtParty{Nm: st.OwnerEmail},
+		Svcr: &camtServicer{FinInstnID: camtFinInstitution{
+			ClrSysMmbID: camtClearingMember{ClrSysID: camtClearingSystem{Cd: camtClearingRU}, MmbID: st.BankBIC},
+			Nm:          st.BankName,
+		}},
+	}
+
+	// Элементы заголовка отчета в порядке, заданном схемой; Ntry дописываются следом
+	elements := []struct {
+		name  string
+		value any
+	}{
+		{"Id", msgID},
+		{"CreDtTm", camtDateTime(st.GeneratedAt)},
+		{"FrToDt", camtPeriod{FrDtTm: camtDateTime(st.From), ToDtTm: camtDateTime(c.periodEnd())}},
+		{"Acct", account},
+		{"Bal", c.balance(camtOpening, st.OpeningBalance, st.From)},
+		{"Bal", c.balance(c.kind.closing, st.ClosingBalance, c.periodEnd())},
+		{"TxsSummry", c.summary(st.Turnover)},
+	}
+	for _, el := range elements {
+		if err := c.enc.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entry записывает запись Ntry по операции
+func (c *camtWriter) Entry(e Entry) error {
+	tx := e.Transaction
+	ref := strconv.FormatInt(tx.ID, 10)
+
+	indicator := camtDebit
+	if tx.Type.IsCredit() {
+		indicator = camtCredit
+	}
+
+	entry := camtEntry{
+		NtryRef:     ref,
+		Amt:         c.amount(tx.Amount),
+		CdtDbtInd:   indicator,
+		Sts:         camtCode{Cd: camtBooked},
+		BookgDt:     camtDate{DtTm: camtDateTime(tx.CreatedAt)},
+		ValDt:       camtDate{Dt: tx.CreatedAt.UTC().Format(dateLayout)},
+		AcctSvcrRef: ref,
+		BkTxCd:      camtTxCode{Prtry: camtProprietary{Cd: string(tx.Type), Issr: c.st.BankName}},
+		NtryDtls: camtEntryDetails{TxDtls: camtTxDetails{
+			Refs: camtRefs{AcctSvcrRef: ref},
+		}},
+	}
+	return c.enc.EncodeElement(entry, xml.StartElement{Name: xml.Name{Local: "Ntry"}})
+}
+
+// End закрывает элементы отчета и документа
+func (c *camtWriter) End(Totals) error {
+	for _, name := range []string{c.kind.report, c.kind.root, "Document"} {
+		if err := c.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return c.enc.Flush()
+}
+
+// messageID формирует уникальный идентификатор сообщения
+func (c *camtWriter) messageID() string {
+	return fmt.Sprintf("%s-%d-%s-%d", c.kind.prefix, c.st.AccountID,
+		c.st.From.Format("20060102"), c.st.GeneratedAt.UnixMilli())
+}
+
+// periodEnd возвращает момент окончания периода: конец дня для camt.053 (за текущий день
+// выписка не формируется) и момент формирования для внутридневного отчета за текущий день
+func (c *camtWriter) periodEnd() time.Time {
+	end := c.st.To.AddDate(0, 0, 1).Add(-time.Second)
+	if c.kind.intraday && c.st.GeneratedAt.Before(end) {
+		return c.st.GeneratedAt
+	}
+	return end
+}
+
+// balance формирует элемент остатка; отрицательный остаток передается как DBIT
+func (c *camtWriter) balance(code string, amount decimal.Decimal, at time.Time) camtBalance {
+	indicator := camtCredit
+	if amount.IsNegative() {
+		indicator = camtDebit
+	}
+	return camtBalance{
+		Tp:        camtBalanceType{CdOrPrtry: camtCode{Cd: code}},
+		Amt:       c.amount(amount.Abs()),
+		CdtDbtInd: indicator,
+		Dt:        camtDate{Dt: at.UTC().Format(dateLayout)},
+	}
+}
+
+// summary формирует сводку оборотов за период
+func (c *camtWriter) summary(t Totals) camtSummary {
+	net := t.Credit.Sub(t.Debit)
+	indicator := camtCredit
+	if net.IsNegative() {
+		indicator = camtDebit
+	}
+	return camtSummary{
+		TtlNtries: camtTotalEntries{
+			NbOfNtries: strconv.Itoa(t.Count),
+			Sum:        t.Credit.Add(t.Debit).StringFixed(2),
+			TtlNetNtry: camtAmountAndDirection{Amt: net.Abs().StringFixed(2), CdtDbtInd: indicator},
+		},
+		TtlCdtNtries: camtNumberAndSum{NbOfNtries: strconv.Itoa(t.CreditCount), Sum: t.Credit.StringFixed(2)},
+		TtlDbtNtries: camtNumberAndSum{NbOfNtries: strconv.Itoa(t.Count - t.CreditCount), Sum: t.Debit.StringFixed(2)},
+	}
+}
+
+// amount формирует сумму с кодом валюты
+func (c *camtWriter) amount(v decimal.Decimal) camtAmount {
+	return camtAmount{Ccy: string(c.st.Currency), Value: v.StringFixed(2)}
+}
+
+// camtDateTime форматирует момент времени как ISODateTime
+func camtDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// Элементы сообщений camt.05x.001.08 в порядке, заданном схемой
+
+type camtGroupHeader struct {
+	MsgID   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtPeriod struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID   camtOtherID   `xml:"Id"`
+	Ccy  string        `xml:"Ccy"`
+	Ownr *camtParty    `xml:"Ownr,omitempty"`
+	Svcr *camtServicer `xml:"Svcr,omitempty"`
+}
+
+type camtOtherID struct {
+	ID string `xml:"Othr>Id"`
+}
+
+type camtParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type camtServicer struct {
+	FinInstnID camtFinInstitution `xml:"FinInstnId"`
+}
+
+type camtFinInstitution struct {
+	ClrSysMmbID camtClearingMember `xml:"ClrSysMmbId"`
+	Nm          string             `xml:"Nm,omitempty"`
+}
+
+type camtClearingMember struct {
+	ClrSysID camtClearingSystem `xml:"ClrSysId"`
+	MmbID    string             `xml:"MmbId"`
+}
+
+type camtClearingSystem struct {
+	Cd string `xml:"Cd"`
+}
+
+type camtBalance struct {
+	Tp        camtBalanceType `xml:"Tp"`
+	Amt       camtAmount      `xml:"Amt"`
+	CdtDbtInd string          `xml:"CdtDbtInd"`
+	Dt        camtDate        `xml:"Dt"`
+}
+
+type camtBalanceType struct {
+	CdOrPrtry camtCode `xml:"CdOrPrtry"`
+}
+
+type camtCode struct {
+	Cd string `xml:"Cd"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt   string `xml:"Dt,omitempty"`
+	DtTm string `xml:"DtTm,omitempty"`
+}
+
+type camtSummary struct {
+	TtlNtries    camtTotalEntries `xml:"TtlNtries"`
+	TtlCdtNtries camtNumberAndSum `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtNumberAndSum `xml:"TtlDbtNtries"`
+}
+
+type camtTotalEntries struct {
+	NbOfNtries string                 `xml:"NbOfNtries"`
+	Sum        string                 `xml:"Sum"`
+	TtlNetNtry camtAmountAndDirection `xml:"TtlNetNtry"`
+}
+
+type camtAmountAndDirection struct {
+	Amt       string `xml:"Amt"`
+	CdtDbtInd string `xml:"CdtDbtInd"`
+}
+
+type camtNumberAndSum struct {
+	NbOfNtries string `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	NtryRef     string           `xml:"NtryRef"`
+	Amt         camtAmount       `xml:"Amt"`
+	CdtDbtInd   string           `xml:"CdtDbtInd"`
+	Sts         camtCode         `xml:"Sts"`
+	BookgDt     camtDate         `xml:"BookgDt"`
+	ValDt       camtDate         `xml:"ValDt"`
+	AcctSvcrRef string           `xml:"AcctSvcrRef"`
+	BkTxCd      camtTxCode       `xml:"BkTxCd"`
+	NtryDtls    camtEntryDetails `xml:"NtryDtls"`
+}
+
+type camtTxCode struct {
+	Prtry camtProprietary `xml:"Prtry"`
+}
+
+type camtProprietary struct {
+	Cd   string `xml:"Cd"`
+	Issr string `xml:"Issr,omitempty"`
+}
+
+type camtEntryDetails struct {
+	TxDtls camtTxDetails `xml:"TxDtls"`
+}
+
+type camtTxDetails struct {
+	Refs camtRefs `xml:"Refs"`
+}
+
+type camtRefs struct {
+	AcctSvcrRef string `xml:"AcctSvcrRef"`
+}
@@ -0,0 +1,185 @@
+package statement
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+// TestCAMTWriterMatchesSchema формирует выписки camt.053 и camt.052 и проверяет их утилитой xmllint
+// по официальным XSD ISO 20022 из testdata
+func TestCAMTWriterMatchesSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint не найден, проверка по XSD пропущена")
+	}
+
+	tests := []struct {
+		format Format
+		schema string
+	}{
+		{CAMT053, "camt.053.001.08.xsd"},
+		{CAMT052, "camt.052.001.08.xsd"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writeSampleStatement(t, tt.format, &buf)
+
+			doc := filepath.Join(t.TempDir(), "statement.xml")
+			if err := os.WriteFile(doc, buf.Bytes(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", tt.schema), doc).
+				CombinedOutput()
+			if err != nil {
+				t.Fatalf("документ не соответствует схеме %s: %v\n%s\n%s", tt.schema, err, out, buf.String())
+			}
+		})
+	}
+}
+
+// TestCAMTWriterNegativeBalance проверяет, что отрицательный остаток передается модулем суммы с признаком DBIT
+func TestCAMTWriterNegativeBalance(t *testing.T) {
+	var buf bytes.Buffer
+	w := newCAMTWriter(&buf, camt053)
+	w.st = sampleStatement()
+
+	bal := w.balance(camtOpening, decimal.RequireFromString("-150.5"), w.st.From)
+	if bal.CdtDbtInd != camtDebit {
+		t.Errorf("CdtDbtInd = %s, ожидается %s", bal.CdtDbtInd, camtDebit)
+	}
+	if bal.Amt.Value != "150.50" {
+		t.Errorf("Amt = %s, ожидается 150.50", bal.Amt.Value)
+	}
+}
+
+// TestCAMTWriterIntradayPeriodEnd проверяет, что внутридневной отчет за текущий день заканчивается
+// моментом формирования, а выписка на конец дня — концом последнего дня периода
+func TestCAMTWriterIntradayPeriodEnd(t *testing.T) {
+	st := sampleStatement()
+	st.To = st.GeneratedAt.Truncate(24 * time.Hour)
+
+	intraday := newCAMTWriter(&bytes.Buffer{}, camt052)
+	intraday.st = st
+	if got := intraday.periodEnd(); !got.Equal(st.GeneratedAt) {
+		t.Errorf("camt.052: конец периода %s, ожидается %s", got, st.GeneratedAt)
+	}
+
+	endOfDay := newCAMTWriter(&bytes.Buffer{}, camt053)
+	endOfDay.st = st
+	want := st.To.AddDate(0, 0, 1).Add(-time.Second)
+	if got := endOfDay.periodEnd(); !got.Equal(want) {
+		t.Errorf("camt.053: конец периода %s, ожидается %s", got, want)
+	}
+}
+
+// TestCheckPeriod проверяет, что выписка на конец дня формируется только за завершенные дни
+func TestCheckPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format Format
+		to     time.Time
+		want   error
+	}{
+		{"camt.053 по вчерашний день", CAMT053, today.AddDate(0, 0, -1), nil},
+		{"camt.053 по сегодня", CAMT053, today, ErrDayNotClosed},
+		{"camt.053 по завтра", CAMT053, today.AddDate(0, 0, 1), ErrDayNotClosed},
+		{"camt.052 по сегодня", CAMT052, today, nil},
+		{"pdf по сегодня", PDF, today, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.CheckPeriod(tt.to, now); got != tt.want {
+				t.Errorf("CheckPeriod = %v, ожидается %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCAMT053RejectsOpenDay проверяет, что выписка camt.053 за текущий день не начинается
+// и в поток не попадает закрывающий остаток CLBD
+func TestCAMT053RejectsOpenDay(t *testing.T) {
+	st := sampleStatement()
+	st.To = st.GeneratedAt.Truncate(24 * time.Hour)
+
+	var buf bytes.Buffer
+	if err := newCAMTWriter(&buf, camt053).Begin(st); err != ErrDayNotClosed {
+		t.Fatalf("Begin = %v, ожидается %v", err, ErrDayNotClosed)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("записано %d байт, ожидается пустой поток", buf.Len())
+	}
+}
+
+// writeSampleStatement записывает выписку с зачислениями и списаниями в формате format
+func writeSampleStatement(t *testing.T, format Format, buf *bytes.Buffer) {
+	t.Helper()
+
+	w, err := NewWriter(format, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := sampleStatement()
+	txs := []*transaction.Transaction{
+		{ID: 101, AccountID: st.AccountID, Amount: decimal.RequireFromString("1500"), Type: transaction.DEPOSIT},
+		{ID: 102, AccountID: st.AccountID, Amount: decimal.RequireFromString("420.75"), Type: transaction.WITHDRAWAL},
+		{ID: 103, AccountID: st.AccountID, Amount: decimal.RequireFromString("0.31"), Type: transaction.DEPOSIT},
+		{ID: 104, AccountID: st.AccountID, Amount: decimal.RequireFromString("2000"), Type: transaction.TRANSFER},
+	}
+
+	var entries []Entry
+	balance := st.OpeningBalance
+	for i, tx := range txs {
+		tx.Status = transaction.COMPLETED
+		tx.CreatedAt = st.From.Add(time.Duration(i+1) * time.Hour)
+		balance = balance.Add(tx.SignedAmount())
+		entry := Entry{Transaction: tx, Balance: balance}
+		st.Turnover.Add(entry)
+		entries = append(entries, entry)
+	}
+	st.ClosingBalance = balance
+
+	if err := w.Begin(st); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := w.Entry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.End(st.Turnover); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sampleStatement возвращает заголовок выписки за три дня с положительным входящим остатком
+func sampleStatement() *Statement {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return &Statement{
+		BankName:       "Bank API",
+		BankBIC:        "044525000",
+		BankAddress:    "Moscow, Russian Federation",
+		AccountID:      42,
+		UserID:         7,
+		OwnerEmail:     "client@example.com",
+		Currency:       account.RUB,
+		AccountOpened:  from.AddDate(0, -1, 0),
+		From:           from,
+		To:             from.AddDate(0, 0, 2),
+		OpeningBalance: decimal.RequireFromString("1000"),
+		GeneratedAt:    time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC),
+	}
+}
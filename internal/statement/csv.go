@@ -0,0 +1,63 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvWriter записывает выписку в формате CSV
+type csvWriter struct {
+	w  *csv.Writer // Поток CSV
+	st *Statement  // Заголовок выписки
+}
+
+// newCSVWriter создает запись выписки в CSV
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// Begin записывает реквизиты, входящий остаток и заголовок таблицы операций
+func (c *csvWriter) Begin(st *Statement) error {
+	c.st = st
+	records := [][]string{
+		{"bank", st.BankName},
+		{"bic", st.BankBIC},
+		{"bank_address", st.BankAddress},
+		{"account_id", strconv.FormatInt(st.AccountID, 10)},
+		{"owner", st.OwnerEmail},
+		{"currency", string(st.Currency)},
+		{"period_from", st.From.Format(dateLayout)},
+		{"period_to", st.To.Format(dateLayout)},
+		{"generated_at", st.GeneratedAt.Format(dateTimeLayout)},
+		{"opening_balance", st.OpeningBalance.StringFixed(2)},
+		{},
+		{"id", "date", "type", "debit", "credit", "balance"},
+	}
+	return c.w.WriteAll(records)
+}
+
+// Entry записывает строку операции; буфер сбрасывается по мере заполнения
+func (c *csvWriter) Entry(e Entry) error {
+	tx := e.Transaction
+	return c.w.Write([]string{
+		strconv.FormatInt(tx.ID, 10),
+		tx.CreatedAt.Format(dateTimeLayout),
+		string(tx.Type),
+		e.Debit().StringFixed(2),
+		e.Credit().StringFixed(2),
+		e.Balance.StringFixed(2),
+	})
+}
+
+// End записывает итоги и исходящий остаток
+func (c *csvWriter) End(totals Totals) error {
+	records := [][]string{
+		{},
+		{"operations", strconv.Itoa(totals.Count)},
+		{"total_debit", totals.Debit.StringFixed(2)},
+		{"total_credit", totals.Credit.StringFixed(2)},
+		{"closing_balance", c.st.ClosingBalance.StringFixed(2)},
+	}
+	return c.w.WriteAll(records)
+}
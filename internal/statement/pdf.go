@@ -0,0 +1,162 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/yujihn/bank_API/internal/pdf"
+)
+
+// Разметка страницы PDF-выписки
+const (
+	pdfMarginLeft   = 40.0
+	pdfMarginRight  = pdf.PageWidth - 40.0
+	pdfMarginTop    = pdf.PageHeight - 40.0
+	pdfMarginBottom = 50.0
+	pdfRowHeight    = 14.0
+	pdfFontSize     = 9.0
+)
+
+// Колонки таблицы операций: левая граница для текста и правая для сумм
+var pdfColumns = struct {
+	id, date, kind, debit, credit, balance float64
+}{
+	id:      pdfMarginLeft,
+	date:    pdfMarginLeft + 60,
+	kind:    pdfMarginLeft + 170,
+	debit:   pdfMarginLeft + 340,
+	credit:  pdfMarginLeft + 425,
+	balance: pdfMarginRight,
+}
+
+// pdfWriter записывает выписку в формате PDF постранично
+type pdfWriter struct {
+	out  io.Writer     // Поток вывода
+	doc  *pdf.Document // Документ
+	page *pdf.Page     // Текущая страница
+	y    float64       // Вертикальная позиция следующей строки
+	st   *Statement    // Заголовок выписки
+}
+
+// newPDFWriter создает запись выписки в PDF
+func newPDFWriter(w io.Writer) *pdfWriter {
+	return &pdfWriter{out: w, page: &pdf.Page{}}
+}
+
+// Begin выводит шапку банка, реквизиты счета и входящий остаток
+func (p *pdfWriter) Begin(st *Statement) error {
+	doc, err := pdf.NewDocument(p.out)
+	if err != nil {
+		return err
+	}
+	p.doc = doc
+	p.st = st
+	p.y = pdfMarginTop
+
+	p.page.Text(pdfMarginLeft, p.y, pdf.FontBold, 16, st.BankName)
+	p.y -= 16
+	p.page.Text(pdfMarginLeft, p.y, pdf.FontRegular, pdfFontSize, fmt.Sprintf("BIC %s, %s", st.BankBIC, st.BankAddress))
+	p.y -= 8
+	p.page.Line(pdfMarginLeft, p.y, pdfMarginRight, p.y, 1)
+	p.y -= 24
+
+	p.page.Text(pdfMarginLeft, p.y, pdf.FontBold, 13, "ACCOUNT STATEMENT")
+	p.y -= 20
+
+	details := [][2]string{
+		{"Account:", strconv.FormatInt(st.AccountID, 10)},
+		{"Account holder:", st.OwnerEmail},
+		{"Currency:", string(st.Currency)},
+		{"Opened:", st.AccountOpened.Format(dateLayout)},
+		{"Period:", st.From.Format(dateLayout) + " - " + st.To.Format(dateLayout)},
+		{"Generated:", st.GeneratedAt.Format(dateTimeLayout) + " UTC"},
+		{"Opening balance:", st.OpeningBalance.StringFixed(2) + " " + string(st.Currency)},
+	}
+	for _, d := range details {
+		p.page.Text(pdfMarginLeft, p.y, pdf.FontBold, pdfFontSize+1, d[0])
+		p.page.Text(pdfMarginLeft+100, p.y, pdf.FontRegular, pdfFontSize+1, d[1])
+		p.y -= pdfRowHeight
+	}
+	p.y -= 10
+
+	p.tableHeader()
+	return nil
+}
+
+// Entry выводит строку операции, начиная новую страницу при необходимости
+func (p *pdfWriter) Entry(e Entry) error {
+	if p.y < pdfMarginBottom {
+		if err := p.flushPage(); err != nil {
+			return err
+		}
+		p.y = pdfMarginTop
+		p.tableHeader()
+	}
+
+	tx := e.Transaction
+	p.page.Text(pdfColumns.id, p.y, pdf.FontRegular, pdfFontSize, strconv.FormatInt(tx.ID, 10))
+	p.page.Text(pdfColumns.date, p.y, pdf.FontRegular, pdfFontSize, tx.CreatedAt.Format(dateTimeLayout))
+	p.page.Text(pdfColumns.kind, p.y, pdf.FontRegular, pdfFontSize, string(tx.Type))
+	if debit := e.Debit(); !debit.IsZero() {
+		p.page.TextRight(pdfColumns.debit, p.y, pdf.FontRegular, pdfFontSize, debit.StringFixed(2))
+	}
+	if credit := e.Credit(); !credit.IsZero() {
+		p.page.TextRight(pdfColumns.credit, p.y, pdf.FontRegular, pdfFontSize, credit.StringFixed(2))
+	}
+	p.page.TextRight(pdfColumns.balance, p.y, pdf.FontRegular, pdfFontSize, e.Balance.StringFixed(2))
+	p.y -= pdfRowHeight
+
+	return nil
+}
+
+// End выводит итоги, исходящий остаток и завершает документ
+func (p *pdfWriter) End(totals Totals) error {
+	// Итоговый блок занимает пять строк
+	if p.y < pdfMarginBottom+5*pdfRowHeight {
+		if err := p.flushPage(); err != nil {
+			return err
+		}
+		p.y = pdfMarginTop
+	}
+
+	p.page.Line(pdfMarginLeft, p.y+pdfRowHeight-4, pdfMarginRight, p.y+pdfRowHeight-4, 0.5)
+	p.y -= 4
+
+	summary := [][2]string{
+		{"Operations:", strconv.Itoa(totals.Count)},
+		{"Total debit:", totals.Debit.StringFixed(2)},
+		{"Total credit:", totals.Credit.StringFixed(2)},
+		{"Closing balance:", p.st.ClosingBalance.StringFixed(2) + " " + string(p.st.Currency)},
+	}
+	for _, s := range summary {
+		p.page.Text(pdfColumns.kind, p.y, pdf.FontBold, pdfFontSize+1, s[0])
+		p.page.TextRight(pdfColumns.balance, p.y, pdf.FontRegular, pdfFontSize+1, s[1])
+		p.y -= pdfRowHeight
+	}
+
+	if err := p.flushPage(); err != nil {
+		return err
+	}
+	return p.doc.Close()
+}
+
+// tableHeader выводит заголовок таблицы операций
+func (p *pdfWriter) tableHeader() {
+	p.page.Text(pdfColumns.id, p.y, pdf.FontBold, pdfFontSize, "ID")
+	p.page.Text(pdfColumns.date, p.y, pdf.FontBold, pdfFontSize, "Date")
+	p.page.Text(pdfColumns.kind, p.y, pdf.FontBold, pdfFontSize, "Type")
+	p.page.TextRight(pdfColumns.debit, p.y, pdf.FontBold, pdfFontSize, "Debit")
+	p.page.TextRight(pdfColumns.credit, p.y, pdf.FontBold, pdfFontSize, "Credit")
+	p.page.TextRight(pdfColumns.balance, p.y, pdf.FontBold, pdfFontSize, "Balance")
+	p.y -= 5
+	p.page.Line(pdfMarginLeft, p.y, pdfMarginRight, p.y, 0.5)
+	p.y -= pdfRowHeight
+}
+
+// flushPage добавляет номер страницы и записывает ее в документ
+func (p *pdfWriter) flushPage() error {
+	number := fmt.Sprintf("Page %d", p.doc.PageCount()+1)
+	p.page.TextRight(pdfMarginRight, pdfMarginBottom-25, pdf.FontRegular, 8, number)
+	return p.doc.AddPage(p.page)
+}
@@ -0,0 +1,160 @@
+// Package statement формирует официальные выписки по счету в различных форматах.
+//
+// Выписка записывается потоково: сначала заголовок с реквизитами банка и счета,
+// затем операции по одной, затем итоги. Поэтому объем выписки не ограничен памятью.
+package statement
+
+import (
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+	"github.com/yujihn/bank_API/internal/models/account"
+	"github.com/yujihn/bank_API/internal/models/transaction"
+)
+
+var (
+	ErrUnsupportedFormat = apperr.New(apperr.Invalid, apperr.CodeUnsupportedFormat, "неподдерживаемый формат выписки")                       // Запрошен неизвестный формат
+	ErrDayNotClosed      = apperr.New(apperr.Invalid, apperr.CodeDayNotClosed, "выписка на конец дня формируется только за завершенные дни") // Период camt.053 включает текущий день
+)
+
+// Format представляет формат выписки
+type Format string
+
+const (
+	CSV     Format = "csv"      // Таблица CSV
+	PDF     Format = "pdf"      // Документ PDF
+	CAMT053 Format = "camt.053" // ISO 20022 camt.053: выписка на конец дня
+	CAMT052 Format = "camt.052" // ISO 20022 camt.052: внутридневной отчет по счету
+)
+
+// ParseFormat разбирает формат выписки из строки; пустая строка означает CSV
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", CSV:
+		return CSV, nil
+	case PDF, CAMT053, CAMT052:
+		return Format(s), nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ContentType возвращает MIME-тип для формата
+func (f Format) ContentType() string {
+	switch f {
+	case PDF:
+		return "application/pdf"
+	case CAMT053, CAMT052:
+		return "application/xml; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// CheckPeriod проверяет, что выписку в формате f можно сформировать за период, заканчивающийся днем to,
+// на момент now: camt.053 сообщает закрывающий остаток CLBD, поэтому день to должен завершиться
+func (f Format) CheckPeriod(to, now time.Time) error {
+	if f == CAMT053 && !to.UTC().Before(now.UTC().Truncate(24*time.Hour)) {
+		return ErrDayNotClosed
+	}
+	return nil
+}
+
+// Extension возвращает расширение файла для формата
+func (f Format) Extension() string {
+	switch f {
+	case CAMT053, CAMT052:
+		return "xml"
+	default:
+		return string(f)
+	}
+}
+
+// Statement содержит заголовочные данные выписки
+type Statement struct {
+	BankName       string           // Наименование банка
+	BankBIC        string           // БИК банка
+	BankAddress    string           // Адрес банка
+	AccountID      int64            // Номер счета
+	UserID         int64            // ID владельца счета
+	OwnerEmail     string           // Email владельца счета
+	Currency       account.Currency // Валюта счета
+	AccountOpened  time.Time        // Дата открытия счета
+	From           time.Time        // Начало периода (включительно)
+	To             time.Time        // Конец периода (включительно, дата)
+	OpeningBalance decimal.Decimal  // Входящий остаток на начало периода
+	ClosingBalance decimal.Decimal  // Исходящий остаток на конец периода
+	Turnover       Totals           // Обороты за период
+	GeneratedAt    time.Time        // Дата и время формирования выписки
+}
+
+// Entry представляет строку выписки
+type Entry struct {
+	Transaction *transaction.Transaction // Операция по счету
+	Balance     decimal.Decimal          // Остаток после операции
+}
+
+// Debit возвращает сумму списания или ноль для зачислений
+func (e Entry) Debit() decimal.Decimal {
+	if e.Transaction.Type.IsCredit() {
+		return decimal.Zero
+	}
+	return e.Transaction.Amount
+}
+
+// Credit возвращает сумму зачисления или ноль для списаний
+func (e Entry) Credit() decimal.Decimal {
+	if e.Transaction.Type.IsCredit() {
+		return e.Transaction.Amount
+	}
+	return decimal.Zero
+}
+
+// Totals содержит итоговые обороты за период
+type Totals struct {
+	Count       int             // Количество операций
+	CreditCount int             // Количество зачислений
+	Debit       decimal.Decimal // Сумма списаний
+	Credit      decimal.Decimal // Сумма зачислений
+}
+
+// Add учитывает строку выписки в итогах
+func (t *Totals) Add(e Entry) {
+	t.Count++
+	if e.Transaction.Type.IsCredit() {
+		t.CreditCount++
+	}
+	t.Debit = t.Debit.Add(e.Debit())
+	t.Credit = t.Credit.Add(e.Credit())
+}
+
+// Writer записывает выписку в определенном формате
+type Writer interface {
+	Begin(st *Statement) error // Записывает заголовок выписки
+	Entry(e Entry) error       // Записывает строку выписки
+	End(totals Totals) error   // Записывает итоги и завершает документ
+}
+
+// NewWriter создает Writer для указанного формата
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w), nil
+	case PDF:
+		return newPDFWriter(w), nil
+	case CAMT053:
+		return newCAMTWriter(w, camt053), nil
+	case CAMT052:
+		return newCAMTWriter(w, camt052), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Дата и время в выписках
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret — секрет SHA-1 из тестовых векторов RFC 6238, приложение B
+var rfcSecret = []byte("12345678901234567890")
+
+// TestCodeRFC6238 проверяет коды по тестовым векторам RFC 6238 для SHA-1.
+// В RFC коды восьмизначные; шестизначный код — его последние шесть цифр
+func TestCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if got != tt.want {
+			t.Errorf("Code(T=%d) = %q, ожидается %q", tt.unix, got, tt.want)
+		}
+	}
+}
+
+// TestVerifySkew проверяет, что код принимается в пределах Skew шагов в каждую сторону и отклоняется за ними
+func TestVerifySkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"текущий шаг", 0, true},
+		{"предыдущий шаг", -Skew, true},
+		{"следующий шаг", Skew, true},
+		{"слишком старый", -Skew - 1, false},
+		{"слишком новый", Skew + 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Verify(rfcSecret, Code(rfcSecret, current+tt.offset), now)
+			if ok != tt.ok {
+				t.Fatalf("Verify = %v, ожидается %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("шаг %d, ожидается %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+// TestVerifyMalformed проверяет отклонение кодов неверной длины
+func TestVerifyMalformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "94287082"} {
+		if _, ok := Verify(rfcSecret, code, now); ok {
+			t.Errorf("Verify(%q) принят, ожидается отказ", code)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// rule описывает правило валидации: check возвращает true, если значение v удовлетворяет правилу
+// с параметром param, message — описание нарушения на поддерживаемых языках
+type rule struct {
+	check   func(v reflect.Value, param string) bool
+	message func(v reflect.Value, param string) text
+}
+
+// text — описание нарушения правила на русском и английском
+type text struct {
+	ru string
+	en string
+}
+
+// rules содержит поддерживаемые правила по именам в теге binding. Кроме общих правил (required, email,
+// min, max) есть прикладные: positive — сумма больше нуля, currency — код валюты ISO 4217, date — дата
+// в формате YYYY-MM-DD, username — имя пользователя, phone — номер телефона в формате E.164
+var rules = map[string]rule{
+	"required": {
+		check:   func(v reflect.Value, _ string) bool { return !isZero(v) },
+		message: fixed("обязательное поле", "required field"),
+	},
+	"email": {
+		check:   func(v reflect.Value, _ string) bool { return isEmail(v.String()) },
+		message: fixed("неверный формат email", "invalid email format"),
+	},
+	"min": {
+		check: func(v reflect.Value, param string) bool {
+			return compare(v, param, func(n, limit decimal.Decimal) bool { return n.GreaterThanOrEqual(limit) })
+		},
+		message: func(v reflect.Value, param string) text {
+			if isLength(v) {
+				return text{fmt.Sprintf("не короче %s символов", param), fmt.Sprintf("at least %s characters", param)}
+			}
+			return text{"не меньше " + param, "must be at least " + param}
+		},
+	},
+	"max": {
+		check: func(v reflect.Value, param string) bool {
+			return compare(v, param, func(n, limit decimal.Decimal) bool { return n.LessThanOrEqual(limit) })
+		},
+		message: func(v reflect.Value, param string) text {
+			if isLength(v) {
+				return text{fmt.Sprintf("не длиннее %s символов", param), fmt.Sprintf("at most %s characters", param)}
+			}
+			return text{"не больше " + param, "must be at most " + param}
+		},
+	},
+	"positive": {
+		check: func(v reflect.Value, _ string) bool {
+			n, ok := number(v)
+			return ok && n.IsPositive()
+		},
+		message: fixed("должно быть больше нуля", "must be greater than zero"),
+	},
+	"currency": {
+		check: func(v reflect.Value, _ string) bool {
+			return v.Kind() == reflect.String && isoCurrencies[v.String()]
+		},
+		message: fixed("неизвестный код валюты ISO 4217", "unknown ISO 4217 currency code"),
+	},
+	"date": {
+		check: func(v reflect.Value, _ string) bool {
+			if v.Kind() != reflect.String {
+				return false
+			}
+			_, err := time.Parse("2006-01-02", v.String())
+			return err == nil
+		},
+		message: fixed("дата в формате YYYY-MM-DD", "date in YYYY-MM-DD format"),
+	},
+	"username": {
+		check: func(v reflect.Value, _ string) bool {
+			return v.Kind() == reflect.String && usernamePattern.MatchString(v.String())
+		},
+		message: fixed("от 3 до 32 латинских букв, цифр, точек и подчеркиваний, начиная с буквы",
+			"3 to 32 Latin letters, digits, dots and underscores, starting with a letter"),
+	},
+	"phone": {
+		check: func(v reflect.Value, _ string) bool {
+			return v.Kind() == reflect.String && phonePattern.MatchString(v.String())
+		},
+		message: fixed("номер телефона в формате +79991234567", "phone number in +79991234567 format"),
+	},
+}
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.]{2,31}$`) // Имя пользователя
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{9,14}$`)          // Номер телефона в формате E.164
+)
+
+// decimalType — тип денежных сумм в запросах
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// fixed возвращает функцию сообщения, не зависящего от значения
+func fixed(ru, en string) func(reflect.Value, string) text {
+	return func(reflect.Value, string) text { return text{ru, en} }
+}
+
+// isZero сообщает, что значение не задано; сумма считается не заданной, если она равна нулю
+func isZero(v reflect.Value) bool {
+	if v.Type() == decimalType {
+		return v.Interface().(decimal.Decimal).IsZero()
+	}
+	return v.IsZero()
+}
+
+// isLength сообщает, что min и max для значения v ограничивают длину, а не величину
+func isLength(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// compare сравнивает длину строки или коллекции либо величину числа v с параметром правила
+func compare(v reflect.Value, param string, ok func(n, limit decimal.Decimal) bool) bool {
+	limit, err := decimal.NewFromString(param)
+	if err != nil {
+		panic(fmt.Sprintf("validation: неверный параметр правила %q", param))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return ok(decimal.NewFromInt(int64(utf8.RuneCountInString(v.String()))), limit)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return ok(decimal.NewFromInt(int64(v.Len())), limit)
+	}
+
+	n, isNumber := number(v)
+	return isNumber && ok(n, limit)
+}
+
+// number возвращает числовое значение v: целого числа, числа с плавающей точкой, суммы decimal.Decimal
+// или строки с десятичным числом
+func number(v reflect.Value) (decimal.Decimal, bool) {
+	if v.Type() == decimalType {
+		return v.Interface().(decimal.Decimal), true
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decimal.NewFromInt(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decimal.NewFromUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return decimal.NewFromFloat(v.Float()), true
+	case reflect.String:
+		n, err := decimal.NewFromString(strings.TrimSpace(v.String()))
+		return n, err == nil
+	}
+	return decimal.Zero, false
+}
+
+// isEmail сообщает, что s — адрес электронной почты без отображаемого имени и угловых скобок
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+// isoCurrencies — действующие коды валют ISO 4217 (без драгоценных металлов, расчетных единиц
+// и тестовых кодов)
+var isoCurrencies = func() map[string]bool {
+	codes := strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BOV BRL BSD BTN BWP BYN
+		BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP
+		GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF
+		KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR
+		MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK
+		SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD USN UYI UYU
+		UYW UZS VED VES VND VUV WST XAF XCD XCG XOF XPF YER ZAR ZMW ZWG`)
+	m := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		m[c] = true
+	}
+	return m
+}()
@@ -0,0 +1,141 @@
+// Package validation проверяет поля структур запросов по тегам binding, например
+// `binding:"required,email"` или `binding:"omitempty,min=6"`. Правила перечисляются через запятую
+// и проверяются по порядку; для поля возвращается первая нарушенная проверка.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yujihn/bank_API/internal/apperr"
+)
+
+// tagName — имя тега со списком правил
+const tagName = "binding"
+
+// FieldError описывает нарушение правила в одном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`           // Имя поля в JSON
+	Rule    string `json:"rule"`            // Нарушенное правило: required, email, min, ...
+	Param   string `json:"param,omitempty"` // Параметр правила, например 6 для min=6
+	Message string `json:"message"`         // Описание ошибки
+	english string // Описание ошибки на английском
+}
+
+// Errors — ошибки валидации по полям в порядке их объявления в структуре
+type Errors []FieldError
+
+// Error реализует интерфейс error
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return "ошибка валидации: " + strings.Join(parts, "; ")
+}
+
+// In возвращает копию ошибок с описаниями на языке lang
+func (e Errors) In(lang apperr.Lang) Errors {
+	out := make(Errors, len(e))
+	copy(out, e)
+	if lang == apperr.EN {
+		for i := range out {
+			if out[i].english != "" {
+				out[i].Message = out[i].english
+			}
+		}
+	}
+	return out
+}
+
+// NewFieldError создает ошибку поля field, нарушившего правило rule, с описанием на русском ru
+// и английском en
+func NewFieldError(field, rule, ru, en string) FieldError {
+	return FieldError{Field: field, Rule: rule, Message: ru, english: en}
+}
+
+// Struct проверяет поля структуры v (или указателя на структуру) по тегам binding и возвращает Errors,
+// если хотя бы одно правило нарушено. Неизвестное правило в теге — ошибка программы, поэтому вызывает panic
+func Struct(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if fe := checkField(rv.Field(i), tag); fe != nil {
+			fe.Field = jsonName(field)
+			errs = append(errs, *fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkField проверяет значение поля по правилам из тега и возвращает первую нарушенную проверку
+func checkField(v reflect.Value, tag string) *FieldError {
+	// Отсутствующее значение необязательного поля-указателя не проверяется
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if hasRule(tag, "required") {
+				t := rules["required"].message(v, "")
+				return &FieldError{Rule: "required", Message: t.ru, english: t.en}
+			}
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	for _, item := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if name == "" {
+			continue
+		}
+		if name == "omitempty" {
+			if isZero(v) {
+				return nil
+			}
+			continue
+		}
+
+		r, ok := rules[name]
+		if !ok {
+			panic(fmt.Sprintf("validation: неизвестное правило %q", name))
+		}
+		if !r.check(v, param) {
+			t := r.message(v, param)
+			return &FieldError{Rule: name, Param: param, Message: t.ru, english: t.en}
+		}
+	}
+	return nil
+}
+
+// hasRule сообщает, есть ли в теге правило name
+func hasRule(tag, name string) bool {
+	for _, item := range strings.Split(tag, ",") {
+		if rule, _, _ := strings.Cut(strings.TrimSpace(item), "="); rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName возвращает имя поля в JSON по тегу json или, если тега нет, имя поля структуры
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yujihn/bank_API/internal/apperr"
+)
+
+// TestRules проверяет каждое правило на допустимых и недопустимых значениях
+func TestRules(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value any
+		want  bool
+	}{
+		{"required", "x", true},
+		{"required", "", false},
+		{"required", 0, false},
+		{"required", decimal.Zero, false},
+		{"required", decimal.RequireFromString("0.01"), true},
+
+		{"email", "user@example.com", true},
+		{"email", "User <user@example.com>", false},
+		{"email", "user@", false},
+		{"email", "user.example.com", false},
+
+		{"min=3", "абв", true},
+		{"min=3", "аб", false},
+		{"max=3", "абвг", false},
+		{"min=1", []int{1}, true},
+		{"min=1", []int{}, false},
+		{"min=10", 10, true},
+		{"min=10", 9, false},
+		{"max=100.50", decimal.RequireFromString("100.50"), true},
+		{"max=100.50", decimal.RequireFromString("100.51"), false},
+		{"min=1", true, false},
+
+		{"positive", decimal.RequireFromString("0.01"), true},
+		{"positive", decimal.Zero, false},
+		{"positive", decimal.RequireFromString("-5"), false},
+		{"positive", 1, true},
+		{"positive", " 2.5 ", true},
+		{"positive", "abc", false},
+
+		{"currency", "RUB", true},
+		{"currency", "USD", true},
+		{"currency", "rub", false},
+		{"currency", "XAU", false},
+		{"currency", "ABC", false},
+
+		{"date", "2024-02-29", true},
+		{"date", "2023-02-29", false},
+		{"date", "29.02.2024", false},
+
+		{"username", "ivan_petrov.1", true},
+		{"username", "ab", false},
+		{"username", "1ivan", false},
+		{"username", "иван", false},
+		{"username", "a234567890123456789012345678901b", true},
+		{"username", "a2345678901234567890123456789012c", false},
+
+		{"phone", "+79991234567", true},
+		{"phone", "89991234567", false},
+		{"phone", "+0991234567", false},
+		{"phone", "+7999", false},
+
+		{"omitempty,email", "", true},
+		{"omitempty,email", "bad", false},
+		{"omitempty,min=6", "", true},
+	}
+	for _, tt := range tests {
+		fe := checkField(reflect.ValueOf(tt.value), tt.tag)
+		if got := fe == nil; got != tt.want {
+			t.Errorf("%s(%#v): допустимо = %v, ожидается %v (ошибка %+v)", tt.tag, tt.value, got, tt.want, fe)
+		}
+	}
+}
+
+// request — структура запроса для проверки Struct
+type request struct {
+	Email    string          `json:"email"              binding:"required,email"`
+	Password string          `json:"password,omitempty" binding:"required,min=6"`
+	Amount   decimal.Decimal `json:"amount"             binding:"positive"`
+	Note     *string         `json:"note"               binding:"max=5"`
+	Currency *string         `json:"currency"           binding:"required,currency"`
+	NoJSON   string          `binding:"required"`
+	Ignored  string          `json:"ignored"`
+	internal string          `binding:"required"`
+}
+
+// TestStruct проверяет порядок ошибок, имена полей из JSON, первую нарушенную проверку и поля-указатели
+func TestStruct(t *testing.T) {
+	long := "слишком длинная заметка"
+	req := &request{Email: "", Password: "123", Amount: decimal.RequireFromString("-1"), Note: &long}
+
+	err := Struct(req)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ошибка %v, ожидается Errors", err)
+	}
+
+	want := []struct{ field, rule, param string }{
+		{"email", "required", ""},
+		{"password", "min", "6"},
+		{"amount", "positive", ""},
+		{"note", "max", "5"},
+		{"currency", "required", ""},
+		{"NoJSON", "required", ""},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("ошибок %d, ожидается %d: %v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		if errs[i].Field != w.field || errs[i].Rule != w.rule || errs[i].Param != w.param {
+			t.Errorf("ошибка %d: %s %s=%s, ожидается %s %s=%s", i, errs[i].Field, errs[i].Rule, errs[i].Param,
+				w.field, w.rule, w.param)
+		}
+	}
+
+	currency, note := "RUB", "ok"
+	valid := request{Email: "user@example.com", Password: "secret", Amount: decimal.NewFromInt(1), Currency: &currency, NoJSON: "x"}
+	if err := Struct(valid); err != nil {
+		t.Errorf("ошибка для корректного запроса без необязательного поля: %v", err)
+	}
+	valid.Note = &note
+	if err := Struct(&valid); err != nil {
+		t.Errorf("ошибка для корректного запроса: %v", err)
+	}
+
+	if err := Struct("не структура"); err != nil {
+		t.Errorf("ошибка для значения, не являющегося структурой: %v", err)
+	}
+}
+
+// TestErrorsIn проверяет выбор языка описаний и то, что исходные ошибки не изменяются
+func TestErrorsIn(t *testing.T) {
+	errs := Errors{
+		NewFieldError("amount", "type", "неверный тип значения", "invalid value type"),
+		{Field: "note", Rule: "custom", Message: "только русский"},
+	}
+
+	en := errs.In(apperr.EN)
+	if en[0].Message != "invalid value type" {
+		t.Errorf("сообщение %q, ожидается английское", en[0].Message)
+	}
+	if en[1].Message != "только русский" {
+		t.Errorf("сообщение без перевода %q, ожидается исходное", en[1].Message)
+	}
+	if errs[0].Message != "неверный тип значения" {
+		t.Errorf("исходное сообщение изменено: %q", errs[0].Message)
+	}
+	if ru := errs.In(apperr.RU); ru[0].Message != "неверный тип значения" {
+		t.Errorf("сообщение %q, ожидается русское", ru[0].Message)
+	}
+	if got, want := errs.Error(), "ошибка валидации: amount: неверный тип значения; note: только русский"; got != want {
+		t.Errorf("Error() = %q, ожидается %q", got, want)
+	}
+}
+
+// TestUnknownRulePanics проверяет, что неизвестное правило в теге считается ошибкой программы
+func TestUnknownRulePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидается panic для неизвестного правила")
+		}
+	}()
+	Struct(struct {
+		Name string `binding:"unknown"`
+	}{Name: "x"})
+}
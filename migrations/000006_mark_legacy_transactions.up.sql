@@ -0,0 +1,10 @@
+-- До исправления Transfer записывал строку отправителя как DEPOSIT, а строку получателя как WITHDRAWAL.
+-- Отличить такие переводы от настоящих пополнений и списаний по сохраненным данным нельзя, поэтому
+-- все существующие операции помечаются как legacy, а новые записываются без пометки
+ALTER TABLE transactions
+    ADD COLUMN legacy BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE transactions
+    ALTER COLUMN legacy SET DEFAULT FALSE;
+
+CREATE INDEX idx_transactions_legacy ON transactions (account_id, created_at) WHERE legacy;
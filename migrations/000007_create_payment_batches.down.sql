@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_payment_batch_items_processing;
+DROP INDEX IF EXISTS idx_payment_batch_items_batch_id;
+DROP TABLE IF EXISTS payment_batch_items;
+DROP INDEX IF EXISTS idx_payment_batches_status;
+DROP TABLE IF EXISTS payment_batches;
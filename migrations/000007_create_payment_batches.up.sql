@@ -0,0 +1,36 @@
+CREATE TABLE payment_batches
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id         BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_account_id BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    message_id      VARCHAR(35)    NOT NULL,
+    source_format   VARCHAR(10)    NOT NULL,
+    item_count      INT            NOT NULL,
+    total_amount    NUMERIC(12, 2) NOT NULL,
+    status          VARCHAR(20)    NOT NULL DEFAULT 'PENDING',
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at    TIMESTAMPTZ,
+    -- Повторная загрузка того же файла (MsgId) отклоняется, а не исполняется второй раз
+    CONSTRAINT uq_payment_batches_user_message UNIQUE (user_id, message_id)
+);
+
+CREATE INDEX idx_payment_batches_status ON payment_batches (status);
+
+CREATE TABLE payment_batch_items
+(
+    id            BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    batch_id      BIGINT         NOT NULL REFERENCES payment_batches (id) ON DELETE CASCADE,
+    end_to_end_id VARCHAR(35)    NOT NULL,
+    to_account_id BIGINT         NOT NULL,
+    amount        NUMERIC(12, 2) NOT NULL,
+    description   VARCHAR(140)   NOT NULL DEFAULT '',
+    status        VARCHAR(20)    NOT NULL DEFAULT 'PENDING',
+    error         TEXT           NOT NULL DEFAULT '',
+    -- Время захвата платежа обработчиком: платеж переводится в PROCESSING до перевода денег, чтобы его
+    -- не исполнили повторно после сбоя или параллельно на другом экземпляре
+    started_at    TIMESTAMPTZ,
+    executed_at   TIMESTAMPTZ
+);
+
+CREATE INDEX idx_payment_batch_items_batch_id ON payment_batch_items (batch_id);
+CREATE INDEX idx_payment_batch_items_processing ON payment_batch_items (batch_id, started_at) WHERE status = 'PROCESSING';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS standing_order_executions;
+DROP INDEX IF EXISTS idx_standing_orders_due;
+DROP INDEX IF EXISTS idx_standing_orders_user_id;
+DROP TABLE IF EXISTS standing_orders;
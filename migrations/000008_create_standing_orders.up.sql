@@ -0,0 +1,38 @@
+CREATE TABLE standing_orders
+(
+    id                   BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id              BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_account_id      BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    to_account_id        BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount               NUMERIC(12, 2) NOT NULL,
+    frequency            VARCHAR(20)    NOT NULL,
+    day_of_month         INT            NOT NULL DEFAULT 0,
+    start_date           DATE           NOT NULL,
+    end_date             DATE,
+    max_occurrences      INT,
+    occurrences          INT            NOT NULL DEFAULT 0,
+    next_run_date        DATE           NOT NULL,
+    consecutive_failures INT            NOT NULL DEFAULT 0,
+    status               VARCHAR(20)    NOT NULL DEFAULT 'ACTIVE',
+    description          VARCHAR(140)   NOT NULL DEFAULT '',
+    created_at           TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_standing_orders_user_id ON standing_orders (user_id);
+CREATE INDEX idx_standing_orders_due ON standing_orders (status, next_run_date);
+
+-- Одна запись на дату исполнения: обработчик захватывает дату вставкой записи до перевода денег,
+-- повторные попытки при недостатке средств обновляют ту же запись
+CREATE TABLE standing_order_executions
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    order_id       BIGINT         NOT NULL REFERENCES standing_orders (id) ON DELETE CASCADE,
+    scheduled_date DATE           NOT NULL,
+    amount         NUMERIC(12, 2) NOT NULL,
+    status         VARCHAR(20)    NOT NULL,
+    error          TEXT           NOT NULL DEFAULT '',
+    attempts       INT            NOT NULL DEFAULT 1,
+    started_at     TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_standing_order_executions_date UNIQUE (order_id, scheduled_date)
+);
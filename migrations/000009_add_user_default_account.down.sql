@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS default_account_id,
+    DROP COLUMN IF EXISTS full_name;
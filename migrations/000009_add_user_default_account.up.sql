@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN full_name          VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN default_account_id BIGINT REFERENCES accounts (id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_payment_request_payments_request_id;
+DROP TABLE IF EXISTS payment_request_payments;
+DROP INDEX IF EXISTS idx_payment_requests_open;
+DROP INDEX IF EXISTS idx_payment_requests_payer_id;
+DROP INDEX IF EXISTS idx_payment_requests_requester_id;
+DROP TABLE IF EXISTS payment_requests;
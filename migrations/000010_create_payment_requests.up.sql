@@ -0,0 +1,29 @@
+CREATE TABLE payment_requests
+(
+    id            BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    requester_id  BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    payer_id      BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    to_account_id BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount        NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    paid_amount   NUMERIC(12, 2) NOT NULL DEFAULT 0.00 CHECK (paid_amount >= 0 AND paid_amount <= amount),
+    memo          VARCHAR(140)   NOT NULL DEFAULT '',
+    due_date      DATE           NOT NULL,
+    status        VARCHAR(20)    NOT NULL DEFAULT 'PENDING',
+    created_at    TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_requests_requester_id ON payment_requests (requester_id);
+CREATE INDEX idx_payment_requests_payer_id ON payment_requests (payer_id);
+CREATE INDEX idx_payment_requests_open ON payment_requests (status, due_date);
+
+CREATE TABLE payment_request_payments
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    request_id      BIGINT         NOT NULL REFERENCES payment_requests (id) ON DELETE CASCADE,
+    from_account_id BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount          NUMERIC(12, 2) NOT NULL,
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_request_payments_request_id ON payment_request_payments (request_id);
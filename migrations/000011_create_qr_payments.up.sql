@@ -0,0 +1,9 @@
+CREATE TABLE qr_payments
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    nonce           VARCHAR(32)    NOT NULL UNIQUE,
+    from_account_id BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    to_account_id   BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount          NUMERIC(12, 2) NOT NULL,
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
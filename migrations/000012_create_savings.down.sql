@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS interest_accruals;
+DROP TABLE IF EXISTS savings_rates;
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS type;
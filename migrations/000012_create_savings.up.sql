@@ -0,0 +1,26 @@
+ALTER TABLE accounts
+    ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'CURRENT';
+
+CREATE TABLE savings_rates
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    effective_date DATE          NOT NULL UNIQUE,
+    rate           NUMERIC(7, 4) NOT NULL CHECK (rate >= 0),
+    day_count      VARCHAR(10)   NOT NULL,
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE interest_accruals
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    account_id     BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    accrual_date   DATE           NOT NULL,
+    balance        NUMERIC(12, 2) NOT NULL,
+    rate           NUMERIC(7, 4)  NOT NULL,
+    day_count      VARCHAR(10)    NOT NULL,
+    amount         NUMERIC(18, 8) NOT NULL,
+    transaction_id BIGINT REFERENCES transactions (id) ON DELETE SET NULL,
+    capitalized_at TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, accrual_date)
+);
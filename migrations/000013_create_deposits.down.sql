@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_deposits_maturity;
+DROP INDEX IF EXISTS idx_deposits_user_id;
+DROP TABLE IF EXISTS deposits;
@@ -0,0 +1,22 @@
+CREATE TABLE deposits
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id         BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    account_id      BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    principal       NUMERIC(12, 2) NOT NULL CHECK (principal > 0),
+    rate            NUMERIC(7, 4)  NOT NULL,
+    rate_source     VARCHAR(20)    NOT NULL,
+    penalty_rate    NUMERIC(7, 4)  NOT NULL,
+    term_months     INT            NOT NULL,
+    maturity_action VARCHAR(20)    NOT NULL,
+    start_date      DATE           NOT NULL,
+    maturity_date   DATE           NOT NULL,
+    rollovers       INT            NOT NULL DEFAULT 0,
+    interest_paid   NUMERIC(12, 2) NOT NULL DEFAULT 0.00,
+    status          VARCHAR(20)    NOT NULL DEFAULT 'ACTIVE',
+    closed_at       TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_deposits_user_id ON deposits (user_id);
+CREATE INDEX idx_deposits_maturity ON deposits (status, maturity_date);
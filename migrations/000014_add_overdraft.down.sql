@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+ALTER TABLE cards
+    DROP COLUMN IF EXISTS blocked_at,
+    DROP COLUMN IF EXISTS cvv_failures;
+DROP TABLE IF EXISTS overdraft_charges;
+ALTER TABLE accounts DROP COLUMN IF EXISTS overdraft_limit;
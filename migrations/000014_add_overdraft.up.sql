@@ -0,0 +1,24 @@
+ALTER TABLE accounts
+    ADD COLUMN overdraft_limit NUMERIC(12, 2) NOT NULL DEFAULT 0.00 CHECK (overdraft_limit >= 0);
+
+CREATE TABLE overdraft_charges
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    account_id     BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    charge_date    DATE           NOT NULL,
+    balance        NUMERIC(12, 2) NOT NULL,
+    rate           NUMERIC(7, 4)  NOT NULL,
+    amount         NUMERIC(12, 2) NOT NULL,
+    transaction_id BIGINT REFERENCES transactions (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, charge_date)
+);
+
+-- Счетчик неверных вводов CVV подряд; после CARD_CVV_MAX_FAILURES попыток карта блокируется
+ALTER TABLE cards
+    ADD COLUMN cvv_failures INT NOT NULL DEFAULT 0,
+    ADD COLUMN blocked_at   TIMESTAMPTZ;
+
+-- Роль пользователя: лимиты овердрафта устанавливает администратор через маршруты /admin
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(10) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'ADMIN'));
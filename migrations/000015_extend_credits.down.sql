@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS credit_payments;
+ALTER TABLE payment_schedules
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS interest,
+    DROP COLUMN IF EXISTS principal;
+ALTER TABLE credits DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,21 @@
+ALTER TABLE credits
+    ADD COLUMN closed_at TIMESTAMPTZ;
+
+ALTER TABLE payment_schedules
+    ADD COLUMN principal NUMERIC(12, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN interest  NUMERIC(12, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN paid_at   TIMESTAMPTZ;
+
+CREATE TABLE credit_payments
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    credit_id      BIGINT         NOT NULL REFERENCES credits (id) ON DELETE CASCADE,
+    amount         NUMERIC(12, 2) NOT NULL,
+    principal      NUMERIC(12, 2) NOT NULL,
+    interest       NUMERIC(12, 2) NOT NULL,
+    kind           VARCHAR(20)    NOT NULL,
+    transaction_id BIGINT REFERENCES transactions (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credit_payments_credit_id ON credit_payments (credit_id);
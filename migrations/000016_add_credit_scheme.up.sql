@@ -0,0 +1,2 @@
+ALTER TABLE credits
+    ADD COLUMN scheme VARCHAR(20) NOT NULL DEFAULT 'ANNUITY';
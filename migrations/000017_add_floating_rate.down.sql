@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS credit_rate_history;
+DROP TABLE IF EXISTS key_rates;
+ALTER TABLE credits
+    DROP COLUMN IF EXISTS rate_margin,
+    DROP COLUMN IF EXISTS rate_type;
@@ -0,0 +1,24 @@
+ALTER TABLE credits
+    ADD COLUMN rate_type   VARCHAR(20) NOT NULL DEFAULT 'FIXED',
+    ADD COLUMN rate_margin NUMERIC(5, 4);
+
+CREATE TABLE key_rates
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    rate       NUMERIC(6, 2) NOT NULL,
+    fetched_at TIMESTAMPTZ   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE credit_rate_history
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    credit_id      BIGINT         NOT NULL REFERENCES credits (id) ON DELETE CASCADE,
+    effective_date DATE           NOT NULL,
+    key_rate       NUMERIC(6, 2)  NOT NULL,
+    margin         NUMERIC(5, 4)  NOT NULL,
+    rate           NUMERIC(5, 4)  NOT NULL,
+    payment        NUMERIC(12, 2) NOT NULL,
+    created_at     TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credit_rate_history_credit_id ON credit_rate_history (credit_id);
@@ -0,0 +1,27 @@
+CREATE TABLE credit_applications
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id         BIGINT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    account_id      BIGINT         NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount          NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    term_months     INT            NOT NULL,
+    scheme          VARCHAR(20)    NOT NULL,
+    rate_type       VARCHAR(20)    NOT NULL,
+    declared_income NUMERIC(12, 2) NOT NULL,
+    observed_income NUMERIC(12, 2) NOT NULL,
+    debt_payments   NUMERIC(12, 2) NOT NULL,
+    payment         NUMERIC(12, 2) NOT NULL,
+    debt_to_income  NUMERIC(7, 4)  NOT NULL,
+    status          VARCHAR(20)    NOT NULL,
+    reasons         TEXT[]         NOT NULL DEFAULT '{}',
+    credit_id       BIGINT REFERENCES credits (id) ON DELETE SET NULL,
+    accepted_at     TIMESTAMPTZ,
+    -- Решение администратора по заявке на ручной проверке
+    reviewed_by     BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at     TIMESTAMPTZ,
+    decision_reason TEXT,
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credit_applications_user_id ON credit_applications (user_id);
+CREATE INDEX idx_credit_applications_status ON credit_applications (status);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS collection_actions;
+ALTER TABLE credit_payments DROP COLUMN IF EXISTS penalty;
+ALTER TABLE payment_schedules DROP COLUMN IF EXISTS penalty;
+ALTER TABLE credits DROP COLUMN IF EXISTS overdue_since;
+ALTER TABLE accounts DROP COLUMN IF EXISTS restricted;
@@ -0,0 +1,28 @@
+ALTER TABLE accounts
+    ADD COLUMN restricted BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE credits
+    ADD COLUMN overdue_since DATE;
+
+ALTER TABLE payment_schedules
+    ADD COLUMN penalty NUMERIC(12, 2) NOT NULL DEFAULT 0.00;
+
+ALTER TABLE credit_payments
+    ADD COLUMN penalty NUMERIC(12, 2) NOT NULL DEFAULT 0.00;
+
+CREATE TABLE collection_actions
+(
+    id            BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    credit_id     BIGINT      NOT NULL REFERENCES credits (id) ON DELETE CASCADE,
+    action        VARCHAR(20) NOT NULL,
+    stage_days    INT         NOT NULL DEFAULT 0,
+    days_past_due INT         NOT NULL,
+    action_date   DATE        NOT NULL,
+    amount        NUMERIC(12, 2),
+    account_id    BIGINT REFERENCES accounts (id) ON DELETE SET NULL,
+    details       TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_collection_actions_credit_id ON collection_actions (credit_id);
+CREATE UNIQUE INDEX idx_collection_actions_penalty ON collection_actions (credit_id, action_date) WHERE action = 'PENALTY';
@@ -0,0 +1,4 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS access_expires_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS access_jti;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS jwt_keys;
@@ -0,0 +1,24 @@
+CREATE TABLE jwt_keys
+(
+    id         VARCHAR(64) PRIMARY KEY,
+    algorithm  VARCHAR(10) NOT NULL,
+    key_data   BYTEA       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE TABLE revoked_tokens
+(
+    jti        VARCHAR(64) PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reason     VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+ALTER TABLE sessions
+    ADD COLUMN access_jti        VARCHAR(64),
+    ADD COLUMN access_expires_at TIMESTAMPTZ;
@@ -0,0 +1,12 @@
+CREATE TABLE login_throttles
+(
+    scope           VARCHAR(10)  NOT NULL,
+    key             VARCHAR(320) NOT NULL,
+    failures        INT          NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ  NOT NULL,
+    blocked_until   TIMESTAMPTZ,
+    locked_at       TIMESTAMPTZ,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_login_throttles_blocked_until ON login_throttles (blocked_until);
@@ -0,0 +1,23 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+UPDATE users SET email_verified_at = created_at;
+
+-- Email хранится в нижнем регистре и уникален без учета регистра. Если уже есть адреса, отличающиеся
+-- только регистром, миграция завершится ошибкой уникальности: такие учетные записи нужно объединить вручную
+UPDATE users SET email = lower(trim(email)) WHERE email <> lower(trim(email));
+
+CREATE UNIQUE INDEX idx_users_email_lower ON users (lower(email));
+
+CREATE TABLE user_tokens
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    VARCHAR(20) NOT NULL,
+    token_hash CHAR(64)    NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id, purpose);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_kyc_pending;
+DROP INDEX IF EXISTS users_username_key;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS kyc_updated_at,
+    DROP COLUMN IF EXISTS kyc_status,
+    DROP COLUMN IF EXISTS address,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS date_of_birth,
+    DROP COLUMN IF EXISTS username;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN username       VARCHAR(32),
+    ADD COLUMN date_of_birth  DATE,
+    ADD COLUMN phone          VARCHAR(16)  NOT NULL DEFAULT '',
+    ADD COLUMN address        VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN kyc_status     VARCHAR(20)  NOT NULL DEFAULT 'UNVERIFIED'
+        CHECK (kyc_status IN ('UNVERIFIED', 'PENDING', 'VERIFIED', 'REJECTED')),
+    ADD COLUMN kyc_updated_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX users_username_key ON users (username);
+CREATE INDEX idx_users_kyc_pending ON users (kyc_updated_at) WHERE kyc_status = 'PENDING';
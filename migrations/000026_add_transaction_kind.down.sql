@@ -0,0 +1,3 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS counterparty_account_id,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,19 @@
+-- Вид операции и счет-контрагент перевода записываются при создании транзакции: поступления для скоринга
+-- и расходы для лимитов KYC выбираются по ним, а не по совпадению сумм и времени
+ALTER TABLE transactions
+    ADD COLUMN kind                    VARCHAR(32) NOT NULL DEFAULT 'UNKNOWN',
+    ADD COLUMN counterparty_account_id BIGINT REFERENCES accounts (id) ON DELETE SET NULL;
+
+-- Транзакции, созданные до появления колонок, размечаются только там, где вид операции однозначно следует
+-- из сохраненных данных; остальные остаются UNKNOWN и в поступления и расходы клиента не попадают
+UPDATE transactions
+SET kind = type
+WHERE type IN ('INTEREST', 'OVERDRAFT_INTEREST');
+
+UPDATE transactions t
+SET kind = 'CREDIT_PAYMENT'
+FROM credit_payments cp
+WHERE cp.transaction_id = t.id;
+
+ALTER TABLE transactions
+    ALTER COLUMN kind DROP DEFAULT;